@@ -0,0 +1,922 @@
+// gRPC API of the spot instance monitor, served on GRPC_ADDR when GRPC_ENABLED=true.
+//
+// Regenerate the Go code after changing this file (from the repository root):
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//     api/spotmonitorpb/spotmonitor.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: api/spotmonitorpb/spotmonitor.proto
+
+package spotmonitorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetInstancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInstancesRequest) Reset() {
+	*x = GetInstancesRequest{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstancesRequest) ProtoMessage() {}
+
+func (x *GetInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstancesRequest.ProtoReflect.Descriptor instead.
+func (*GetInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{0}
+}
+
+type InstanceStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Provider        string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"` // "aliyun" or "gcp"
+	AccountLabel    string                 `protobuf:"bytes,2,opt,name=account_label,json=accountLabel,proto3" json:"account_label,omitempty"`
+	InstanceId      string                 `protobuf:"bytes,3,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // GCP: the instance name
+	InstanceName    string                 `protobuf:"bytes,4,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	Region          string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"` // Aliyun region ID or GCP zone
+	InstanceType    string                 `protobuf:"bytes,6,opt,name=instance_type,json=instanceType,proto3" json:"instance_type,omitempty"`
+	Status          string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"` // as reported by the provider, e.g. Running / Stopped or RUNNING / TERMINATED
+	PublicIp        string                 `protobuf:"bytes,8,opt,name=public_ip,json=publicIp,proto3" json:"public_ip,omitempty"`
+	ManuallyStopped bool                   `protobuf:"varint,9,opt,name=manually_stopped,json=manuallyStopped,proto3" json:"manually_stopped,omitempty"`  // stopped via /stop, not started automatically
+	TrafficShutdown bool                   `protobuf:"varint,10,opt,name=traffic_shutdown,json=trafficShutdown,proto3" json:"traffic_shutdown,omitempty"` // its traffic limit scope is shut down
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InstanceStatus) Reset() {
+	*x = InstanceStatus{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceStatus) ProtoMessage() {}
+
+func (x *InstanceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceStatus.ProtoReflect.Descriptor instead.
+func (*InstanceStatus) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InstanceStatus) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetAccountLabel() string {
+	if x != nil {
+		return x.AccountLabel
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetInstanceType() string {
+	if x != nil {
+		return x.InstanceType
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetPublicIp() string {
+	if x != nil {
+		return x.PublicIp
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetManuallyStopped() bool {
+	if x != nil {
+		return x.ManuallyStopped
+	}
+	return false
+}
+
+func (x *InstanceStatus) GetTrafficShutdown() bool {
+	if x != nil {
+		return x.TrafficShutdown
+	}
+	return false
+}
+
+type GetBillingSummaryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Look-back window in hours, 0 = the current month
+	Hours         int32 `protobuf:"varint,1,opt,name=hours,proto3" json:"hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBillingSummaryRequest) Reset() {
+	*x = GetBillingSummaryRequest{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBillingSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBillingSummaryRequest) ProtoMessage() {}
+
+func (x *GetBillingSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBillingSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetBillingSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetBillingSummaryRequest) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+type GetBillingSummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      []*AccountBilling      `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBillingSummaryResponse) Reset() {
+	*x = GetBillingSummaryResponse{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBillingSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBillingSummaryResponse) ProtoMessage() {}
+
+func (x *GetBillingSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBillingSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetBillingSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetBillingSummaryResponse) GetAccounts() []*AccountBilling {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+type AccountBilling struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountLabel    string                 `protobuf:"bytes,1,opt,name=account_label,json=accountLabel,proto3" json:"account_label,omitempty"`
+	BillingCycle    string                 `protobuf:"bytes,2,opt,name=billing_cycle,json=billingCycle,proto3" json:"billing_cycle,omitempty"`
+	StartTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	TotalAmount     float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	MonthlyEstimate float64                `protobuf:"fixed64,6,opt,name=monthly_estimate,json=monthlyEstimate,proto3" json:"monthly_estimate,omitempty"`
+	TotalSavings    float64                `protobuf:"fixed64,7,opt,name=total_savings,json=totalSavings,proto3" json:"total_savings,omitempty"` // compared with on-demand prices, 0 = unknown
+	Instances       []*InstanceBilling     `protobuf:"bytes,8,rep,name=instances,proto3" json:"instances,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AccountBilling) Reset() {
+	*x = AccountBilling{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountBilling) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountBilling) ProtoMessage() {}
+
+func (x *AccountBilling) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountBilling.ProtoReflect.Descriptor instead.
+func (*AccountBilling) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AccountBilling) GetAccountLabel() string {
+	if x != nil {
+		return x.AccountLabel
+	}
+	return ""
+}
+
+func (x *AccountBilling) GetBillingCycle() string {
+	if x != nil {
+		return x.BillingCycle
+	}
+	return ""
+}
+
+func (x *AccountBilling) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *AccountBilling) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *AccountBilling) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *AccountBilling) GetMonthlyEstimate() float64 {
+	if x != nil {
+		return x.MonthlyEstimate
+	}
+	return 0
+}
+
+func (x *AccountBilling) GetTotalSavings() float64 {
+	if x != nil {
+		return x.TotalSavings
+	}
+	return 0
+}
+
+func (x *AccountBilling) GetInstances() []*InstanceBilling {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+type InstanceBilling struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	InstanceName  string                 `protobuf:"bytes,2,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	Region        string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	InstanceSpec  string                 `protobuf:"bytes,4,opt,name=instance_spec,json=instanceSpec,proto3" json:"instance_spec,omitempty"`
+	TotalAmount   float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	RunningHours  float64                `protobuf:"fixed64,6,opt,name=running_hours,json=runningHours,proto3" json:"running_hours,omitempty"`
+	HourlyCost    float64                `protobuf:"fixed64,7,opt,name=hourly_cost,json=hourlyCost,proto3" json:"hourly_cost,omitempty"`
+	Savings       float64                `protobuf:"fixed64,8,opt,name=savings,proto3" json:"savings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceBilling) Reset() {
+	*x = InstanceBilling{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceBilling) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceBilling) ProtoMessage() {}
+
+func (x *InstanceBilling) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceBilling.ProtoReflect.Descriptor instead.
+func (*InstanceBilling) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InstanceBilling) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *InstanceBilling) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *InstanceBilling) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *InstanceBilling) GetInstanceSpec() string {
+	if x != nil {
+		return x.InstanceSpec
+	}
+	return ""
+}
+
+func (x *InstanceBilling) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *InstanceBilling) GetRunningHours() float64 {
+	if x != nil {
+		return x.RunningHours
+	}
+	return 0
+}
+
+func (x *InstanceBilling) GetHourlyCost() float64 {
+	if x != nil {
+		return x.HourlyCost
+	}
+	return 0
+}
+
+func (x *InstanceBilling) GetSavings() float64 {
+	if x != nil {
+		return x.Savings
+	}
+	return 0
+}
+
+type TriggerCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerCheckRequest) Reset() {
+	*x = TriggerCheckRequest{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerCheckRequest) ProtoMessage() {}
+
+func (x *TriggerCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerCheckRequest.ProtoReflect.Descriptor instead.
+func (*TriggerCheckRequest) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{6}
+}
+
+type TriggerCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerCheckResponse) Reset() {
+	*x = TriggerCheckResponse{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerCheckResponse) ProtoMessage() {}
+
+func (x *TriggerCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerCheckResponse.ProtoReflect.Descriptor instead.
+func (*TriggerCheckResponse) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TriggerCheckResponse) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *TriggerCheckResponse) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type GetTrafficSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrafficSummaryRequest) Reset() {
+	*x = GetTrafficSummaryRequest{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrafficSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrafficSummaryRequest) ProtoMessage() {}
+
+func (x *GetTrafficSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrafficSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetTrafficSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{8}
+}
+
+type GetTrafficSummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      []*AccountTraffic      `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrafficSummaryResponse) Reset() {
+	*x = GetTrafficSummaryResponse{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrafficSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrafficSummaryResponse) ProtoMessage() {}
+
+func (x *GetTrafficSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrafficSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetTrafficSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTrafficSummaryResponse) GetAccounts() []*AccountTraffic {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+type AccountTraffic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountLabel  string                 `protobuf:"bytes,1,opt,name=account_label,json=accountLabel,proto3" json:"account_label,omitempty"`
+	BillingCycle  string                 `protobuf:"bytes,2,opt,name=billing_cycle,json=billingCycle,proto3" json:"billing_cycle,omitempty"`
+	TotalGb       float64                `protobuf:"fixed64,3,opt,name=total_gb,json=totalGb,proto3" json:"total_gb,omitempty"`
+	Scopes        []*ScopeTraffic        `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountTraffic) Reset() {
+	*x = AccountTraffic{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountTraffic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountTraffic) ProtoMessage() {}
+
+func (x *AccountTraffic) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountTraffic.ProtoReflect.Descriptor instead.
+func (*AccountTraffic) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AccountTraffic) GetAccountLabel() string {
+	if x != nil {
+		return x.AccountLabel
+	}
+	return ""
+}
+
+func (x *AccountTraffic) GetBillingCycle() string {
+	if x != nil {
+		return x.BillingCycle
+	}
+	return ""
+}
+
+func (x *AccountTraffic) GetTotalGb() float64 {
+	if x != nil {
+		return x.TotalGb
+	}
+	return 0
+}
+
+func (x *AccountTraffic) GetScopes() []*ScopeTraffic {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+// ScopeTraffic is the usage of one traffic limit scope: a region, global-china or global-non-china
+type ScopeTraffic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scope         string                 `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	UsedGb        float64                `protobuf:"fixed64,2,opt,name=used_gb,json=usedGb,proto3" json:"used_gb,omitempty"`
+	LimitGb       float64                `protobuf:"fixed64,3,opt,name=limit_gb,json=limitGb,proto3" json:"limit_gb,omitempty"` // 0 = no limit
+	Shutdown      bool                   `protobuf:"varint,4,opt,name=shutdown,proto3" json:"shutdown,omitempty"`               // instances of the scope are shut down for exceeding the limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScopeTraffic) Reset() {
+	*x = ScopeTraffic{}
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScopeTraffic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScopeTraffic) ProtoMessage() {}
+
+func (x *ScopeTraffic) ProtoReflect() protoreflect.Message {
+	mi := &file_api_spotmonitorpb_spotmonitor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScopeTraffic.ProtoReflect.Descriptor instead.
+func (*ScopeTraffic) Descriptor() ([]byte, []int) {
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ScopeTraffic) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *ScopeTraffic) GetUsedGb() float64 {
+	if x != nil {
+		return x.UsedGb
+	}
+	return 0
+}
+
+func (x *ScopeTraffic) GetLimitGb() float64 {
+	if x != nil {
+		return x.LimitGb
+	}
+	return 0
+}
+
+func (x *ScopeTraffic) GetShutdown() bool {
+	if x != nil {
+		return x.Shutdown
+	}
+	return false
+}
+
+var File_api_spotmonitorpb_spotmonitor_proto protoreflect.FileDescriptor
+
+const file_api_spotmonitorpb_spotmonitor_proto_rawDesc = "" +
+	"\n" +
+	"#api/spotmonitorpb/spotmonitor.proto\x12\x0espotmonitor.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x15\n" +
+	"\x13GetInstancesRequest\"\xdf\x02\n" +
+	"\x0eInstanceStatus\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12#\n" +
+	"\raccount_label\x18\x02 \x01(\tR\faccountLabel\x12\x1f\n" +
+	"\vinstance_id\x18\x03 \x01(\tR\n" +
+	"instanceId\x12#\n" +
+	"\rinstance_name\x18\x04 \x01(\tR\finstanceName\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12#\n" +
+	"\rinstance_type\x18\x06 \x01(\tR\finstanceType\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1b\n" +
+	"\tpublic_ip\x18\b \x01(\tR\bpublicIp\x12)\n" +
+	"\x10manually_stopped\x18\t \x01(\bR\x0fmanuallyStopped\x12)\n" +
+	"\x10traffic_shutdown\x18\n" +
+	" \x01(\bR\x0ftrafficShutdown\"0\n" +
+	"\x18GetBillingSummaryRequest\x12\x14\n" +
+	"\x05hours\x18\x01 \x01(\x05R\x05hours\"W\n" +
+	"\x19GetBillingSummaryResponse\x12:\n" +
+	"\baccounts\x18\x01 \x03(\v2\x1e.spotmonitor.v1.AccountBillingR\baccounts\"\xfe\x02\n" +
+	"\x0eAccountBilling\x12#\n" +
+	"\raccount_label\x18\x01 \x01(\tR\faccountLabel\x12#\n" +
+	"\rbilling_cycle\x18\x02 \x01(\tR\fbillingCycle\x129\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12!\n" +
+	"\ftotal_amount\x18\x05 \x01(\x01R\vtotalAmount\x12)\n" +
+	"\x10monthly_estimate\x18\x06 \x01(\x01R\x0fmonthlyEstimate\x12#\n" +
+	"\rtotal_savings\x18\a \x01(\x01R\ftotalSavings\x12=\n" +
+	"\tinstances\x18\b \x03(\v2\x1f.spotmonitor.v1.InstanceBillingR\tinstances\"\x97\x02\n" +
+	"\x0fInstanceBilling\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12#\n" +
+	"\rinstance_name\x18\x02 \x01(\tR\finstanceName\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12#\n" +
+	"\rinstance_spec\x18\x04 \x01(\tR\finstanceSpec\x12!\n" +
+	"\ftotal_amount\x18\x05 \x01(\x01R\vtotalAmount\x12#\n" +
+	"\rrunning_hours\x18\x06 \x01(\x01R\frunningHours\x12\x1f\n" +
+	"\vhourly_cost\x18\a \x01(\x01R\n" +
+	"hourlyCost\x12\x18\n" +
+	"\asavings\x18\b \x01(\x01R\asavings\"\x15\n" +
+	"\x13TriggerCheckRequest\"\x8e\x01\n" +
+	"\x14TriggerCheckResponse\x129\n" +
+	"\n" +
+	"started_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"\x1a\n" +
+	"\x18GetTrafficSummaryRequest\"W\n" +
+	"\x19GetTrafficSummaryResponse\x12:\n" +
+	"\baccounts\x18\x01 \x03(\v2\x1e.spotmonitor.v1.AccountTrafficR\baccounts\"\xab\x01\n" +
+	"\x0eAccountTraffic\x12#\n" +
+	"\raccount_label\x18\x01 \x01(\tR\faccountLabel\x12#\n" +
+	"\rbilling_cycle\x18\x02 \x01(\tR\fbillingCycle\x12\x19\n" +
+	"\btotal_gb\x18\x03 \x01(\x01R\atotalGb\x124\n" +
+	"\x06scopes\x18\x04 \x03(\v2\x1c.spotmonitor.v1.ScopeTrafficR\x06scopes\"t\n" +
+	"\fScopeTraffic\x12\x14\n" +
+	"\x05scope\x18\x01 \x01(\tR\x05scope\x12\x17\n" +
+	"\aused_gb\x18\x02 \x01(\x01R\x06usedGb\x12\x19\n" +
+	"\blimit_gb\x18\x03 \x01(\x01R\alimitGb\x12\x1a\n" +
+	"\bshutdown\x18\x04 \x01(\bR\bshutdown2\x93\x03\n" +
+	"\vSpotMonitor\x12U\n" +
+	"\fGetInstances\x12#.spotmonitor.v1.GetInstancesRequest\x1a\x1e.spotmonitor.v1.InstanceStatus0\x01\x12h\n" +
+	"\x11GetBillingSummary\x12(.spotmonitor.v1.GetBillingSummaryRequest\x1a).spotmonitor.v1.GetBillingSummaryResponse\x12Y\n" +
+	"\fTriggerCheck\x12#.spotmonitor.v1.TriggerCheckRequest\x1a$.spotmonitor.v1.TriggerCheckResponse\x12h\n" +
+	"\x11GetTrafficSummary\x12(.spotmonitor.v1.GetTrafficSummaryRequest\x1a).spotmonitor.v1.GetTrafficSummaryResponseB:Z8github.com/iliyian/aliyun-spot-manager/api/spotmonitorpbb\x06proto3"
+
+var (
+	file_api_spotmonitorpb_spotmonitor_proto_rawDescOnce sync.Once
+	file_api_spotmonitorpb_spotmonitor_proto_rawDescData []byte
+)
+
+func file_api_spotmonitorpb_spotmonitor_proto_rawDescGZIP() []byte {
+	file_api_spotmonitorpb_spotmonitor_proto_rawDescOnce.Do(func() {
+		file_api_spotmonitorpb_spotmonitor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_spotmonitorpb_spotmonitor_proto_rawDesc), len(file_api_spotmonitorpb_spotmonitor_proto_rawDesc)))
+	})
+	return file_api_spotmonitorpb_spotmonitor_proto_rawDescData
+}
+
+var file_api_spotmonitorpb_spotmonitor_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_api_spotmonitorpb_spotmonitor_proto_goTypes = []any{
+	(*GetInstancesRequest)(nil),       // 0: spotmonitor.v1.GetInstancesRequest
+	(*InstanceStatus)(nil),            // 1: spotmonitor.v1.InstanceStatus
+	(*GetBillingSummaryRequest)(nil),  // 2: spotmonitor.v1.GetBillingSummaryRequest
+	(*GetBillingSummaryResponse)(nil), // 3: spotmonitor.v1.GetBillingSummaryResponse
+	(*AccountBilling)(nil),            // 4: spotmonitor.v1.AccountBilling
+	(*InstanceBilling)(nil),           // 5: spotmonitor.v1.InstanceBilling
+	(*TriggerCheckRequest)(nil),       // 6: spotmonitor.v1.TriggerCheckRequest
+	(*TriggerCheckResponse)(nil),      // 7: spotmonitor.v1.TriggerCheckResponse
+	(*GetTrafficSummaryRequest)(nil),  // 8: spotmonitor.v1.GetTrafficSummaryRequest
+	(*GetTrafficSummaryResponse)(nil), // 9: spotmonitor.v1.GetTrafficSummaryResponse
+	(*AccountTraffic)(nil),            // 10: spotmonitor.v1.AccountTraffic
+	(*ScopeTraffic)(nil),              // 11: spotmonitor.v1.ScopeTraffic
+	(*timestamppb.Timestamp)(nil),     // 12: google.protobuf.Timestamp
+}
+var file_api_spotmonitorpb_spotmonitor_proto_depIdxs = []int32{
+	4,  // 0: spotmonitor.v1.GetBillingSummaryResponse.accounts:type_name -> spotmonitor.v1.AccountBilling
+	12, // 1: spotmonitor.v1.AccountBilling.start_time:type_name -> google.protobuf.Timestamp
+	12, // 2: spotmonitor.v1.AccountBilling.end_time:type_name -> google.protobuf.Timestamp
+	5,  // 3: spotmonitor.v1.AccountBilling.instances:type_name -> spotmonitor.v1.InstanceBilling
+	12, // 4: spotmonitor.v1.TriggerCheckResponse.started_at:type_name -> google.protobuf.Timestamp
+	12, // 5: spotmonitor.v1.TriggerCheckResponse.finished_at:type_name -> google.protobuf.Timestamp
+	10, // 6: spotmonitor.v1.GetTrafficSummaryResponse.accounts:type_name -> spotmonitor.v1.AccountTraffic
+	11, // 7: spotmonitor.v1.AccountTraffic.scopes:type_name -> spotmonitor.v1.ScopeTraffic
+	0,  // 8: spotmonitor.v1.SpotMonitor.GetInstances:input_type -> spotmonitor.v1.GetInstancesRequest
+	2,  // 9: spotmonitor.v1.SpotMonitor.GetBillingSummary:input_type -> spotmonitor.v1.GetBillingSummaryRequest
+	6,  // 10: spotmonitor.v1.SpotMonitor.TriggerCheck:input_type -> spotmonitor.v1.TriggerCheckRequest
+	8,  // 11: spotmonitor.v1.SpotMonitor.GetTrafficSummary:input_type -> spotmonitor.v1.GetTrafficSummaryRequest
+	1,  // 12: spotmonitor.v1.SpotMonitor.GetInstances:output_type -> spotmonitor.v1.InstanceStatus
+	3,  // 13: spotmonitor.v1.SpotMonitor.GetBillingSummary:output_type -> spotmonitor.v1.GetBillingSummaryResponse
+	7,  // 14: spotmonitor.v1.SpotMonitor.TriggerCheck:output_type -> spotmonitor.v1.TriggerCheckResponse
+	9,  // 15: spotmonitor.v1.SpotMonitor.GetTrafficSummary:output_type -> spotmonitor.v1.GetTrafficSummaryResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_spotmonitorpb_spotmonitor_proto_init() }
+func file_api_spotmonitorpb_spotmonitor_proto_init() {
+	if File_api_spotmonitorpb_spotmonitor_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_spotmonitorpb_spotmonitor_proto_rawDesc), len(file_api_spotmonitorpb_spotmonitor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_spotmonitorpb_spotmonitor_proto_goTypes,
+		DependencyIndexes: file_api_spotmonitorpb_spotmonitor_proto_depIdxs,
+		MessageInfos:      file_api_spotmonitorpb_spotmonitor_proto_msgTypes,
+	}.Build()
+	File_api_spotmonitorpb_spotmonitor_proto = out.File
+	file_api_spotmonitorpb_spotmonitor_proto_goTypes = nil
+	file_api_spotmonitorpb_spotmonitor_proto_depIdxs = nil
+}
@@ -0,0 +1,261 @@
+// gRPC API of the spot instance monitor, served on GRPC_ADDR when GRPC_ENABLED=true.
+//
+// Regenerate the Go code after changing this file (from the repository root):
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//     api/spotmonitorpb/spotmonitor.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             (unknown)
+// source: api/spotmonitorpb/spotmonitor.proto
+
+package spotmonitorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SpotMonitor_GetInstances_FullMethodName      = "/spotmonitor.v1.SpotMonitor/GetInstances"
+	SpotMonitor_GetBillingSummary_FullMethodName = "/spotmonitor.v1.SpotMonitor/GetBillingSummary"
+	SpotMonitor_TriggerCheck_FullMethodName      = "/spotmonitor.v1.SpotMonitor/TriggerCheck"
+	SpotMonitor_GetTrafficSummary_FullMethodName = "/spotmonitor.v1.SpotMonitor/GetTrafficSummary"
+)
+
+// SpotMonitorClient is the client API for SpotMonitor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SpotMonitor exposes the monitored instances, billing and traffic of the monitor.
+// When GRPC_TOKEN is set, every call must carry "authorization: Bearer <token>" metadata.
+type SpotMonitorClient interface {
+	// GetInstances streams the status of every monitored Aliyun and GCP instance
+	GetInstances(ctx context.Context, in *GetInstancesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstanceStatus], error)
+	// GetBillingSummary returns the billing summary of each Aliyun account
+	GetBillingSummary(ctx context.Context, in *GetBillingSummaryRequest, opts ...grpc.CallOption) (*GetBillingSummaryResponse, error)
+	// TriggerCheck runs a check cycle right away and returns once it has finished
+	TriggerCheck(ctx context.Context, in *TriggerCheckRequest, opts ...grpc.CallOption) (*TriggerCheckResponse, error)
+	// GetTrafficSummary returns the internet traffic of each Aliyun account this month
+	GetTrafficSummary(ctx context.Context, in *GetTrafficSummaryRequest, opts ...grpc.CallOption) (*GetTrafficSummaryResponse, error)
+}
+
+type spotMonitorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSpotMonitorClient(cc grpc.ClientConnInterface) SpotMonitorClient {
+	return &spotMonitorClient{cc}
+}
+
+func (c *spotMonitorClient) GetInstances(ctx context.Context, in *GetInstancesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstanceStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SpotMonitor_ServiceDesc.Streams[0], SpotMonitor_GetInstances_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetInstancesRequest, InstanceStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SpotMonitor_GetInstancesClient = grpc.ServerStreamingClient[InstanceStatus]
+
+func (c *spotMonitorClient) GetBillingSummary(ctx context.Context, in *GetBillingSummaryRequest, opts ...grpc.CallOption) (*GetBillingSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBillingSummaryResponse)
+	err := c.cc.Invoke(ctx, SpotMonitor_GetBillingSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotMonitorClient) TriggerCheck(ctx context.Context, in *TriggerCheckRequest, opts ...grpc.CallOption) (*TriggerCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerCheckResponse)
+	err := c.cc.Invoke(ctx, SpotMonitor_TriggerCheck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotMonitorClient) GetTrafficSummary(ctx context.Context, in *GetTrafficSummaryRequest, opts ...grpc.CallOption) (*GetTrafficSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTrafficSummaryResponse)
+	err := c.cc.Invoke(ctx, SpotMonitor_GetTrafficSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SpotMonitorServer is the server API for SpotMonitor service.
+// All implementations must embed UnimplementedSpotMonitorServer
+// for forward compatibility.
+//
+// SpotMonitor exposes the monitored instances, billing and traffic of the monitor.
+// When GRPC_TOKEN is set, every call must carry "authorization: Bearer <token>" metadata.
+type SpotMonitorServer interface {
+	// GetInstances streams the status of every monitored Aliyun and GCP instance
+	GetInstances(*GetInstancesRequest, grpc.ServerStreamingServer[InstanceStatus]) error
+	// GetBillingSummary returns the billing summary of each Aliyun account
+	GetBillingSummary(context.Context, *GetBillingSummaryRequest) (*GetBillingSummaryResponse, error)
+	// TriggerCheck runs a check cycle right away and returns once it has finished
+	TriggerCheck(context.Context, *TriggerCheckRequest) (*TriggerCheckResponse, error)
+	// GetTrafficSummary returns the internet traffic of each Aliyun account this month
+	GetTrafficSummary(context.Context, *GetTrafficSummaryRequest) (*GetTrafficSummaryResponse, error)
+	mustEmbedUnimplementedSpotMonitorServer()
+}
+
+// UnimplementedSpotMonitorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSpotMonitorServer struct{}
+
+func (UnimplementedSpotMonitorServer) GetInstances(*GetInstancesRequest, grpc.ServerStreamingServer[InstanceStatus]) error {
+	return status.Error(codes.Unimplemented, "method GetInstances not implemented")
+}
+func (UnimplementedSpotMonitorServer) GetBillingSummary(context.Context, *GetBillingSummaryRequest) (*GetBillingSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBillingSummary not implemented")
+}
+func (UnimplementedSpotMonitorServer) TriggerCheck(context.Context, *TriggerCheckRequest) (*TriggerCheckResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TriggerCheck not implemented")
+}
+func (UnimplementedSpotMonitorServer) GetTrafficSummary(context.Context, *GetTrafficSummaryRequest) (*GetTrafficSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTrafficSummary not implemented")
+}
+func (UnimplementedSpotMonitorServer) mustEmbedUnimplementedSpotMonitorServer() {}
+func (UnimplementedSpotMonitorServer) testEmbeddedByValue()                     {}
+
+// UnsafeSpotMonitorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SpotMonitorServer will
+// result in compilation errors.
+type UnsafeSpotMonitorServer interface {
+	mustEmbedUnimplementedSpotMonitorServer()
+}
+
+func RegisterSpotMonitorServer(s grpc.ServiceRegistrar, srv SpotMonitorServer) {
+	// If the following call panics, it indicates UnimplementedSpotMonitorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SpotMonitor_ServiceDesc, srv)
+}
+
+func _SpotMonitor_GetInstances_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetInstancesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SpotMonitorServer).GetInstances(m, &grpc.GenericServerStream[GetInstancesRequest, InstanceStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SpotMonitor_GetInstancesServer = grpc.ServerStreamingServer[InstanceStatus]
+
+func _SpotMonitor_GetBillingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBillingSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotMonitorServer).GetBillingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpotMonitor_GetBillingSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotMonitorServer).GetBillingSummary(ctx, req.(*GetBillingSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpotMonitor_TriggerCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotMonitorServer).TriggerCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpotMonitor_TriggerCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotMonitorServer).TriggerCheck(ctx, req.(*TriggerCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpotMonitor_GetTrafficSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrafficSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotMonitorServer).GetTrafficSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpotMonitor_GetTrafficSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotMonitorServer).GetTrafficSummary(ctx, req.(*GetTrafficSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SpotMonitor_ServiceDesc is the grpc.ServiceDesc for SpotMonitor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SpotMonitor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spotmonitor.v1.SpotMonitor",
+	HandlerType: (*SpotMonitorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBillingSummary",
+			Handler:    _SpotMonitor_GetBillingSummary_Handler,
+		},
+		{
+			MethodName: "TriggerCheck",
+			Handler:    _SpotMonitor_TriggerCheck_Handler,
+		},
+		{
+			MethodName: "GetTrafficSummary",
+			Handler:    _SpotMonitor_GetTrafficSummary_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetInstances",
+			Handler:       _SpotMonitor_GetInstances_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/spotmonitorpb/spotmonitor.proto",
+}
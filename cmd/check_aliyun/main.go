@@ -0,0 +1,199 @@
+// Command check_aliyun verifies the Aliyun credentials of the monitor by calling each API it
+// depends on (ECS, BSS billing, VPC bandwidth packages) and reports which calls succeed.
+//
+// Usage: check_aliyun [region]
+//
+// Credentials are read from ALIYUN_ACCESS_KEY_ID / ALIYUN_ACCESS_KEY_SECRET (comma-separated
+// for multiple accounts), or ALIYUN_RAM_ROLE_NAME when ALIYUN_AUTH_MODE=ram_role.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/joho/godotenv"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultRegion is the region queried for instances and bandwidth packages
+const defaultRegion = "cn-hangzhou"
+
+// stepTimeout bounds each API call
+const stepTimeout = 30 * time.Second
+
+func main() {
+	if len(os.Args) > 2 {
+		fmt.Fprintln(os.Stderr, "usage: check_aliyun [region]")
+		os.Exit(2)
+	}
+	regionID := defaultRegion
+	if len(os.Args) == 2 {
+		regionID = os.Args[1]
+	}
+
+	// Same sources as the monitor: .env is optional
+	_ = godotenv.Load()
+	// Keep the client libraries' progress logs out of the report
+	log.SetLevel(log.WarnLevel)
+
+	creds, labels, err := loadCredentials()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for i, cred := range creds {
+		failed += checkAccount(labels[i], cred, regionID)
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n❌ %d check(s) failed\n", failed)
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ All checks passed")
+}
+
+// loadCredentials reads the Aliyun credentials from the environment, returning a display
+// label (masked AccessKey ID or RAM role) for each
+func loadCredentials() ([]aliyun.Credential, []string, error) {
+	if strings.EqualFold(os.Getenv("ALIYUN_AUTH_MODE"), "ram_role") {
+		role := strings.TrimSpace(os.Getenv("ALIYUN_RAM_ROLE_NAME"))
+		if role == "" {
+			return nil, nil, fmt.Errorf("ALIYUN_RAM_ROLE_NAME is required when ALIYUN_AUTH_MODE=ram_role")
+		}
+		return []aliyun.Credential{{RAMRoleName: role}}, []string{"RAM role " + role}, nil
+	}
+
+	keyIDs := splitList(os.Getenv("ALIYUN_ACCESS_KEY_ID"))
+	secrets := splitList(os.Getenv("ALIYUN_ACCESS_KEY_SECRET"))
+	if len(keyIDs) == 0 || len(secrets) == 0 {
+		return nil, nil, fmt.Errorf("ALIYUN_ACCESS_KEY_ID and ALIYUN_ACCESS_KEY_SECRET are required")
+	}
+	if len(keyIDs) != len(secrets) {
+		return nil, nil, fmt.Errorf("ALIYUN_ACCESS_KEY_ID count (%d) does not match ALIYUN_ACCESS_KEY_SECRET count (%d)",
+			len(keyIDs), len(secrets))
+	}
+
+	creds := make([]aliyun.Credential, len(keyIDs))
+	labels := make([]string, len(keyIDs))
+	for i := range keyIDs {
+		creds[i] = aliyun.Credential{AccessKeyID: keyIDs[i], AccessKeySecret: secrets[i]}
+		labels[i] = "AccessKey " + mask(keyIDs[i])
+	}
+	return creds, labels, nil
+}
+
+// checkAccount runs every check for one account and returns the number of failed checks
+func checkAccount(label string, cred aliyun.Credential, regionID string) int {
+	fmt.Printf("\n[%s]\n", label)
+	failed := 0
+	run := func(name, permission string, call func(ctx context.Context) (string, error)) {
+		ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+		defer cancel()
+
+		start := time.Now()
+		detail, err := call(ctx)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err != nil {
+			failed++
+			fmt.Printf("  ❌ %-52s %8s\n", name, elapsed)
+			fmt.Printf("     %s\n", redact(err.Error(), cred))
+			fmt.Printf("     → %s\n", hint(err, permission))
+			return
+		}
+		fmt.Printf("  ✅ %-52s %8s  %s\n", name, elapsed, detail)
+	}
+
+	ecsClient := aliyun.NewECSClient(cred)
+	run("ECS DescribeRegions", "ecs:DescribeRegions", func(ctx context.Context) (string, error) {
+		regions, err := ecsClient.GetAllRegions(ctx)
+		return fmt.Sprintf("%d regions", len(regions)), err
+	})
+
+	var instances []aliyun.InstanceInfo
+	run("ECS DescribeInstances ("+regionID+")", "ecs:DescribeInstances", func(ctx context.Context) (string, error) {
+		spot, err := ecsClient.GetSpotInstances(ctx, regionID, "")
+		for _, inst := range spot {
+			instances = append(instances, aliyun.InstanceInfo{
+				InstanceID:   inst.InstanceID,
+				InstanceName: inst.InstanceName,
+				RegionID:     inst.RegionID,
+			})
+		}
+		return fmt.Sprintf("%d spot instances", len(spot)), err
+	})
+
+	run("BSS QueryInstanceBill", "bss:QueryInstanceBill", func(ctx context.Context) (string, error) {
+		billingClient, err := aliyun.NewBillingClient(cred)
+		if err != nil {
+			return "", err
+		}
+		summary, err := billingClient.QueryBilling(ctx, instances, "")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("¥%.2f this month", summary.TotalAmount), nil
+	})
+
+	run("VPC DescribeCommonBandwidthPackages ("+regionID+")", "vpc:DescribeCommonBandwidthPackages", func(ctx context.Context) (string, error) {
+		packages, err := aliyun.NewCBWPClient(cred).DescribeCommonBandwidthPackages(regionID)
+		return fmt.Sprintf("%d bandwidth packages", len(packages)), err
+	})
+
+	return failed
+}
+
+// hint returns an actionable suggestion for a failed call that needs permission
+func hint(err error, permission string) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "InvalidAccessKeyId"):
+		return "The AccessKey ID does not exist or is disabled: check ALIYUN_ACCESS_KEY_ID in the RAM console"
+	case strings.Contains(msg, "InvalidAccessKeySecret"), strings.Contains(msg, "SignatureDoesNotMatch"):
+		return "The AccessKey secret is wrong: check ALIYUN_ACCESS_KEY_SECRET (no extra spaces or quotes)"
+	case strings.Contains(msg, "Forbidden.RAM"), strings.Contains(msg, "NoPermission"):
+		return fmt.Sprintf("The RAM identity lacks permission: grant %s", permission)
+	case strings.Contains(msg, "InvalidRegionId"), strings.Contains(msg, "InvalidRegion"):
+		return "Unknown region: pass a valid region ID, e.g. check_aliyun cn-hangzhou"
+	case aliyun.IsThrottlingError(err):
+		return "The API is rate limited: wait a moment and run the check again"
+	case strings.Contains(msg, "100.100.100.200"), strings.Contains(msg, "ecs_ram_role"):
+		return "STS credentials could not be fetched: run on an ECS instance with the RAM role attached"
+	case strings.Contains(msg, "deadline exceeded"), strings.Contains(msg, "timeout"),
+		strings.Contains(msg, "no such host"), strings.Contains(msg, "connection refused"):
+		return "The API endpoint is unreachable: check network access and proxy settings"
+	default:
+		return fmt.Sprintf("Check that the account can call %s", permission)
+	}
+}
+
+// redact masks the AccessKey ID in s; SDK errors include the signed request URL
+func redact(s string, cred aliyun.Credential) string {
+	if cred.AccessKeyID == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, cred.AccessKeyID, mask(cred.AccessKeyID))
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// mask shows only the first 4 characters of a secret
+func mask(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", len(s)-4)
+}
@@ -0,0 +1,382 @@
+// Command check_config parses and validates the monitor configuration (config file plus
+// environment variables) and prints a summary without starting the monitor.
+//
+// Usage: check_config [config.yaml]
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/config"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if len(os.Args) > 2 {
+		fmt.Fprintln(os.Stderr, "usage: check_config [config-file]")
+		os.Exit(2)
+	}
+	if len(os.Args) == 2 {
+		os.Setenv("CONFIG_FILE", os.Args[1])
+	}
+
+	// Same sources as the monitor: .env is optional
+	_ = godotenv.Load()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	printSummary(cfg)
+	fmt.Println("\n✅ Configuration is valid")
+}
+
+// printSummary prints the effective configuration with secrets masked
+func printSummary(cfg *config.Config) {
+	section("Source")
+	if cfg.ConfigFile == "" {
+		field("Config file", "(none, environment only)")
+	} else {
+		field("Config file", cfg.ConfigFile)
+		if len(cfg.EnvOverrides) > 0 {
+			field("Overridden by env", strings.Join(cfg.EnvOverrides, ", "))
+		}
+	}
+
+	section("Aliyun")
+	field("Auth mode", cfg.AliyunAuthMode)
+	for i, acc := range cfg.AliyunAccounts {
+		label := acc.Label
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if acc.RAMRoleName != "" {
+			field("Account "+label, "RAM role "+acc.RAMRoleName)
+		} else {
+			field("Account "+label, "AccessKey "+mask(acc.AccessKeyID))
+		}
+	}
+	if len(cfg.InstanceFilterTags) > 0 {
+		field("Tag filter", formatStringMap(cfg.InstanceFilterTags))
+	}
+	if len(cfg.InstanceNamePatterns) > 0 {
+		field("Name patterns", strings.Join(cfg.InstanceNamePatterns, ", "))
+	}
+	if len(cfg.InstanceNameDenyPatterns) > 0 {
+		field("Name deny patterns", strings.Join(cfg.InstanceNameDenyPatterns, ", "))
+	}
+	if len(cfg.InstanceTypeAllowlist) > 0 {
+		field("Type allowlist", strings.Join(cfg.InstanceTypeAllowlist, ", "))
+	}
+	if len(cfg.InstanceTypeDenylist) > 0 {
+		field("Type denylist", strings.Join(cfg.InstanceTypeDenylist, ", "))
+	}
+	if len(cfg.InstanceVPCFilter) > 0 {
+		field("VPC filter", strings.Join(cfg.InstanceVPCFilter, ", "))
+	}
+	if len(cfg.InstanceSGFilter) > 0 {
+		field("Security group filter", strings.Join(cfg.InstanceSGFilter, ", "))
+	}
+	if cfg.ResourceGroupID != "" {
+		field("Resource group", cfg.ResourceGroupID)
+	}
+
+	section("GCP")
+	field("Enabled", fmt.Sprintf("%t", cfg.GCPEnabled))
+	if cfg.GCPEnabled {
+		field("Project", cfg.GCPProjectID)
+		field("Credentials", fmt.Sprintf("%d bytes", len(cfg.GCPCredentialsJSON)))
+		if len(cfg.GCPZones) > 0 {
+			field("Zones", strings.Join(cfg.GCPZones, ", "))
+		}
+		if cfg.GCPBigQueryDataset != "" {
+			field("BigQuery billing export", cfg.GCPBigQueryProject+"."+cfg.GCPBigQueryDataset+"."+cfg.GCPBigQueryTable)
+		}
+	}
+
+	section("AWS")
+	field("Enabled", fmt.Sprintf("%t", cfg.AWSEnabled))
+	if cfg.AWSEnabled {
+		field("Access key", mask(cfg.AWSAccessKeyID))
+		field("Regions", strings.Join(cfg.AWSRegions, ", "))
+	}
+
+	section("Telegram")
+	field("Enabled", fmt.Sprintf("%t", cfg.TelegramEnabled))
+	if cfg.TelegramEnabled {
+		field("Bot token", mask(cfg.TelegramBotToken))
+		field("Chat IDs", strings.Join(cfg.TelegramChatIDs, ", "))
+		field("Parse mode", string(cfg.TelegramParseMode))
+		field("Language", cfg.BotLanguage)
+		if cfg.TelegramWebhookURL != "" {
+			field("Webhook", fmt.Sprintf("%s (port %d)", cfg.TelegramWebhookURL, cfg.TelegramWebhookPort))
+		} else {
+			field("Updates", "long polling")
+		}
+	}
+
+	section("Discord")
+	if cfg.DiscordWebhookURL != "" {
+		field("Webhook", mask(cfg.DiscordWebhookURL))
+	} else {
+		field("Webhook", "(disabled)")
+	}
+
+	section("Slack")
+	if cfg.SlackWebhookURL != "" {
+		field("Webhook", mask(cfg.SlackWebhookURL))
+		field("Username", cfg.SlackUsername)
+		field("Icon", cfg.SlackIconEmoji)
+	} else {
+		field("Webhook", "(disabled)")
+	}
+
+	section("DingTalk")
+	if cfg.DingTalkWebhookURL != "" {
+		field("Webhook", mask(cfg.DingTalkWebhookURL))
+		if cfg.DingTalkSecret != "" {
+			field("Secret", mask(cfg.DingTalkSecret))
+		} else {
+			field("Secret", "(not set, unsigned)")
+		}
+	} else {
+		field("Webhook", "(disabled)")
+	}
+
+	section("WeChat Work")
+	if cfg.WeChatWorkKey != "" {
+		field("Bot key", mask(cfg.WeChatWorkKey))
+	} else {
+		field("Bot key", "(disabled)")
+	}
+
+	section("Monitoring")
+	field("Check interval", fmt.Sprintf("%ds", cfg.CheckInterval))
+	if cfg.DryRun {
+		field("Dry run", "enabled, no instances or bandwidth packages will be modified")
+	}
+	field("Retry", fmt.Sprintf("%d times, %ds base, %ds max", cfg.RetryCount, cfg.RetryInterval, cfg.MaxRetryInterval))
+	field("Shutdown timeout", fmt.Sprintf("%ds", cfg.ShutdownTimeout))
+	field("Notify cooldown", fmt.Sprintf("%ds", cfg.NotifyCooldown))
+	if cfg.NotifyBatchThreshold > 0 {
+		field("Reclaim batching", fmt.Sprintf("merge >%d within %ds", cfg.NotifyBatchThreshold, cfg.NotifyBatchWindow))
+	}
+	if len(cfg.InstanceOverrides) > 0 {
+		ids := make([]string, 0, len(cfg.InstanceOverrides))
+		for id := range cfg.InstanceOverrides {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			o := cfg.InstanceOverrides[id]
+			field("Override "+id, fmt.Sprintf("check=%ds retry=%d interval=%ds ssh=%d tls_skip_verify=%t", o.CheckInterval, o.RetryCount, o.RetryInterval, o.SSHPort, o.TLSSkipVerify))
+		}
+	}
+	if cfg.InstancePriorityParallel {
+		field("Start order", "parallel")
+	} else if len(cfg.InstancePriority) > 0 {
+		ids := make([]string, 0, len(cfg.InstancePriority))
+		for id := range cfg.InstancePriority {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return cfg.InstancePriority[ids[i]] < cfg.InstancePriority[ids[j]] ||
+				cfg.InstancePriority[ids[i]] == cfg.InstancePriority[ids[j]] && ids[i] < ids[j]
+		})
+		order := make([]string, len(ids))
+		for i, id := range ids {
+			order[i] = fmt.Sprintf("%s=%d", id, cfg.InstancePriority[id])
+		}
+		field("Start order", strings.Join(order, ", ")+fmt.Sprintf(", others=%d", config.DefaultInstancePriority))
+	}
+	for i, w := range cfg.MaintenanceWindows {
+		targets := "all instances"
+		if len(w.InstanceIDs) > 0 {
+			targets = strings.Join(w.InstanceIDs, ", ")
+		}
+		if len(w.Regions) > 0 {
+			targets += " in " + strings.Join(w.Regions, ", ")
+		}
+		field(fmt.Sprintf("Maintenance #%d", i+1), fmt.Sprintf("%s for %dm, %s", w.Cron, w.DurationMinutes, targets))
+	}
+	if cfg.RapidReclaimCount > 0 {
+		field("Rapid reclaim", fmt.Sprintf("pause %ds after >%d reclaims in %ds", cfg.RapidReclaimPause, cfg.RapidReclaimCount, cfg.RapidReclaimWindow))
+	}
+	if cfg.MetadataPollEnabled {
+		script := "(no script)"
+		if cfg.SpotTerminationScript != "" {
+			script = "script " + cfg.SpotTerminationScript
+		}
+		field("Reclaim notice poll", fmt.Sprintf("every %ds, %s", cfg.MetadataPollInterval, script))
+	}
+	if cfg.ReclaimWarnMinutes > 0 {
+		field("Scheduled event warn", fmt.Sprintf("%d minutes ahead", cfg.ReclaimWarnMinutes))
+	} else {
+		field("Scheduled event warn", "(disabled)")
+	}
+	if cfg.WebhookSecret != "" {
+		hook := "(no hook)"
+		if cfg.PreStopHook != "" {
+			hook = "hook " + cfg.PreStopHook
+		}
+		tls := ""
+		if cfg.WebhookTLSCert != "" {
+			tls = ", TLS"
+		}
+		field("EventBridge events", fmt.Sprintf("%s/aliyun/event%s, %s", cfg.WebhookAddr, tls, hook))
+	}
+	field("ECS API rate", fmt.Sprintf("%g requests/s per account", cfg.ECSAPIRPS))
+	if cfg.CircuitBreakerThreshold > 0 {
+		field("Circuit breaker", fmt.Sprintf("open after %d failures for %ds", cfg.CircuitBreakerThreshold, cfg.CircuitBreakerTimeout))
+	}
+	field("Health check", fmt.Sprintf("%t (timeout %ds)", cfg.HealthCheckEnabled, cfg.HealthCheckTimeout))
+	if cfg.HealthCheckSSH {
+		field("SSH check", fmt.Sprintf("every %ds for up to %ds", cfg.HealthCheckInterval, cfg.HealthCheckTimeout))
+	}
+	if cfg.MinUptimeSeconds > 0 {
+		field("Min uptime", fmt.Sprintf("%ds", cfg.MinUptimeSeconds))
+	}
+	if len(cfg.InstanceHealthURLs) > 0 {
+		field("HTTP check", formatStringMap(cfg.InstanceHealthURLs))
+	}
+	field("CloudMonitor alerts", fmt.Sprintf("CPU %g%%, memory %g%%, disk %g%% (0 = off)",
+		cfg.CloudMonitorAlertCPU, cfg.CloudMonitorAlertMemory, cfg.CloudMonitorAlertDisk))
+
+	section("Traffic")
+	field("Shutdown", fmt.Sprintf("%t (every %ds)", cfg.TrafficShutdownEnabled, cfg.TrafficCheckInterval))
+	if cfg.TrafficShutdownEnabled {
+		field("Restart after reset", fmt.Sprintf("%ds after the monthly reset", cfg.PostResetRestartDelay))
+	}
+	scopes := make([]string, 0, len(cfg.TrafficLimits))
+	for scope := range cfg.TrafficLimits {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		field("Limit "+scope, fmt.Sprintf("%.0f GB", cfg.TrafficLimits[scope]))
+	}
+	field("Warn percents", fmt.Sprintf("%v", cfg.TrafficWarnPercents))
+	field("CBWP auto-unbind", fmt.Sprintf("%t", cfg.CBWPAutoUnbindOnShutdown))
+	field("Snapshot before stop", fmt.Sprintf("%t", cfg.AutoSnapshotBeforeStop))
+	if len(cfg.AutoBindBWP) > 0 {
+		field("CBWP auto-bind", formatStringMap(cfg.AutoBindBWP))
+	}
+	if cfg.EIPQuotaWarnPercent > 0 {
+		field("EIP quota warn", fmt.Sprintf("%.0f%%", cfg.EIPQuotaWarnPercent*100))
+	} else {
+		field("EIP quota warn", "(disabled)")
+	}
+	if cfg.BandwidthWarnPercent > 0 {
+		field("Bandwidth warn", fmt.Sprintf("%.0f%% of package capacity (every %ds)", cfg.BandwidthWarnPercent*100, cfg.BandwidthCheckInterval))
+	} else {
+		field("Bandwidth warn", "(disabled)")
+	}
+	field("EIP bandwidth", fmt.Sprintf("%d Mbps", cfg.DefaultEIPBandwidth))
+
+	if len(cfg.InstanceWebhooks) > 0 {
+		section("Webhooks")
+		field("Post-start", formatStringMap(cfg.InstanceWebhooks))
+	}
+
+	if len(cfg.ExpectedSecurityGroups) > 0 {
+		section("Security groups")
+		ids := make([]string, 0, len(cfg.ExpectedSecurityGroups))
+		for id := range cfg.ExpectedSecurityGroups {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			field(id, strings.Join(cfg.ExpectedSecurityGroups[id], ", "))
+		}
+	}
+
+	section("Billing")
+	if cfg.BillingReportSchedule != "" {
+		field("Report schedule", cfg.BillingReportSchedule)
+	} else {
+		field("Report schedule", "(disabled)")
+	}
+	if cfg.BillingWeeklySchedule != "" {
+		field("Weekly schedule", cfg.BillingWeeklySchedule)
+	} else {
+		field("Weekly schedule", "(disabled)")
+	}
+	field("Cache TTL", fmt.Sprintf("%ds", cfg.BillingCacheTTL))
+	field("Anomaly multiplier", fmt.Sprintf("%.1f", cfg.CostAnomalyMultiplier))
+	if cfg.MonthlyBudgetCNY > 0 {
+		field("Monthly budget", fmt.Sprintf("¥%.2f (warn at %.0f%%)", cfg.MonthlyBudgetCNY, cfg.MonthlyBudgetWarnPercent*100))
+	}
+
+	section("Runtime")
+	if cfg.StateFile != "" {
+		field("State file", cfg.StateFile)
+	} else if cfg.DBPath != "" {
+		field("State file", "(database)")
+	} else {
+		field("State file", "(in-memory)")
+	}
+	if cfg.DBPath != "" {
+		field("Database", cfg.DBPath)
+	} else {
+		field("Database", "(disabled)")
+	}
+	field("Timezone", cfg.Location.String())
+	field("Health addr", cfg.HealthAddr)
+	if cfg.MetricsEnabled {
+		field("Metrics addr", cfg.MetricsAddr)
+	}
+	if cfg.GRPCEnabled {
+		tls := "plaintext"
+		if cfg.GRPCTLSCert != "" {
+			tls = "TLS"
+		}
+		auth := "no token"
+		if cfg.GRPCToken != "" {
+			auth = "token required"
+		}
+		field("gRPC addr", fmt.Sprintf("%s (%s, %s)", cfg.GRPCAddr, tls, auth))
+	}
+	if cfg.OTLPEndpoint != "" {
+		field("OTLP endpoint", cfg.OTLPEndpoint)
+	}
+	field("Log level", cfg.LogLevel)
+	field("Log format", cfg.LogFormat)
+	if cfg.LogFile != "" {
+		field("Log file", cfg.LogFile)
+		field("Log rotation", fmt.Sprintf("%d MB, %d backups, %d days", cfg.LogMaxSizeMB, cfg.LogMaxBackups, cfg.LogMaxAgeDays))
+	}
+}
+
+func section(name string) {
+	fmt.Printf("\n[%s]\n", name)
+}
+
+func field(name, value string) {
+	fmt.Printf("  %-24s %s\n", name+":", value)
+}
+
+// mask hides all but the first and last 4 characters of a secret
+func mask(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", len(s)-8) + s[len(s)-4:]
+}
+
+func formatStringMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+":"+m[k])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,171 @@
+// Command grpc-client is an example client of the monitor's gRPC API (GRPC_ENABLED=true).
+//
+// Usage: grpc-client [flags] instances|billing|traffic|check
+//
+//	grpc-client -addr localhost:9091 -token $GRPC_TOKEN instances
+//	grpc-client -tls billing -hours 24
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/api/spotmonitorpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9091", "gRPC server address")
+	token := flag.String("token", os.Getenv("GRPC_TOKEN"), "bearer token (default $GRPC_TOKEN)")
+	useTLS := flag.Bool("tls", false, "connect with TLS")
+	caFile := flag.String("ca", "", "CA certificate to verify the server with (default system roots)")
+	timeout := flag.Duration("timeout", 5*time.Minute, "call timeout")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: grpc-client [flags] instances|billing [-hours N]|traffic|check")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	creds := insecure.NewCredentials()
+	if *useTLS {
+		if *caFile != "" {
+			var err error
+			if creds, err = credentials.NewClientTLSFromFile(*caFile, ""); err != nil {
+				fatal(err)
+			}
+		} else {
+			creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		}
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		fatal(err)
+	}
+	defer conn.Close()
+	client := spotmonitorpb.NewSpotMonitorClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "instances":
+		err = printInstances(ctx, client, w)
+	case "billing":
+		fs := flag.NewFlagSet("billing", flag.ExitOnError)
+		hours := fs.Int("hours", 0, "look-back window in hours, 0 = current month")
+		_ = fs.Parse(args)
+		err = printBilling(ctx, client, w, int32(*hours))
+	case "traffic":
+		err = printTraffic(ctx, client, w)
+	case "check":
+		err = triggerCheck(ctx, client)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		w.Flush()
+		fatal(err)
+	}
+}
+
+func printInstances(ctx context.Context, client spotmonitorpb.SpotMonitorClient, w io.Writer) error {
+	stream, err := client.GetInstances(ctx, &spotmonitorpb.GetInstancesRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "PROVIDER\tACCOUNT\tID\tNAME\tREGION\tTYPE\tSTATUS\tPUBLIC IP\tNOTE")
+	for {
+		inst, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		note := ""
+		switch {
+		case inst.GetManuallyStopped():
+			note = "manually stopped"
+		case inst.GetTrafficShutdown():
+			note = "traffic shutdown"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", inst.GetProvider(), inst.GetAccountLabel(),
+			inst.GetInstanceId(), inst.GetInstanceName(), inst.GetRegion(), inst.GetInstanceType(),
+			inst.GetStatus(), inst.GetPublicIp(), note)
+	}
+}
+
+func printBilling(ctx context.Context, client spotmonitorpb.SpotMonitorClient, w io.Writer, hours int32) error {
+	resp, err := client.GetBillingSummary(ctx, &spotmonitorpb.GetBillingSummaryRequest{Hours: hours})
+	if err != nil {
+		return err
+	}
+	for _, acc := range resp.GetAccounts() {
+		fmt.Fprintf(w, "[%s] %s: ¥%.2f (estimate ¥%.2f/month, saved ¥%.2f)\n", acc.GetAccountLabel(),
+			acc.GetBillingCycle(), acc.GetTotalAmount(), acc.GetMonthlyEstimate(), acc.GetTotalSavings())
+		fmt.Fprintln(w, "ID\tNAME\tSPEC\tHOURS\tCOST")
+		for _, inst := range acc.GetInstances() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.1f\t¥%.2f\n", inst.GetInstanceId(), inst.GetInstanceName(),
+				inst.GetInstanceSpec(), inst.GetRunningHours(), inst.GetTotalAmount())
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func printTraffic(ctx context.Context, client spotmonitorpb.SpotMonitorClient, w io.Writer) error {
+	resp, err := client.GetTrafficSummary(ctx, &spotmonitorpb.GetTrafficSummaryRequest{})
+	if err != nil {
+		return err
+	}
+	for _, acc := range resp.GetAccounts() {
+		fmt.Fprintf(w, "[%s] %s: %.2f GB\n", acc.GetAccountLabel(), acc.GetBillingCycle(), acc.GetTotalGb())
+		fmt.Fprintln(w, "SCOPE\tUSED\tLIMIT\tSHUTDOWN")
+		for _, scope := range acc.GetScopes() {
+			limit := "-"
+			if scope.GetLimitGb() > 0 {
+				limit = fmt.Sprintf("%.0f GB", scope.GetLimitGb())
+			}
+			fmt.Fprintf(w, "%s\t%.2f GB\t%s\t%t\n", scope.GetScope(), scope.GetUsedGb(), limit, scope.GetShutdown())
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func triggerCheck(ctx context.Context, client spotmonitorpb.SpotMonitorClient) error {
+	resp, err := client.TriggerCheck(ctx, &spotmonitorpb.TriggerCheckRequest{})
+	if err != nil {
+		return err
+	}
+	took := resp.GetFinishedAt().AsTime().Sub(resp.GetStartedAt().AsTime())
+	fmt.Printf("Check cycle finished in %s\n", took.Round(time.Millisecond))
+	return nil
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "grpc-client: %v\n", err)
+	os.Exit(1)
+}
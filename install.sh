@@ -201,8 +201,10 @@ Type=simple
 User=root
 WorkingDirectory=$INSTALL_DIR
 ExecStart=$INSTALL_DIR/aliyun-spot-manager
+ExecReload=/bin/kill -HUP \$MAINPID
 Restart=always
 RestartSec=10
+TimeoutStopSec=150
 EnvironmentFile=$INSTALL_DIR/.env
 
 [Install]
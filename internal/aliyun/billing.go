@@ -1,12 +1,21 @@
 package aliyun
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/bssopenapi"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
 // BillingItem represents a billing item for an instance
@@ -33,6 +42,18 @@ type InstanceBillingSummary struct {
 	TotalAmount  float64
 	RunningHours float64 // 运行小时数
 	HourlyCost   float64 // 平均每小时费用
+
+	OnDemandHourly float64 // 同规格按量付费每小时价格 (0 = 未知)
+	Savings        float64 // 相比按量付费节省的费用: 按量价格 × 运行小时数 - 实际费用
+}
+
+// DiscountPercent returns the savings as a percentage of the on-demand cost
+func (s InstanceBillingSummary) DiscountPercent() float64 {
+	onDemandCost := s.OnDemandHourly * s.RunningHours
+	if onDemandCost <= 0 {
+		return 0
+	}
+	return s.Savings / onDemandCost * 100
 }
 
 // BillingSummary represents the billing summary for the current month
@@ -47,26 +68,202 @@ type BillingSummary struct {
 	TotalAmount         float64
 	MonthlyEstimate     float64 // 月度估算
 	EstimateMethod      string  // 估算方法说明
+	WindowHours         int     // 回溯小时数 (0 = 本月)
+	TotalSavings        float64 // 相比按量付费节省的费用合计
+
+	// Rolling average and month-end projection (filled in for scheduled digests)
+	RollingDays         int     // 滚动平均天数 (0 = 不显示)
+	RollingDailyAverage float64 // 近 N 日日均费用
+	MonthEndProjection  float64 // 按日均推算的月末累计
+
+	// Daily totals of the last days, oldest first and ending today (filled in for /billing)
+	DailyTotals []float64
 }
 
+// onDemandPriceTTL is how long on-demand prices are reused; list prices change rarely
+const onDemandPriceTTL = 24 * time.Hour
+
+// ecsProductCode is the BSS product code of ECS, the only product of instance billing reports
+const ecsProductCode = "ecs"
+
+// cbwpProductCode is the BSS product code of common bandwidth packages
+const cbwpProductCode = "cbwp"
+
+// billQueryConcurrency caps the daily bills queried at once; the BSS API is rate limited per account
+const billQueryConcurrency = 3
+
 // BillingClient wraps the Aliyun BSS client
 type BillingClient struct {
 	client *bssopenapi.Client
+
+	// On-demand prices for savings, keyed by region + "/" + instance type; pricer nil = disabled
+	pricer     *ECSClient
+	onDemand   map[string]onDemandPrice
+	onDemandMu sync.Mutex
+
+	// QueryBilling results, keyed by billingCacheKey; cacheTTL 0 = disabled
+	cacheTTL time.Duration
+	cache    map[string]billingCacheEntry
+	cacheMu  sync.RWMutex
+}
+
+type billingCacheEntry struct {
+	cycle     string
+	summary   *BillingSummary
+	fetchedAt time.Time
+}
+
+type onDemandPrice struct {
+	hourly    float64
+	fetchedAt time.Time
 }
 
 // NewBillingClient creates a new BSS client
-func NewBillingClient(accessKeyID, accessKeySecret string) (*BillingClient, error) {
+func NewBillingClient(cred Credential) (*BillingClient, error) {
 	// BSS API uses cn-hangzhou as the default region
-	client, err := bssopenapi.NewClientWithAccessKey("cn-hangzhou", accessKeyID, accessKeySecret)
+	var client *bssopenapi.Client
+	var err error
+	if cred.UsesRAMRole() {
+		client, err = bssopenapi.NewClientWithEcsRamRole("cn-hangzhou", cred.RAMRoleName)
+	} else {
+		client, err = bssopenapi.NewClientWithAccessKey("cn-hangzhou", cred.AccessKeyID, cred.AccessKeySecret)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BSS client: %w", err)
 	}
 
 	return &BillingClient{
 		client: client,
+		cache:  make(map[string]billingCacheEntry),
 	}, nil
 }
 
+// SetCacheTTL reuses QueryBilling results for ttl (BILLING_CACHE_TTL); 0 disables the cache
+func (c *BillingClient) SetCacheTTL(ttl time.Duration) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	c.cacheTTL = ttl
+}
+
+// billingCacheKey identifies a QueryBilling result by billing cycle, account and instances
+func billingCacheKey(cycle, accountLabel string, instances []InstanceInfo) string {
+	ids := make([]string, len(instances))
+	for i, inst := range instances {
+		ids[i] = inst.InstanceID + "/" + inst.InstanceName + "/" + inst.RegionID
+	}
+	sort.Strings(ids)
+	sum := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	return cycle + "|" + accountLabel + "|" + hex.EncodeToString(sum[:8])
+}
+
+// cachedBilling returns a copy of a cached QueryBilling result that is still fresh
+func (c *BillingClient) cachedBilling(key string) (*BillingSummary, bool) {
+	c.cacheMu.RLock()
+	defer c.cacheMu.RUnlock()
+	entry, ok := c.cache[key]
+	if !ok || c.cacheTTL <= 0 || time.Since(entry.fetchedAt) >= c.cacheTTL {
+		return nil, false
+	}
+	return cloneBillingSummary(entry.summary), true
+}
+
+// storeBilling caches a QueryBilling result, dropping entries of earlier billing cycles
+func (c *BillingClient) storeBilling(key string, summary *BillingSummary) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if c.cacheTTL <= 0 {
+		return
+	}
+	for k, entry := range c.cache {
+		if entry.cycle != summary.BillingCycle {
+			delete(c.cache, k)
+		}
+	}
+	c.cache[key] = billingCacheEntry{cycle: summary.BillingCycle, summary: cloneBillingSummary(summary), fetchedAt: time.Now()}
+}
+
+// cloneBillingSummary copies a summary so callers can add to it (trend, rolling average)
+// without changing the cached one
+func cloneBillingSummary(s *BillingSummary) *BillingSummary {
+	clone := *s
+	clone.Instances = make([]InstanceBillingSummary, len(s.Instances))
+	for i, inst := range s.Instances {
+		inst.Items = append([]BillingItem(nil), inst.Items...)
+		clone.Instances[i] = inst
+	}
+	clone.DailyTotals = append([]float64(nil), s.DailyTotals...)
+	return &clone
+}
+
+// ValidateBillingCredentials makes a minimal QueryInstanceBill call (one item of the current
+// billing cycle) to verify the account's credentials and BSS permission
+func (c *BillingClient) ValidateBillingCredentials() error {
+	request := bssopenapi.CreateQueryInstanceBillRequest()
+	request.Scheme = "https"
+	request.BillingCycle = time.Now().Format("2006-01")
+	request.PageSize = requests.NewInteger(1)
+	request.PageNum = requests.NewInteger(1)
+
+	if _, err := c.client.QueryInstanceBill(request); err != nil {
+		return fmt.Errorf("failed to query instance bill: %w", err)
+	}
+	return nil
+}
+
+// SetOnDemandPricer enables savings against on-demand prices, looked up via the given ECS client
+func (c *BillingClient) SetOnDemandPricer(ecsClient *ECSClient) {
+	c.onDemandMu.Lock()
+	defer c.onDemandMu.Unlock()
+	c.pricer = ecsClient
+	c.onDemand = make(map[string]onDemandPrice)
+}
+
+// onDemandHourly returns the cached on-demand hourly price of an instance type
+func (c *BillingClient) onDemandHourly(ctx context.Context, regionID, instanceType string) (float64, error) {
+	key := regionID + "/" + instanceType
+
+	c.onDemandMu.Lock()
+	entry, ok := c.onDemand[key]
+	c.onDemandMu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < onDemandPriceTTL {
+		return entry.hourly, nil
+	}
+
+	hourly, err := c.pricer.DescribeOnDemandPrice(ctx, regionID, instanceType)
+	if err != nil {
+		return 0, err
+	}
+
+	c.onDemandMu.Lock()
+	c.onDemand[key] = onDemandPrice{hourly: hourly, fetchedAt: time.Now()}
+	c.onDemandMu.Unlock()
+
+	return hourly, nil
+}
+
+// fillSavings computes each instance's savings against the on-demand price of its spec.
+// Instances whose price cannot be looked up are left without savings.
+func (c *BillingClient) fillSavings(ctx context.Context, summary *BillingSummary) {
+	if c.pricer == nil {
+		return
+	}
+
+	for i := range summary.Instances {
+		inst := &summary.Instances[i]
+		if inst.InstanceSpec == "" || inst.RunningHours <= 0 {
+			continue
+		}
+		hourly, err := c.onDemandHourly(ctx, inst.Region, inst.InstanceSpec)
+		if err != nil {
+			log.Warnf("[%s] Failed to get on-demand price of %s: %v", summary.AccountLabel, inst.InstanceSpec, err)
+			continue
+		}
+		inst.OnDemandHourly = hourly
+		inst.Savings = hourly*inst.RunningHours - inst.TotalAmount
+		summary.TotalSavings += inst.Savings
+	}
+}
+
 // InstanceInfo contains basic instance information for billing display
 type InstanceInfo struct {
 	InstanceID   string
@@ -74,10 +271,25 @@ type InstanceInfo struct {
 	RegionID     string
 }
 
-// QueryBilling queries billing for the specified instances for the current month
+// QueryBilling queries billing for the specified instances for the current month, reusing
+// the result of an identical query within BILLING_CACHE_TTL; see QueryBillingForce
+func (c *BillingClient) QueryBilling(ctx context.Context, instances []InstanceInfo, accountLabel string) (*BillingSummary, error) {
+	key := billingCacheKey(time.Now().Format("2006-01"), accountLabel, instances)
+	if summary, ok := c.cachedBilling(key); ok {
+		log.Debugf("[%s] Using cached billing for %d instances", accountLabel, len(instances))
+		return summary, nil
+	}
+	return c.QueryBillingForce(ctx, instances, accountLabel)
+}
+
+// QueryBillingForce queries billing for the specified instances for the current month,
+// bypassing the cache, and caches the result
 // Note: Aliyun API returns monthly cumulative data, so we query the current month's data
 // and calculate monthly estimate based on actual running time (ServicePeriod in seconds)
-func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel string) (*BillingSummary, error) {
+func (c *BillingClient) QueryBillingForce(ctx context.Context, instances []InstanceInfo, accountLabel string) (*BillingSummary, error) {
+	ctx, span := tracer.Start(ctx, "billing.QueryBilling")
+	defer span.End()
+
 	now := time.Now()
 	// Start of current month
 	startTime := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
@@ -85,41 +297,160 @@ func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel stri
 	log.Debugf("[%s] Querying billing for %d instances, current month %s",
 		accountLabel, len(instances), now.Format("2006-01"))
 
-	// Create instance ID to info map for quick lookup
-	instanceMap := make(map[string]InstanceInfo)
-	for _, inst := range instances {
-		instanceMap[inst.InstanceID] = inst
-	}
-
 	// Query current month's billing cycle
 	cycle := now.Format("2006-01")
 
-	// Group billing items by instance
-	instanceBillings := make(map[string]*InstanceBillingSummary)
-	
-	// Track running seconds per instance (to avoid duplicate counting)
-	// Each instance has multiple billing items with the same ServicePeriod
-	instanceRunningSeconds := make(map[string]float64)
-
 	log.Debugf("[%s] Querying billing cycle: %s", accountLabel, cycle)
 
-	// Query instance bill
-	request := bssopenapi.CreateQueryInstanceBillRequest()
-	request.Scheme = "https"
-	request.BillingCycle = cycle
-	request.ProductCode = "ecs"
-	request.IsBillingItem = requests.NewBoolean(true)
-	request.PageSize = requests.NewInteger(300)
-	request.PageNum = requests.NewInteger(1)
+	items, err := c.queryInstanceBillItems(ctx, cycle, "", ecsProductCode)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("[%s] Got %d billing items from API for cycle %s", accountLabel, len(items), cycle)
 
-	response, err := c.client.QueryInstanceBill(request)
+	// Calculate elapsed days this month
+	result := c.buildBillingSummary(items, instances, accountLabel, startTime, now, now.Day(), false)
+	result.BillingCycle = cycle
+	c.fillSavings(ctx, result)
+
+	log.Infof("[%s] Found billing for %d instances, total: %.4f, running hours: %.2f, monthly estimate: %.2f",
+		accountLabel, len(result.Instances), result.TotalAmount, result.TotalRunningHours, result.MonthlyEstimate)
+
+	c.storeBilling(billingCacheKey(cycle, accountLabel, instances), result)
+	return result, nil
+}
+
+// QueryBillingByHours queries billing for the specified instances over the last N hours
+// Note: daily bills are the finest granularity offered by the BSS API, so the window
+// is widened to whole calendar days (the day containing now-N hours up to today)
+func (c *BillingClient) QueryBillingByHours(ctx context.Context, instances []InstanceInfo, hours int) (*BillingSummary, error) {
+	if hours <= 0 {
+		return nil, fmt.Errorf("hours must be positive, got %d", hours)
+	}
+
+	ctx, span := tracer.Start(ctx, "billing.QueryBillingByHours", trace.WithAttributes(attribute.Int("hours", hours)))
+	defer span.End()
+
+	now := time.Now()
+	windowStart := now.Add(-time.Duration(hours) * time.Hour)
+	startDay := time.Date(windowStart.Year(), windowStart.Month(), windowStart.Day(), 0, 0, 0, 0, now.Location())
+
+	log.Debugf("Querying billing for %d instances, last %d hours (daily bills since %s)",
+		len(instances), hours, startDay.Format("2006-01-02"))
+
+	var days []time.Time
+	for day := startDay; !day.After(now); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	items, err := c.queryDailyBillItems(ctx, days)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query instance bill for cycle %s: %w", cycle, err)
+		return nil, err
 	}
 
-	log.Debugf("[%s] Got %d billing items from API for cycle %s", accountLabel, len(response.Data.Items.Item), cycle)
+	result := c.buildBillingSummary(items, instances, "", startDay, now, len(days), true)
+	result.BillingCycle = fmt.Sprintf("近 %d 小时", hours)
+	result.WindowHours = hours
+	c.fillSavings(ctx, result)
+
+	log.Infof("Found billing for %d instances over last %d hours, total: %.4f, running hours: %.2f",
+		len(result.Instances), hours, result.TotalAmount, result.TotalRunningHours)
 
-	for _, item := range response.Data.Items.Item {
+	return result, nil
+}
+
+// queryDailyBillItems fetches the daily ECS billing items of several days, up to
+// billQueryConcurrency days at once. Items are returned in day order.
+func (c *BillingClient) queryDailyBillItems(ctx context.Context, days []time.Time) ([]bssopenapi.Item, error) {
+	results := make([][]bssopenapi.Item, len(days))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(billQueryConcurrency)
+	for i, day := range days {
+		g.Go(func() error {
+			items, err := c.queryInstanceBillItems(ctx, day.Format("2006-01"), day.Format("2006-01-02"), ecsProductCode)
+			results[i] = items
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var items []bssopenapi.Item
+	for _, dayItems := range results {
+		items = append(items, dayItems...)
+	}
+	return items, nil
+}
+
+// queryInstanceBillItems fetches all billing items of productCode in a billing cycle, all
+// products if productCode is empty. If billingDate (YYYY-MM-DD) is set, daily bills for that
+// date are returned instead of the monthly cumulative bill. Cancelling ctx stops before the next page.
+func (c *BillingClient) queryInstanceBillItems(ctx context.Context, cycle, billingDate, productCode string) (items []bssopenapi.Item, err error) {
+	attrs := []attribute.KeyValue{attribute.String("billing_cycle", cycle), attribute.String("product_code", productCode)}
+	if billingDate != "" {
+		attrs = append(attrs, attribute.String("billing_date", billingDate))
+	}
+	ctx, span := tracer.Start(ctx, "bss.QueryInstanceBill",
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer func() { endSpan(span, err) }()
+
+	pageSize := 300
+
+	for pageNum := 1; ; pageNum++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		request := bssopenapi.CreateQueryInstanceBillRequest()
+		request.Scheme = "https"
+		request.BillingCycle = cycle
+		request.ProductCode = productCode
+		request.IsBillingItem = requests.NewBoolean(true)
+		request.PageSize = requests.NewInteger(pageSize)
+		request.PageNum = requests.NewInteger(pageNum)
+		if billingDate != "" {
+			request.Granularity = "DAILY"
+			request.BillingDate = billingDate
+		}
+
+		response, err := c.client.QueryInstanceBill(request)
+		if err != nil {
+			if billingDate != "" {
+				return nil, fmt.Errorf("failed to query instance bill for date %s: %w", billingDate, err)
+			}
+			return nil, fmt.Errorf("failed to query instance bill for cycle %s: %w", cycle, err)
+		}
+
+		items = append(items, response.Data.Items.Item...)
+
+		if len(response.Data.Items.Item) < pageSize || len(items) >= response.Data.TotalCount {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// buildBillingSummary groups billing items by instance and calculates running time and estimates.
+// elapsedDays is the number of days covered by the items, used for the fallback estimate.
+// When daily is true, items of the same billing item across dates are merged into one line.
+func (c *BillingClient) buildBillingSummary(items []bssopenapi.Item, instances []InstanceInfo, accountLabel string, startTime, endTime time.Time, elapsedDays int, daily bool) *BillingSummary {
+	// Create instance ID to info map for quick lookup
+	instanceMap := make(map[string]InstanceInfo)
+	for _, inst := range instances {
+		instanceMap[inst.InstanceID] = inst
+	}
+
+	// Group billing items by instance
+	instanceBillings := make(map[string]*InstanceBillingSummary)
+
+	// Track running seconds per instance and billing date (to avoid duplicate counting)
+	// Each instance has multiple billing items with the same ServicePeriod
+	periodRunningSeconds := make(map[string]float64)
+	periodInstance := make(map[string]string)
+
+	for _, item := range items {
 		// Skip if not in our instance list
 		instInfo, exists := instanceMap[item.InstanceID]
 		if !exists {
@@ -127,8 +458,8 @@ func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel stri
 		}
 
 		// Debug log to see actual API response fields
-		log.Debugf("[%s] Billing item: InstanceID=%s, InstanceSpec=%s, BillingItem=%s, ServicePeriod=%s, PretaxAmount=%.4f",
-			accountLabel, item.InstanceID, item.InstanceSpec, item.BillingItem, item.ServicePeriod, item.PretaxAmount)
+		log.Debugf("[%s] Billing item: InstanceID=%s, InstanceSpec=%s, BillingItem=%s, BillingDate=%s, ServicePeriod=%s, PretaxAmount=%.4f",
+			accountLabel, item.InstanceID, item.InstanceSpec, item.BillingItem, item.BillingDate, item.ServicePeriod, item.PretaxAmount)
 
 		summary, exists := instanceBillings[item.InstanceID]
 		if !exists {
@@ -150,14 +481,19 @@ func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel stri
 		}
 
 		// Parse ServicePeriod for running time calculation
-		// Only count once per instance (avoid duplicate counting from multiple billing items)
+		// Only count once per instance and billing date (avoid duplicate counting from multiple billing items)
 		// Note: Only count instances with ServicePeriodUnit "秒" (seconds) for spot instances
 		// Instances with "天" (days) are typically prepaid/subscription instances
 		if item.ServicePeriod != "" && item.ServicePeriodUnit == "秒" {
 			if seconds, err := parseServicePeriod(item.ServicePeriod, item.ServicePeriodUnit); err == nil {
+				key := item.InstanceID
+				if daily {
+					key += "|" + item.BillingDate
+				}
 				// Only update if this is a larger value (in case different billing items have different periods)
-				if seconds > instanceRunningSeconds[item.InstanceID] {
-					instanceRunningSeconds[item.InstanceID] = seconds
+				if seconds > periodRunningSeconds[key] {
+					periodRunningSeconds[key] = seconds
+					periodInstance[key] = item.InstanceID
 				}
 			}
 		}
@@ -165,37 +501,48 @@ func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel stri
 		// Format billing item name with InstanceSpec for compute resources
 		billingItemName := formatBillingItemName(item.BillingItem, item.InstanceSpec)
 
-		billingItem := BillingItem{
-			InstanceID:      item.InstanceID,
-			InstanceName:    instInfo.InstanceName,
-			Region:          instInfo.RegionID,
-			ProductCode:     item.ProductCode,
-			ProductDetail:   item.ProductDetail,
-			BillingItemName: billingItemName,
-			InstanceSpec:    item.InstanceSpec,
-			PretaxAmount:    item.PretaxAmount,
-			Currency:        item.Currency,
+		// Merge daily items of the same billing item into one line
+		merged := false
+		if daily {
+			for i := range summary.Items {
+				if summary.Items[i].BillingItemName == billingItemName {
+					summary.Items[i].PretaxAmount += item.PretaxAmount
+					merged = true
+					break
+				}
+			}
+		}
+		if !merged {
+			summary.Items = append(summary.Items, BillingItem{
+				InstanceID:      item.InstanceID,
+				InstanceName:    instInfo.InstanceName,
+				Region:          instInfo.RegionID,
+				ProductCode:     item.ProductCode,
+				ProductDetail:   item.ProductDetail,
+				BillingItemName: billingItemName,
+				InstanceSpec:    item.InstanceSpec,
+				PretaxAmount:    item.PretaxAmount,
+				Currency:        item.Currency,
+			})
 		}
 
-		summary.Items = append(summary.Items, billingItem)
 		summary.TotalAmount += item.PretaxAmount
 	}
 
-	// Calculate total running seconds from per-instance data (deduplicated)
+	// Sum running seconds per instance across billing dates
+	instanceRunningSeconds := make(map[string]float64)
 	var totalRunningSeconds float64
-	for _, seconds := range instanceRunningSeconds {
+	for key, seconds := range periodRunningSeconds {
+		instanceRunningSeconds[periodInstance[key]] += seconds
 		totalRunningSeconds += seconds
 	}
-	
-	// Calculate elapsed days this month
-	elapsedDays := now.Day()
+
 	totalRunningHours := totalRunningSeconds / 3600
 
 	// Build final summary
 	result := &BillingSummary{
 		StartTime:         startTime,
-		EndTime:           now,
-		BillingCycle:      cycle,
+		EndTime:           endTime,
 		AccountLabel:      accountLabel,
 		ElapsedDays:       elapsedDays,
 		TotalRunningHours: totalRunningHours,
@@ -223,13 +570,13 @@ func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel stri
 			totalHourlyCost += inst.HourlyCost
 		}
 	}
-	
+
 	if totalHourlyCost > 0 {
 		// Sum of all instance hourly costs × 720 hours
 		result.MonthlyEstimate = totalHourlyCost * 30 * 24
 		result.EstimateMethod = fmt.Sprintf("按每小时费用总和: ¥%.4f/小时 × 720小时", totalHourlyCost)
 	} else if result.TotalAmount > 0 {
-		// Fallback: use elapsed days this month
+		// Fallback: use elapsed days
 		if elapsedDays > 0 {
 			dailyRate := result.TotalAmount / float64(elapsedDays)
 			result.MonthlyEstimate = dailyRate * 30
@@ -237,16 +584,226 @@ func (c *BillingClient) QueryBilling(instances []InstanceInfo, accountLabel stri
 		}
 	}
 
-	log.Infof("[%s] Found billing for %d instances, total: %.4f, running hours: %.2f, monthly estimate: %.2f",
-		accountLabel, len(result.Instances), result.TotalAmount, totalRunningHours, result.MonthlyEstimate)
+	return result
+}
+
+// QueryRollingDailyAverage returns the average daily spend over the last days complete days
+func (c *BillingClient) QueryRollingDailyAverage(ctx context.Context, instances []InstanceInfo, days int) (float64, error) {
+	if days <= 0 {
+		return 0, fmt.Errorf("days must be positive, got %d", days)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startDay := today.AddDate(0, 0, -days)
+
+	ctx, span := tracer.Start(ctx, "billing.QueryRollingDailyAverage", trace.WithAttributes(attribute.Int("days", days)))
+	defer span.End()
+
+	var dayList []time.Time
+	for day := startDay; day.Before(today); day = day.AddDate(0, 0, 1) {
+		dayList = append(dayList, day)
+	}
+	items, err := c.queryDailyBillItems(ctx, dayList)
+	if err != nil {
+		return 0, err
+	}
+
+	summary := c.buildBillingSummary(items, instances, "", startDay, today, days, true)
+	return summary.TotalAmount / float64(days), nil
+}
+
+// QueryDailyCosts returns the cost of each instance on a single day (from the daily bill).
+// Instances without charges that day are reported as 0.
+func (c *BillingClient) QueryDailyCosts(ctx context.Context, instances []InstanceInfo, day time.Time) (map[string]float64, error) {
+	items, err := c.queryInstanceBillItems(ctx, day.Format("2006-01"), day.Format("2006-01-02"), ecsProductCode)
+	if err != nil {
+		return nil, err
+	}
+
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	summary := c.buildBillingSummary(items, instances, "", dayStart, dayStart.AddDate(0, 0, 1), 1, true)
+
+	costs := make(map[string]float64, len(instances))
+	for _, inst := range instances {
+		costs[inst.InstanceID] = 0
+	}
+	for _, inst := range summary.Instances {
+		costs[inst.InstanceID] = inst.TotalAmount
+	}
+	return costs, nil
+}
+
+// QueryDailyTotals returns the total daily cost of the specified instances over the last days
+// days, oldest first; the last value is today so far
+func (c *BillingClient) QueryDailyTotals(ctx context.Context, instances []InstanceInfo, days int) ([]float64, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive, got %d", days)
+	}
+
+	ctx, span := tracer.Start(ctx, "billing.QueryDailyTotals", trace.WithAttributes(attribute.Int("days", days)))
+	defer span.End()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayList := make([]time.Time, days)
+	dayIndex := make(map[string]int, days)
+	for i := range dayList {
+		dayList[i] = today.AddDate(0, 0, i-days+1)
+		dayIndex[dayList[i].Format("2006-01-02")] = i
+	}
+	items, err := c.queryDailyBillItems(ctx, dayList)
+	if err != nil {
+		return nil, err
+	}
+
+	monitored := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		monitored[inst.InstanceID] = true
+	}
+	totals := make([]float64, days)
+	for _, item := range items {
+		i, ok := dayIndex[item.BillingDate]
+		if ok && monitored[item.InstanceID] {
+			totals[i] += item.PretaxAmount
+		}
+	}
+	return totals, nil
+}
+
+// QueryBillingForDays queries billing for the specified instances over days whole calendar
+// days starting at startDay, using the daily bills. Unlike QueryBillingByHours the window
+// does not have to end today, so it can cover a past billing cycle.
+func (c *BillingClient) QueryBillingForDays(ctx context.Context, instances []InstanceInfo, startDay time.Time, days int) (*BillingSummary, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive, got %d", days)
+	}
+
+	ctx, span := tracer.Start(ctx, "billing.QueryBillingForDays", trace.WithAttributes(
+		attribute.String("start_day", startDay.Format("2006-01-02")), attribute.Int("days", days)))
+	defer span.End()
+
+	startDay = time.Date(startDay.Year(), startDay.Month(), startDay.Day(), 0, 0, 0, 0, startDay.Location())
+	endDay := startDay.AddDate(0, 0, days)
+
+	var dayList []time.Time
+	for day := startDay; day.Before(endDay); day = day.AddDate(0, 0, 1) {
+		dayList = append(dayList, day)
+	}
+	items, err := c.queryDailyBillItems(ctx, dayList)
+	if err != nil {
+		return nil, err
+	}
 
+	result := c.buildBillingSummary(items, instances, "", startDay, endDay, days, true)
+	result.BillingCycle = startDay.Format("2006-01")
 	return result, nil
 }
 
-// QueryBillingByHours is deprecated, use QueryBilling instead
-// Kept for backward compatibility
-func (c *BillingClient) QueryBillingByHours(instances []InstanceInfo, hours int) (*BillingSummary, error) {
-	return c.QueryBilling(instances, "")
+// QueryCurrentMonthBilling queries the daily bills from the 1st of the current month through
+// today, so the billing cycle queried exactly matches the current calendar month
+func (c *BillingClient) QueryCurrentMonthBilling(ctx context.Context, instances []InstanceInfo, accountLabel string) (*BillingSummary, error) {
+	now := time.Now()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	result, err := c.QueryBillingForDays(ctx, instances, startOfMonth, now.Day())
+	if err != nil {
+		return nil, err
+	}
+	result.AccountLabel = accountLabel
+	for i := range result.Instances {
+		result.Instances[i].AccountLabel = accountLabel
+	}
+	result.EndTime = now
+	return result, nil
+}
+
+// QueryBandwidthPackageCosts returns the spend on each common bandwidth package this month,
+// keyed by bandwidth package ID
+func (c *BillingClient) QueryBandwidthPackageCosts(ctx context.Context) (map[string]float64, error) {
+	items, err := c.queryInstanceBillItems(ctx, time.Now().Format("2006-01"), "", cbwpProductCode)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make(map[string]float64)
+	for _, item := range items {
+		costs[item.InstanceID] += item.PretaxAmount
+	}
+	return costs, nil
+}
+
+// AllProductBillingSummary is the spend of an account on all products (ECS, OSS, SLB, RDS, ...)
+type AllProductBillingSummary struct {
+	Cycles       []string           // 账单周期 (YYYY-MM)
+	AccountLabel string             // 账号标签
+	ByProduct    map[string]float64 // 产品名称 -> 应付金额
+	Total        float64
+}
+
+// ProductCost is the spend on one product
+type ProductCost struct {
+	Product string
+	Amount  float64
+}
+
+// SortedProducts returns the products ordered by cost, highest first
+func (s *AllProductBillingSummary) SortedProducts() []ProductCost {
+	products := make([]ProductCost, 0, len(s.ByProduct))
+	for product, amount := range s.ByProduct {
+		products = append(products, ProductCost{Product: product, Amount: amount})
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Amount != products[j].Amount {
+			return products[i].Amount > products[j].Amount
+		}
+		return products[i].Product < products[j].Product
+	})
+	return products
+}
+
+// QueryAllProductBilling queries the spend on all products in the given billing cycles (YYYY-MM),
+// unlike the other queries which only cover ECS. Products are keyed by their display name.
+func (c *BillingClient) QueryAllProductBilling(ctx context.Context, cycles []string) (*AllProductBillingSummary, error) {
+	if len(cycles) == 0 {
+		return nil, fmt.Errorf("no billing cycle given")
+	}
+
+	ctx, span := tracer.Start(ctx, "billing.QueryAllProductBilling",
+		trace.WithAttributes(attribute.StringSlice("billing_cycles", cycles)))
+	defer span.End()
+
+	results := make([][]bssopenapi.Item, len(cycles))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(billQueryConcurrency)
+	for i, cycle := range cycles {
+		g.Go(func() error {
+			items, err := c.queryInstanceBillItems(gctx, cycle, "", "")
+			results[i] = items
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	summary := &AllProductBillingSummary{
+		Cycles:    cycles,
+		ByProduct: make(map[string]float64),
+	}
+	for _, items := range results {
+		for _, item := range items {
+			product := item.ProductName
+			if product == "" {
+				product = item.ProductCode
+			}
+			summary.ByProduct[product] += item.PretaxAmount
+			summary.Total += item.PretaxAmount
+		}
+	}
+
+	log.Infof("Found billing for %d products in %v, total: %.4f", len(summary.ByProduct), cycles, summary.Total)
+
+	return summary, nil
 }
 
 // parseServicePeriod parses ServicePeriod string and converts to seconds based on unit
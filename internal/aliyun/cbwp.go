@@ -1,18 +1,22 @@
 package aliyun
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cms"
 	log "github.com/sirupsen/logrus"
 )
 
 // CBWPClient wraps Aliyun VPC API calls for Common Bandwidth Package operations
 type CBWPClient struct {
-	accessKeyID     string
-	accessKeySecret string
+	cred   Credential
+	dryRun bool // log bandwidth package changes instead of sending them
 }
 
 // BandwidthPackage represents a common bandwidth package
@@ -22,6 +26,7 @@ type BandwidthPackage struct {
 	Bandwidth          string
 	RegionID           string
 	Status             string
+	MemberIPs          []string // IP addresses of the EIPs in the package
 }
 
 // EIPInfo represents an Elastic IP address
@@ -35,13 +40,19 @@ type EIPInfo struct {
 }
 
 // NewCBWPClient creates a new CBWP client
-func NewCBWPClient(accessKeyID, accessKeySecret string) *CBWPClient {
+func NewCBWPClient(cred Credential) *CBWPClient {
 	return &CBWPClient{
-		accessKeyID:     accessKeyID,
-		accessKeySecret: accessKeySecret,
+		cred: cred,
 	}
 }
 
+// SetDryRun makes AddCommonBandwidthPackageIp, RemoveCommonBandwidthPackageIp and the EIP
+// allocation, association and release calls log their intent and return without calling
+// the API. Read operations are unaffected.
+func (c *CBWPClient) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
 // newVPCRequest creates a CommonRequest for VPC API
 func (c *CBWPClient) newVPCRequest(regionID, apiName string) *requests.CommonRequest {
 	request := requests.NewCommonRequest()
@@ -56,31 +67,91 @@ func (c *CBWPClient) newVPCRequest(regionID, apiName string) *requests.CommonReq
 
 // newClient creates an SDK client for the specified region
 func (c *CBWPClient) newClient(regionID string) (*sdk.Client, error) {
-	client, err := sdk.NewClientWithAccessKey(regionID, c.accessKeyID, c.accessKeySecret)
+	var client *sdk.Client
+	var err error
+	if c.cred.UsesRAMRole() {
+		client, err = sdk.NewClientWithEcsRamRole(regionID, c.cred.RAMRoleName)
+	} else {
+		client, err = sdk.NewClientWithAccessKey(regionID, c.cred.AccessKeyID, c.cred.AccessKeySecret)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SDK client for region %s: %w", regionID, err)
 	}
 	return client, nil
 }
 
-// DescribeEipAddresses queries EIP addresses associated with an instance
+// newCMSClient creates a CloudMonitor client for the specified region
+func (c *CBWPClient) newCMSClient(regionID string) (*cms.Client, error) {
+	var client *cms.Client
+	var err error
+	if c.cred.UsesRAMRole() {
+		client, err = cms.NewClientWithEcsRamRole(regionID, c.cred.RAMRoleName)
+	} else {
+		client, err = cms.NewClientWithAccessKey(regionID, c.cred.AccessKeyID, c.cred.AccessKeySecret)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CloudMonitor client for region %s: %w", regionID, err)
+	}
+	return client, nil
+}
+
+// DescribeEipAddresses queries EIP addresses associated with an instance.
+// An empty instanceID returns the first 50 EIPs of the region; see DescribeAllEipAddresses.
 func (c *CBWPClient) DescribeEipAddresses(regionID, instanceID string) ([]*EIPInfo, error) {
-	client, err := c.newClient(regionID)
+	eips, _, err := c.describeEipAddresses(regionID, instanceID, 1, 50)
 	if err != nil {
 		return nil, err
 	}
 
+	if instanceID != "" {
+		log.Debugf("Found %d EIPs for instance %s in region %s", len(eips), instanceID, regionID)
+	}
+	return eips, nil
+}
+
+// DescribeAllEipAddresses queries all EIP addresses in a region, following pagination
+func (c *CBWPClient) DescribeAllEipAddresses(regionID string) ([]*EIPInfo, error) {
+	const pageSize = 100
+
+	var all []*EIPInfo
+	for page := 1; ; page++ {
+		eips, total, err := c.describeEipAddresses(regionID, "", page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, eips...)
+		if len(eips) < pageSize || len(all) >= total {
+			break
+		}
+	}
+
+	log.Debugf("Found %d EIPs in region %s", len(all), regionID)
+	return all, nil
+}
+
+// describeEipAddresses queries a page of EIP addresses, filtered by associated instance
+// unless instanceID is empty, and returns the total number of matching EIPs
+func (c *CBWPClient) describeEipAddresses(regionID, instanceID string, pageNumber, pageSize int) ([]*EIPInfo, int, error) {
+	client, err := c.newClient(regionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	request := c.newVPCRequest(regionID, "DescribeEipAddresses")
-	request.QueryParams["AssociatedInstanceType"] = "EcsInstance"
-	request.QueryParams["AssociatedInstanceId"] = instanceID
-	request.QueryParams["PageSize"] = "50"
+	if instanceID != "" {
+		request.QueryParams["AssociatedInstanceType"] = "EcsInstance"
+		request.QueryParams["AssociatedInstanceId"] = instanceID
+	}
+	request.QueryParams["PageNumber"] = strconv.Itoa(pageNumber)
+	request.QueryParams["PageSize"] = strconv.Itoa(pageSize)
 
 	resp, err := client.ProcessCommonRequest(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe EIP addresses: %w", err)
+		return nil, 0, fmt.Errorf("failed to describe EIP addresses: %w", err)
 	}
 
 	var result struct {
+		TotalCount   int `json:"TotalCount"`
 		EipAddresses struct {
 			EipAddress []struct {
 				AllocationId       string `json:"AllocationId"`
@@ -93,7 +164,7 @@ func (c *CBWPClient) DescribeEipAddresses(regionID, instanceID string) ([]*EIPIn
 	}
 
 	if err := json.Unmarshal(resp.GetHttpContentBytes(), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse EIP response: %w", err)
+		return nil, 0, fmt.Errorf("failed to parse EIP response: %w", err)
 	}
 
 	var eips []*EIPInfo
@@ -108,8 +179,7 @@ func (c *CBWPClient) DescribeEipAddresses(regionID, instanceID string) ([]*EIPIn
 		})
 	}
 
-	log.Debugf("Found %d EIPs for instance %s in region %s", len(eips), instanceID, regionID)
-	return eips, nil
+	return eips, result.TotalCount, nil
 }
 
 // DescribeCommonBandwidthPackages queries common bandwidth packages in a region
@@ -135,6 +205,11 @@ func (c *CBWPClient) DescribeCommonBandwidthPackages(regionID string) ([]*Bandwi
 				Bandwidth          string `json:"Bandwidth"`
 				RegionId           string `json:"RegionId"`
 				Status             string `json:"Status"`
+				PublicIpAddresses  struct {
+					PublicIpAddresse []struct {
+						IpAddress string `json:"IpAddress"`
+					} `json:"PublicIpAddresse"`
+				} `json:"PublicIpAddresses"`
 			} `json:"CommonBandwidthPackage"`
 		} `json:"CommonBandwidthPackages"`
 	}
@@ -145,12 +220,17 @@ func (c *CBWPClient) DescribeCommonBandwidthPackages(regionID string) ([]*Bandwi
 
 	var packages []*BandwidthPackage
 	for _, pkg := range result.CommonBandwidthPackages.CommonBandwidthPackage {
+		var members []string
+		for _, ip := range pkg.PublicIpAddresses.PublicIpAddresse {
+			members = append(members, ip.IpAddress)
+		}
 		packages = append(packages, &BandwidthPackage{
 			BandwidthPackageID: pkg.BandwidthPackageId,
 			Name:               pkg.Name,
 			Bandwidth:          pkg.Bandwidth,
 			RegionID:           pkg.RegionId,
 			Status:             pkg.Status,
+			MemberIPs:          members,
 		})
 	}
 
@@ -160,6 +240,11 @@ func (c *CBWPClient) DescribeCommonBandwidthPackages(regionID string) ([]*Bandwi
 
 // AddCommonBandwidthPackageIp adds an EIP to a common bandwidth package
 func (c *CBWPClient) AddCommonBandwidthPackageIp(regionID, bandwidthPackageID, eipID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would add EIP %s to bandwidth package %s", eipID, bandwidthPackageID)
+		return nil
+	}
+
 	client, err := c.newClient(regionID)
 	if err != nil {
 		return err
@@ -180,6 +265,11 @@ func (c *CBWPClient) AddCommonBandwidthPackageIp(regionID, bandwidthPackageID, e
 
 // RemoveCommonBandwidthPackageIp removes an EIP from a common bandwidth package
 func (c *CBWPClient) RemoveCommonBandwidthPackageIp(regionID, bandwidthPackageID, eipID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would remove EIP %s from bandwidth package %s", eipID, bandwidthPackageID)
+		return nil
+	}
+
 	client, err := c.newClient(regionID)
 	if err != nil {
 		return err
@@ -197,3 +287,240 @@ func (c *CBWPClient) RemoveCommonBandwidthPackageIp(regionID, bandwidthPackageID
 	log.Infof("Successfully removed EIP %s from bandwidth package %s", eipID, bandwidthPackageID)
 	return nil
 }
+
+// AllocateEipAddress allocates a pay-as-you-go EIP billed by traffic with the given peak
+// bandwidth in Mbps. In dry-run mode the returned EIP has no allocation ID or address.
+func (c *CBWPClient) AllocateEipAddress(regionID string, bandwidth int) (*EIPInfo, error) {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would allocate an EIP with %d Mbps in region %s", bandwidth, regionID)
+		return &EIPInfo{RegionID: regionID, Status: "Available"}, nil
+	}
+
+	client, err := c.newClient(regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := c.newVPCRequest(regionID, "AllocateEipAddress")
+	request.QueryParams["Bandwidth"] = strconv.Itoa(bandwidth)
+	request.QueryParams["InternetChargeType"] = "PayByTraffic"
+	request.QueryParams["InstanceChargeType"] = "PostPaid"
+
+	resp, err := client.ProcessCommonRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate EIP in region %s: %w", regionID, err)
+	}
+
+	var result struct {
+		AllocationId string `json:"AllocationId"`
+		EipAddress   string `json:"EipAddress"`
+	}
+	if err := json.Unmarshal(resp.GetHttpContentBytes(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse allocate EIP response: %w", err)
+	}
+
+	log.Infof("Successfully allocated EIP %s (%s) in region %s", result.AllocationId, result.EipAddress, regionID)
+	return &EIPInfo{
+		AllocationID: result.AllocationId,
+		IPAddress:    result.EipAddress,
+		RegionID:     regionID,
+		Status:       "Available",
+	}, nil
+}
+
+// AssociateEipAddress binds an EIP to an ECS instance. The association completes
+// asynchronously; the EIP status changes from Associating to InUse.
+func (c *CBWPClient) AssociateEipAddress(regionID, allocationID, instanceID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would associate EIP %s with instance %s", allocationID, instanceID)
+		return nil
+	}
+
+	client, err := c.newClient(regionID)
+	if err != nil {
+		return err
+	}
+
+	request := c.newVPCRequest(regionID, "AssociateEipAddress")
+	request.QueryParams["AllocationId"] = allocationID
+	request.QueryParams["InstanceId"] = instanceID
+	request.QueryParams["InstanceType"] = "EcsInstance"
+
+	_, err = client.ProcessCommonRequest(request)
+	if err != nil {
+		return fmt.Errorf("failed to associate EIP %s with instance %s: %w", allocationID, instanceID, err)
+	}
+
+	log.Infof("Successfully associated EIP %s with instance %s", allocationID, instanceID)
+	return nil
+}
+
+// UnassociateEipAddress unbinds an EIP from an ECS instance
+func (c *CBWPClient) UnassociateEipAddress(regionID, allocationID, instanceID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would unassociate EIP %s from instance %s", allocationID, instanceID)
+		return nil
+	}
+
+	client, err := c.newClient(regionID)
+	if err != nil {
+		return err
+	}
+
+	request := c.newVPCRequest(regionID, "UnassociateEipAddress")
+	request.QueryParams["AllocationId"] = allocationID
+	request.QueryParams["InstanceId"] = instanceID
+	request.QueryParams["InstanceType"] = "EcsInstance"
+
+	_, err = client.ProcessCommonRequest(request)
+	if err != nil {
+		return fmt.Errorf("failed to unassociate EIP %s from instance %s: %w", allocationID, instanceID, err)
+	}
+
+	log.Infof("Successfully unassociated EIP %s from instance %s", allocationID, instanceID)
+	return nil
+}
+
+// ReleaseEipAddress releases an EIP that is not associated with any instance
+func (c *CBWPClient) ReleaseEipAddress(regionID, allocationID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would release EIP %s", allocationID)
+		return nil
+	}
+
+	client, err := c.newClient(regionID)
+	if err != nil {
+		return err
+	}
+
+	request := c.newVPCRequest(regionID, "ReleaseEipAddress")
+	request.QueryParams["AllocationId"] = allocationID
+
+	_, err = client.ProcessCommonRequest(request)
+	if err != nil {
+		return fmt.Errorf("failed to release EIP %s: %w", allocationID, err)
+	}
+
+	log.Infof("Successfully released EIP %s", allocationID)
+	return nil
+}
+
+const (
+	// bandwidthPackageMetricNamespace is the CloudMonitor namespace for common bandwidth packages
+	bandwidthPackageMetricNamespace = "acs_bandwidth_package"
+	// BandwidthMetricWindow is the time range GetBandwidthPackageMetrics looks back over
+	BandwidthMetricWindow = 10 * time.Minute
+)
+
+// GetBandwidthPackageMetrics returns the peak and average bandwidth (inbound + outbound, Mbps)
+// of a common bandwidth package over the last BandwidthMetricWindow, using the CloudMonitor
+// net_rx.rate and net_tx.rate metrics at one-minute granularity
+func (c *CBWPClient) GetBandwidthPackageMetrics(regionID, bwpID string) (peakMbps, avgMbps float64, err error) {
+	client, err := c.newCMSClient(regionID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-BandwidthMetricWindow)
+	dimensions := map[string]string{"instanceId": bwpID}
+
+	// Sum receive and transmit rates per timestamp (bit/s)
+	peakBps := make(map[int64]float64)
+	avgBps := make(map[int64]float64)
+	for _, metricName := range []string{"net_rx.rate", "net_tx.rate"} {
+		datapoints, err := listMetricDatapoints(context.Background(), client, bandwidthPackageMetricNamespace,
+			metricName, dimensions, startTime, endTime, 60)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, dp := range datapoints {
+			peakBps[dp.Timestamp] += dp.Maximum
+			avgBps[dp.Timestamp] += dp.Average
+		}
+	}
+
+	if len(avgBps) == 0 {
+		return 0, 0, nil
+	}
+	var sum float64
+	for ts, bps := range avgBps {
+		sum += bps
+		if peakBps[ts] > peakMbps {
+			peakMbps = peakBps[ts]
+		}
+	}
+	peakMbps /= 1e6
+	avgMbps = sum / float64(len(avgBps)) / 1e6
+
+	log.Debugf("Bandwidth package %s in region %s: peak=%.1f Mbps, avg=%.1f Mbps", bwpID, regionID, peakMbps, avgMbps)
+	return peakMbps, avgMbps, nil
+}
+
+// defaultEIPQuota is Aliyun's default number of EIPs per region, used when the
+// quota cannot be read from Quota Center
+const defaultEIPQuota = 20
+
+// eipQuotaActionCode identifies the per-region EIP quota in Quota Center
+const eipQuotaActionCode = "vpc_quota_eip_num"
+
+// DescribeEipQuota returns the number of EIPs allocated in a region and the region's EIP quota.
+// The quota is read from Quota Center (quotas:ListProductQuotas); if that fails or the quota
+// is not listed, defaultEIPQuota is assumed.
+func (c *CBWPClient) DescribeEipQuota(regionID string) (used, limit int, err error) {
+	// Only the total count is needed, so a single-item page suffices
+	_, used, err = c.describeEipAddresses(regionID, "", 1, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	limit, err = c.queryEipQuota(regionID)
+	if err != nil {
+		log.Debugf("Failed to query EIP quota in region %s, assuming %d: %v", regionID, defaultEIPQuota, err)
+		limit = defaultEIPQuota
+	}
+
+	log.Debugf("EIP usage in region %s: %d/%d", regionID, used, limit)
+	return used, limit, nil
+}
+
+// queryEipQuota reads the EIP quota of a region from Quota Center
+func (c *CBWPClient) queryEipQuota(regionID string) (int, error) {
+	client, err := c.newClient(regionID)
+	if err != nil {
+		return 0, err
+	}
+
+	request := requests.NewCommonRequest()
+	request.Method = "POST"
+	request.Scheme = "https"
+	request.Domain = "quotas.aliyuncs.com"
+	request.Version = "2020-05-10"
+	request.ApiName = "ListProductQuotas"
+	request.QueryParams["ProductCode"] = "vpc"
+	request.QueryParams["QuotaActionCode"] = eipQuotaActionCode
+	request.QueryParams["Dimensions.1.Key"] = "regionId"
+	request.QueryParams["Dimensions.1.Value"] = regionID
+
+	resp, err := client.ProcessCommonRequest(request)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list VPC quotas: %w", err)
+	}
+
+	var result struct {
+		Quotas []struct {
+			QuotaActionCode string  `json:"QuotaActionCode"`
+			TotalQuota      float64 `json:"TotalQuota"`
+		} `json:"Quotas"`
+	}
+	if err := json.Unmarshal(resp.GetHttpContentBytes(), &result); err != nil {
+		return 0, fmt.Errorf("failed to parse quota response: %w", err)
+	}
+
+	for _, q := range result.Quotas {
+		if q.QuotaActionCode == eipQuotaActionCode && q.TotalQuota > 0 {
+			return int(q.TotalQuota), nil
+		}
+	}
+	return 0, fmt.Errorf("quota %s not found", eipQuotaActionCode)
+}
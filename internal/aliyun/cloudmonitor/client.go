@@ -0,0 +1,68 @@
+package cloudmonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+)
+
+// ECS metrics of the acs_ecs_dashboard namespace. Memory and disk usage are reported by the
+// CloudMonitor agent installed on the instance.
+const (
+	MetricCPU    = "CPUUtilization"
+	MetricMemory = "memory_usedutilization"
+	MetricDisk   = "diskusage_utilization"
+)
+
+// metricWindow is the window GetInstanceMetrics averages over
+const metricWindow = 5 * time.Minute
+
+// ErrNoData is returned when CloudMonitor has no datapoints for a metric in the window,
+// e.g. because the CloudMonitor agent is not installed
+var ErrNoData = errors.New("no metric data")
+
+// CloudMonitorClient queries ECS instance metrics from Aliyun CloudMonitor. It shares the
+// CloudMonitor clients of the account's ECSClient.
+type CloudMonitorClient struct {
+	ecs *aliyun.ECSClient
+}
+
+// NewCloudMonitorClient creates a new CloudMonitor client
+func NewCloudMonitorClient(ecs *aliyun.ECSClient) *CloudMonitorClient {
+	return &CloudMonitorClient{ecs: ecs}
+}
+
+// GetInstanceMetrics returns the average of an ECS metric (a percentage for the Metric* constants)
+// over the last 5 minutes. For per-disk metrics the highest disk average is returned.
+func (c *CloudMonitorClient) GetInstanceMetrics(ctx context.Context, regionID, instanceID string, metric string) (float64, error) {
+	endTime := time.Now()
+	datapoints, err := c.ecs.ListMetricDatapoints(ctx, regionID, aliyun.ECSMetricNamespace, metric,
+		map[string]string{"instanceId": instanceID}, endTime.Add(-metricWindow), endTime, 60)
+	if err != nil {
+		return 0, err
+	}
+	if len(datapoints) == 0 {
+		return 0, fmt.Errorf("%s of instance %s: %w", metric, instanceID, ErrNoData)
+	}
+
+	return highestAverage(datapoints), nil
+}
+
+// highestAverage averages the datapoints of each device and returns the highest average
+func highestAverage(datapoints []aliyun.MetricDatapoint) float64 {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, dp := range datapoints {
+		sums[dp.Device] += dp.Average
+		counts[dp.Device]++
+	}
+
+	var highest float64
+	for device, sum := range sums {
+		highest = max(highest, sum/float64(counts[device]))
+	}
+	return highest
+}
@@ -0,0 +1,114 @@
+package aliyun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cms"
+)
+
+// ECSMetricNamespace is the CloudMonitor namespace for ECS instance metrics
+const ECSMetricNamespace = "acs_ecs_dashboard"
+
+// MetricDatapoint represents a single CloudMonitor datapoint
+type MetricDatapoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Average   float64 `json:"Average"`
+	Maximum   float64 `json:"Maximum"`
+	Minimum   float64 `json:"Minimum"`
+	Device    string  `json:"device"` // set for per-disk metrics
+}
+
+// getCMSClient gets or creates a CloudMonitor client for the specified region
+func (c *ECSClient) getCMSClient(regionID string) (*cms.Client, error) {
+	c.clientsMu.RLock()
+	if client, ok := c.cmsClients[regionID]; ok {
+		c.clientsMu.RUnlock()
+		return client, nil
+	}
+	c.clientsMu.RUnlock()
+
+	c.clientsMu.Lock()
+	defer c.clientsMu.Unlock()
+
+	if client, ok := c.cmsClients[regionID]; ok {
+		return client, nil
+	}
+
+	var client *cms.Client
+	var err error
+	if c.cred.UsesRAMRole() {
+		client, err = cms.NewClientWithEcsRamRole(regionID, c.cred.RAMRoleName)
+	} else {
+		client, err = cms.NewClientWithAccessKey(regionID, c.cred.AccessKeyID, c.cred.AccessKeySecret)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CloudMonitor client for region %s: %w", regionID, err)
+	}
+
+	c.cmsClients[regionID] = client
+	return client, nil
+}
+
+// ListMetricDatapoints queries all datapoints of a CloudMonitor metric in the given time range
+// with the account's CloudMonitor client of the region. period is the aggregation period in
+// seconds. CloudMonitor calls are not rate limited.
+func (c *ECSClient) ListMetricDatapoints(ctx context.Context, regionID, namespace, metricName string, dimensions map[string]string, startTime, endTime time.Time, period int) ([]MetricDatapoint, error) {
+	client, err := c.getCMSClient(regionID)
+	if err != nil {
+		return nil, err
+	}
+	return listMetricDatapoints(ctx, client, namespace, metricName, dimensions, startTime, endTime, period)
+}
+
+// listMetricDatapoints pages through DescribeMetricList with the given CloudMonitor client
+func listMetricDatapoints(ctx context.Context, client *cms.Client, namespace, metricName string, dimensions map[string]string, startTime, endTime time.Time, period int) ([]MetricDatapoint, error) {
+	dims, err := json.Marshal([]map[string]string{dimensions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metric dimensions: %w", err)
+	}
+
+	var datapoints []MetricDatapoint
+	nextToken := ""
+	for {
+		request := cms.CreateDescribeMetricListRequest()
+		request.Scheme = "https"
+		request.Namespace = namespace
+		request.MetricName = metricName
+		request.Dimensions = string(dims)
+		request.Period = strconv.Itoa(period)
+		request.StartTime = strconv.FormatInt(startTime.UnixMilli(), 10)
+		request.EndTime = strconv.FormatInt(endTime.UnixMilli(), 10)
+		request.NextToken = nextToken
+
+		var response *cms.DescribeMetricListResponse
+		err := withContext(ctx, func() (err error) {
+			response, err = client.DescribeMetricList(request)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe metric %s: %w", metricName, err)
+		}
+		if !response.Success {
+			return nil, fmt.Errorf("CloudMonitor returned error for metric %s: %s %s", metricName, response.Code, response.Message)
+		}
+
+		if response.Datapoints != "" {
+			var page []MetricDatapoint
+			if err := json.Unmarshal([]byte(response.Datapoints), &page); err != nil {
+				return nil, fmt.Errorf("failed to parse datapoints for metric %s: %w", metricName, err)
+			}
+			datapoints = append(datapoints, page...)
+		}
+
+		if response.NextToken == "" {
+			break
+		}
+		nextToken = response.NextToken
+	}
+
+	return datapoints, nil
+}
@@ -0,0 +1,14 @@
+package aliyun
+
+// Credential identifies how API clients authenticate: either a static AccessKey pair
+// or a RAM role attached to the ECS instance the monitor runs on
+type Credential struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	RAMRoleName     string // non-empty = fetch STS credentials from the instance metadata service
+}
+
+// UsesRAMRole reports whether the credential is an ECS RAM instance role
+func (c Credential) UsesRAMRole() bool {
+	return c.RAMRoleName != ""
+}
@@ -1,16 +1,33 @@
 package aliyun
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cms"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/iliyian/aliyun-spot-manager/internal/ratelimit"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
+// tracer creates a span per ECS API call, exported when tracing is enabled
+var tracer = otel.Tracer("github.com/iliyian/aliyun-spot-manager/internal/aliyun")
+
 // SpotInstance represents a spot instance
 type SpotInstance struct {
 	InstanceID       string
@@ -20,24 +37,315 @@ type SpotInstance struct {
 	PublicIPAddress  string
 	PrivateIPAddress string
 	SpotStrategy     string
+	InstanceType     string
+	ZoneID           string
+	VpcID            string
+	SecurityGroups   []string // security group IDs
+	ResourceGroupID  string
 	AccountLabel     string // label of the Aliyun account that owns this instance
 }
 
 // ECSClient wraps the Aliyun ECS client
 type ECSClient struct {
-	accessKeyID     string
-	accessKeySecret string
-	clients         map[string]*ecs.Client // region -> client
-	clientsMu       sync.RWMutex
+	cred       Credential
+	clients    map[string]*ecs.Client // region -> client
+	cmsClients map[string]*cms.Client // region -> CloudMonitor client
+	clientsMu  sync.RWMutex
+	tagFilter  map[string]string // required tag key -> value, empty = no filtering
+	dryRun     bool              // log start/stop requests instead of sending them
+
+	// Instance name glob patterns (path.Match): names must match one of namePatterns, unless
+	// empty, and none of nameDenyPatterns
+	namePatterns     []string
+	nameDenyPatterns []string
+
+	// Instance type prefixes (e.g. ecs.c6): types must start with one of typeAllowlist, unless
+	// empty, and with none of typeDenylist
+	typeAllowlist []string
+	typeDenylist  []string
+
+	// VPC IDs instances must belong to, empty = all VPCs
+	vpcFilter []string
+
+	// Security group IDs instances must have at least one of, empty = all
+	sgFilter []string
+
+	// Resource group instances must belong to, empty = all
+	resourceGroupID string
+
+	// Per-region circuit breakers for read calls, created on first use; threshold 0 = disabled
+	breakers         map[string]*ratelimit.CircuitBreaker
+	breakersMu       sync.Mutex
+	breakerThreshold int
+	breakerTimeout   time.Duration
+	onBreakerChange  func(regionID string, from, to ratelimit.State)
+
+	// Rate limit of ECS API requests, shared by all regions since Aliyun's QPS limit is per account
+	limiter *rate.Limiter
 }
 
+const (
+	// DefaultECSAPIRPS is the default rate of ECS API requests per second (ECS_API_RPS)
+	DefaultECSAPIRPS = 10
+	// throttlingRetryDelay is the wait before the single retry of a throttled request
+	throttlingRetryDelay = time.Second
+)
+
+// errRateLimitDeadline is returned when the context deadline would pass before the rate limiter
+// allows the request
+var errRateLimitDeadline = errors.New("ECS API rate limit wait would exceed the context deadline")
+
 // NewECSClient creates a new ECS client
-func NewECSClient(accessKeyID, accessKeySecret string) *ECSClient {
+func NewECSClient(cred Credential) *ECSClient {
 	return &ECSClient{
-		accessKeyID:     accessKeyID,
-		accessKeySecret: accessKeySecret,
-		clients:         make(map[string]*ecs.Client),
+		cred:       cred,
+		clients:    make(map[string]*ecs.Client),
+		cmsClients: make(map[string]*cms.Client),
+		limiter:    rate.NewLimiter(DefaultECSAPIRPS, DefaultECSAPIRPS),
+	}
+}
+
+// SetRateLimit limits ECS API requests of this client to rps requests per second across
+// all regions, allowing bursts of up to rps requests
+func (c *ECSClient) SetRateLimit(rps float64) {
+	c.limiter.SetLimit(rate.Limit(rps))
+	c.limiter.SetBurst(max(1, int(rps)))
+}
+
+// throttled sends an API request once the rate limiter allows it. A request rejected with a
+// throttling error is retried once after throttlingRetryDelay.
+func (c *ECSClient) throttled(ctx context.Context, request func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return errRateLimitDeadline
+		}
+		err := withContext(ctx, request)
+		if attempt > 1 || !IsThrottlingError(err) {
+			return err
+		}
+
+		log.Debugf("ECS API request throttled, retrying in %s: %v", throttlingRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(throttlingRetryDelay):
+		}
+	}
+}
+
+// withContext runs an SDK call, which takes no context itself, and returns ctx's error as soon
+// as ctx is done. An abandoned call still completes in the background, so a start or stop
+// request that was already sent may take effect after its caller gave up.
+func withContext(ctx context.Context, call func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- call() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetTagFilter restricts instance discovery to instances carrying all of the given tags.
+// An empty map disables tag filtering.
+func (c *ECSClient) SetTagFilter(tags map[string]string) {
+	c.tagFilter = tags
+}
+
+// SetNameFilter restricts instance discovery to instances whose name matches one of allow
+// (all names when empty) and none of deny. DescribeInstances cannot match names by glob, so
+// the filter is applied to the API results.
+func (c *ECSClient) SetNameFilter(allow, deny []string) {
+	c.namePatterns = allow
+	c.nameDenyPatterns = deny
+}
+
+// nameAllowed reports whether an instance name passes the name filter
+func (c *ECSClient) nameAllowed(name string) bool {
+	for _, p := range c.nameDenyPatterns {
+		if ok, _ := path.Match(p, name); ok {
+			return false
+		}
+	}
+	if len(c.namePatterns) == 0 {
+		return true
+	}
+	for _, p := range c.namePatterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTypeFilter restricts instance discovery to instance types starting with one of allow
+// (all types when empty) and with none of deny, applied to the DescribeInstances results
+func (c *ECSClient) SetTypeFilter(allow, deny []string) {
+	c.typeAllowlist = allow
+	c.typeDenylist = deny
+}
+
+// typeAllowed reports whether an instance type passes the type filter
+func (c *ECSClient) typeAllowed(instanceType string) bool {
+	hasPrefix := func(prefix string) bool { return strings.HasPrefix(instanceType, prefix) }
+	if len(c.typeAllowlist) > 0 && !slices.ContainsFunc(c.typeAllowlist, hasPrefix) {
+		return false
+	}
+	return !slices.ContainsFunc(c.typeDenylist, hasPrefix)
+}
+
+// SetVPCFilter restricts instance discovery to instances in one of vpcIDs (all VPCs when
+// empty). A single VPC is filtered by DescribeInstances itself, several VPCs in the results.
+func (c *ECSClient) SetVPCFilter(vpcIDs []string) {
+	c.vpcFilter = vpcIDs
+}
+
+// vpcAllowed reports whether an instance in vpcID passes the VPC filter
+func (c *ECSClient) vpcAllowed(vpcID string) bool {
+	return len(c.vpcFilter) == 0 || slices.Contains(c.vpcFilter, vpcID)
+}
+
+// SetSecurityGroupFilter restricts instance discovery to instances in at least one of
+// securityGroupIDs (all instances when empty). A single security group is filtered by
+// DescribeInstances itself, several in the results.
+func (c *ECSClient) SetSecurityGroupFilter(securityGroupIDs []string) {
+	c.sgFilter = securityGroupIDs
+}
+
+// securityGroupAllowed reports whether an instance with securityGroupIDs passes the
+// security group filter
+func (c *ECSClient) securityGroupAllowed(securityGroupIDs []string) bool {
+	if len(c.sgFilter) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(securityGroupIDs, func(id string) bool { return slices.Contains(c.sgFilter, id) })
+}
+
+// SetResourceGroup restricts instance discovery to a resource group (all when empty)
+func (c *ECSClient) SetResourceGroup(resourceGroupID string) {
+	c.resourceGroupID = resourceGroupID
+}
+
+// SetCircuitBreaker enables a circuit breaker per region: after threshold consecutive
+// failed API calls the region's read calls fail fast with ratelimit.ErrCircuitOpen for
+// timeout. onChange, if not nil, is called on every state transition of a region's breaker.
+func (c *ECSClient) SetCircuitBreaker(threshold int, timeout time.Duration, onChange func(regionID string, from, to ratelimit.State)) {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+	c.breakerThreshold = threshold
+	c.breakerTimeout = timeout
+	c.onBreakerChange = onChange
+	c.breakers = make(map[string]*ratelimit.CircuitBreaker)
+}
+
+// breaker returns the circuit breaker of a region, nil if disabled
+func (c *ECSClient) breaker(regionID string) *ratelimit.CircuitBreaker {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+	if c.breakerThreshold <= 0 {
+		return nil
+	}
+	if b, ok := c.breakers[regionID]; ok {
+		return b
+	}
+
+	var onChange func(from, to ratelimit.State)
+	if c.onBreakerChange != nil {
+		notify := c.onBreakerChange
+		onChange = func(from, to ratelimit.State) { notify(regionID, from, to) }
+	}
+	b := ratelimit.NewCircuitBreaker(c.breakerThreshold, c.breakerTimeout, onChange)
+	c.breakers[regionID] = b
+	return b
+}
+
+// guard runs a rate-limited API call of a region through the region's circuit breaker.
+// A call cut short by ctx is not recorded, it says nothing about the endpoint.
+func (c *ECSClient) guard(ctx context.Context, regionID string, call func() error) error {
+	b := c.breaker(regionID)
+	if b == nil {
+		return c.throttled(ctx, call)
+	}
+	if err := b.Allow(); err != nil {
+		return fmt.Errorf("region %s: %w", regionID, err)
+	}
+
+	err := c.throttled(ctx, call)
+	if errors.Is(err, errRateLimitDeadline) || (ctx.Err() != nil && errors.Is(err, ctx.Err())) {
+		b.Release()
+		return err
+	}
+	b.Record(!isEndpointFailure(err))
+	return err
+}
+
+// isEndpointFailure reports whether err indicates a degraded endpoint (network error or
+// server-side failure) rather than a rejected request, which proves the endpoint works
+func isEndpointFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var serverErr *sdkerrors.ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.HttpStatus() >= 500
+	}
+	return true
+}
+
+// startSpan starts the span of an ECS API call such as ecs.StartInstance.
+// Empty regionID or instanceID are not recorded.
+func startSpan(ctx context.Context, name, regionID, instanceID string) trace.Span {
+	var attrs []attribute.KeyValue
+	if regionID != "" {
+		attrs = append(attrs, attribute.String("region_id", regionID))
+	}
+	if instanceID != "" {
+		attrs = append(attrs, attribute.String("instance_id", instanceID))
+	}
+	_, span := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return span
+}
+
+// endSpan records the outcome of an API call on its span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
+	span.End()
+}
+
+// SetDryRun makes StartInstance and StopInstance log their intent and return nil
+// without calling the API. Read operations are unaffected.
+func (c *ECSClient) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// describeInstancesTags converts the tag filter into DescribeInstances query parameters
+func (c *ECSClient) describeInstancesTags() *[]ecs.DescribeInstancesTag {
+	if len(c.tagFilter) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(c.tagFilter))
+	for k := range c.tagFilter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]ecs.DescribeInstancesTag, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, ecs.DescribeInstancesTag{Key: k, Value: c.tagFilter[k]})
+	}
+	return &tags
 }
 
 // getClient gets or creates an ECS client for the specified region
@@ -59,7 +367,13 @@ func (c *ECSClient) getClient(regionID string) (*ecs.Client, error) {
 		return client, nil
 	}
 
-	client, err := ecs.NewClientWithAccessKey(regionID, c.accessKeyID, c.accessKeySecret)
+	var client *ecs.Client
+	var err error
+	if c.cred.UsesRAMRole() {
+		client, err = ecs.NewClientWithEcsRamRole(regionID, c.cred.RAMRoleName)
+	} else {
+		client, err = ecs.NewClientWithAccessKey(regionID, c.cred.AccessKeyID, c.cred.AccessKeySecret)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ECS client for region %s: %w", regionID, err)
 	}
@@ -69,7 +383,10 @@ func (c *ECSClient) getClient(regionID string) (*ecs.Client, error) {
 }
 
 // GetAllRegions returns all available regions
-func (c *ECSClient) GetAllRegions() ([]string, error) {
+func (c *ECSClient) GetAllRegions(ctx context.Context) (_ []string, err error) {
+	span := startSpan(ctx, "ecs.GetAllRegions", "", "")
+	defer func() { endSpan(span, err) }()
+
 	// Use cn-hangzhou as default region to query all regions
 	client, err := c.getClient("cn-hangzhou")
 	if err != nil {
@@ -79,7 +396,11 @@ func (c *ECSClient) GetAllRegions() ([]string, error) {
 	request := ecs.CreateDescribeRegionsRequest()
 	request.Scheme = "https"
 
-	response, err := client.DescribeRegions(request)
+	var response *ecs.DescribeRegionsResponse
+	err = c.throttled(ctx, func() (err error) {
+		response, err = client.DescribeRegions(request)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe regions: %w", err)
 	}
@@ -92,8 +413,18 @@ func (c *ECSClient) GetAllRegions() ([]string, error) {
 	return regions, nil
 }
 
+// ValidateCredentials makes a harmless DescribeRegions call to verify the account's credentials.
+// Use IsCredentialError to tell rejected credentials from transient failures.
+func (c *ECSClient) ValidateCredentials(ctx context.Context) error {
+	_, err := c.GetAllRegions(ctx)
+	return err
+}
+
 // GetSpotInstances returns all spot instances in the specified region
-func (c *ECSClient) GetSpotInstances(regionID string, accountLabel string) ([]*SpotInstance, error) {
+func (c *ECSClient) GetSpotInstances(ctx context.Context, regionID string, accountLabel string) (_ []*SpotInstance, err error) {
+	span := startSpan(ctx, "ecs.GetSpotInstances", regionID, "")
+	defer func() { endSpan(span, err) }()
+
 	client, err := c.getClient(regionID)
 	if err != nil {
 		return nil, err
@@ -111,15 +442,32 @@ func (c *ECSClient) GetSpotInstances(regionID string, accountLabel string) ([]*S
 		request.PageSize = requests.NewInteger(pageSize)
 		// Filter for pay-as-you-go instances (spot instances are a type of pay-as-you-go)
 		request.InstanceChargeType = "PostPaid"
+		// Only return instances carrying all required tags (Tag.N.Key / Tag.N.Value)
+		request.Tag = c.describeInstancesTags()
+		// DescribeInstances accepts a single VPC ID; several VPCs are filtered below
+		if len(c.vpcFilter) == 1 {
+			request.VpcId = c.vpcFilter[0]
+		}
+		// Likewise for a single security group
+		if len(c.sgFilter) == 1 {
+			request.SecurityGroupId = c.sgFilter[0]
+		}
+		request.ResourceGroupId = c.resourceGroupID
 
-		response, err := client.DescribeInstances(request)
+		var response *ecs.DescribeInstancesResponse
+		err := c.guard(ctx, regionID, func() (err error) {
+			response, err = client.DescribeInstances(request)
+			return err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe instances in region %s: %w", regionID, err)
 		}
 
 		for _, inst := range response.Instances.Instance {
 			// Filter for spot instances only
-			if inst.SpotStrategy != "NoSpot" && inst.SpotStrategy != "" {
+			if inst.SpotStrategy != "NoSpot" && inst.SpotStrategy != "" &&
+				c.nameAllowed(inst.InstanceName) && c.typeAllowed(inst.InstanceType) &&
+				c.vpcAllowed(inst.VpcAttributes.VpcId) && c.securityGroupAllowed(inst.SecurityGroupIds.SecurityGroupId) {
 				var publicIP, privateIP string
 				if len(inst.PublicIpAddress.IpAddress) > 0 {
 					publicIP = inst.PublicIpAddress.IpAddress[0]
@@ -143,6 +491,11 @@ func (c *ECSClient) GetSpotInstances(regionID string, accountLabel string) ([]*S
 					PublicIPAddress:  publicIP,
 					PrivateIPAddress: privateIP,
 					SpotStrategy:     inst.SpotStrategy,
+					InstanceType:     inst.InstanceType,
+					ZoneID:           inst.ZoneId,
+					VpcID:            inst.VpcAttributes.VpcId,
+					SecurityGroups:   inst.SecurityGroupIds.SecurityGroupId,
+					ResourceGroupID:  inst.ResourceGroupId,
 					AccountLabel:     accountLabel,
 				})
 			}
@@ -159,7 +512,10 @@ func (c *ECSClient) GetSpotInstances(regionID string, accountLabel string) ([]*S
 }
 
 // GetInstanceStatus returns the current status of an instance
-func (c *ECSClient) GetInstanceStatus(regionID, instanceID string) (string, error) {
+func (c *ECSClient) GetInstanceStatus(ctx context.Context, regionID, instanceID string) (_ string, err error) {
+	span := startSpan(ctx, "ecs.GetInstanceStatus", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
 	client, err := c.getClient(regionID)
 	if err != nil {
 		return "", err
@@ -170,20 +526,27 @@ func (c *ECSClient) GetInstanceStatus(regionID, instanceID string) (string, erro
 	request.RegionId = regionID
 	request.InstanceId = &[]string{instanceID}
 
-	response, err := client.DescribeInstanceStatus(request)
+	var response *ecs.DescribeInstanceStatusResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		response, err = client.DescribeInstanceStatus(request)
+		return err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get instance status: %w", err)
 	}
 
 	if len(response.InstanceStatuses.InstanceStatus) == 0 {
-		return "", fmt.Errorf("instance %s not found", instanceID)
+		return "", fmt.Errorf("%w: %s", ErrInstanceNotFound, instanceID)
 	}
 
 	return response.InstanceStatuses.InstanceStatus[0].Status, nil
 }
 
 // GetInstance returns detailed information about an instance
-func (c *ECSClient) GetInstance(regionID, instanceID string, accountLabel string) (*SpotInstance, error) {
+func (c *ECSClient) GetInstance(ctx context.Context, regionID, instanceID string, accountLabel string) (_ *SpotInstance, err error) {
+	span := startSpan(ctx, "ecs.GetInstance", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
 	client, err := c.getClient(regionID)
 	if err != nil {
 		return nil, err
@@ -194,13 +557,17 @@ func (c *ECSClient) GetInstance(regionID, instanceID string, accountLabel string
 	request.RegionId = regionID
 	request.InstanceIds = fmt.Sprintf(`["%s"]`, instanceID)
 
-	response, err := client.DescribeInstances(request)
+	var response *ecs.DescribeInstancesResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		response, err = client.DescribeInstances(request)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get instance: %w", err)
 	}
 
 	if len(response.Instances.Instance) == 0 {
-		return nil, fmt.Errorf("instance %s not found", instanceID)
+		return nil, fmt.Errorf("%w: %s", ErrInstanceNotFound, instanceID)
 	}
 
 	inst := response.Instances.Instance[0]
@@ -226,12 +593,300 @@ func (c *ECSClient) GetInstance(regionID, instanceID string, accountLabel string
 		PublicIPAddress:  publicIP,
 		PrivateIPAddress: privateIP,
 		SpotStrategy:     inst.SpotStrategy,
+		InstanceType:     inst.InstanceType,
+		ZoneID:           inst.ZoneId,
 		AccountLabel:     accountLabel,
 	}, nil
 }
 
+// SecurityGroupInfo is a security group attached to an instance
+type SecurityGroupInfo struct {
+	ID   string
+	Name string
+}
+
+// GetInstanceSecurityGroups returns the security groups attached to an instance, in the order
+// reported by ECS. Groups whose name cannot be resolved are returned with an empty Name.
+func (c *ECSClient) GetInstanceSecurityGroups(ctx context.Context, regionID, instanceID string) (_ []SecurityGroupInfo, err error) {
+	span := startSpan(ctx, "ecs.GetInstanceSecurityGroups", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := ecs.CreateDescribeInstanceAttributeRequest()
+	request.Scheme = "https"
+	request.InstanceId = instanceID
+
+	var response *ecs.DescribeInstanceAttributeResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		response, err = client.DescribeInstanceAttribute(request)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance attribute: %w", err)
+	}
+
+	ids := response.SecurityGroupIds.SecurityGroupId
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	sgRequest := ecs.CreateDescribeSecurityGroupsRequest()
+	sgRequest.Scheme = "https"
+	sgRequest.RegionId = regionID
+	sgRequest.SecurityGroupIds = `["` + strings.Join(ids, `","`) + `"]`
+	sgRequest.PageSize = requests.NewInteger(50)
+
+	var sgResponse *ecs.DescribeSecurityGroupsResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		sgResponse, err = client.DescribeSecurityGroups(sgRequest)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security groups: %w", err)
+	}
+
+	names := make(map[string]string, len(sgResponse.SecurityGroups.SecurityGroup))
+	for _, sg := range sgResponse.SecurityGroups.SecurityGroup {
+		names[sg.SecurityGroupId] = sg.SecurityGroupName
+	}
+
+	groups := make([]SecurityGroupInfo, 0, len(ids))
+	for _, id := range ids {
+		groups = append(groups, SecurityGroupInfo{ID: id, Name: names[id]})
+	}
+	return groups, nil
+}
+
+// ReclaimEvent is a scheduled system event that will stop or release an instance
+type ReclaimEvent struct {
+	InstanceID string
+	EventTime  time.Time // NotBefore: the earliest time the event is executed
+	EventType  string    // e.g. SystemMaintenance.Reboot
+	Status     string    // Scheduled or Executing
+}
+
+// GetPendingReclaimEvents returns the scheduled system events of a region that have not been
+// executed yet, queried via DescribeInstanceHistoryEvents. Events already due are included
+// while they are still executing.
+func (c *ECSClient) GetPendingReclaimEvents(ctx context.Context, regionID string) (_ []*ReclaimEvent, err error) {
+	span := startSpan(ctx, "ecs.GetPendingReclaimEvents", regionID, "")
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	const pageSize = 100
+	var events []*ReclaimEvent
+	for page := 1; ; page++ {
+		request := ecs.CreateDescribeInstanceHistoryEventsRequest()
+		request.Scheme = "https"
+		request.RegionId = regionID
+		request.ResourceType = "instance"
+		request.InstanceEventCycleStatus = &[]string{"Scheduled", "Executing"}
+		request.PageNumber = requests.NewInteger(page)
+		request.PageSize = requests.NewInteger(pageSize)
+
+		var response *ecs.DescribeInstanceHistoryEventsResponse
+		err = c.guard(ctx, regionID, func() (err error) {
+			response, err = client.DescribeInstanceHistoryEvents(request)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance events: %w", err)
+		}
+
+		set := response.InstanceSystemEventSet.InstanceSystemEventType
+		for _, e := range set {
+			eventTime, err := time.Parse(time.RFC3339, e.NotBefore)
+			if err != nil {
+				log.Debugf("Skipping event %s of instance %s with invalid time %q", e.EventId, e.InstanceId, e.NotBefore)
+				continue
+			}
+			events = append(events, &ReclaimEvent{
+				InstanceID: e.InstanceId,
+				EventTime:  eventTime,
+				EventType:  e.EventType.Name,
+				Status:     e.EventCycleStatus.Name,
+			})
+		}
+		if len(set) < pageSize || page*pageSize >= response.TotalCount {
+			break
+		}
+	}
+
+	log.Debugf("Found %d pending system events in region %s", len(events), regionID)
+	return events, nil
+}
+
+// InstanceEvent is a system, maintenance or scheduled event of an instance
+type InstanceEvent struct {
+	EventID    string
+	InstanceID string
+	EventType  string    // e.g. SystemMaintenance.Stop, Instance:PreemptibleInstanceInterruption
+	EventTime  time.Time // NotBefore, or the publish time if the event has none
+	Status     string    // e.g. Scheduled, Executed, Canceled
+	Reason     string
+}
+
+// GetInstanceEvents returns the events of an instance published in the last days days, all
+// instances of the region if instanceID is empty, queried via DescribeInstanceHistoryEvents
+func (c *ECSClient) GetInstanceEvents(ctx context.Context, regionID, instanceID string, days int) (_ []*InstanceEvent, err error) {
+	span := startSpan(ctx, "ecs.GetInstanceEvents", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	const pageSize = 100
+	since := time.Now().AddDate(0, 0, -days).UTC().Format("2006-01-02T15:04:05Z")
+	var events []*InstanceEvent
+	for page := 1; ; page++ {
+		request := ecs.CreateDescribeInstanceHistoryEventsRequest()
+		request.Scheme = "https"
+		request.RegionId = regionID
+		request.ResourceType = "instance"
+		request.InstanceId = instanceID
+		request.EventPublishTimeStart = since
+		request.PageNumber = requests.NewInteger(page)
+		request.PageSize = requests.NewInteger(pageSize)
+
+		var response *ecs.DescribeInstanceHistoryEventsResponse
+		err = c.guard(ctx, regionID, func() (err error) {
+			response, err = client.DescribeInstanceHistoryEvents(request)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance events: %w", err)
+		}
+
+		set := response.InstanceSystemEventSet.InstanceSystemEventType
+		for _, e := range set {
+			eventTime, err := time.Parse(time.RFC3339, e.NotBefore)
+			if err != nil {
+				if eventTime, err = time.Parse(time.RFC3339, e.EventPublishTime); err != nil {
+					log.Debugf("Skipping event %s of instance %s with invalid time %q", e.EventId, e.InstanceId, e.EventPublishTime)
+					continue
+				}
+			}
+			events = append(events, &InstanceEvent{
+				EventID:    e.EventId,
+				InstanceID: e.InstanceId,
+				EventType:  e.EventType.Name,
+				EventTime:  eventTime,
+				Status:     e.EventCycleStatus.Name,
+				Reason:     e.Reason,
+			})
+		}
+		if len(set) < pageSize || page*pageSize >= response.TotalCount {
+			break
+		}
+	}
+
+	log.Debugf("Found %d events in region %s in the last %d days", len(events), regionID, days)
+	return events, nil
+}
+
+// CreateSnapshot starts a snapshot of the system disk of an instance and returns the snapshot
+// ID without waiting for it to complete; see GetSnapshotStatus
+func (c *ECSClient) CreateSnapshot(ctx context.Context, regionID, instanceID string) (snapshotID string, err error) {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would create a system disk snapshot of instance %s in region %s", instanceID, regionID)
+		return "", nil
+	}
+
+	span := startSpan(ctx, "ecs.CreateSnapshot", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return "", err
+	}
+
+	disksRequest := ecs.CreateDescribeDisksRequest()
+	disksRequest.Scheme = "https"
+	disksRequest.RegionId = regionID
+	disksRequest.InstanceId = instanceID
+	disksRequest.DiskType = "system"
+
+	var disks *ecs.DescribeDisksResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		disks, err = client.DescribeDisks(disksRequest)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe disks: %w", newECSError(err))
+	}
+	if len(disks.Disks.Disk) == 0 {
+		return "", fmt.Errorf("no system disk found for instance %s", instanceID)
+	}
+	diskID := disks.Disks.Disk[0].DiskId
+
+	request := ecs.CreateCreateSnapshotRequest()
+	request.Scheme = "https"
+	request.DiskId = diskID
+	request.SnapshotName = fmt.Sprintf("spot-monitor-%s-%s", instanceID, time.Now().UTC().Format("20060102-150405"))
+	request.Description = "Created by aliyun-spot-manager"
+
+	var response *ecs.CreateSnapshotResponse
+	err = c.throttled(ctx, func() (err error) {
+		response, err = client.CreateSnapshot(request)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot of disk %s: %w", diskID, newECSError(err))
+	}
+
+	log.Infof("Creating snapshot %s of system disk %s (instance %s)", response.SnapshotId, diskID, instanceID)
+	return response.SnapshotId, nil
+}
+
+// GetSnapshotStatus returns the status (progressing, accomplished or failed) and progress
+// (e.g. "45%") of a snapshot
+func (c *ECSClient) GetSnapshotStatus(ctx context.Context, regionID, snapshotID string) (status, progress string, err error) {
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	request := ecs.CreateDescribeSnapshotsRequest()
+	request.Scheme = "https"
+	request.RegionId = regionID
+	request.SnapshotIds = fmt.Sprintf(`["%s"]`, snapshotID)
+
+	var response *ecs.DescribeSnapshotsResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		response, err = client.DescribeSnapshots(request)
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to describe snapshot: %w", newECSError(err))
+	}
+	if len(response.Snapshots.Snapshot) == 0 {
+		return "", "", fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+
+	snapshot := response.Snapshots.Snapshot[0]
+	return snapshot.Status, snapshot.Progress, nil
+}
+
 // StartInstance starts an instance
-func (c *ECSClient) StartInstance(regionID, instanceID string) error {
+func (c *ECSClient) StartInstance(ctx context.Context, regionID, instanceID string) (err error) {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would start instance %s in region %s", instanceID, regionID)
+		return nil
+	}
+
+	span := startSpan(ctx, "ecs.StartInstance", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
 	client, err := c.getClient(regionID)
 	if err != nil {
 		return err
@@ -241,10 +896,14 @@ func (c *ECSClient) StartInstance(regionID, instanceID string) error {
 	request.Scheme = "https"
 	request.InstanceId = instanceID
 
-	_, err = client.StartInstance(request)
+	err = c.throttled(ctx, func() error {
+		_, err := client.StartInstance(request)
+		return err
+	})
 	if err != nil {
+		err = newECSError(err)
 		// Check if instance is already running or starting
-		if strings.Contains(err.Error(), "IncorrectInstanceStatus") {
+		if errors.Is(err, ErrIncorrectInstanceStatus) {
 			log.Warnf("Instance %s is not in stopped state, skipping start", instanceID)
 			return nil
 		}
@@ -256,7 +915,15 @@ func (c *ECSClient) StartInstance(regionID, instanceID string) error {
 
 // StopInstance stops an instance with the specified stopped mode
 // stoppedMode can be "StopCharging" (cost-saving) or "KeepCharging"
-func (c *ECSClient) StopInstance(regionID, instanceID, stoppedMode string) error {
+func (c *ECSClient) StopInstance(ctx context.Context, regionID, instanceID, stoppedMode string) (err error) {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would stop instance %s in region %s (%s)", instanceID, regionID, stoppedMode)
+		return nil
+	}
+
+	span := startSpan(ctx, "ecs.StopInstance", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
 	client, err := c.getClient(regionID)
 	if err != nil {
 		return err
@@ -267,10 +934,14 @@ func (c *ECSClient) StopInstance(regionID, instanceID, stoppedMode string) error
 	request.InstanceId = instanceID
 	request.StoppedMode = stoppedMode
 
-	_, err = client.StopInstance(request)
+	err = c.throttled(ctx, func() error {
+		_, err := client.StopInstance(request)
+		return err
+	})
 	if err != nil {
+		err = newECSError(err)
 		// Check if instance is already stopped
-		if strings.Contains(err.Error(), "IncorrectInstanceStatus") {
+		if errors.Is(err, ErrIncorrectInstanceStatus) {
 			log.Warnf("Instance %s is not in running state, skipping stop", instanceID)
 			return nil
 		}
@@ -280,12 +951,29 @@ func (c *ECSClient) StopInstance(regionID, instanceID, stoppedMode string) error
 	return nil
 }
 
-// DiscoverAllSpotInstances discovers all spot instances across all regions
-func (c *ECSClient) DiscoverAllSpotInstances(accountLabel string) ([]*SpotInstance, error) {
-	log.Infof("[%s] Fetching all regions...", accountLabel)
-	regions, err := c.GetAllRegions()
-	if err != nil {
-		return nil, err
+// DiscoverAllSpotInstances discovers all spot instances across all regions. It fails when ctx
+// is cancelled before every region was scanned.
+func (c *ECSClient) DiscoverAllSpotInstances(ctx context.Context, accountLabel string) ([]*SpotInstance, error) {
+	var regions []string
+	if c.resourceGroupID != "" {
+		// Only scan the regions holding ECS instances of the resource group
+		var err error
+		if regions, err = c.GetResourceGroupRegions(ctx); err != nil {
+			log.Warnf("[%s] Failed to list resource group %s, scanning all regions: %v", accountLabel, c.resourceGroupID, err)
+			regions = nil
+		} else {
+			log.Infof("[%s] Resource group %s has ECS instances in %d region(s)", accountLabel, c.resourceGroupID, len(regions))
+			if len(regions) == 0 {
+				return nil, nil
+			}
+		}
+	}
+	if regions == nil {
+		log.Infof("[%s] Fetching all regions...", accountLabel)
+		var err error
+		if regions, err = c.GetAllRegions(ctx); err != nil {
+			return nil, err
+		}
 	}
 	log.Infof("[%s] Found %d regions, scanning for spot instances...", accountLabel, len(regions))
 
@@ -300,6 +988,7 @@ func (c *ECSClient) DiscoverAllSpotInstances(accountLabel string) ([]*SpotInstan
 	startTime := time.Now()
 	scannedCount := 0
 	var scannedMu sync.Mutex
+	var tagErr error
 
 	for _, region := range regions {
 		wg.Add(1)
@@ -308,7 +997,7 @@ func (c *ECSClient) DiscoverAllSpotInstances(accountLabel string) ([]*SpotInstan
 			semaphore <- struct{}{}        // Acquire
 			defer func() { <-semaphore }() // Release
 
-			instances, err := c.GetSpotInstances(regionID, accountLabel)
+			instances, err := c.GetSpotInstances(ctx, regionID, accountLabel)
 
 			scannedMu.Lock()
 			scannedCount++
@@ -316,6 +1005,14 @@ func (c *ECSClient) DiscoverAllSpotInstances(accountLabel string) ([]*SpotInstan
 			scannedMu.Unlock()
 
 			if err != nil {
+				// A rejected tag filter fails every region alike, so surface it instead of reporting zero instances
+				if IsInvalidTagError(err) {
+					mu.Lock()
+					if tagErr == nil {
+						tagErr = err
+					}
+					mu.Unlock()
+				}
 				log.Debugf("[%s] [%d/%d] Region %s: error - %v", accountLabel, progress, len(regions), regionID, err)
 				return
 			}
@@ -332,11 +1029,221 @@ func (c *ECSClient) DiscoverAllSpotInstances(accountLabel string) ([]*SpotInstan
 	}
 
 	wg.Wait()
+	// Regions skipped because of the cancellation would otherwise look like regions without instances
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	log.Infof("[%s] Scan completed in %.1f seconds", accountLabel, time.Since(startTime).Seconds())
 
+	if tagErr != nil {
+		return nil, fmt.Errorf("tag filter rejected by API: %w", tagErr)
+	}
+
 	return allInstances, nil
 }
 
+// GetResourceGroupRegions returns the sorted regions holding ECS instances of the resource
+// group set by SetResourceGroup, using the Resource Manager ListResources API
+func (c *ECSClient) GetResourceGroupRegions(ctx context.Context) (_ []string, err error) {
+	span := startSpan(ctx, "ecs.GetResourceGroupRegions", "", "")
+	defer func() { endSpan(span, err) }()
+
+	// Resource Manager is a global service, any regional client can call it
+	client, err := c.getClient("cn-hangzhou")
+	if err != nil {
+		return nil, err
+	}
+
+	regionSet := make(map[string]bool)
+	pageSize := 100
+	for pageNumber := 1; ; pageNumber++ {
+		request := requests.NewCommonRequest()
+		request.Method = "POST"
+		request.Scheme = "https"
+		request.Domain = "resourcemanager.aliyuncs.com"
+		request.Version = "2020-03-31"
+		request.ApiName = "ListResources"
+		request.QueryParams["ResourceGroupId"] = c.resourceGroupID
+		request.QueryParams["Service"] = "ecs"
+		request.QueryParams["ResourceType"] = "instance"
+		request.QueryParams["PageNumber"] = fmt.Sprint(pageNumber)
+		request.QueryParams["PageSize"] = fmt.Sprint(pageSize)
+
+		var body []byte
+		err := c.throttled(ctx, func() error {
+			resp, err := client.ProcessCommonRequest(request)
+			if err != nil {
+				return err
+			}
+			body = resp.GetHttpContentBytes()
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources of group %s: %w", c.resourceGroupID, err)
+		}
+
+		var result struct {
+			Resources struct {
+				Resource []struct {
+					RegionId string `json:"RegionId"`
+				} `json:"Resource"`
+			} `json:"Resources"`
+			TotalCount int `json:"TotalCount"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse ListResources response: %w", err)
+		}
+		for _, r := range result.Resources.Resource {
+			if r.RegionId != "" {
+				regionSet[r.RegionId] = true
+			}
+		}
+
+		if len(result.Resources.Resource) < pageSize || pageNumber*pageSize >= result.TotalCount {
+			break
+		}
+	}
+
+	regions := make([]string, 0, len(regionSet))
+	for regionID := range regionSet {
+		regions = append(regions, regionID)
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// NetworkMetric holds aggregated statistics for a single network metric
+type NetworkMetric struct {
+	AvgBps  float64 // average of hourly averages, in bits per second
+	PeakBps float64 // highest value observed in the window, in bits per second
+}
+
+// NetworkStats represents per-instance network usage over a time window
+type NetworkStats struct {
+	InstanceID  string
+	RegionID    string
+	Hours       int
+	StartTime   time.Time
+	EndTime     time.Time
+	InternetIn  NetworkMetric
+	InternetOut NetworkMetric
+	IntranetIn  NetworkMetric
+	IntranetOut NetworkMetric
+}
+
+// GetNetworkUsageStats returns hourly average and peak bandwidth for an instance
+// over the last N hours, using CloudMonitor network rate metrics
+func (c *ECSClient) GetNetworkUsageStats(ctx context.Context, regionID, instanceID string, hours int) (_ *NetworkStats, err error) {
+	span := startSpan(ctx, "ecs.GetNetworkUsageStats", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
+	if hours <= 0 {
+		return nil, fmt.Errorf("hours must be positive, got %d", hours)
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(hours) * time.Hour)
+
+	stats := &NetworkStats{
+		InstanceID: instanceID,
+		RegionID:   regionID,
+		Hours:      hours,
+		StartTime:  startTime,
+		EndTime:    endTime,
+	}
+
+	metrics := []struct {
+		name   string
+		target *NetworkMetric
+	}{
+		{"InternetInRate", &stats.InternetIn},
+		{"InternetOutRate", &stats.InternetOut},
+		{"IntranetInRate", &stats.IntranetIn},
+		{"IntranetOutRate", &stats.IntranetOut},
+	}
+
+	dimensions := map[string]string{"instanceId": instanceID}
+	for _, metric := range metrics {
+		datapoints, err := c.ListMetricDatapoints(ctx, regionID, ECSMetricNamespace, metric.name, dimensions, startTime, endTime, 3600)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(datapoints) == 0 {
+			continue
+		}
+
+		var sum float64
+		for _, dp := range datapoints {
+			sum += dp.Average
+			if dp.Maximum > metric.target.PeakBps {
+				metric.target.PeakBps = dp.Maximum
+			}
+		}
+		metric.target.AvgBps = sum / float64(len(datapoints))
+	}
+
+	log.Debugf("Network stats for instance %s over %dh: internet in avg=%.0f bps, out avg=%.0f bps",
+		instanceID, hours, stats.InternetIn.AvgBps, stats.InternetOut.AvgBps)
+
+	return stats, nil
+}
+
+// GetDiskIOPS returns average disk read/write IOPS and throughput (MB/s) of an instance
+// over the last N minutes, using CloudMonitor disk metrics. If diskID is empty, the
+// instance-level aggregate over all disks is returned.
+func (c *ECSClient) GetDiskIOPS(ctx context.Context, regionID, instanceID, diskID string, minutes int) (readIOPS, writeIOPS, readMBps, writeMBps float64, err error) {
+	span := startSpan(ctx, "ecs.GetDiskIOPS", regionID, instanceID)
+	defer func() { endSpan(span, err) }()
+
+	if minutes <= 0 {
+		return 0, 0, 0, 0, fmt.Errorf("minutes must be positive, got %d", minutes)
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(minutes) * time.Minute)
+
+	dimensions := map[string]string{"instanceId": instanceID}
+	if diskID != "" {
+		dimensions["diskId"] = diskID
+	}
+
+	metrics := []struct {
+		name   string
+		scale  float64
+		target *float64
+	}{
+		{"DiskReadIOPS", 1, &readIOPS},
+		{"DiskWriteIOPS", 1, &writeIOPS},
+		{"DiskReadBPS", 1.0 / (1024 * 1024), &readMBps},
+		{"DiskWriteBPS", 1.0 / (1024 * 1024), &writeMBps},
+	}
+
+	found := false
+	for _, metric := range metrics {
+		datapoints, err := c.ListMetricDatapoints(ctx, regionID, ECSMetricNamespace, metric.name, dimensions, startTime, endTime, 60)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if len(datapoints) == 0 {
+			continue
+		}
+
+		found = true
+		var sum float64
+		for _, dp := range datapoints {
+			sum += dp.Average
+		}
+		*metric.target = sum / float64(len(datapoints)) * metric.scale
+	}
+
+	if !found {
+		return 0, 0, 0, 0, fmt.Errorf("no disk metrics reported for instance %s in the last %d minutes", instanceID, minutes)
+	}
+
+	return readIOPS, writeIOPS, readMBps, writeMBps, nil
+}
+
 // IsNoStockError checks if the error is a NoStock error (resource sold out)
 func IsNoStockError(err error) bool {
 	if err == nil {
@@ -346,3 +1253,53 @@ func IsNoStockError(err error) bool {
 	return strings.Contains(errMsg, "OperationDenied.NoStock") ||
 		strings.Contains(errMsg, "is sold out in the specified zone")
 }
+
+// IsThrottlingError checks if the error is a rejection by the API's rate limit
+func IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "Throttling") ||
+		strings.Contains(errMsg, "ServiceUnavailable")
+}
+
+// IsCredentialError checks if the error is caused by an invalid AccessKey or a RAM identity
+// lacking permission for the API
+func IsCredentialError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errMsg := err.Error()
+	return IsInvalidKeyError(err) ||
+		strings.Contains(errMsg, "Forbidden.RAM") ||
+		strings.Contains(errMsg, "NoPermission")
+}
+
+// IsInvalidKeyError checks if the error is caused by an unknown AccessKey ID or a wrong secret
+func IsInvalidKeyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrInvalidCredentials) {
+		return true
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "InvalidAccessKeyId") ||
+		strings.Contains(errMsg, "InvalidAccessKeySecret") ||
+		strings.Contains(errMsg, "SignatureDoesNotMatch")
+}
+
+// IsInvalidTagError checks if the error is caused by a malformed tag filter
+func IsInvalidTagError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "InvalidTagKey") ||
+		strings.Contains(errMsg, "InvalidTagValue") ||
+		strings.Contains(errMsg, "InvalidTag.")
+}
@@ -0,0 +1,70 @@
+package aliyun
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+)
+
+// Sentinel errors matched with errors.Is against the ECSError returned by ECS API calls
+var (
+	ErrInstanceNotFound        = errors.New("instance not found")
+	ErrRateLimited             = errors.New("rate limited")
+	ErrInvalidCredentials      = errors.New("invalid credentials")
+	ErrIncorrectInstanceStatus = errors.New("incorrect instance status")
+)
+
+// ECSError is an error response of the ECS API
+type ECSError struct {
+	Code      string
+	Message   string
+	RequestID string
+
+	err error // the SDK error, for errors.As
+}
+
+// Error implements error; the code comes first so the Is*Error helpers keep matching it
+func (e ECSError) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s: %s (RequestId: %s)", e.Code, e.Message, e.RequestID)
+}
+
+// Unwrap returns the SDK error
+func (e ECSError) Unwrap() error {
+	return e.err
+}
+
+// Is matches the sentinel errors by error code
+func (e ECSError) Is(target error) bool {
+	switch target {
+	case ErrInstanceNotFound:
+		return e.Code == "InvalidInstanceId.NotFound"
+	case ErrRateLimited:
+		return strings.HasPrefix(e.Code, "Throttling") || e.Code == "ServiceUnavailable"
+	case ErrInvalidCredentials:
+		return e.Code == "InvalidAccessKeyId.NotFound" || e.Code == "InvalidAccessKeyId" ||
+			e.Code == "InvalidAccessKeySecret" || e.Code == "SignatureDoesNotMatch"
+	case ErrIncorrectInstanceStatus:
+		return strings.HasPrefix(e.Code, "IncorrectInstanceStatus")
+	}
+	return false
+}
+
+// newECSError converts an SDK server error into an ECSError; other errors (network
+// failures, cancellation) are returned unchanged
+func newECSError(err error) error {
+	var serverErr *sdkerrors.ServerError
+	if !errors.As(err, &serverErr) {
+		return err
+	}
+	return fmt.Errorf("%w", ECSError{
+		Code:      serverErr.ErrorCode(),
+		Message:   serverErr.Message(),
+		RequestID: serverErr.RequestId(),
+		err:       err,
+	})
+}
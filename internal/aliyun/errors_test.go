@@ -0,0 +1,70 @@
+package aliyun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+)
+
+// serverError returns the SDK error of an ECS error response with the given code
+func serverError(httpStatus int, code string) error {
+	body := fmt.Sprintf(`{"RequestId":"req-1","Code":%q,"Message":"test message"}`, code)
+	return sdkerrors.NewServerError(httpStatus, body, "")
+}
+
+func TestECSErrorIs(t *testing.T) {
+	sentinels := []error{ErrInstanceNotFound, ErrRateLimited, ErrInvalidCredentials, ErrIncorrectInstanceStatus}
+
+	tests := []struct {
+		code   string
+		status int
+		want   error // nil = matches no sentinel
+	}{
+		{code: "InvalidInstanceId.NotFound", status: 404, want: ErrInstanceNotFound},
+		{code: "Throttling", status: 400, want: ErrRateLimited},
+		{code: "Throttling.User", status: 400, want: ErrRateLimited},
+		{code: "ServiceUnavailable", status: 503, want: ErrRateLimited},
+		{code: "InvalidAccessKeyId.NotFound", status: 404, want: ErrInvalidCredentials},
+		{code: "InvalidAccessKeyId", status: 400, want: ErrInvalidCredentials},
+		{code: "InvalidAccessKeySecret", status: 400, want: ErrInvalidCredentials},
+		{code: "SignatureDoesNotMatch", status: 400, want: ErrInvalidCredentials},
+		{code: "IncorrectInstanceStatus", status: 403, want: ErrIncorrectInstanceStatus},
+		{code: "IncorrectInstanceStatus.Initializing", status: 403, want: ErrIncorrectInstanceStatus},
+		{code: "OperationDenied.NoStock", status: 403},
+		{code: "InvalidInstanceId.Malformed", status: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := newECSError(serverError(tt.status, tt.code))
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%s, %v) = %v, want %v", tt.code, sentinel, got, !got)
+				}
+			}
+
+			var ecsErr ECSError
+			if !errors.As(err, &ecsErr) {
+				t.Fatalf("errors.As(%v, ECSError) = false", err)
+			}
+			if ecsErr.Code != tt.code || ecsErr.RequestID != "req-1" || ecsErr.Message != "test message" {
+				t.Errorf("ECSError = %+v, want code %s, request req-1 and the response message", ecsErr, tt.code)
+			}
+			var sdkErr *sdkerrors.ServerError
+			if !errors.As(err, &sdkErr) {
+				t.Errorf("errors.As(%v, *ServerError) = false, want the SDK error kept", err)
+			}
+		})
+	}
+}
+
+func TestNewECSErrorKeepsOtherErrors(t *testing.T) {
+	for _, err := range []error{context.DeadlineExceeded, errors.New("connection reset")} {
+		if got := newECSError(err); got != err {
+			t.Errorf("newECSError(%v) = %v, want the error unchanged", err, got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultEndpoint is the ECS instance metadata service, reachable only from within an instance
+const DefaultEndpoint = "http://100.100.100.200/latest/meta-data"
+
+// MetadataPoller watches the instance metadata service of the instance it runs on for a
+// spot reclaim notice, which Aliyun publishes about 5 minutes before the instance is released.
+type MetadataPoller struct {
+	endpoint string
+	interval time.Duration
+	client   *http.Client
+}
+
+// NewMetadataPoller creates a poller querying the metadata service every interval
+func NewMetadataPoller(interval time.Duration) *MetadataPoller {
+	return &MetadataPoller{
+		endpoint: DefaultEndpoint,
+		interval: interval,
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// get fetches a metadata path; found is false if the metadata service returns 404
+func (p *MetadataPoller) get(ctx context.Context, path string) (value string, found bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+path, nil)
+	if err != nil {
+		return "", false, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to query metadata %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("metadata %s returned HTTP %d", path, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read metadata %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(body)), true, nil
+}
+
+// InstanceID returns the ID of the instance the poller runs on
+func (p *MetadataPoller) InstanceID(ctx context.Context) (string, error) {
+	id, found, err := p.get(ctx, "/instance-id")
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("instance ID not available from metadata service")
+	}
+	return id, nil
+}
+
+// TerminationTime returns the scheduled reclaim time of the instance; found is false
+// while no reclaim is scheduled
+func (p *MetadataPoller) TerminationTime(ctx context.Context) (t time.Time, found bool, err error) {
+	value, found, err := p.get(ctx, "/instance/spot/termination-time")
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+
+	t, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid termination time %q: %w", value, err)
+	}
+	return t, true, nil
+}
+
+// Run polls until ctx is cancelled and calls onNotice once for each distinct termination time
+func (p *MetadataPoller) Run(ctx context.Context, onNotice func(terminationTime time.Time)) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var notified time.Time
+	for {
+		t, found, err := p.TerminationTime(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Debugf("Spot termination poll failed: %v", err)
+		} else if found && !t.Equal(notified) {
+			notified = t
+			onNotice(t)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+)
+
+// SpotPricePoint is a single spot price history entry of an instance type in a zone
+type SpotPricePoint struct {
+	ZoneID      string
+	Timestamp   time.Time
+	SpotPrice   float64 // per hour
+	OriginPrice float64 // on-demand list price per hour
+}
+
+// ZoneSpotPrice summarizes the spot price history of an instance type in one zone
+type ZoneSpotPrice struct {
+	ZoneID       string
+	CurrentPrice float64 // latest spot price per hour
+	AveragePrice float64 // average spot price over the queried window
+	OriginPrice  float64 // on-demand list price per hour
+}
+
+// DiscountPercent returns how much cheaper the current spot price is than on-demand
+func (z ZoneSpotPrice) DiscountPercent() float64 {
+	if z.OriginPrice <= 0 {
+		return 0
+	}
+	return (1 - z.CurrentPrice/z.OriginPrice) * 100
+}
+
+// DescribeSpotPriceHistory returns the Linux VPC spot price history of an instance type
+// in all zones of a region since the given time
+func (c *ECSClient) DescribeSpotPriceHistory(ctx context.Context, regionID, instanceType string, since time.Time) (_ []SpotPricePoint, err error) {
+	span := startSpan(ctx, "ecs.DescribeSpotPriceHistory", regionID, "")
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []SpotPricePoint
+	offset := 0
+	for {
+		request := ecs.CreateDescribeSpotPriceHistoryRequest()
+		request.Scheme = "https"
+		request.RegionId = regionID
+		request.InstanceType = instanceType
+		request.NetworkType = "vpc"
+		request.OSType = "linux"
+		request.StartTime = since.UTC().Format("2006-01-02T15:04:05Z")
+		if offset > 0 {
+			request.Offset = requests.NewInteger(offset)
+		}
+
+		var response *ecs.DescribeSpotPriceHistoryResponse
+		err := c.guard(ctx, regionID, func() (err error) {
+			response, err = client.DescribeSpotPriceHistory(request)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe spot price history of %s in %s: %w", instanceType, regionID, err)
+		}
+
+		for _, p := range response.SpotPrices.SpotPriceType {
+			ts, err := time.Parse(time.RFC3339, p.Timestamp)
+			if err != nil {
+				continue
+			}
+			points = append(points, SpotPricePoint{
+				ZoneID:      p.ZoneId,
+				Timestamp:   ts,
+				SpotPrice:   p.SpotPrice,
+				OriginPrice: p.OriginPrice,
+			})
+		}
+
+		if response.NextOffset <= offset || len(response.SpotPrices.SpotPriceType) == 0 {
+			break
+		}
+		offset = response.NextOffset
+	}
+
+	return points, nil
+}
+
+// SummarizeSpotPrices groups price history by zone, cheapest current price first
+func SummarizeSpotPrices(points []SpotPricePoint) []ZoneSpotPrice {
+	type zoneAgg struct {
+		latest SpotPricePoint
+		sum    float64
+		count  int
+	}
+	zones := make(map[string]*zoneAgg)
+	for _, p := range points {
+		agg, ok := zones[p.ZoneID]
+		if !ok {
+			agg = &zoneAgg{latest: p}
+			zones[p.ZoneID] = agg
+		}
+		if p.Timestamp.After(agg.latest.Timestamp) {
+			agg.latest = p
+		}
+		agg.sum += p.SpotPrice
+		agg.count++
+	}
+
+	result := make([]ZoneSpotPrice, 0, len(zones))
+	for zoneID, agg := range zones {
+		result = append(result, ZoneSpotPrice{
+			ZoneID:       zoneID,
+			CurrentPrice: agg.latest.SpotPrice,
+			AveragePrice: agg.sum / float64(agg.count),
+			OriginPrice:  agg.latest.OriginPrice,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CurrentPrice != result[j].CurrentPrice {
+			return result[i].CurrentPrice < result[j].CurrentPrice
+		}
+		return result[i].ZoneID < result[j].ZoneID
+	})
+	return result
+}
+
+// DescribeOnDemandPrice returns the pay-as-you-go hourly price of a Linux VPC instance type in a region
+func (c *ECSClient) DescribeOnDemandPrice(ctx context.Context, regionID, instanceType string) (_ float64, err error) {
+	span := startSpan(ctx, "ecs.DescribeOnDemandPrice", regionID, "")
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.getClient(regionID)
+	if err != nil {
+		return 0, err
+	}
+
+	request := ecs.CreateDescribePriceRequest()
+	request.Scheme = "https"
+	request.RegionId = regionID
+	request.ResourceType = "instance"
+	request.InstanceType = instanceType
+	request.InstanceNetworkType = "vpc"
+	request.SpotStrategy = "NoSpot"
+	request.PriceUnit = "Hour"
+
+	var response *ecs.DescribePriceResponse
+	err = c.guard(ctx, regionID, func() (err error) {
+		response, err = client.DescribePrice(request)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe on-demand price of %s in %s: %w", instanceType, regionID, err)
+	}
+
+	return response.PriceInfo.Price.TradePrice, nil
+}
@@ -11,15 +11,22 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// TrafficClient wraps the Aliyun CDT client for traffic queries
+// TrafficClient wraps the Aliyun CDT client for traffic queries. CDT (ListCdtInternetTraffic)
+// reports billed internet traffic per region and product, it has no per-instance breakdown.
 type TrafficClient struct {
 	client *sdk.Client
 }
 
 // NewTrafficClient creates a new CDT traffic client
-func NewTrafficClient(accessKeyID, accessKeySecret string) (*TrafficClient, error) {
+func NewTrafficClient(cred Credential) (*TrafficClient, error) {
 	// CDT API uses cn-hangzhou as the default region
-	client, err := sdk.NewClientWithAccessKey("cn-hangzhou", accessKeyID, accessKeySecret)
+	var client *sdk.Client
+	var err error
+	if cred.UsesRAMRole() {
+		client, err = sdk.NewClientWithEcsRamRole("cn-hangzhou", cred.RAMRoleName)
+	} else {
+		client, err = sdk.NewClientWithAccessKey("cn-hangzhou", cred.AccessKeyID, cred.AccessKeySecret)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CDT client: %w", err)
 	}
@@ -113,6 +120,37 @@ func IsChinaMainlandRegion(regionId string) bool {
 	return false
 }
 
+// Traffic limit scopes that aggregate all regions without a limit of their own
+const (
+	TrafficScopeChina    = "global-china"
+	TrafficScopeNonChina = "global-non-china"
+)
+
+// TrafficScope returns the limit scope of a region: the region itself when it has its own
+// entry in limits, otherwise the China mainland or non-China fallback scope
+func TrafficScope(regionID string, limits map[string]float64) string {
+	if _, ok := limits[regionID]; ok && regionID != TrafficScopeChina && regionID != TrafficScopeNonChina {
+		return regionID
+	}
+	if IsChinaMainlandRegion(regionID) {
+		return TrafficScopeChina
+	}
+	return TrafficScopeNonChina
+}
+
+// ScopeTrafficGB sums traffic in GB per limit scope. Regions with their own limit are not
+// counted towards the fallback scopes. Every scope in limits is present in the result.
+func (s *TrafficSummary) ScopeTrafficGB(limits map[string]float64) map[string]float64 {
+	usage := make(map[string]float64, len(limits))
+	for scope := range limits {
+		usage[scope] = 0
+	}
+	for _, detail := range s.RegionDetails {
+		usage[TrafficScope(detail.BusinessRegionId, limits)] += float64(detail.Traffic) / (1024 * 1024 * 1024)
+	}
+	return usage
+}
+
 // QueryInternetTraffic queries internet traffic for the current month
 func (c *TrafficClient) QueryInternetTraffic(accountLabel string) (*TrafficSummary, error) {
 	now := time.Now()
@@ -145,8 +183,25 @@ func (c *TrafficClient) QueryInternetTrafficByTimeRange(startTime, endTime time.
 		return nil, fmt.Errorf("CDT API returned status %d: %s", response.GetHttpStatus(), string(response.GetHttpContentBytes()))
 	}
 
+	summary, err := parseTrafficSummary(response.GetHttpContentBytes(), startTime, endTime, accountLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("[%s] Traffic summary: Total=%.2f GB, China Mainland=%.2f GB (%d regions), Non-China=%.2f GB (%d regions)",
+		accountLabel,
+		summary.TotalTrafficGB,
+		summary.ChinaMainland.TrafficGB, summary.ChinaMainland.RegionCount,
+		summary.NonChinaMainland.TrafficGB, summary.NonChinaMainland.RegionCount)
+
+	return summary, nil
+}
+
+// parseTrafficSummary builds the traffic summary of a ListCdtInternetTraffic response body,
+// splitting the regions into China Mainland and the rest
+func parseTrafficSummary(body []byte, startTime, endTime time.Time, accountLabel string) (*TrafficSummary, error) {
 	var cdtResponse cdtInternetTrafficResponse
-	if err := json.Unmarshal(response.GetHttpContentBytes(), &cdtResponse); err != nil {
+	if err := json.Unmarshal(body, &cdtResponse); err != nil {
 		return nil, fmt.Errorf("failed to parse CDT response: %w", err)
 	}
 
@@ -191,12 +246,6 @@ func (c *TrafficClient) QueryInternetTrafficByTimeRange(startTime, endTime time.
 	summary.ChinaMainland.TrafficGB = float64(summary.ChinaMainland.Traffic) / (1024 * 1024 * 1024)
 	summary.NonChinaMainland.TrafficGB = float64(summary.NonChinaMainland.Traffic) / (1024 * 1024 * 1024)
 
-	log.Infof("[%s] Traffic summary: Total=%.2f GB, China Mainland=%.2f GB (%d regions), Non-China=%.2f GB (%d regions)",
-		accountLabel,
-		summary.TotalTrafficGB,
-		summary.ChinaMainland.TrafficGB, summary.ChinaMainland.RegionCount,
-		summary.NonChinaMainland.TrafficGB, summary.NonChinaMainland.RegionCount)
-
 	return summary, nil
 }
 
@@ -223,6 +272,26 @@ func FormatTrafficSize(bytes int64) string {
 	}
 }
 
+// FormatBitRate formats a bit rate in bits per second in human-readable format
+func FormatBitRate(bps float64) string {
+	const (
+		Kbps = 1000
+		Mbps = Kbps * 1000
+		Gbps = Mbps * 1000
+	)
+
+	switch {
+	case bps >= Gbps:
+		return fmt.Sprintf("%.2f Gbps", bps/Gbps)
+	case bps >= Mbps:
+		return fmt.Sprintf("%.2f Mbps", bps/Mbps)
+	case bps >= Kbps:
+		return fmt.Sprintf("%.2f Kbps", bps/Kbps)
+	default:
+		return fmt.Sprintf("%.0f bps", bps)
+	}
+}
+
 // GetRegionDisplayName returns a friendly display name for a region
 func GetRegionDisplayName(regionId string) string {
 	regionNames := map[string]string{
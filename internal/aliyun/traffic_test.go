@@ -0,0 +1,122 @@
+package aliyun
+
+import (
+	"testing"
+	"time"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func TestParseTrafficSummary(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		wantTotalGB    float64
+		wantChinaGB    float64
+		wantNonChinaGB float64
+		wantChinaCount int
+		wantNonCount   int
+		wantProducts   map[string]int64 // non-China product traffic in bytes
+	}{
+		{
+			name: "no traffic",
+			body: `{"RequestId":"r","TrafficDetails":[]}`,
+		},
+		{
+			name:           "zero bytes in a region",
+			body:           `{"TrafficDetails":[{"BusinessRegionId":"cn-hangzhou","Traffic":0}]}`,
+			wantChinaCount: 1,
+		},
+		{
+			name:           "exactly one GiB",
+			body:           `{"TrafficDetails":[{"BusinessRegionId":"cn-beijing","Traffic":1073741824}]}`,
+			wantTotalGB:    1,
+			wantChinaGB:    1,
+			wantChinaCount: 1,
+		},
+		{
+			name:           "one byte below a GiB",
+			body:           `{"TrafficDetails":[{"BusinessRegionId":"ap-southeast-1","Traffic":1073741823}]}`,
+			wantTotalGB:    float64(gib-1) / gib,
+			wantNonChinaGB: float64(gib-1) / gib,
+			wantNonCount:   1,
+		},
+		{
+			name: "regions split by China Mainland",
+			body: `{"TrafficDetails":[
+				{"BusinessRegionId":"cn-shanghai","Traffic":2147483648,
+				 "ProductTrafficDetails":[{"Product":"eip","Traffic":2147483648}]},
+				{"BusinessRegionId":"cn-hongkong","Traffic":536870912,
+				 "ProductTrafficDetails":[{"Product":"eip","Traffic":268435456},{"Product":"cbwp","Traffic":268435456}]},
+				{"BusinessRegionId":"us-west-1","Traffic":536870912,
+				 "ProductTrafficDetails":[{"Product":"eip","Traffic":536870912}]}
+			]}`,
+			wantTotalGB:    3,
+			wantChinaGB:    2,
+			wantNonChinaGB: 1,
+			wantChinaCount: 1,
+			wantNonCount:   2,
+			wantProducts:   map[string]int64{"eip": 805306368, "cbwp": 268435456},
+		},
+	}
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := parseTrafficSummary([]byte(tt.body), start, end, "main")
+			if err != nil {
+				t.Fatalf("parseTrafficSummary: %v", err)
+			}
+			if summary.TotalTrafficGB != tt.wantTotalGB {
+				t.Errorf("TotalTrafficGB = %v, want %v", summary.TotalTrafficGB, tt.wantTotalGB)
+			}
+			if summary.ChinaMainland.TrafficGB != tt.wantChinaGB {
+				t.Errorf("ChinaMainland.TrafficGB = %v, want %v", summary.ChinaMainland.TrafficGB, tt.wantChinaGB)
+			}
+			if summary.NonChinaMainland.TrafficGB != tt.wantNonChinaGB {
+				t.Errorf("NonChinaMainland.TrafficGB = %v, want %v", summary.NonChinaMainland.TrafficGB, tt.wantNonChinaGB)
+			}
+			if summary.ChinaMainland.RegionCount != tt.wantChinaCount || summary.NonChinaMainland.RegionCount != tt.wantNonCount {
+				t.Errorf("region counts = %d/%d, want %d/%d", summary.ChinaMainland.RegionCount,
+					summary.NonChinaMainland.RegionCount, tt.wantChinaCount, tt.wantNonCount)
+			}
+			for product, want := range tt.wantProducts {
+				if got := summary.NonChinaMainland.ProductDetails[product]; got != want {
+					t.Errorf("NonChinaMainland.ProductDetails[%s] = %d, want %d", product, got, want)
+				}
+			}
+			if summary.BillingCycle != "2026-10" || summary.AccountLabel != "main" {
+				t.Errorf("BillingCycle/AccountLabel = %s/%s, want 2026-10/main", summary.BillingCycle, summary.AccountLabel)
+			}
+		})
+	}
+}
+
+func TestParseTrafficSummaryInvalidBody(t *testing.T) {
+	if _, err := parseTrafficSummary([]byte("<html>"), time.Time{}, time.Time{}, ""); err == nil {
+		t.Fatal("parseTrafficSummary accepted a non-JSON body")
+	}
+}
+
+func TestFormatTrafficSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.00 KB"},
+		{1024*1024 - 1, "1024.00 KB"},
+		{1024 * 1024, "1.00 MB"},
+		{gib, "1.00 GB"},
+		{1536 * 1024 * 1024, "1.50 GB"},
+		{1024 * gib, "1.00 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTrafficSize(tt.bytes); got != tt.want {
+			t.Errorf("FormatTrafficSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package aws monitors AWS EC2 spot instances alongside the Aliyun ones.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// EC2 instance states (types.InstanceStateName)
+const (
+	StatePending  = "pending"
+	StateRunning  = "running"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+)
+
+// SpotInstance represents an EC2 spot instance
+type SpotInstance struct {
+	InstanceID   string
+	InstanceName string // Name tag, the instance ID if not set
+	Region       string
+	Status       string // EC2 state name, e.g. "running"
+	InstanceType string
+	PublicIP     string
+	PrivateIP    string
+}
+
+// EC2Client wraps the EC2 API clients of each region of one AWS account
+type EC2Client struct {
+	creds   awssdk.CredentialsProvider
+	clients map[string]*ec2.Client // region -> client, created on first use
+	mu      sync.Mutex
+	dryRun  bool // log start/stop requests instead of sending them
+}
+
+// NewEC2Client creates an EC2 client authenticated with an access key
+func NewEC2Client(accessKeyID, secretAccessKey string) *EC2Client {
+	return &EC2Client{
+		creds:   awssdk.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")),
+		clients: make(map[string]*ec2.Client),
+	}
+}
+
+// SetDryRun makes StartInstance and StopInstance log their intent and return nil
+// without calling the API. Read operations are unaffected.
+func (c *EC2Client) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// client returns the EC2 client of a region
+func (c *EC2Client) client(region string) *ec2.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[region]; ok {
+		return client
+	}
+	client := ec2.New(ec2.Options{
+		Region:      region,
+		Credentials: c.creds,
+	})
+	c.clients[region] = client
+	return client
+}
+
+// GetSpotInstances returns the spot instances (lifecycle=spot) in a region, except terminated ones
+func (c *EC2Client) GetSpotInstances(ctx context.Context, region string) ([]*SpotInstance, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{Name: awssdk.String("instance-lifecycle"), Values: []string{"spot"}},
+			{Name: awssdk.String("instance-state-name"), Values: []string{StatePending, StateRunning, StateStopping, StateStopped}},
+		},
+	}
+
+	var instances []*SpotInstance
+	paginator := ec2.NewDescribeInstancesPaginator(c.client(region), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances in region %s: %w", region, err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, inst := range reservation.Instances {
+				instances = append(instances, newSpotInstance(region, inst))
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// newSpotInstance converts an EC2 API instance
+func newSpotInstance(region string, inst types.Instance) *SpotInstance {
+	si := &SpotInstance{
+		InstanceID:   awssdk.ToString(inst.InstanceId),
+		Region:       region,
+		InstanceType: string(inst.InstanceType),
+		PublicIP:     awssdk.ToString(inst.PublicIpAddress),
+		PrivateIP:    awssdk.ToString(inst.PrivateIpAddress),
+	}
+	if inst.State != nil {
+		si.Status = string(inst.State.Name)
+	}
+	for _, tag := range inst.Tags {
+		if awssdk.ToString(tag.Key) == "Name" {
+			si.InstanceName = awssdk.ToString(tag.Value)
+		}
+	}
+	if si.InstanceName == "" {
+		si.InstanceName = si.InstanceID
+	}
+	return si
+}
+
+// GetInstance returns the current details of an instance
+func (c *EC2Client) GetInstance(ctx context.Context, region, instanceID string) (*SpotInstance, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	output, err := c.client(region).DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instance %s: %w", instanceID, err)
+	}
+	for _, reservation := range output.Reservations {
+		for _, inst := range reservation.Instances {
+			return newSpotInstance(region, inst), nil
+		}
+	}
+	return nil, fmt.Errorf("instance %s not found in region %s", instanceID, region)
+}
+
+// GetInstanceStatus returns the EC2 state name of an instance, e.g. "stopped"
+func (c *EC2Client) GetInstanceStatus(ctx context.Context, region, instanceID string) (string, error) {
+	inst, err := c.GetInstance(ctx, region, instanceID)
+	if err != nil {
+		return "", err
+	}
+	return inst.Status, nil
+}
+
+// StartInstance starts a stopped instance
+func (c *EC2Client) StartInstance(ctx context.Context, region, instanceID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would start AWS instance %s in region %s", instanceID, region)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	_, err := c.client(region).StartInstances(ctx, &ec2.StartInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// StopInstance stops a running instance
+func (c *EC2Client) StopInstance(ctx context.Context, region, instanceID string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would stop AWS instance %s in region %s", instanceID, region)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	_, err := c.client(region).StopInstances(ctx, &ec2.StopInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// DiscoverAllSpotInstances discovers the spot instances of all given regions concurrently.
+// Regions that fail are logged and skipped; an error is returned only if all of them failed.
+func (c *EC2Client) DiscoverAllSpotInstances(ctx context.Context, regions []string) ([]*SpotInstance, error) {
+	results := make([][]*SpotInstance, len(regions))
+	errs := make([]error, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			results[i], errs[i] = c.GetSpotInstances(ctx, region)
+		}(i, region)
+	}
+	wg.Wait()
+
+	var instances []*SpotInstance
+	var lastErr error
+	failed := 0
+	for i, region := range regions {
+		if errs[i] != nil {
+			log.Warnf("AWS: Failed to scan region %s: %v", region, errs[i])
+			lastErr = errs[i]
+			failed++
+			continue
+		}
+		instances = append(instances, results[i]...)
+	}
+	if len(regions) > 0 && failed == len(regions) {
+		return nil, fmt.Errorf("all %d regions failed: %w", failed, lastErr)
+	}
+	return instances, nil
+}
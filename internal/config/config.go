@@ -1,11 +1,20 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/format"
+	"github.com/robfig/cron/v3"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -14,11 +23,32 @@ type AliyunAccount struct {
 	Label           string // display label for this account
 	AccessKeyID     string
 	AccessKeySecret string
+	RAMRoleName     string // ECS RAM instance role, used instead of AccessKeys in ram_role auth mode
+}
+
+// InstanceOverride holds per-instance settings that replace the global defaults.
+// Zero fields fall back to the global value.
+type InstanceOverride struct {
+	CheckInterval int  `json:"check_interval"` // seconds
+	RetryCount    int  `json:"retry_count"`
+	RetryInterval int  `json:"retry_interval"`  // seconds, base interval for exponential backoff
+	SSHPort       int  `json:"ssh_port"`        // port dialled by the SSH health check
+	TLSSkipVerify bool `json:"tls_skip_verify"` // skip certificate verification of the HTTP health check
+}
+
+// MaintenanceWindow suppresses auto-start of matching instances for DurationMinutes
+// after each time Cron fires. Empty InstanceIDs / Regions match all instances / regions.
+type MaintenanceWindow struct {
+	Cron            string   `json:"cron"`
+	DurationMinutes int      `json:"duration_minutes"`
+	InstanceIDs     []string `json:"instance_ids"`
+	Regions         []string `json:"regions"`
 }
 
 // Config holds all configuration for the application
 type Config struct {
 	// Aliyun credentials (multi-account)
+	AliyunAuthMode string // "key" (static AccessKeys) or "ram_role" (ECS instance RAM role)
 	AliyunAccounts []AliyunAccount
 
 	// GCP settings
@@ -27,79 +57,489 @@ type Config struct {
 	GCPCredentialsJSON string   // service account JSON content
 	GCPZones           []string // specific zones to monitor, empty = auto-discover
 
+	// GCP BigQuery billing export table for cost reports, empty dataset = not used
+	GCPBigQueryProject string // defaults to GCPProjectID
+	GCPBigQueryDataset string
+	GCPBigQueryTable   string
+
+	// AWS settings, enabled when AWS_ACCESS_KEY_ID is set
+	AWSEnabled         bool
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSRegions         []string // regions scanned for EC2 spot instances
+
+	// Instance discovery filters
+	InstanceFilterTags       map[string]string // required ECS tags (key -> value), empty = no filtering
+	InstanceNamePatterns     []string          // glob patterns (path.Match) of instance names to monitor, empty = all
+	InstanceNameDenyPatterns []string          // glob patterns of instance names never monitored
+	InstanceTypeAllowlist    []string          // instance type prefixes to monitor (e.g. ecs.c6), empty = all
+	InstanceTypeDenylist     []string          // instance type prefixes never monitored (e.g. ecs.t6)
+	InstanceVPCFilter        []string          // VPC IDs whose instances are monitored, empty = all
+	InstanceSGFilter         []string          // security group IDs whose instances are monitored, empty = all
+	ResourceGroupID          string            // resource group whose instances are monitored, empty = all
+
 	// Telegram settings
-	TelegramEnabled  bool
-	TelegramBotToken string
-	TelegramChatID   string
+	TelegramEnabled   bool
+	TelegramBotToken  string
+	TelegramChatIDs   []string         // alerts go to every chat, commands are accepted from any of them
+	TelegramParseMode format.ParseMode // formatting of Telegram messages, which are written in HTML
+	BotLanguage       string           // language of notifications, /help and /status, e.g. "en"; unknown codes fall back to en-US
+
+	// Telegram webhook mode (replaces long-polling when URL is set)
+	TelegramWebhookURL      string // public base URL, updates arrive at <url>/telegram/webhook
+	TelegramWebhookPort     int
+	TelegramWebhookSecret   string // empty = random secret generated at startup
+	TelegramWebhookCertFile string // optional, serve TLS directly
+	TelegramWebhookKeyFile  string
+
+	// Discord channel webhook, notifications are sent there as well when set
+	DiscordWebhookURL string
+
+	// Slack incoming webhook, notifications are sent there as well when set
+	SlackWebhookURL string
+	SlackUsername   string
+	SlackIconEmoji  string
+
+	// DingTalk custom robot webhook, notifications are sent there as well when set
+	DingTalkWebhookURL string
+	DingTalkSecret     string // optional, signs requests ("加签")
+
+	// WeChat Work (企业微信) group bot key, notifications are sent there as well when set
+	WeChatWorkKey string
 
 	// Check settings
 	CheckInterval int    // seconds
 	CronSchedule  string // cron expression
 
+	// Per-instance overrides keyed by instance ID (GCP: instance name)
+	InstanceOverrides map[string]InstanceOverride
+
+	// Start order of stopped instances: instance ID -> priority, lower starts first
+	InstancePriority         map[string]int
+	InstancePriorityParallel bool // start stopped instances concurrently, ignoring the priority
+	// Scheduled windows in which auto-start is suppressed
+	MaintenanceWindows []MaintenanceWindow
+
+	// Dry run: log mutating API calls (start/stop, bandwidth package changes) instead of making them
+	DryRun bool
+
 	// Retry settings
-	RetryCount    int
-	RetryInterval int // seconds
+	RetryCount       int
+	RetryInterval    int // seconds, base interval for exponential backoff
+	MaxRetryInterval int // seconds, upper bound of the backoff interval
+
+	// Seconds in-progress starts may take to finish after a shutdown signal
+	ShutdownTimeout int
 
 	// Notification settings
-	NotifyCooldown int // seconds
+	NotifyCooldown           int     // seconds
+	NotifyBatchWindow        int     // seconds reclaim notifications are collected before sending
+	NotifyBatchThreshold     int     // merge reclaim notifications when more than this many arrive in a window, 0 = disabled
+	BillingReportSchedule    string  // cron expression for the scheduled billing digest, empty = disabled
+	BillingCacheTTL          int     // seconds a current-month billing query is reused, 0 = disabled
+	BillingWeeklySchedule    string  // cron expression for the week-over-week billing comparison, empty = disabled
+	CostAnomalyMultiplier    float64 // alert when a day's cost exceeds this multiple of the 7-day average, 0 = disabled
+	MonthlyBudgetCNY         float64 // monthly budget in CNY compared with the month-end projection, 0 = none
+	MonthlyBudgetWarnPercent float64 // warn when the projection reaches this ratio of the budget, 0 = disabled
+
+	// Rapid reclaim protection
+	RapidReclaimCount  int // pause auto-start after more than this many reclaims, 0 = disabled
+	RapidReclaimWindow int // seconds
+	RapidReclaimPause  int // seconds
+
+	// Spot reclaim notice polling of the instance the monitor runs on
+	MetadataPollEnabled   bool
+	MetadataPollInterval  int    // seconds
+	SpotTerminationScript string // executable run when a reclaim notice appears, empty = none
+
+	// Warn about scheduled ECS system events this many minutes ahead, 0 = disabled
+	ReclaimWarnMinutes int
+
+	// EventBridge spot interruption events, enabled when WebhookSecret is set
+	WebhookAddr    string
+	WebhookSecret  string // HMAC-SHA256 key of the X-Signature header
+	WebhookTLSCert string // optional, serve TLS directly
+	WebhookTLSKey  string
+	PreStopHook    string // executable run when an interruption event arrives, empty = none
+
+	// Per-region circuit breaker for ECS API calls
+	CircuitBreakerThreshold int // consecutive failures that open a region's circuit, 0 = disabled
+	CircuitBreakerTimeout   int // seconds a region is skipped before a probe request
+
+	// ECS API requests per second per account, shared by all regions
+	ECSAPIRPS float64
 
 	// Health check settings
 	HealthCheckEnabled  bool
-	HealthCheckTimeout  int // seconds
-	HealthCheckInterval int // seconds
+	HealthCheckTimeout  int               // seconds
+	HealthCheckInterval int               // seconds
+	HealthCheckSSH      bool              // wait for the SSH port to accept TCP connections before reporting a start
+	MinUptimeSeconds    int               // seconds an instance must stay running before a start counts, 0 = disabled
+	InstanceHealthURLs  map[string]string // instance ID -> URL polled after start, {ip} is replaced with the public IP
+
+	// CloudMonitor thresholds (percent) checked a few minutes after a start, 0 = disabled
+	CloudMonitorAlertCPU    float64
+	CloudMonitorAlertMemory float64
+	CloudMonitorAlertDisk   float64
 
 	// Traffic auto-shutdown settings
 	TrafficShutdownEnabled bool
-	TrafficLimitChinaGB    float64 // China mainland traffic limit in GB
-	TrafficLimitNonChinaGB float64 // Non-China traffic limit in GB
-	TrafficCheckInterval   int     // seconds
+	TrafficLimitChinaGB    float64            // China mainland traffic limit in GB
+	TrafficLimitNonChinaGB float64            // Non-China traffic limit in GB
+	TrafficLimits          map[string]float64 // limit scope (region ID or global-china/global-non-china) -> GB
+	TrafficCheckInterval   int                // seconds
+	TrafficWarnPercents    []int              // ascending warning thresholds in percent of the limit
+	PostResetRestartDelay  int                // seconds between the monthly traffic reset and restarting shut down instances
+
+	// CBWP settings
+	CBWPAutoUnbindOnShutdown bool              // remove EIPs from bandwidth packages before traffic shutdown
+	AutoSnapshotBeforeStop   bool              // snapshot the system disk before traffic shutdown
+	AutoBindBWP              map[string]string // instance ID -> bandwidth package ID to add its EIPs to after start
+	EIPQuotaWarnPercent      float64           // warn when used/limit of a region's EIP quota reaches this ratio, 0 = disabled
+	BandwidthWarnPercent     float64           // warn when a bandwidth package's peak reaches this ratio of its capacity, 0 = disabled
+	BandwidthCheckInterval   int               // seconds
+	DefaultEIPBandwidth      int               // Mbps of EIPs allocated via /allocate-eip
+
+	// Post-start hooks
+	InstanceWebhooks  map[string]string // instance ID -> URL POSTed to after the instance was started
+	AllowHTTPWebhooks bool              // accept plain http:// webhook URLs
+
+	// Security group audit: instance ID -> security group IDs expected by /sgroups
+	ExpectedSecurityGroups map[string][]string
+
+	// State persistence
+	StateFile string // JSON file for reclaim counts and cooldowns, empty = use DBPath
+	DBPath    string // SQLite database for incident history (and state without STATE_FILE), empty = disabled
+
+	// Liveness / readiness probe server
+	HealthAddr string
+
+	// Prometheus metrics
+	MetricsEnabled bool
+	MetricsAddr    string // listen address of the /metrics endpoint
+
+	// gRPC API
+	GRPCEnabled bool
+	GRPCAddr    string
+	GRPCTLSCert string // TLS certificate file, empty = plaintext (development only)
+	GRPCTLSKey  string
+	GRPCToken   string // bearer token required on every call, empty = no authentication
+
+	// OpenTelemetry tracing, empty = spans are not exported
+	OTLPEndpoint string
 
 	// Logging
-	LogLevel string
-	LogFile  string
+	LogLevel      string
+	LogFormat     string // "text" or "json"
+	LogFile       string // rotating log file written in addition to stdout, empty = stdout only
+	LogMaxSizeMB  int    // rotate the log file when it reaches this size
+	LogMaxBackups int    // rotated log files kept, 0 = keep all
+	LogMaxAgeDays int    // days rotated log files are kept, 0 = no age limit
+	LogBufferSize int    // recent log lines kept in memory for /logs
+
+	// Time zone of daily schedules such as the traffic snapshot
+	Timezone string         // IANA name, empty = server local time
+	Location *time.Location // parsed Timezone
+
+	// Config file the settings were loaded from, empty = environment only
+	ConfigFile   string
+	EnvOverrides []string // config file keys overridden by environment variables
 }
 
-// Load loads configuration from environment variables
+// Load loads configuration from environment variables, filling unset ones from the config file
 func Load() (*Config, error) {
+	configFile, envOverrides, err := applyConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
+		ConfigFile:   configFile,
+		EnvOverrides: envOverrides,
+
 		// GCP
 		GCPEnabled:         getEnvBool("GCP_ENABLED", false),
 		GCPProjectID:       os.Getenv("GCP_PROJECT_ID"),
 		GCPCredentialsJSON: loadGCPCredentials(),
+		GCPBigQueryProject: os.Getenv("GCP_BIGQUERY_PROJECT"),
+		GCPBigQueryDataset: os.Getenv("GCP_BIGQUERY_DATASET"),
+		GCPBigQueryTable:   os.Getenv("GCP_BIGQUERY_TABLE"),
 
 		// Telegram
 		TelegramEnabled:  getEnvBool("TELEGRAM_ENABLED", true),
 		TelegramBotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
-		TelegramChatID:   os.Getenv("TELEGRAM_CHAT_ID"),
+		TelegramChatIDs:  parseChatIDs(),
+
+		// Telegram webhook
+		TelegramWebhookURL:      os.Getenv("TELEGRAM_WEBHOOK_URL"),
+		TelegramWebhookPort:     getEnvInt("TELEGRAM_WEBHOOK_PORT", 8443),
+		TelegramWebhookSecret:   os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+		TelegramWebhookCertFile: os.Getenv("TELEGRAM_WEBHOOK_CERT_FILE"),
+		TelegramWebhookKeyFile:  os.Getenv("TELEGRAM_WEBHOOK_KEY_FILE"),
+
+		// Discord
+		DiscordWebhookURL: os.Getenv("DISCORD_WEBHOOK_URL"),
+
+		// Slack
+		SlackWebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+		SlackUsername:   getEnvString("SLACK_USERNAME", "Aliyun Spot Monitor"),
+		SlackIconEmoji:  getEnvString("SLACK_ICON_EMOJI", ":cloud:"),
+
+		// DingTalk
+		DingTalkWebhookURL: os.Getenv("DINGTALK_WEBHOOK_URL"),
+		DingTalkSecret:     os.Getenv("DINGTALK_SECRET"),
+
+		// WeChat Work
+		WeChatWorkKey: os.Getenv("WECHATWORK_KEY"),
 
 		// Check settings
 		CheckInterval: getEnvInt("CHECK_INTERVAL", 60),
+		DryRun:        getEnvBool("DRY_RUN", false),
 
 		// Retry settings
-		RetryCount:    getEnvInt("RETRY_COUNT", 3),
-		RetryInterval: getEnvInt("RETRY_INTERVAL", 30),
+		RetryCount:       getEnvInt("RETRY_COUNT", 3),
+		RetryInterval:    getEnvInt("RETRY_INTERVAL", 30),
+		MaxRetryInterval: getEnvInt("MAX_RETRY_INTERVAL", 300),
+
+		ShutdownTimeout: getEnvInt("SHUTDOWN_TIMEOUT", 120),
 
 		// Notification settings
-		NotifyCooldown: getEnvInt("NOTIFY_COOLDOWN", 300),
+		NotifyCooldown:           getEnvInt("NOTIFY_COOLDOWN", 300),
+		NotifyBatchWindow:        getEnvInt("NOTIFY_BATCH_WINDOW", 10),
+		NotifyBatchThreshold:     getEnvInt("NOTIFY_BATCH_THRESHOLD", 3),
+		BillingReportSchedule:    os.Getenv("BILLING_REPORT_SCHEDULE"),
+		BillingCacheTTL:          getEnvInt("BILLING_CACHE_TTL", 300),
+		BillingWeeklySchedule:    getEnvStringAllowEmpty("BILLING_WEEKLY_SCHEDULE", "0 9 * * 1"),
+		CostAnomalyMultiplier:    getEnvFloat64("COST_ANOMALY_MULTIPLIER", 3.0),
+		MonthlyBudgetCNY:         getEnvFloat64("MONTHLY_BUDGET_CNY", 0),
+		MonthlyBudgetWarnPercent: getEnvFloat64("MONTHLY_BUDGET_WARN_PERCENT", 0.8),
+
+		// Rapid reclaim protection
+		RapidReclaimCount:  getEnvInt("RAPID_RECLAIM_COUNT", 3),
+		RapidReclaimWindow: getEnvInt("RAPID_RECLAIM_WINDOW", 3600),
+		RapidReclaimPause:  getEnvInt("RAPID_RECLAIM_PAUSE", 3600),
+
+		// Metadata polling
+		MetadataPollEnabled:   getEnvBool("METADATA_POLL_ENABLED", false),
+		MetadataPollInterval:  getEnvInt("METADATA_POLL_INTERVAL", 15),
+		SpotTerminationScript: os.Getenv("SPOT_TERMINATION_SCRIPT"),
+
+		ReclaimWarnMinutes: getEnvInt("RECLAIM_WARN_MINUTES", 10),
+
+		// EventBridge webhook
+		WebhookAddr:    getEnvString("WEBHOOK_ADDR", ":8443"),
+		WebhookSecret:  os.Getenv("WEBHOOK_SECRET"),
+		WebhookTLSCert: os.Getenv("WEBHOOK_TLS_CERT"),
+		WebhookTLSKey:  os.Getenv("WEBHOOK_TLS_KEY"),
+		PreStopHook:    os.Getenv("PRE_STOP_HOOK"),
+
+		// Circuit breaker
+		CircuitBreakerThreshold: getEnvInt("CIRCUIT_BREAKER_THRESHOLD", 5),
+		CircuitBreakerTimeout:   getEnvInt("CIRCUIT_BREAKER_TIMEOUT", 120),
+
+		ECSAPIRPS: getEnvFloat64("ECS_API_RPS", aliyun.DefaultECSAPIRPS),
 
 		// Health check settings
 		HealthCheckEnabled:  getEnvBool("HEALTH_CHECK_ENABLED", true),
 		HealthCheckTimeout:  getEnvInt("HEALTH_CHECK_TIMEOUT", 300),
 		HealthCheckInterval: getEnvInt("HEALTH_CHECK_INTERVAL", 10),
+		HealthCheckSSH:      getEnvBool("HEALTH_CHECK_SSH", false),
+		MinUptimeSeconds:    getEnvInt("MIN_UPTIME_SECONDS", 0),
+
+		CloudMonitorAlertCPU:    getEnvFloat64("CLOUDMONITOR_ALERT_CPU", 95),
+		CloudMonitorAlertMemory: getEnvFloat64("CLOUDMONITOR_ALERT_MEMORY", 90),
+		CloudMonitorAlertDisk:   getEnvFloat64("CLOUDMONITOR_ALERT_DISK", 85),
 
 		// Traffic auto-shutdown settings
 		TrafficShutdownEnabled: getEnvBool("TRAFFIC_SHUTDOWN_ENABLED", true),
 		TrafficLimitChinaGB:    getEnvFloat64("TRAFFIC_LIMIT_CHINA_GB", 19),
 		TrafficLimitNonChinaGB: getEnvFloat64("TRAFFIC_LIMIT_NON_CHINA_GB", 195),
 		TrafficCheckInterval:   getEnvInt("TRAFFIC_CHECK_INTERVAL", 300),
+		PostResetRestartDelay:  getEnvInt("POST_RESET_RESTART_DELAY_SECONDS", 60),
+
+		// CBWP settings
+		CBWPAutoUnbindOnShutdown: getEnvBool("CBWP_AUTO_UNBIND_ON_SHUTDOWN", false),
+		AutoSnapshotBeforeStop:   getEnvBool("AUTO_SNAPSHOT_BEFORE_STOP", false),
+		EIPQuotaWarnPercent:      getEnvFloat64("EIP_QUOTA_WARN_PERCENT", 0.8),
+		BandwidthWarnPercent:     getEnvFloat64("BANDWIDTH_WARN_PERCENT", 0.8),
+		BandwidthCheckInterval:   getEnvInt("BANDWIDTH_CHECK_INTERVAL", 300),
+		DefaultEIPBandwidth:      getEnvInt("ALIYUN_DEFAULT_EIP_BANDWIDTH", 100),
+
+		// State persistence
+		StateFile: os.Getenv("STATE_FILE"),
+		DBPath:    getEnvStringAllowEmpty("DB_PATH", "./state.db"),
+
+		// Probe server
+		HealthAddr: getEnvString("HEALTH_ADDR", ":8080"),
+
+		// Prometheus metrics
+		MetricsEnabled: getEnvBool("METRICS_ENABLED", true),
+		MetricsAddr:    getEnvString("METRICS_ADDR", ":9090"),
+
+		// gRPC API
+		GRPCEnabled: getEnvBool("GRPC_ENABLED", false),
+		GRPCAddr:    getEnvString("GRPC_ADDR", ":9091"),
+		GRPCTLSCert: os.Getenv("GRPC_TLS_CERT"),
+		GRPCTLSKey:  os.Getenv("GRPC_TLS_KEY"),
+		GRPCToken:   os.Getenv("GRPC_TOKEN"),
+
+		// OpenTelemetry tracing
+		OTLPEndpoint: os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
 
 		// Logging
-		LogLevel: getEnvString("LOG_LEVEL", "info"),
-		LogFile:  os.Getenv("LOG_FILE"),
+		LogLevel:      getEnvString("LOG_LEVEL", "info"),
+		LogFormat:     strings.ToLower(getEnvString("LOG_FORMAT", "text")),
+		LogFile:       os.Getenv("LOG_FILE"),
+		LogMaxSizeMB:  getEnvInt("LOG_MAX_SIZE_MB", 100),
+		LogMaxBackups: getEnvInt("LOG_MAX_BACKUPS", 3),
+		LogMaxAgeDays: getEnvInt("LOG_MAX_AGE_DAYS", 28),
+		LogBufferSize: getEnvInt("LOG_BUFFER_SIZE", 200),
+
+		// Time zone
+		Timezone: os.Getenv("TIMEZONE"),
+	}
+
+	cfg.Location = time.Local
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TIMEZONE %q: %w", cfg.Timezone, err)
+		}
+		cfg.Location = loc
+	}
+
+	// Parse traffic warning thresholds
+	warnPercents, err := parsePercentList(getEnvString("TRAFFIC_WARN_PERCENT", "50,80,90"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRAFFIC_WARN_PERCENT: %w", err)
+	}
+	cfg.TrafficWarnPercents = warnPercents
+
+	if cfg.LogFormat != "text" && cfg.LogFormat != "json" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q: must be \"text\" or \"json\"", cfg.LogFormat)
+	}
+	if cfg.LogMaxSizeMB <= 0 || cfg.LogMaxBackups < 0 || cfg.LogMaxAgeDays < 0 {
+		return nil, fmt.Errorf("invalid log rotation settings: LOG_MAX_SIZE_MB must be positive, LOG_MAX_BACKUPS and LOG_MAX_AGE_DAYS must not be negative")
+	}
+
+	for name, v := range map[string]float64{
+		"CLOUDMONITOR_ALERT_CPU":    cfg.CloudMonitorAlertCPU,
+		"CLOUDMONITOR_ALERT_MEMORY": cfg.CloudMonitorAlertMemory,
+		"CLOUDMONITOR_ALERT_DISK":   cfg.CloudMonitorAlertDisk,
+	} {
+		if v < 0 || v > 100 {
+			return nil, fmt.Errorf("invalid %s %g: must be between 0 and 100", name, v)
+		}
+	}
+
+	if cfg.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT %d: must be positive", cfg.ShutdownTimeout)
+	}
+
+	if cfg.ECSAPIRPS <= 0 {
+		return nil, fmt.Errorf("invalid ECS_API_RPS %g: must be positive", cfg.ECSAPIRPS)
+	}
+
+	if cfg.PostResetRestartDelay < 0 {
+		return nil, fmt.Errorf("invalid POST_RESET_RESTART_DELAY_SECONDS %d: must not be negative", cfg.PostResetRestartDelay)
+	}
+
+	if cfg.ReclaimWarnMinutes < 0 {
+		return nil, fmt.Errorf("invalid RECLAIM_WARN_MINUTES %d: must not be negative", cfg.ReclaimWarnMinutes)
+	}
+
+	if cfg.MinUptimeSeconds < 0 {
+		return nil, fmt.Errorf("invalid MIN_UPTIME_SECONDS %d: must not be negative", cfg.MinUptimeSeconds)
+	}
+
+	if cfg.BillingCacheTTL < 0 {
+		return nil, fmt.Errorf("invalid BILLING_CACHE_TTL %d: must not be negative", cfg.BillingCacheTTL)
+	}
+
+	if cfg.MonthlyBudgetCNY < 0 {
+		return nil, fmt.Errorf("invalid MONTHLY_BUDGET_CNY %.2f: must be non-negative", cfg.MonthlyBudgetCNY)
+	}
+	if cfg.MonthlyBudgetWarnPercent < 0 || cfg.MonthlyBudgetWarnPercent > 1 {
+		return nil, fmt.Errorf("invalid MONTHLY_BUDGET_WARN_PERCENT %.2f: must be between 0 and 1", cfg.MonthlyBudgetWarnPercent)
+	}
+	if cfg.EIPQuotaWarnPercent < 0 || cfg.EIPQuotaWarnPercent > 1 {
+		return nil, fmt.Errorf("invalid EIP_QUOTA_WARN_PERCENT %.2f: must be between 0 and 1", cfg.EIPQuotaWarnPercent)
+	}
+
+	if cfg.BandwidthWarnPercent < 0 || cfg.BandwidthWarnPercent > 1 {
+		return nil, fmt.Errorf("invalid BANDWIDTH_WARN_PERCENT %.2f: must be between 0 and 1", cfg.BandwidthWarnPercent)
+	}
+	if cfg.BandwidthCheckInterval <= 0 {
+		return nil, fmt.Errorf("invalid BANDWIDTH_CHECK_INTERVAL %d: must be positive", cfg.BandwidthCheckInterval)
+	}
+	// Pay-by-traffic EIPs allow 1-200 Mbps
+	if cfg.DefaultEIPBandwidth < 1 || cfg.DefaultEIPBandwidth > 200 {
+		return nil, fmt.Errorf("invalid ALIYUN_DEFAULT_EIP_BANDWIDTH %d: must be between 1 and 200", cfg.DefaultEIPBandwidth)
+	}
+
+	// Parse per-region traffic limits, falling back to the China / non-China limits
+	trafficLimits, err := parseTrafficLimits(os.Getenv("TRAFFIC_LIMITS"), cfg.TrafficLimitChinaGB, cfg.TrafficLimitNonChinaGB)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TrafficLimits = trafficLimits
+	cfg.TrafficLimitChinaGB = trafficLimits[aliyun.TrafficScopeChina]
+	cfg.TrafficLimitNonChinaGB = trafficLimits[aliyun.TrafficScopeNonChina]
+
+	// Parse EIP auto-bind targets
+	autoBind, err := parseAutoBindBWP(os.Getenv("AUTO_BIND_BWP"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.AutoBindBWP = autoBind
+
+	// Parse post-start webhooks
+	cfg.AllowHTTPWebhooks = getEnvBool("ALLOW_HTTP_WEBHOOKS", false)
+	webhooks, err := parseInstanceWebhooks(os.Getenv("INSTANCE_WEBHOOKS"), cfg.AllowHTTPWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	cfg.InstanceWebhooks = webhooks
+
+	// Parse post-start HTTP health checks
+	healthURLs, err := parseInstanceHealthURLs(os.Getenv("INSTANCE_HEALTH_URLS"))
+	if err != nil {
+		return nil, err
 	}
+	cfg.InstanceHealthURLs = healthURLs
 
-	// Generate cron schedule from check interval
-	cfg.CronSchedule = fmt.Sprintf("@every %ds", cfg.CheckInterval)
+	// Parse expected security groups for /sgroups
+	expectedGroups, err := parseExpectedSecurityGroups(os.Getenv("EXPECTED_SECURITY_GROUPS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.ExpectedSecurityGroups = expectedGroups
+
+	// Parse maintenance windows
+	windows, err := parseMaintenanceWindows(os.Getenv("MAINTENANCE_WINDOWS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaintenanceWindows = windows
+
+	// Parse per-instance overrides
+	overrides, err := parseInstanceOverrides(os.Getenv("INSTANCE_OVERRIDES"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.InstanceOverrides = overrides
+
+	// Parse start priorities
+	priority, err := parseInstancePriority(os.Getenv("INSTANCE_PRIORITY"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.InstancePriority = priority
+	cfg.InstancePriorityParallel = getEnvBool("INSTANCE_PRIORITY_PARALLEL", false)
+
+	// Generate cron schedule from the shortest check interval; instances with
+	// longer intervals are skipped on ticks where they are not yet due
+	cfg.CronSchedule = fmt.Sprintf("@every %ds", cfg.MinCheckInterval())
 
 	// Parse GCP zones
 	if zonesStr := os.Getenv("GCP_ZONES"); zonesStr != "" {
@@ -111,17 +551,112 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// Parse instance tag filter
+	tags, err := parseTagFilter(os.Getenv("INSTANCE_FILTER_TAGS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.InstanceFilterTags = tags
+
+	// Parse instance name allow / deny patterns
+	if cfg.InstanceNamePatterns, err = parseNamePatterns("INSTANCE_NAME_PATTERNS", os.Getenv("INSTANCE_NAME_PATTERNS")); err != nil {
+		return nil, err
+	}
+	if cfg.InstanceNameDenyPatterns, err = parseNamePatterns("INSTANCE_NAME_DENY_PATTERNS", os.Getenv("INSTANCE_NAME_DENY_PATTERNS")); err != nil {
+		return nil, err
+	}
+
+	// Parse instance type allow / deny prefixes
+	cfg.InstanceTypeAllowlist = parseList(os.Getenv("INSTANCE_TYPE_ALLOWLIST"))
+	cfg.InstanceTypeDenylist = parseList(os.Getenv("INSTANCE_TYPE_DENYLIST"))
+
+	cfg.ResourceGroupID = strings.TrimSpace(os.Getenv("RESOURCE_GROUP_ID"))
+
+	// Parse VPC filter
+	cfg.InstanceVPCFilter = parseList(os.Getenv("INSTANCE_VPC_FILTER"))
+	for _, vpcID := range cfg.InstanceVPCFilter {
+		if !strings.HasPrefix(vpcID, "vpc-") {
+			return nil, fmt.Errorf("invalid INSTANCE_VPC_FILTER entry %q: must be a VPC ID (vpc-...)", vpcID)
+		}
+	}
+
+	// Parse security group filter
+	cfg.InstanceSGFilter = parseList(os.Getenv("INSTANCE_SG_FILTER"))
+	for _, sgID := range cfg.InstanceSGFilter {
+		if !strings.HasPrefix(sgID, "sg-") {
+			return nil, fmt.Errorf("invalid INSTANCE_SG_FILTER entry %q: must be a security group ID (sg-...)", sgID)
+		}
+	}
+
+	parseMode, err := format.ParseParseMode(getEnvString("TELEGRAM_PARSE_MODE", string(format.ParseModeHTML)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TELEGRAM_PARSE_MODE %q: %w", os.Getenv("TELEGRAM_PARSE_MODE"), err)
+	}
+	cfg.TelegramParseMode = parseMode
+	cfg.BotLanguage = getEnvString("BOT_LANGUAGE", "zh-CN")
+
 	// Parse Aliyun accounts (comma-separated, one-to-one correspondence)
-	cfg.AliyunAccounts = parseAliyunAccounts()
+	cfg.AliyunAuthMode = strings.ToLower(getEnvString("ALIYUN_AUTH_MODE", "key"))
+	switch cfg.AliyunAuthMode {
+	case "key":
+		if accountsJSON := os.Getenv("ALIYUN_ACCOUNTS"); strings.TrimSpace(accountsJSON) != "" {
+			accounts, err := parseAliyunAccountsJSON(accountsJSON)
+			if err != nil {
+				return nil, err
+			}
+			cfg.AliyunAccounts = accounts
+		} else {
+			cfg.AliyunAccounts = parseAliyunAccounts()
+		}
+	case "ram_role":
+		cfg.AliyunAccounts = parseAliyunRAMRoleAccount()
+	default:
+		return nil, fmt.Errorf("invalid ALIYUN_AUTH_MODE %q: must be \"key\" or \"ram_role\"", cfg.AliyunAuthMode)
+	}
 
-	// Validate required fields - Aliyun is optional when GCP is enabled
-	if !cfg.GCPEnabled {
+	// Parse AWS settings
+	cfg.AWSAccessKeyID = strings.TrimSpace(os.Getenv("AWS_ACCESS_KEY_ID"))
+	cfg.AWSSecretAccessKey = strings.TrimSpace(os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	cfg.AWSRegions = parseList(os.Getenv("AWS_REGIONS"))
+	cfg.AWSEnabled = cfg.AWSAccessKeyID != ""
+	if cfg.AWSEnabled {
+		if cfg.AWSSecretAccessKey == "" {
+			return nil, fmt.Errorf("AWS_SECRET_ACCESS_KEY is required when AWS_ACCESS_KEY_ID is set")
+		}
+		if len(cfg.AWSRegions) == 0 {
+			return nil, fmt.Errorf("AWS_REGIONS is required when AWS_ACCESS_KEY_ID is set")
+		}
+	}
+
+	// Validate required fields - Aliyun is optional when GCP or AWS is enabled
+	if !cfg.GCPEnabled && !cfg.AWSEnabled {
 		if len(cfg.AliyunAccounts) == 0 {
-			return nil, fmt.Errorf("ALIYUN_ACCESS_KEY_ID and ALIYUN_ACCESS_KEY_SECRET are required (support comma-separated multiple accounts)")
+			if cfg.AliyunAuthMode == "ram_role" {
+				return nil, fmt.Errorf("ALIYUN_RAM_ROLE_NAME is required when ALIYUN_AUTH_MODE=ram_role")
+			}
+			return nil, fmt.Errorf("ALIYUN_ACCESS_KEY_ID and ALIYUN_ACCESS_KEY_SECRET (comma-separated for multiple accounts) or ALIYUN_ACCOUNTS are required")
+		}
+	}
+	if cfg.GCPEnabled && cfg.GCPProjectID == "" {
+		return nil, fmt.Errorf("GCP_PROJECT_ID is required when GCP is enabled")
+	}
+
+	if (cfg.GCPBigQueryDataset == "") != (cfg.GCPBigQueryTable == "") {
+		return nil, fmt.Errorf("GCP_BIGQUERY_DATASET and GCP_BIGQUERY_TABLE must be set together")
+	}
+	if cfg.GCPBigQueryDataset != "" {
+		if cfg.GCPBigQueryProject == "" {
+			cfg.GCPBigQueryProject = cfg.GCPProjectID
+		}
+		// The table name is part of the query text, so only plain identifiers are accepted
+		if !bigQueryProjectPattern.MatchString(cfg.GCPBigQueryProject) {
+			return nil, fmt.Errorf("invalid GCP_BIGQUERY_PROJECT %q", cfg.GCPBigQueryProject)
+		}
+		if !bigQueryNamePattern.MatchString(cfg.GCPBigQueryDataset) {
+			return nil, fmt.Errorf("invalid GCP_BIGQUERY_DATASET %q: only letters, digits and underscores are allowed", cfg.GCPBigQueryDataset)
 		}
-	} else {
-		if cfg.GCPProjectID == "" {
-			return nil, fmt.Errorf("GCP_PROJECT_ID is required when GCP is enabled")
+		if !bigQueryNamePattern.MatchString(cfg.GCPBigQueryTable) {
+			return nil, fmt.Errorf("invalid GCP_BIGQUERY_TABLE %q: only letters, digits and underscores are allowed", cfg.GCPBigQueryTable)
 		}
 	}
 
@@ -129,11 +664,41 @@ func Load() (*Config, error) {
 		if cfg.TelegramBotToken == "" {
 			return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required when Telegram is enabled")
 		}
-		if cfg.TelegramChatID == "" {
-			return nil, fmt.Errorf("TELEGRAM_CHAT_ID is required when Telegram is enabled")
+		if len(cfg.TelegramChatIDs) == 0 {
+			return nil, fmt.Errorf("TELEGRAM_CHAT_IDS (or TELEGRAM_CHAT_ID) is required when Telegram is enabled")
+		}
+		if cfg.TelegramWebhookURL != "" && !strings.HasPrefix(cfg.TelegramWebhookURL, "https://") {
+			return nil, fmt.Errorf("TELEGRAM_WEBHOOK_URL must be an https:// URL")
+		}
+	}
+
+	if cfg.GRPCEnabled && (cfg.GRPCTLSCert == "") != (cfg.GRPCTLSKey == "") {
+		return nil, fmt.Errorf("GRPC_TLS_CERT and GRPC_TLS_KEY must be set together")
+	}
+
+	if cfg.WebhookSecret != "" {
+		if (cfg.WebhookTLSCert == "") != (cfg.WebhookTLSKey == "") {
+			return nil, fmt.Errorf("WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+		}
+		if cfg.TelegramEnabled && cfg.TelegramWebhookURL != "" && cfg.WebhookAddr == fmt.Sprintf(":%d", cfg.TelegramWebhookPort) {
+			return nil, fmt.Errorf("WEBHOOK_ADDR %s is already used by the Telegram webhook (TELEGRAM_WEBHOOK_PORT)", cfg.WebhookAddr)
 		}
 	}
 
+	if cfg.DiscordWebhookURL != "" && !strings.HasPrefix(cfg.DiscordWebhookURL, "https://") {
+		return nil, fmt.Errorf("DISCORD_WEBHOOK_URL must be an https:// URL")
+	}
+	if cfg.SlackWebhookURL != "" && !strings.HasPrefix(cfg.SlackWebhookURL, "https://") {
+		return nil, fmt.Errorf("SLACK_WEBHOOK_URL must be an https:// URL")
+	}
+	if cfg.DingTalkWebhookURL != "" && !strings.HasPrefix(cfg.DingTalkWebhookURL, "https://") {
+		return nil, fmt.Errorf("DINGTALK_WEBHOOK_URL must be an https:// URL")
+	}
+	// The key is the only part of the webhook URL that varies; a full URL is a common mistake
+	if strings.Contains(cfg.WeChatWorkKey, "/") || strings.Contains(cfg.WeChatWorkKey, "=") {
+		return nil, fmt.Errorf("WECHATWORK_KEY must be the bot key, not the full webhook URL")
+	}
+
 	return cfg, nil
 }
 
@@ -189,6 +754,451 @@ func parseAliyunAccounts() []AliyunAccount {
 	return accounts
 }
 
+// aliyunAccountJSON is an entry of ALIYUN_ACCOUNTS
+type aliyunAccountJSON struct {
+	Name            string `json:"name"`
+	AccessKeyID     string `json:"access_key_id"`
+	AccessKeySecret string `json:"access_key_secret"`
+}
+
+// parseAliyunAccountsJSON parses ALIYUN_ACCOUNTS, a JSON array of accounts. It takes precedence
+// over the comma-separated ALIYUN_ACCESS_KEY_ID / ALIYUN_ACCESS_KEY_SECRET / ALIYUN_ACCOUNT_LABELS:
+// ALIYUN_ACCOUNTS=[{"name":"prod","access_key_id":"LTAI...","access_key_secret":"..."}]
+func parseAliyunAccountsJSON(s string) ([]AliyunAccount, error) {
+	var entries []aliyunAccountJSON
+	if err := json.Unmarshal([]byte(s), &entries); err != nil {
+		return nil, fmt.Errorf("invalid ALIYUN_ACCOUNTS JSON: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	accounts := make([]AliyunAccount, 0, len(entries))
+	for i, e := range entries {
+		e.Name = strings.TrimSpace(e.Name)
+		if strings.TrimSpace(e.AccessKeyID) == "" || strings.TrimSpace(e.AccessKeySecret) == "" {
+			return nil, fmt.Errorf("invalid ALIYUN_ACCOUNTS entry %d: access_key_id and access_key_secret are required", i+1)
+		}
+		if e.Name == "" {
+			e.Name = fmt.Sprintf("账号%d", i+1)
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("invalid ALIYUN_ACCOUNTS entry %d: duplicate name %q", i+1, e.Name)
+		}
+		seen[e.Name] = true
+
+		accounts = append(accounts, AliyunAccount{
+			Label:           e.Name,
+			AccessKeyID:     strings.TrimSpace(e.AccessKeyID),
+			AccessKeySecret: strings.TrimSpace(e.AccessKeySecret),
+		})
+	}
+
+	// When there's only one account, use empty label (no need to distinguish)
+	if len(accounts) == 1 {
+		accounts[0].Label = ""
+	}
+
+	return accounts, nil
+}
+
+// parseChatIDs reads the authorized Telegram chats. TELEGRAM_CHAT_IDS (comma-separated)
+// takes precedence over the single TELEGRAM_CHAT_ID.
+func parseChatIDs() []string {
+	raw := os.Getenv("TELEGRAM_CHAT_IDS")
+	if strings.TrimSpace(raw) == "" {
+		raw = os.Getenv("TELEGRAM_CHAT_ID")
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range splitAndTrim(raw, ",") {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// parseAliyunRAMRoleAccount builds the single account of ram_role auth mode.
+// Credentials are fetched from the ECS metadata service using the role attached to
+// the instance the monitor runs on, so only one account is possible.
+// ALIYUN_RAM_ROLE_NAME=spot-manager-role
+func parseAliyunRAMRoleAccount() []AliyunAccount {
+	roleName := strings.TrimSpace(os.Getenv("ALIYUN_RAM_ROLE_NAME"))
+	if roleName == "" {
+		return nil
+	}
+
+	// Single account, no label needed to distinguish it
+	return []AliyunAccount{{RAMRoleName: roleName}}
+}
+
+// Secrets returns all configured credentials, for redaction from logs shown in Telegram
+func (c *Config) Secrets() []string {
+	secrets := []string{c.TelegramBotToken, c.TelegramWebhookSecret, c.DiscordWebhookURL, c.SlackWebhookURL,
+		c.DingTalkWebhookURL, c.DingTalkSecret, c.WeChatWorkKey, c.WebhookSecret, c.GRPCToken,
+		c.AWSAccessKeyID, c.AWSSecretAccessKey}
+	for _, acc := range c.AliyunAccounts {
+		secrets = append(secrets, acc.AccessKeyID, acc.AccessKeySecret)
+	}
+	return append(secrets, gcpCredentialSecrets(c.GCPCredentialsJSON)...)
+}
+
+// gcpPrivateKeyPattern matches the private_key field of a service account JSON, which
+// loadGCPCredentials may have turned into invalid JSON by unescaping its newlines
+var gcpPrivateKeyPattern = regexp.MustCompile(`"private_key"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+// gcpCredentialSecrets returns the service account JSON and its private key. A log line may
+// show the key escaped or only part of it, so the escaped key and each PEM line are added too.
+func gcpCredentialSecrets(credentialsJSON string) []string {
+	if credentialsJSON == "" {
+		return nil
+	}
+	secrets := []string{credentialsJSON}
+	match := gcpPrivateKeyPattern.FindStringSubmatch(credentialsJSON)
+	if match == nil {
+		return secrets
+	}
+	key := strings.ReplaceAll(match[1], `\n`, "\n")
+	secrets = append(secrets, key, strings.ReplaceAll(key, "\n", `\n`))
+	for _, line := range strings.Split(key, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "-----") {
+			secrets = append(secrets, line)
+		}
+	}
+	return secrets
+}
+
+// redactedValue replaces credentials in Sanitized
+const redactedValue = "***"
+
+// Sanitized returns a copy of the configuration with all credentials replaced by "***",
+// safe to share outside the deployment. Unset credentials stay empty.
+func (c *Config) Sanitized() *Config {
+	s := *c
+	s.AliyunAccounts = make([]AliyunAccount, len(c.AliyunAccounts))
+	for i, acc := range c.AliyunAccounts {
+		acc.AccessKeySecret = redact(acc.AccessKeySecret)
+		s.AliyunAccounts[i] = acc
+	}
+	s.GCPCredentialsJSON = redact(c.GCPCredentialsJSON)
+	s.AWSSecretAccessKey = redact(c.AWSSecretAccessKey)
+	s.TelegramBotToken = redact(c.TelegramBotToken)
+	s.TelegramWebhookSecret = redact(c.TelegramWebhookSecret)
+	s.DiscordWebhookURL = redact(c.DiscordWebhookURL)
+	s.SlackWebhookURL = redact(c.SlackWebhookURL)
+	s.DingTalkWebhookURL = redact(c.DingTalkWebhookURL)
+	s.DingTalkSecret = redact(c.DingTalkSecret)
+	s.WeChatWorkKey = redact(c.WeChatWorkKey)
+	s.WebhookSecret = redact(c.WebhookSecret)
+	s.GRPCToken = redact(c.GRPCToken)
+	return &s
+}
+
+// redact returns redactedValue for a set credential
+func redact(v string) string {
+	if v == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// MinCheckInterval returns the shortest check interval across global and per-instance settings
+func (c *Config) MinCheckInterval() int {
+	minInterval := c.CheckInterval
+	for _, o := range c.InstanceOverrides {
+		if o.CheckInterval > 0 && o.CheckInterval < minInterval {
+			minInterval = o.CheckInterval
+		}
+	}
+	return minInterval
+}
+
+// parseInstanceOverrides parses INSTANCE_OVERRIDES, a JSON object keyed by instance ID:
+// INSTANCE_OVERRIDES={"i-xxx":{"check_interval":30,"retry_count":5},"i-yyy":{"check_interval":300,"ssh_port":2222}}
+func parseInstanceOverrides(s string) (map[string]InstanceOverride, error) {
+	overrides := make(map[string]InstanceOverride)
+	if strings.TrimSpace(s) == "" {
+		return overrides, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("invalid INSTANCE_OVERRIDES JSON: %w", err)
+	}
+
+	for id, data := range raw {
+		var o InstanceOverride
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&o); err != nil {
+			return nil, fmt.Errorf("invalid INSTANCE_OVERRIDES entry for %s: %w", id, err)
+		}
+		if o.CheckInterval < 0 || o.RetryCount < 0 || o.RetryInterval < 0 || o.SSHPort < 0 {
+			return nil, fmt.Errorf("invalid INSTANCE_OVERRIDES entry for %s: values must not be negative", id)
+		}
+		if o.SSHPort > 65535 {
+			return nil, fmt.Errorf("invalid INSTANCE_OVERRIDES entry for %s: ssh_port %d is out of range", id, o.SSHPort)
+		}
+		overrides[id] = o
+	}
+
+	return overrides, nil
+}
+
+// DefaultInstancePriority is the start priority of instances missing from INSTANCE_PRIORITY
+const DefaultInstancePriority = 100
+
+// PriorityOf returns the start priority of an instance, lower starts first
+func (c *Config) PriorityOf(instanceID string) int {
+	if p, ok := c.InstancePriority[instanceID]; ok {
+		return p
+	}
+	return DefaultInstancePriority
+}
+
+// parseInstancePriority parses INSTANCE_PRIORITY, a JSON object mapping instance IDs to their
+// start priority: INSTANCE_PRIORITY={"i-xxx":1,"i-yyy":2,"i-zzz":10}
+func parseInstancePriority(s string) (map[string]int, error) {
+	priority := make(map[string]int)
+	if strings.TrimSpace(s) == "" {
+		return priority, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &priority); err != nil {
+		return nil, fmt.Errorf("invalid INSTANCE_PRIORITY JSON: %w", err)
+	}
+
+	return priority, nil
+}
+
+// parseAutoBindBWP parses AUTO_BIND_BWP, a JSON object mapping instance IDs to bandwidth packages:
+// AUTO_BIND_BWP={"i-xxx":"cbwp-yyy","i-zzz":"cbwp-yyy"}
+func parseAutoBindBWP(s string) (map[string]string, error) {
+	bindings := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return bindings, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &bindings); err != nil {
+		return nil, fmt.Errorf("invalid AUTO_BIND_BWP JSON: %w", err)
+	}
+	for id, bwpID := range bindings {
+		if strings.TrimSpace(bwpID) == "" {
+			return nil, fmt.Errorf("invalid AUTO_BIND_BWP entry for %s: bandwidth package ID is empty", id)
+		}
+	}
+
+	return bindings, nil
+}
+
+// parseInstanceWebhooks parses INSTANCE_WEBHOOKS, a JSON object mapping instance IDs to the
+// URL notified after the instance was started. URLs must use https unless allowHTTP is set:
+// INSTANCE_WEBHOOKS={"i-xxx":"https://my-api.example.com/instance-started"}
+func parseInstanceWebhooks(s string, allowHTTP bool) (map[string]string, error) {
+	webhooks := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return webhooks, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &webhooks); err != nil {
+		return nil, fmt.Errorf("invalid INSTANCE_WEBHOOKS JSON: %w", err)
+	}
+	for id, raw := range webhooks {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid INSTANCE_WEBHOOKS URL for %s: %q", id, raw)
+		}
+		switch {
+		case u.Scheme == "https":
+		case u.Scheme == "http" && allowHTTP:
+		case u.Scheme == "http":
+			return nil, fmt.Errorf("invalid INSTANCE_WEBHOOKS URL for %s: http is not allowed (use https or set ALLOW_HTTP_WEBHOOKS=true)", id)
+		default:
+			return nil, fmt.Errorf("invalid INSTANCE_WEBHOOKS URL for %s: unsupported scheme %q", id, u.Scheme)
+		}
+	}
+
+	return webhooks, nil
+}
+
+// parseInstanceHealthURLs parses INSTANCE_HEALTH_URLS, a JSON object mapping instance IDs to the
+// URL checked after the instance was started. {ip} is replaced with the public IP at check time:
+// INSTANCE_HEALTH_URLS={"i-xxx":"http://{ip}:8080/health","i-yyy":"https://{ip}/ready"}
+func parseInstanceHealthURLs(s string) (map[string]string, error) {
+	healthURLs := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return healthURLs, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &healthURLs); err != nil {
+		return nil, fmt.Errorf("invalid INSTANCE_HEALTH_URLS JSON: %w", err)
+	}
+	for id, raw := range healthURLs {
+		u, err := url.Parse(strings.ReplaceAll(raw, "{ip}", "127.0.0.1"))
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid INSTANCE_HEALTH_URLS URL for %s: %q", id, raw)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid INSTANCE_HEALTH_URLS URL for %s: unsupported scheme %q", id, u.Scheme)
+		}
+	}
+
+	return healthURLs, nil
+}
+
+// parseExpectedSecurityGroups parses EXPECTED_SECURITY_GROUPS, a JSON object mapping instance IDs
+// to the security group IDs they should have attached:
+// EXPECTED_SECURITY_GROUPS={"i-xxx":["sg-yyy","sg-zzz"]}
+func parseExpectedSecurityGroups(s string) (map[string][]string, error) {
+	expected := make(map[string][]string)
+	if strings.TrimSpace(s) == "" {
+		return expected, nil
+	}
+
+	if err := json.Unmarshal([]byte(s), &expected); err != nil {
+		return nil, fmt.Errorf("invalid EXPECTED_SECURITY_GROUPS JSON: %w", err)
+	}
+	for id, groups := range expected {
+		for _, sg := range groups {
+			if strings.TrimSpace(sg) == "" {
+				return nil, fmt.Errorf("invalid EXPECTED_SECURITY_GROUPS entry for %s: empty security group ID", id)
+			}
+		}
+	}
+
+	return expected, nil
+}
+
+// parseMaintenanceWindows parses MAINTENANCE_WINDOWS, a JSON array of windows with a standard
+// 5-field cron expression for the window start:
+// MAINTENANCE_WINDOWS=[{"cron":"0 2 * * 0","duration_minutes":120,"instance_ids":["i-xxx"],"regions":["cn-hangzhou"]}]
+func parseMaintenanceWindows(s string) ([]MaintenanceWindow, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var windows []MaintenanceWindow
+	if err := json.Unmarshal([]byte(s), &windows); err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_WINDOWS JSON: %w", err)
+	}
+	for i, w := range windows {
+		if _, err := cron.ParseStandard(w.Cron); err != nil {
+			return nil, fmt.Errorf("invalid MAINTENANCE_WINDOWS entry %d: bad cron %q: %w", i+1, w.Cron, err)
+		}
+		if w.DurationMinutes <= 0 {
+			return nil, fmt.Errorf("invalid MAINTENANCE_WINDOWS entry %d: duration_minutes must be positive", i+1)
+		}
+	}
+
+	return windows, nil
+}
+
+// parseTrafficLimits parses TRAFFIC_LIMITS, a JSON object of limits in GB keyed by region ID.
+// global-china and global-non-china cover all regions without their own limit and default to
+// TRAFFIC_LIMIT_CHINA_GB / TRAFFIC_LIMIT_NON_CHINA_GB:
+// TRAFFIC_LIMITS={"cn-hangzhou":50,"ap-southeast-1":100,"global-non-china":195}
+func parseTrafficLimits(s string, chinaGB, nonChinaGB float64) (map[string]float64, error) {
+	limits := make(map[string]float64)
+	if strings.TrimSpace(s) != "" {
+		if err := json.Unmarshal([]byte(s), &limits); err != nil {
+			return nil, fmt.Errorf("invalid TRAFFIC_LIMITS JSON: %w", err)
+		}
+		for scope, limit := range limits {
+			if limit <= 0 {
+				return nil, fmt.Errorf("invalid TRAFFIC_LIMITS entry for %s: limit must be positive", scope)
+			}
+		}
+	}
+
+	if _, ok := limits[aliyun.TrafficScopeChina]; !ok {
+		limits[aliyun.TrafficScopeChina] = chinaGB
+	}
+	if _, ok := limits[aliyun.TrafficScopeNonChina]; !ok {
+		limits[aliyun.TrafficScopeNonChina] = nonChinaGB
+	}
+
+	return limits, nil
+}
+
+// parsePercentList parses a comma-separated list of ascending percentages in 1-99
+func parsePercentList(s string) ([]int, error) {
+	var percents []int
+	for _, part := range splitAndTrim(s, ",") {
+		if part == "" {
+			continue
+		}
+		p, err := strconv.Atoi(strings.TrimSuffix(part, "%"))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", part)
+		}
+		if p < 1 || p > 99 {
+			return nil, fmt.Errorf("%d is outside 1-99", p)
+		}
+		if len(percents) > 0 && p <= percents[len(percents)-1] {
+			return nil, fmt.Errorf("percentages must be in ascending order")
+		}
+		percents = append(percents, p)
+	}
+	return percents, nil
+}
+
+// parseTagFilter parses INSTANCE_FILTER_TAGS into a tag map.
+// INSTANCE_FILTER_TAGS=env:prod,team:infra
+func parseTagFilter(s string) (map[string]string, error) {
+	tags := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return tags, nil
+	}
+
+	for _, pair := range splitAndTrim(s, ",") {
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid INSTANCE_FILTER_TAGS entry %q, expected key:value", pair)
+		}
+		tags[key] = strings.TrimSpace(value)
+	}
+
+	// DescribeInstances accepts at most 20 tags
+	if len(tags) > 20 {
+		return nil, fmt.Errorf("INSTANCE_FILTER_TAGS supports at most 20 tags, got %d", len(tags))
+	}
+
+	return tags, nil
+}
+
+// parseNamePatterns parses a comma-separated list of instance name glob patterns, rejecting
+// patterns path.Match cannot evaluate.
+// INSTANCE_NAME_PATTERNS=spot-*,worker-*
+func parseNamePatterns(name, s string) ([]string, error) {
+	var patterns []string
+	for _, p := range splitAndTrim(s, ",") {
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", name, p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// parseList parses a comma-separated list, dropping empty entries
+func parseList(s string) []string {
+	var list []string
+	for _, item := range splitAndTrim(s, ",") {
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // splitAndTrim splits a string by separator and trims whitespace from each part
 func splitAndTrim(s, sep string) []string {
 	parts := strings.Split(s, sep)
@@ -206,6 +1216,14 @@ func getEnvString(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvStringAllowEmpty is like getEnvString, but an explicitly empty variable disables the default
+func getEnvStringAllowEmpty(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return strings.TrimSpace(value)
+	}
+	return defaultValue
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -233,6 +1251,12 @@ func getEnvFloat64(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+// bigQueryProjectPattern and bigQueryNamePattern match GCP project IDs and BigQuery dataset / table names
+var (
+	bigQueryProjectPattern = regexp.MustCompile(`^[a-z][a-z0-9:.-]*$`)
+	bigQueryNamePattern    = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
 // loadGCPCredentials loads GCP service account JSON from file (GCP_CREDENTIALS_FILE)
 // or falls back to the inline env var (GCP_CREDENTIALS_JSON), unescaping \n in the latter.
 // Using a file is strongly recommended when running under systemd, because
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"github.com/joho/godotenv"
+)
+
+// DotEnvFile is the .env file loaded from the working directory at startup and on reload
+const DotEnvFile = ".env"
+
+// LoadDotEnv loads a .env file into the process environment. Like the config file, variables
+// already set in the environment take precedence, and calling it again (on reload) picks up
+// edits to the file.
+func LoadDotEnv(path string) error {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return err
+	}
+	_, err = applyEnvValues(path, values)
+	return err
+}
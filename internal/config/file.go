@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is loaded from the working directory when CONFIG_FILE is not set
+const defaultConfigFile = "config.yaml"
+
+// appliedValues remembers the variables set from each source (.env, config file), so that a
+// reload updates them while variables set in the real environment keep precedence
+var appliedValues = make(map[string]map[string]string) // source -> variable -> value
+
+// applyEnvValues sets the variables of a source that are not set in the environment. Variables
+// the source set on a previous call, or that already held the source's value (e.g. a .env also
+// loaded by systemd's EnvironmentFile), are updated, or unset when removed from the source,
+// unless they were changed since. Returns the sorted variables overridden by the environment.
+func applyEnvValues(source string, values map[string]string) ([]string, error) {
+	previous := appliedValues[source]
+	applied := make(map[string]string, len(values))
+
+	var overridden []string
+	for key, value := range values {
+		if current, ok := os.LookupEnv(key); ok && current != value {
+			if prev, fromSource := previous[key]; !fromSource || current != prev {
+				overridden = append(overridden, key)
+				continue
+			}
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to apply %s from %s: %w", key, source, err)
+		}
+		applied[key] = value
+	}
+	for key, prev := range previous {
+		if _, ok := values[key]; !ok && os.Getenv(key) == prev {
+			os.Unsetenv(key)
+		}
+	}
+	appliedValues[source] = applied
+
+	sort.Strings(overridden)
+	return overridden, nil
+}
+
+// applyConfigFile loads the YAML config file named by CONFIG_FILE (or ./config.yaml if present)
+// into the process environment. Keys are the environment variable names in snake_case, e.g.
+// check_interval: 60 for CHECK_INTERVAL. Variables already set in the environment take precedence,
+// so secrets can still be injected via env vars. Returns the path of the loaded file (empty if none)
+// and the sorted file keys that were overridden by the environment.
+func applyConfigFile() (string, []string, error) {
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err != nil {
+			return "", nil, nil
+		}
+		path = defaultConfigFile
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	overridden, err := applyEnvValues("config file", values)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path, overridden, nil
+}
+
+// readConfigFile parses a YAML config file into environment variable names and values.
+// Lists become comma-separated values and mappings become JSON (for INSTANCE_OVERRIDES,
+// TRAFFIC_LIMITS and similar JSON-valued settings).
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if value == nil {
+			continue
+		}
+		s, err := configValueString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s in config file %s: %w", key, path, err)
+		}
+		values[strings.ToUpper(key)] = s
+	}
+
+	return values, nil
+}
+
+// configValueString converts a decoded YAML value into its environment variable form
+func configValueString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		// Lists of objects (e.g. maintenance_windows) are passed on as a JSON array
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				data, err := json.Marshal(v)
+				if err != nil {
+					return "", err
+				}
+				return string(data), nil
+			}
+		}
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := configValueString(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ","), nil
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", value)
+	}
+}
@@ -0,0 +1,42 @@
+// Package format renders values as text for chat messages.
+package format
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkBlocks are the bar heights of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// FormatSparkline renders the last width values (all values when width <= 0) as Unicode block
+// characters, so the last bar is the most recent value. Bars are scaled linearly between the
+// lowest non-zero value (▁) and the maximum (█); zero values are ▁ as well. When all non-zero
+// values are equal, or all values are zero, every bar is ▁.
+func FormatSparkline(values []float64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	// Zero days (e.g. a stopped instance) would otherwise lift every other bar off ▁
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if v != 0 {
+			lo = min(lo, v)
+		}
+		hi = max(hi, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo && v > lo {
+			idx = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		}
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return sb.String()
+}
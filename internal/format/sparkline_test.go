@@ -0,0 +1,29 @@
+package format
+
+import "testing"
+
+func TestFormatSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{name: "empty", values: nil, want: ""},
+		{name: "all zero", values: []float64{0, 0, 0}, want: "▁▁▁"},
+		{name: "all equal", values: []float64{5, 5}, want: "▁▁"},
+		{name: "min and max", values: []float64{1, 8}, want: "▁█"},
+		{name: "linear", values: []float64{1, 2, 3, 4, 5, 6, 7, 8}, want: "▁▂▃▄▅▆▇█"},
+		{name: "rounds to nearest bar", values: []float64{1, 15, 2.5}, want: "▁█▂"},
+		{name: "zero excluded from minimum", values: []float64{0, 10, 20, 80}, want: "▁▁▂█"},
+		{name: "width keeps the most recent", values: []float64{100, 1, 8}, width: 2, want: "▁█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("FormatSparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
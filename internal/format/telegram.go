@@ -0,0 +1,154 @@
+package format
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// ParseMode is the formatting of Telegram messages (TELEGRAM_PARSE_MODE)
+type ParseMode string
+
+const (
+	ParseModeHTML       ParseMode = "HTML"
+	ParseModeMarkdownV2 ParseMode = "MarkdownV2"
+	ParseModePlain      ParseMode = "plain"
+)
+
+// ParseParseMode parses a TELEGRAM_PARSE_MODE value, case-insensitively
+func ParseParseMode(s string) (ParseMode, error) {
+	for _, mode := range []ParseMode{ParseModeHTML, ParseModeMarkdownV2, ParseModePlain} {
+		if strings.EqualFold(s, string(mode)) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("must be HTML, MarkdownV2 or plain")
+}
+
+// APIValue returns the parse_mode of Telegram API requests, empty for plain text
+func (m ParseMode) APIValue() string {
+	if m == ParseModePlain {
+		return ""
+	}
+	return string(m)
+}
+
+// htmlTagPattern matches the tags of Telegram HTML; text between them is HTML-escaped, so a
+// literal < never starts a tag
+var htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z-]+)([^>]*)>`)
+
+// hrefPattern extracts the link target of an <a> tag
+var hrefPattern = regexp.MustCompile(`href="([^"]*)"`)
+
+// markdownV2Special are the characters MarkdownV2 requires to be escaped outside of code
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+// markdownV2Tags maps Telegram HTML tags to their MarkdownV2 delimiters
+var markdownV2Tags = map[string]string{
+	"b": "*", "strong": "*",
+	"i": "_", "em": "_",
+	"u": "__", "ins": "__",
+	"s": "~", "strike": "~", "del": "~",
+	"tg-spoiler": "||",
+}
+
+// ConvertHTML converts a message written in Telegram HTML to mode: HTML is returned
+// unchanged, plain drops the tags and unescapes entities, MarkdownV2 maps the tags to their
+// MarkdownV2 markup and escapes the special characters
+func ConvertHTML(message string, mode ParseMode) string {
+	switch mode {
+	case ParseModePlain:
+		return html.UnescapeString(htmlTagPattern.ReplaceAllString(message, ""))
+	case ParseModeMarkdownV2:
+		return htmlToMarkdownV2(message)
+	default:
+		return message
+	}
+}
+
+// EscapeMarkdownV2 escapes the MarkdownV2 special characters of plain text
+func EscapeMarkdownV2(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownV2Special, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// escapeMarkdownV2Code escapes text inside code and pre entities, where only ` and \ are special
+func escapeMarkdownV2Code(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(s)
+}
+
+// htmlToMarkdownV2 rewrites a Telegram HTML message as MarkdownV2
+func htmlToMarkdownV2(message string) string {
+	var sb strings.Builder
+	preDepth, codeDepth := 0, 0
+	var links []string // href of each open <a>
+
+	writeText := func(text string) {
+		text = html.UnescapeString(text)
+		if preDepth > 0 || codeDepth > 0 {
+			sb.WriteString(escapeMarkdownV2Code(text))
+		} else {
+			sb.WriteString(EscapeMarkdownV2(text))
+		}
+	}
+
+	last := 0
+	for _, loc := range htmlTagPattern.FindAllStringSubmatchIndex(message, -1) {
+		writeText(message[last:loc[0]])
+		last = loc[1]
+
+		closing := loc[3] > loc[2]
+		tag := strings.ToLower(message[loc[4]:loc[5]])
+		attrs := message[loc[6]:loc[7]]
+
+		switch tag {
+		case "pre":
+			if closing {
+				sb.WriteString("\n```")
+				preDepth--
+			} else {
+				sb.WriteString("```\n")
+				preDepth++
+			}
+		case "a":
+			if closing {
+				href := ""
+				if n := len(links); n > 0 {
+					href, links = links[n-1], links[:n-1]
+				}
+				sb.WriteString("](" + strings.NewReplacer("\\", "\\\\", ")", "\\)").Replace(href) + ")")
+			} else {
+				href := ""
+				if m := hrefPattern.FindStringSubmatch(attrs); m != nil {
+					href = html.UnescapeString(m[1])
+				}
+				links = append(links, href)
+				sb.WriteString("[")
+			}
+		case "code":
+			if closing {
+				codeDepth--
+			} else {
+				codeDepth++
+			}
+			// Code inside pre is already code
+			if preDepth == 0 {
+				sb.WriteString("`")
+			}
+		default:
+			// Unsupported tags (span, blockquote) keep only their text
+			if delim, ok := markdownV2Tags[tag]; ok {
+				sb.WriteString(delim)
+			}
+		}
+	}
+	writeText(message[last:])
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// ErrCostDataUnavailable is returned by clients that cannot query cost data
+var ErrCostDataUnavailable = errors.New("GCP cost data requires a BigQuery billing export")
+
+// ServiceCost is the cost of one GCP service over a period, after credits
+type ServiceCost struct {
+	Service  string
+	Cost     float64
+	Credits  float64 // credits (free trial, discounts) consumed, as a positive amount
+	Currency string
+}
+
+// CostSummaryQuerier queries the GCP cost of the last days grouped by service, highest first
+type CostSummaryQuerier interface {
+	QueryCostSummary(days int) ([]ServiceCost, error)
+}
+
+// QueryCostSummary implements CostSummaryQuerier. The Cloud Billing API exposes no cost data,
+// so it always returns ErrCostDataUnavailable; use GetBillingInfo for the linked account.
+func (c *ComputeClient) QueryCostSummary(days int) ([]ServiceCost, error) {
+	return nil, ErrCostDataUnavailable
+}
+
+// BigQueryBillingClient queries the Cloud Billing export table in BigQuery
+type BigQueryBillingClient struct {
+	client  *bigquery.Client
+	dataset string
+	table   string
+}
+
+// NewBigQueryBillingClient creates a client for the billing export table projectID.dataset.table.
+// credentialsJSON is the raw JSON content of a service account key; empty = use ADC
+func NewBigQueryBillingClient(projectID, dataset, table, credentialsJSON string) (*BigQueryBillingClient, error) {
+	var opts []option.ClientOption
+	if credentialsJSON != "" {
+		opts = append(opts, option.WithCredentialsJSON([]byte(credentialsJSON)))
+	}
+
+	client, err := bigquery.NewClient(context.Background(), projectID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP BigQuery client: %w", err)
+	}
+
+	return &BigQueryBillingClient{
+		client:  client,
+		dataset: dataset,
+		table:   table,
+	}, nil
+}
+
+// Close closes the underlying client
+func (c *BigQueryBillingClient) Close() {
+	if c.client != nil {
+		c.client.Close()
+	}
+}
+
+// QueryCostSummary implements CostSummaryQuerier using the billing export table
+func (c *BigQueryBillingClient) QueryCostSummary(days int) ([]ServiceCost, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// Credits (discounts, free tier) are negative amounts on each row
+	q := c.client.Query(fmt.Sprintf("SELECT service, currency, cost + credits AS total, -credits AS credits_used FROM ("+
+		"SELECT service.description AS service, currency, SUM(cost) AS cost, "+
+		"SUM(IFNULL((SELECT SUM(cr.amount) FROM UNNEST(credits) cr), 0)) AS credits "+
+		"FROM `%s.%s.%s` "+
+		"WHERE usage_start_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL @days DAY) "+
+		"GROUP BY service, currency) ORDER BY total DESC",
+		c.client.Project(), c.dataset, c.table))
+	q.Parameters = []bigquery.QueryParameter{{Name: "days", Value: days}}
+
+	it, err := q.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GCP billing export: %w", err)
+	}
+
+	var costs []ServiceCost
+	for {
+		var row struct {
+			Service     string  `bigquery:"service"`
+			Currency    string  `bigquery:"currency"`
+			Total       float64 `bigquery:"total"`
+			CreditsUsed float64 `bigquery:"credits_used"`
+		}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GCP billing export: %w", err)
+		}
+		costs = append(costs, ServiceCost{Service: row.Service, Cost: row.Total, Credits: row.CreditsUsed, Currency: row.Currency})
+	}
+
+	return costs, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	compute "cloud.google.com/go/compute/apiv1"
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/cloudbilling/v1"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	log "github.com/sirupsen/logrus"
@@ -31,7 +32,16 @@ type ComputeClient struct {
 	projectID   string
 	client      *compute.InstancesClient
 	zonesClient *compute.ZonesClient
+	opts        []option.ClientOption // credentials, reused by other GCP API clients
 	mu          sync.Mutex
+	dryRun      bool // log start/stop requests instead of sending them
+}
+
+// BillingInfo is the billing account linked to a GCP project
+type BillingInfo struct {
+	ProjectID          string
+	BillingAccountName string // billingAccounts/XXXXXX-XXXXXX-XXXXXX, empty if none is linked
+	BillingEnabled     bool
 }
 
 // NewComputeClient creates a new GCP Compute Engine client
@@ -59,6 +69,7 @@ func NewComputeClient(projectID, credentialsJSON string) (*ComputeClient, error)
 		projectID:   projectID,
 		client:      instancesClient,
 		zonesClient: zonesClient,
+		opts:        opts,
 	}, nil
 }
 
@@ -72,6 +83,32 @@ func (c *ComputeClient) Close() {
 	}
 }
 
+// GetBillingInfo returns the billing account of the configured project
+func (c *ComputeClient) GetBillingInfo() (*BillingInfo, error) {
+	if c.projectID == "" {
+		return nil, fmt.Errorf("GCP project ID not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	svc, err := cloudbilling.NewService(ctx, c.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP billing client: %w", err)
+	}
+
+	info, err := svc.Projects.GetBillingInfo("projects/" + c.projectID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billing info of project %s: %w", c.projectID, err)
+	}
+
+	return &BillingInfo{
+		ProjectID:          c.projectID,
+		BillingAccountName: info.BillingAccountName,
+		BillingEnabled:     info.BillingEnabled,
+	}, nil
+}
+
 // GetAllZones returns all available zones in the project
 func (c *ComputeClient) GetAllZones() ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -187,9 +224,20 @@ func (c *ComputeClient) GetInstanceStatus(zone, instanceName string) (string, er
 	return inst.GetStatus(), nil
 }
 
+// SetDryRun makes StartInstance and StopInstance log their intent and return nil
+// without calling the API. Read operations are unaffected.
+func (c *ComputeClient) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
 // StartInstance starts a stopped/terminated instance
-func (c *ComputeClient) StartInstance(zone, instanceName string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (c *ComputeClient) StartInstance(ctx context.Context, zone, instanceName string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would start GCP instance %s in zone %s", instanceName, zone)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := &computepb.StartInstanceRequest{
@@ -212,8 +260,13 @@ func (c *ComputeClient) StartInstance(zone, instanceName string) error {
 }
 
 // StopInstance stops a running instance
-func (c *ComputeClient) StopInstance(zone, instanceName string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (c *ComputeClient) StopInstance(ctx context.Context, zone, instanceName string) error {
+	if c.dryRun {
+		log.Warnf("[DRY RUN] Would stop GCP instance %s in zone %s", instanceName, zone)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := &computepb.StopInstanceRequest{
@@ -235,8 +288,8 @@ func (c *ComputeClient) StopInstance(zone, instanceName string) error {
 }
 
 // GetInstance returns detailed information about an instance
-func (c *ComputeClient) GetInstance(zone, instanceName string) (*PreemptibleInstance, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+func (c *ComputeClient) GetInstance(ctx context.Context, zone, instanceName string) (*PreemptibleInstance, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	req := &computepb.GetInstanceRequest{
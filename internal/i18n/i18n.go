@@ -0,0 +1,112 @@
+// Package i18n translates the user-facing text of Telegram notifications and bot replies.
+//
+// Messages are Printf templates keyed by name, loaded from the YAML catalogs embedded under
+// locales/. zh-CN is the source catalog: a key missing from another locale falls back to it.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported locales
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+)
+
+// fallbackLocale is used for unknown BOT_LANGUAGE values
+const fallbackLocale = EnUS
+
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+// Translator renders messages in one locale
+type Translator interface {
+	// T formats the template of key with args, returning key itself when no catalog has it
+	T(key string, args ...any) string
+	// Locale returns the locale messages are rendered in, e.g. "en-US"
+	Locale() string
+}
+
+// catalog is a Translator backed by the messages of one locale file
+type catalog struct {
+	locale   string
+	messages map[string]string
+	fallback *catalog // consulted for keys missing from messages, nil for the source catalog
+}
+
+// catalogs holds the embedded locales, parsed once at startup
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[string]*catalog {
+	source, err := loadCatalog(ZhCN)
+	if err != nil {
+		panic(err)
+	}
+	en, err := loadCatalog(EnUS)
+	if err != nil {
+		panic(err)
+	}
+	en.fallback = source
+	return map[string]*catalog{ZhCN: source, EnUS: en}
+}
+
+// loadCatalog parses locales/<locale>.yaml
+func loadCatalog(locale string) (*catalog, error) {
+	data, err := localeFS.ReadFile("locales/" + locale + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locale %s: %w", locale, err)
+	}
+	messages := make(map[string]string)
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse locale %s: %w", locale, err)
+	}
+	return &catalog{locale: locale, messages: messages}, nil
+}
+
+// T implements Translator
+func (c *catalog) T(key string, args ...any) string {
+	tmpl, ok := c.messages[key]
+	if !ok && c.fallback != nil {
+		tmpl, ok = c.fallback.messages[key]
+	}
+	if !ok {
+		log.Debugf("Missing %s translation: %s", c.locale, key)
+		return key
+	}
+	return fmt.Sprintf(tmpl, args...)
+}
+
+// Locale implements Translator
+func (c *catalog) Locale() string {
+	return c.locale
+}
+
+// Normalize maps a language code (e.g. "en", "zh_cn", "EN-us") to a supported locale,
+// reporting false for unknown codes
+func Normalize(lang string) (string, bool) {
+	lang = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+	switch {
+	case lang == "zh" || strings.HasPrefix(lang, "zh-"):
+		return ZhCN, true
+	case lang == "en" || strings.HasPrefix(lang, "en-"):
+		return EnUS, true
+	default:
+		return "", false
+	}
+}
+
+// New returns the Translator of lang (BOT_LANGUAGE). Unknown codes fall back to en-US with a warning.
+func New(lang string) Translator {
+	locale, ok := Normalize(lang)
+	if !ok {
+		log.Warnf("Unsupported BOT_LANGUAGE %q, falling back to %s", lang, fallbackLocale)
+		locale = fallbackLocale
+	}
+	return catalogs[locale]
+}
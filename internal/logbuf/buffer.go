@@ -0,0 +1,123 @@
+package logbuf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// redactPatterns match credentials that may appear in log lines even when not configured
+// (e.g. keys echoed back in API errors)
+var redactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bLTAI[0-9A-Za-z]{12,28}\b`),     // Aliyun AccessKey ID
+	regexp.MustCompile(`\b\d{8,12}:[0-9A-Za-z_-]{35}\b`), // Telegram bot token
+}
+
+// Buffer is a goroutine-safe ring buffer of recent log lines.
+// It implements logrus.Hook so it can be attached with log.AddHook.
+type Buffer struct {
+	lines   []string
+	next    int  // index of the slot the next line is written to
+	full    bool // the ring has wrapped at least once
+	secrets []string
+	mu      sync.Mutex
+}
+
+// New creates a buffer keeping the last size lines. Occurrences of any of the
+// secrets are replaced before a line is stored.
+func New(size int, secrets []string) *Buffer {
+	if size < 1 {
+		size = 1
+	}
+
+	var nonEmpty []string
+	for _, s := range secrets {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	// Longest first so a secret containing another is replaced whole
+	sort.Slice(nonEmpty, func(i, j int) bool { return len(nonEmpty[i]) > len(nonEmpty[j]) })
+
+	return &Buffer{
+		lines:   make([]string, size),
+		secrets: nonEmpty,
+	}
+}
+
+// Levels implements logrus.Hook: Info level and above are captured
+func (b *Buffer) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel, log.WarnLevel, log.InfoLevel}
+}
+
+// Fire implements logrus.Hook
+func (b *Buffer) Fire(entry *log.Entry) error {
+	line := fmt.Sprintf("%s %-5s %s", entry.Time.Format("01-02 15:04:05"), strings.ToUpper(entry.Level.String()), entry.Message)
+	if len(entry.Data) > 0 {
+		keys := make([]string, 0, len(entry.Data))
+		for k := range entry.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			line += fmt.Sprintf(" %s=%v", k, entry.Data[k])
+		}
+	}
+
+	b.Add(line)
+	return nil
+}
+
+// Add redacts and stores a line, overwriting the oldest one when full
+func (b *Buffer) Add(line string) {
+	line = b.redact(line)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Last returns up to n of the most recent lines, oldest first
+func (b *Buffer) Last(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := b.next
+	if b.full {
+		count = len(b.lines)
+	}
+	if n > count {
+		n = count
+	}
+
+	result := make([]string, 0, n)
+	for i := n; i > 0; i-- {
+		result = append(result, b.lines[(b.next-i+len(b.lines))%len(b.lines)])
+	}
+	return result
+}
+
+// Size returns the capacity of the buffer
+func (b *Buffer) Size() int {
+	return len(b.lines)
+}
+
+// redact masks configured secrets and credential-like strings
+func (b *Buffer) redact(line string) string {
+	for _, s := range b.secrets {
+		line = strings.ReplaceAll(line, s, "***")
+	}
+	for _, re := range redactPatterns {
+		line = re.ReplaceAllString(line, "***")
+	}
+	return line
+}
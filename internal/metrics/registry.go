@@ -0,0 +1,296 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Metric types in the Prometheus text exposition format
+const (
+	TypeGauge     = "gauge"
+	TypeCounter   = "counter"
+	TypeHistogram = "histogram"
+)
+
+// Labels is a set of metric labels
+type Labels map[string]string
+
+// key returns a stable identity for a label set
+func (l Labels) key() string {
+	return l.format()
+}
+
+// format renders labels as {k1="v1",k2="v2"} sorted by key
+func (l Labels) format() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, l[k]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// with returns a copy of the labels with an extra label added
+func (l Labels) with(key, value string) Labels {
+	out := make(Labels, len(l)+1)
+	for k, v := range l {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// series is a single labelled time series of a metric family
+type series struct {
+	labels Labels
+	value  float64 // gauge / counter value
+
+	// Histogram state
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// family is a named metric with all of its series
+type family struct {
+	name    string
+	help    string
+	typ     string
+	buckets []float64 // histogram upper bounds, ascending
+	series  map[string]*series
+}
+
+// Registry holds metric families and renders them in Prometheus text format
+type Registry struct {
+	families map[string]*family
+	order    []string // registration order, used for output
+	mu       sync.Mutex
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// Register declares a metric family. buckets is only used for histograms.
+func (r *Registry) Register(name, help, typ string, buckets []float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.families[name]; ok {
+		return
+	}
+	r.families[name] = &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.order = append(r.order, name)
+}
+
+// getSeries returns the series for labels, creating it if needed. Caller must hold r.mu.
+func (r *Registry) getSeries(name string, labels Labels) *series {
+	f, ok := r.families[name]
+	if !ok {
+		return nil
+	}
+
+	key := labels.key()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		if f.typ == TypeHistogram {
+			s.bucketCounts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Set sets the value of a gauge, or of a counter tracked elsewhere
+func (r *Registry) Set(name string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s := r.getSeries(name, labels); s != nil {
+		s.value = value
+	}
+}
+
+// Add increments a counter or gauge
+func (r *Registry) Add(name string, labels Labels, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s := r.getSeries(name, labels); s != nil {
+		s.value += delta
+	}
+}
+
+// Observe records a histogram observation
+func (r *Registry) Observe(name string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok || f.typ != TypeHistogram {
+		return
+	}
+	s := r.getSeries(name, labels)
+	for i, upper := range f.buckets {
+		if value <= upper {
+			s.bucketCounts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// Delete removes the series with exactly the given labels
+func (r *Registry) Delete(name string, labels Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		delete(f.series, labels.key())
+	}
+}
+
+// DeleteMatching removes all series of a metric whose labels contain the given label
+func (r *Registry) DeleteMatching(name, labelKey, labelValue string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		return
+	}
+	for key, s := range f.series {
+		if s.labels[labelKey] == labelValue {
+			delete(f.series, key)
+		}
+	}
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sb strings.Builder
+	for _, name := range r.order {
+		f := r.families[name]
+		sb.WriteString(fmt.Sprintf("# HELP %s %s\n", f.name, f.help))
+		sb.WriteString(fmt.Sprintf("# TYPE %s %s\n", f.name, f.typ))
+
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			s := f.series[k]
+			if f.typ != TypeHistogram {
+				sb.WriteString(fmt.Sprintf("%s%s %s\n", f.name, s.labels.format(), formatFloat(s.value)))
+				continue
+			}
+
+			for i, upper := range f.buckets {
+				sb.WriteString(fmt.Sprintf("%s_bucket%s %d\n", f.name, s.labels.with("le", formatFloat(upper)).format(), s.bucketCounts[i]))
+			}
+			sb.WriteString(fmt.Sprintf("%s_bucket%s %d\n", f.name, s.labels.with("le", "+Inf").format(), s.count))
+			sb.WriteString(fmt.Sprintf("%s_sum%s %s\n", f.name, s.labels.format(), formatFloat(s.sum)))
+			sb.WriteString(fmt.Sprintf("%s_count%s %d\n", f.name, s.labels.format(), s.count))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatFloat renders a sample value
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler returns an http.Handler serving the registry
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			log.Debugf("Failed to write metrics: %v", err)
+		}
+	})
+}
+
+// Serve serves /metrics on addr until ctx is cancelled
+func (r *Registry) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Infof("Metrics server listening on %s/metrics", addr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("metrics server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down metrics server: %w", err)
+	}
+
+	log.Info("Metrics server stopped")
+	return nil
+}
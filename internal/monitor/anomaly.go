@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// costBaselineDays is the window of the rolling average daily cost
+	costBaselineDays = 7
+	// costBaselineMinDays is the minimum number of baseline days required before alerting
+	costBaselineMinDays = 3
+)
+
+// CheckCostAnomaly compares each instance's cost on the most recent complete day with its
+// rolling 7-day average (excluding that day) and alerts when it exceeds COST_ANOMALY_MULTIPLIER times
+// the average. Daily costs are kept in the state store so only missing days are queried.
+// The month-end projection is also checked against MONTHLY_BUDGET_CNY.
+func (m *Monitor) CheckCostAnomaly() error {
+	m.checkMonthlyBudget()
+
+	if m.cfg.CostAnomalyMultiplier <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	checkDay := today.AddDate(0, 0, -1)
+	instancesByAccount := m.billingInstancesByAccount()
+
+	for _, acc := range m.aliyunClients {
+		instanceInfos := instancesByAccount[acc.Account.Label]
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
+			continue
+		}
+
+		// Fill in missing days of the check day plus the baseline window
+		for offset := 0; offset <= costBaselineDays; offset++ {
+			day := checkDay.AddDate(0, 0, -offset)
+			dayKey := day.Format("2006-01-02")
+
+			missing := false
+			for _, inst := range instanceInfos {
+				if _, ok := m.state.Get(inst.InstanceID).DailyCosts[dayKey]; !ok {
+					missing = true
+					break
+				}
+			}
+			if !missing {
+				continue
+			}
+
+			costs, err := acc.BillingClient.QueryDailyCosts(context.Background(), instanceInfos, day)
+			if err != nil {
+				log.Warnf("[%s] Failed to query daily costs for %s: %v", acc.Account.Label, dayKey, err)
+				continue
+			}
+			for instanceID, cost := range costs {
+				m.updateState(instanceID, func(st *state.InstanceState) {
+					if st.DailyCosts == nil {
+						st.DailyCosts = make(map[string]float64)
+					}
+					st.DailyCosts[dayKey] = cost
+					pruneDailyCosts(st.DailyCosts, today.AddDate(0, 0, -costBaselineDays-1))
+				})
+			}
+		}
+
+		for _, inst := range instanceInfos {
+			m.checkInstanceCostAnomaly(acc.Account.Label, inst.InstanceID, inst.InstanceName, inst.RegionID, checkDay)
+		}
+	}
+
+	return nil
+}
+
+// checkInstanceCostAnomaly evaluates a single instance against its baseline
+func (m *Monitor) checkInstanceCostAnomaly(accountLabel, instanceID, instanceName, regionID string, checkDay time.Time) {
+	st := m.state.Get(instanceID)
+	dayKey := checkDay.Format("2006-01-02")
+
+	cost, ok := st.DailyCosts[dayKey]
+	if !ok || st.LastCostAnomalyDate == dayKey {
+		return
+	}
+
+	// Baseline excludes the day being checked
+	var total float64
+	days := 0
+	for offset := 1; offset <= costBaselineDays; offset++ {
+		if c, ok := st.DailyCosts[checkDay.AddDate(0, 0, -offset).Format("2006-01-02")]; ok {
+			total += c
+			days++
+		}
+	}
+	if days < costBaselineMinDays {
+		log.Debugf("[%s] Skipping cost anomaly check for %s: only %d day(s) of baseline", accountLabel, instanceID, days)
+		return
+	}
+
+	baseline := total / float64(days)
+	// A zero baseline (e.g. first charge ever) has no meaningful multiple
+	if baseline <= 0 {
+		log.Debugf("[%s] Skipping cost anomaly check for %s: zero baseline, cost %.4f on %s", accountLabel, instanceID, cost, dayKey)
+		return
+	}
+	if cost <= baseline*m.cfg.CostAnomalyMultiplier {
+		return
+	}
+
+	log.Warnf("[%s] Cost anomaly for instance %s on %s: ¥%.4f vs %d-day average ¥%.4f",
+		accountLabel, instanceID, dayKey, cost, days, baseline)
+
+	if m.notifier != nil {
+		if err := m.notifier.NotifyCostAnomaly(accountLabel, instanceID, instanceName, regionID, dayKey, cost, baseline, days); err != nil {
+			log.Warnf("[%s] Failed to send cost anomaly notification: %v", accountLabel, err)
+			return
+		}
+	}
+
+	m.updateState(instanceID, func(st *state.InstanceState) {
+		st.LastCostAnomalyDate = dayKey
+	})
+}
+
+// pruneDailyCosts drops daily costs older than cutoff
+func pruneDailyCosts(costs map[string]float64, cutoff time.Time) {
+	cutoffKey := cutoff.Format("2006-01-02")
+	for day := range costs {
+		if day < cutoffKey {
+			delete(costs, day)
+		}
+	}
+}
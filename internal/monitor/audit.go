@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultAuditLogEntries is the number of entries /auditlog shows without an argument
+	defaultAuditLogEntries = 20
+	// maxAuditLogEntries caps the entries accepted by /auditlog
+	maxAuditLogEntries = 100
+)
+
+// auditStart records a bot command or callback query as pending before it runs and returns
+// the ID of the audit entry, 0 when the audit log is disabled or the entry was not recorded
+func (m *Monitor) auditStart(ctx notify.CommandContext, command string) int64 {
+	entry := storage.AuditEntry{
+		Command: command,
+		ChatID:  ctx.ChatID,
+		Outcome: storage.AuditPending,
+	}
+	if ctx.User != nil {
+		entry.UserID = ctx.User.ID
+		entry.Username = ctx.User.Username
+		entry.FirstName = ctx.User.FirstName
+	}
+	log.WithFields(log.Fields{
+		"audit":   "bot",
+		"user_id": entry.UserID,
+		"chat_id": entry.ChatID,
+	}).Infof("Bot command %s from %s", command, formatAuditUser(entry))
+
+	if m.db == nil {
+		return 0
+	}
+	id, err := m.db.RecordAudit(entry)
+	if err != nil {
+		log.Warnf("Failed to record audit entry for %s: %v", command, err)
+		return 0
+	}
+	return id
+}
+
+// auditFinish records the outcome of a bot command or callback query started with auditStart
+func (m *Monitor) auditFinish(id int64, err error) {
+	if m.db == nil || id == 0 {
+		return
+	}
+	outcome, errMsg := storage.AuditSuccess, ""
+	if err != nil {
+		outcome, errMsg = storage.AuditError, err.Error()
+	}
+	if err := m.db.FinishAudit(id, outcome, errMsg); err != nil {
+		log.Warnf("Failed to record audit outcome: %v", err)
+	}
+}
+
+// sendAuditLog handles /auditlog [n]: sends the last n bot commands with who sent them
+func (m *Monitor) sendAuditLog(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if m.db == nil {
+		return m.reply().Send("🔐 <b>审计日志</b>\n\n审计日志未启用（请设置 <code>DB_PATH</code>）")
+	}
+
+	limit := defaultAuditLogEntries
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 || n > maxAuditLogEntries {
+			return m.reply().Send(fmt.Sprintf("❌ 无效的条数: %s\n\n用法: <code>/auditlog [条数]</code> (1-%d)",
+				html.EscapeString(args[0]), maxAuditLogEntries))
+		}
+		limit = n
+	}
+
+	entries, err := m.db.RecentAudit(limit)
+	if err != nil {
+		return m.reply().Send(fmt.Sprintf("❌ 查询审计日志失败: %s", html.EscapeString(err.Error())))
+	}
+	if len(entries) == 0 {
+		return m.reply().Send("🔐 <b>审计日志</b>\n\n暂无记录")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔐 <b>审计日志</b> (最近 %d 条)\n", len(entries)))
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n<pre>")
+	for _, e := range entries {
+		mark := "✅"
+		switch e.Outcome {
+		case storage.AuditError:
+			mark = "❌"
+		case storage.AuditPending:
+			mark = "⏳"
+		}
+		line := fmt.Sprintf("%s %s %s %s", e.Time.In(m.cfg.Location).Format("01-02 15:04"), mark,
+			formatAuditUser(e), truncateRunes(e.Command, 40))
+		sb.WriteString(html.EscapeString(line) + "\n")
+		if e.Error != "" {
+			sb.WriteString("   ↳ " + html.EscapeString(truncateRunes(e.Error, 80)) + "\n")
+		}
+	}
+	sb.WriteString("</pre>")
+
+	return m.reply().Send(sb.String())
+}
+
+// formatAuditUser renders the sender of an audit entry as "@username", the first name or the user ID
+func formatAuditUser(e storage.AuditEntry) string {
+	switch {
+	case e.Username != "":
+		return "@" + e.Username
+	case e.FirstName != "":
+		return e.FirstName
+	case e.UserID != 0:
+		return strconv.FormatInt(e.UserID, 10)
+	}
+	return "unknown"
+}
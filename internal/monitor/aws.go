@@ -0,0 +1,233 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// discoverAWSInstances discovers the EC2 spot instances of AWS_REGIONS
+func (m *Monitor) discoverAWSInstances(ctx context.Context) {
+	awsInstances, err := m.awsClient.DiscoverAllSpotInstances(ctx, m.cfg.AWSRegions)
+	if err != nil {
+		log.Warnf("Failed to discover AWS instances: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.awsInstances = awsInstances
+	m.mu.Unlock()
+
+	log.Infof("Discovered %d AWS spot instances", len(awsInstances))
+	for _, inst := range awsInstances {
+		log.Infof("  - %s (%s) in %s [%s]", inst.InstanceName, inst.InstanceID, inst.Region, inst.Status)
+	}
+}
+
+// refreshAWSInstances re-discovers AWS spot instances
+func (m *Monitor) refreshAWSInstances(ctx context.Context) {
+	awsInstances, err := m.awsClient.DiscoverAllSpotInstances(ctx, m.cfg.AWSRegions)
+	if err != nil {
+		log.Warnf("Failed to refresh AWS instances: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldMap := make(map[string]bool, len(m.awsInstances))
+	for _, inst := range m.awsInstances {
+		oldMap[inst.InstanceID] = true
+	}
+	newMap := make(map[string]bool, len(awsInstances))
+	for _, inst := range awsInstances {
+		newMap[inst.InstanceID] = true
+	}
+
+	for _, inst := range awsInstances {
+		if !oldMap[inst.InstanceID] {
+			log.Infof("AWS: New instance discovered: %s (%s) in %s", inst.InstanceName, inst.InstanceID, inst.Region)
+		}
+	}
+	for _, inst := range m.awsInstances {
+		if !newMap[inst.InstanceID] {
+			log.Infof("AWS: Instance removed: %s (%s) in %s", inst.InstanceName, inst.InstanceID, inst.Region)
+			m.forgetInstanceMetrics(inst.InstanceID)
+		}
+	}
+
+	m.awsInstances = awsInstances
+}
+
+// checkProviderInstance checks a single instance of a non-Aliyun provider and starts it if stopped
+func (m *Monitor) checkProviderInstance(ctx context.Context, p CloudProvider, inst *ProviderInstance) error {
+	if reason, blocked := m.autoStartBlocked(inst.StateKey, inst.InstanceID, inst.RegionID); blocked {
+		log.Debugf("[%s] Instance %s (%s) skipped: %s", p.Name(), inst.InstanceName, inst.InstanceID, reason)
+		return nil
+	}
+
+	current, err := p.GetInstance(ctx, inst.RegionID, inst.InstanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get instance status: %w", err)
+	}
+	inst = current
+
+	region := p.Name() + "/" + inst.RegionID
+	log.Debugf("[%s] Instance %s (%s) status: %s", p.Name(), inst.InstanceName, inst.InstanceID, inst.Status)
+	m.recordInstanceMetrics(inst.StateKey, inst.InstanceID, inst.InstanceName, inst.RegionID, inst.Status == providerStatusRunning)
+
+	// Only handle stopped instances
+	if inst.Status != providerStatusStopped {
+		return nil
+	}
+
+	if until, paused := m.autoStartPausedUntil(inst.StateKey); paused {
+		log.Debugf("[%s] Instance %s (%s) skipped: auto-start paused until %s after rapid reclaims",
+			p.Name(), inst.InstanceName, inst.InstanceID, until.Format("15:04:05"))
+		return nil
+	}
+
+	// See checkInstance: a dry run must not count the same stop again at every check
+	if !m.cfg.DryRun {
+		if m.state.Get(inst.StateKey).ConsecutiveFailures == 0 {
+			m.recordIncident(storage.EventReclaimDetected, inst.InstanceID, inst.InstanceName, region, 0, nil)
+		}
+		if recent, paused := m.recordReclaim(inst.StateKey); paused {
+			m.notifyRapidReclaim(inst.StateKey, inst.InstanceName, region, p.Name(), recent)
+			return nil
+		}
+	}
+
+	log.Warnf("[%s] Instance %s (%s) is %s, attempting to start", p.Name(), inst.InstanceName, inst.InstanceID, inst.Status)
+	m.broadcastStatus(streamEventReclaim, inst.InstanceID, inst.InstanceName, inst.RegionID, inst.Status)
+
+	if !m.canNotify(inst.StateKey) {
+		log.Debugf("Notification cooldown active for %s instance %s", p.Name(), inst.InstanceID)
+	} else {
+		if m.notifier != nil {
+			if err := m.notifier.NotifyInstanceReclaimed(p.AccountLabel(), inst.InstanceID, inst.InstanceName, region); err != nil {
+				log.Warnf("Failed to send %s reclaimed notification: %v", p.Name(), err)
+			}
+		}
+		m.updateNotifyTime(inst.StateKey)
+	}
+
+	getStatus := func(ctx context.Context, regionID, instanceID string) (string, error) {
+		current, err := p.GetInstance(ctx, regionID, instanceID)
+		if err != nil {
+			return "", err
+		}
+		return current.Status, nil
+	}
+
+	// Try to start the instance with retries
+	m.markStarting(inst.StateKey, inst.InstanceName)
+	defer m.clearStarting(inst.StateKey)
+	m.broadcastStatus(streamEventStarting, inst.InstanceID, inst.InstanceName, inst.RegionID, providerStatusStarting)
+	startTime := time.Now()
+	var lastErr error
+	retryCount := m.retryCountFor(inst.InstanceID)
+	for i := 0; i < retryCount; i++ {
+		if i > 0 {
+			delay := m.startRetryDelay(inst.InstanceID, i)
+			log.Infof("[%s] Retry %d/%d for instance %s in %s", p.Name(), i+1, retryCount, inst.InstanceID, delay.Round(time.Second))
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("start of instance %s aborted: %w", inst.InstanceID, err)
+			}
+		}
+
+		err := p.StartInstance(ctx, inst.RegionID, inst.InstanceID)
+		if !m.cfg.DryRun {
+			m.recordIncident(storage.EventStartAttempted, inst.InstanceID, inst.InstanceName, region, 0, err)
+		}
+		if err != nil {
+			lastErr = err
+			log.Warnf("[%s] Failed to start instance %s (attempt %d): %v", p.Name(), inst.InstanceID, i+1, err)
+			continue
+		}
+
+		if m.cfg.DryRun {
+			return nil
+		}
+
+		// Wait for instance to be running
+		if err := waitForInstanceStatus(ctx, getStatus, inst.RegionID, inst.InstanceID, providerStatusRunning, 2*time.Minute); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("start of instance %s aborted: %w", inst.InstanceID, err)
+			}
+			lastErr = err
+			log.Warnf("[%s] Instance %s did not reach running state: %v", p.Name(), inst.InstanceID, err)
+			continue
+		}
+		if err := m.waitMinUptime(ctx, providerStatusRunning, func() (string, error) {
+			return getStatus(ctx, inst.RegionID, inst.InstanceID)
+		}); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("start of instance %s aborted: %w", inst.InstanceID, err)
+			}
+			lastErr = err
+			log.Warnf("[%s] Instance %s did not stay running: %v", p.Name(), inst.InstanceID, err)
+			continue
+		}
+		m.recordStatus(inst.StateKey, providerStatusRunning)
+
+		// The public IP changes on every start
+		if updated, err := p.GetInstance(ctx, inst.RegionID, inst.InstanceID); err != nil {
+			log.Warnf("[%s] Failed to get updated instance info: %v", p.Name(), err)
+		} else {
+			inst = updated
+		}
+
+		var sshErr error
+		sshPort := m.sshPortFor(inst.InstanceID)
+		if m.cfg.HealthCheckSSH {
+			if sshErr = m.waitForSSH(inst.PublicIP, sshPort); sshErr != nil {
+				log.Warnf("[%s] Instance %s is running but SSH is not reachable: %v", p.Name(), inst.InstanceID, sshErr)
+			}
+		}
+
+		duration := time.Since(startTime)
+		log.Infof("[%s] Instance %s started successfully in %.0f seconds", p.Name(), inst.InstanceID, duration.Seconds())
+
+		healthInfo := ""
+		if healthURL, ok := m.cfg.InstanceHealthURLs[inst.InstanceID]; ok {
+			healthInfo = m.checkHTTPHealth(inst.InstanceID, healthURL, inst.PublicIP, p.Name())
+		}
+
+		if m.notifier != nil {
+			var err error
+			if sshErr != nil {
+				err = m.notifier.NotifySSHUnreachable(p.AccountLabel(), inst.InstanceID, inst.InstanceName, region, inst.PublicIP, sshPort, duration, healthInfo)
+			} else {
+				err = m.notifier.NotifyInstanceStarted(p.AccountLabel(), inst.InstanceID, inst.InstanceName, region, inst.PublicIP, duration, healthInfo)
+			}
+			if err != nil {
+				log.Warnf("Failed to send %s started notification: %v", p.Name(), err)
+			}
+		}
+
+		m.recordStartResult(inst.StateKey, true)
+		m.recordIncident(storage.EventStartSucceeded, inst.InstanceID, inst.InstanceName, region, duration, nil)
+		m.broadcastStatus(streamEventStarted, inst.InstanceID, inst.InstanceName, inst.RegionID, providerStatusRunning)
+		m.recordInstanceMetrics(inst.StateKey, inst.InstanceID, inst.InstanceName, inst.RegionID, true)
+		m.observeStartDuration(inst.InstanceID, inst.InstanceName, inst.RegionID, duration)
+		return nil
+	}
+
+	m.recordStartResult(inst.StateKey, false)
+	m.recordIncident(storage.EventStartFailed, inst.InstanceID, inst.InstanceName, region, time.Since(startTime), lastErr)
+	m.broadcastStatus(streamEventFailed, inst.InstanceID, inst.InstanceName, inst.RegionID, providerStatusStopped)
+
+	// All retries failed
+	log.Errorf("[%s] Failed to start instance %s after %d retries", p.Name(), inst.InstanceID, retryCount)
+	if m.notifier != nil {
+		if err := m.notifier.NotifyInstanceStartFailed(p.AccountLabel(), inst.InstanceID, inst.InstanceName, region, retryCount, lastErr); err != nil {
+			log.Warnf("Failed to send %s failure notification: %v", p.Name(), err)
+		}
+	}
+
+	return lastErr
+}
@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	"math/rand"
+	"time"
+)
+
+// retryJitter is the fraction of random jitter applied to each backoff delay (±25%)
+const retryJitter = 0.25
+
+// retryBackoff returns the sleep before the given retry (1 = first retry).
+// The delay doubles from base on every retry, gets ±25% jitter so instances
+// reclaimed together don't retry in lockstep, and never exceeds max.
+func retryBackoff(retry int, base, max time.Duration) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	if max < base {
+		max = base
+	}
+
+	d := base
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	// Scale by a random factor in [1-jitter, 1+jitter)
+	d = time.Duration(float64(d) * (1 - retryJitter + 2*retryJitter*rand.Float64()))
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// startRetryDelay returns the backoff delay before a start retry of an instance
+func (m *Monitor) startRetryDelay(instanceKey string, retry int) time.Duration {
+	return retryBackoff(retry, m.retryIntervalFor(instanceKey), time.Duration(m.cfg.MaxRetryInterval)*time.Second)
+}
@@ -0,0 +1,53 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	const base, maxDelay = 10 * time.Second, 60 * time.Second
+
+	tests := []struct {
+		retry    int
+		nominal  time.Duration // delay before jitter
+		clamped  bool          // nominal reaches max, so jitter can only lower it
+		scenario string
+	}{
+		{retry: 1, nominal: 10 * time.Second, scenario: "first retry uses base"},
+		{retry: 2, nominal: 20 * time.Second, scenario: "doubles"},
+		{retry: 3, nominal: 40 * time.Second, scenario: "doubles again"},
+		{retry: 4, nominal: 60 * time.Second, clamped: true, scenario: "capped at max"},
+		{retry: 10, nominal: 60 * time.Second, clamped: true, scenario: "stays at max"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			lo := time.Duration(float64(tt.nominal) * (1 - retryJitter))
+			hi := time.Duration(float64(tt.nominal) * (1 + retryJitter))
+			if tt.clamped {
+				hi = maxDelay
+			}
+			// The jitter is random, so sample it repeatedly
+			for i := 0; i < 1000; i++ {
+				d := retryBackoff(tt.retry, base, maxDelay)
+				if d < lo || d > hi {
+					t.Fatalf("retryBackoff(%d) = %s, want within [%s, %s]", tt.retry, d, lo, hi)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryBackoffEdgeCases(t *testing.T) {
+	if d := retryBackoff(3, 0, time.Minute); d != 0 {
+		t.Errorf("zero base: got %s, want 0", d)
+	}
+
+	// A max below base is raised to base
+	for i := 0; i < 1000; i++ {
+		if d := retryBackoff(5, 30*time.Second, 10*time.Second); d > 30*time.Second {
+			t.Fatalf("max below base: got %s, want at most 30s", d)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CheckBandwidthUtilization checks the common bandwidth packages in every region with monitored
+// instances and warns when a package's recent peak reaches BANDWIDTH_WARN_PERCENT of its
+// capacity, since a saturated package drops packets on all EIPs sharing it.
+// Warnings per package share the notification cooldown.
+func (m *Monitor) CheckBandwidthUtilization() error {
+	if m.cfg.BandwidthWarnPercent <= 0 || m.notifier == nil {
+		return nil
+	}
+
+	regionsByAccount := make(map[string]map[string]bool)
+	m.mu.RLock()
+	for _, inst := range m.instances {
+		if regionsByAccount[inst.AccountLabel] == nil {
+			regionsByAccount[inst.AccountLabel] = make(map[string]bool)
+		}
+		regionsByAccount[inst.AccountLabel][inst.RegionID] = true
+	}
+	m.mu.RUnlock()
+
+	for _, acc := range m.aliyunClients {
+		if acc.CBWPClient == nil {
+			continue
+		}
+
+		regions := make([]string, 0, len(regionsByAccount[acc.Account.Label]))
+		for regionID := range regionsByAccount[acc.Account.Label] {
+			regions = append(regions, regionID)
+		}
+		sort.Strings(regions)
+
+		for _, regionID := range regions {
+			packages, err := acc.CBWPClient.DescribeCommonBandwidthPackages(regionID)
+			if err != nil {
+				log.Warnf("[%s] Failed to list bandwidth packages in region %s: %v", acc.Account.Label, regionID, err)
+				continue
+			}
+
+			for _, pkg := range packages {
+				capacity, err := strconv.ParseFloat(pkg.Bandwidth, 64)
+				if err != nil || capacity <= 0 {
+					log.Debugf("[%s] Skipping bandwidth package %s with bandwidth %q", acc.Account.Label, pkg.BandwidthPackageID, pkg.Bandwidth)
+					continue
+				}
+
+				peakMbps, avgMbps, err := acc.CBWPClient.GetBandwidthPackageMetrics(regionID, pkg.BandwidthPackageID)
+				if err != nil {
+					log.Warnf("[%s] Failed to get metrics of bandwidth package %s: %v", acc.Account.Label, pkg.BandwidthPackageID, err)
+					continue
+				}
+				if peakMbps/capacity < m.cfg.BandwidthWarnPercent {
+					continue
+				}
+
+				notifyKey := fmt.Sprintf("bandwidth:%s:%s", acc.Account.Label, pkg.BandwidthPackageID)
+				if !m.canNotify(notifyKey) {
+					continue
+				}
+
+				log.Warnf("[%s] Bandwidth package %s in region %s near capacity: peak %.1f/%.0f Mbps",
+					acc.Account.Label, pkg.BandwidthPackageID, regionID, peakMbps, capacity)
+				accountTitle := ""
+				if acc.Account.Label != "" {
+					accountTitle = fmt.Sprintf(" [%s]", html.EscapeString(acc.Account.Label))
+				}
+				name := pkg.Name
+				if name == "" {
+					name = pkg.BandwidthPackageID
+				}
+				message := fmt.Sprintf(`⚠️ <b>共享带宽包接近满载%s</b>
+
+📦 带宽包: %s (<code>%s</code>)
+📍 区域: %s
+📈 峰值: %.1f / %.0f Mbps (%.0f%%)
+📊 平均: %.1f Mbps
+
+带宽包满载时其中所有 EIP 都可能丢包，请考虑升级带宽包规格`,
+					accountTitle, html.EscapeString(name), pkg.BandwidthPackageID, regionID,
+					peakMbps, capacity, peakMbps/capacity*100, avgMbps)
+				if err := m.notifier.Send(message); err != nil {
+					log.Errorf("[%s] Failed to send bandwidth package warning: %v", acc.Account.Label, err)
+					continue
+				}
+				m.updateNotifyTime(notifyKey)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/i18n"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// topProductsShown is the number of products listed when an account has more
+	topProductsShown = 5
+	// maxBillingAllCycles caps the billing cycles accepted by /billingall
+	maxBillingAllCycles = 12
+)
+
+// sendAllProductBilling handles /billingall [YYYY-MM ...]: sends the spend of each Aliyun account
+// on all products (ECS, OSS, SLB, RDS, ...) in the given billing cycles, the current month by default
+func (m *Monitor) sendAllProductBilling(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	cycles, err := parseBillingCycles(m.tr, args, time.Now())
+	if err != nil {
+		return m.reply().Send(m.tr.T("billingall.usage", html.EscapeString(err.Error()), maxBillingAllCycles))
+	}
+
+	sent := false
+	for _, acc := range m.aliyunClients {
+		if acc.BillingClient == nil {
+			continue
+		}
+
+		summary, err := acc.BillingClient.QueryAllProductBilling(context.Background(), cycles)
+		if err != nil {
+			log.Errorf("[%s] Failed to query all-product billing: %v", acc.Account.Label, err)
+			err = m.reply().Send(m.tr.T("billingall.query_failed",
+				html.EscapeString(acc.Account.Label), html.EscapeString(err.Error())))
+		} else {
+			summary.AccountLabel = acc.Account.Label
+			err = m.reply().Send(formatAllProductBilling(m.tr, summary))
+		}
+		if err != nil {
+			log.Warnf("Failed to send /billingall report: %v", err)
+		}
+		sent = true
+	}
+
+	if !sent {
+		return m.reply().Send(m.tr.T("billingall.empty"))
+	}
+	return nil
+}
+
+// parseBillingCycles parses /billingall arguments, billing cycles in YYYY-MM format, defaulting
+// to the cycle of now. Duplicates are dropped and future months are rejected. Errors are
+// written in the language of tr, as they are shown to the user.
+func parseBillingCycles(tr i18n.Translator, args []string, now time.Time) ([]string, error) {
+	if len(args) == 0 {
+		return []string{now.Format("2006-01")}, nil
+	}
+
+	var cycles []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		month, err := time.ParseInLocation("2006-01", arg, now.Location())
+		if err != nil {
+			return nil, errors.New(tr.T("billingall.invalid_cycle", arg))
+		}
+		if month.After(now) {
+			return nil, errors.New(tr.T("billingall.future_cycle", arg))
+		}
+		if !seen[arg] {
+			seen[arg] = true
+			cycles = append(cycles, arg)
+		}
+	}
+	if len(cycles) > maxBillingAllCycles {
+		return nil, errors.New(tr.T("billingall.too_many_cycles", maxBillingAllCycles))
+	}
+	return cycles, nil
+}
+
+// formatAllProductBilling renders a /billingall report for one account. With more than
+// topProductsShown products only the most expensive ones are listed and the rest summed up.
+func formatAllProductBilling(tr i18n.Translator, summary *aliyun.AllProductBillingSummary) string {
+	accountTitle := ""
+	if summary.AccountLabel != "" {
+		accountTitle = fmt.Sprintf(" [%s]", html.EscapeString(summary.AccountLabel))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("billingall.header", accountTitle) + "\n")
+	sb.WriteString(tr.T("billingall.cycles", strings.Join(summary.Cycles, ", ")) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	products := summary.SortedProducts()
+	if len(products) == 0 {
+		sb.WriteString(tr.T("billingall.no_cost"))
+		return sb.String()
+	}
+
+	shown := products
+	if len(products) > topProductsShown {
+		shown = products[:topProductsShown]
+		sb.WriteString(tr.T("billingall.top", topProductsShown) + "\n")
+	}
+	sb.WriteString("<pre>")
+	for _, p := range shown {
+		sb.WriteString(fmt.Sprintf("%-20s %10.2f %5.1f%%\n", html.EscapeString(truncateRunes(p.Product, 20)),
+			p.Amount, costShare(p.Amount, summary.Total)))
+	}
+	if rest := products[len(shown):]; len(rest) > 0 {
+		var restAmount float64
+		for _, p := range rest {
+			restAmount += p.Amount
+		}
+		sb.WriteString(fmt.Sprintf("%-20s %10.2f %5.1f%%\n", tr.T("billingall.others", len(rest)),
+			restAmount, costShare(restAmount, summary.Total)))
+	}
+	sb.WriteString("</pre>\n")
+	sb.WriteString(tr.T("billingall.total", summary.Total, len(products)))
+
+	return truncateTelegramMessage(sb.String())
+}
+
+// costShare returns amount as a percentage of total, 0 when total is not positive
+func costShare(amount, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return amount / total * 100
+}
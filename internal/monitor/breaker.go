@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/metrics"
+	"github.com/iliyian/aliyun-spot-manager/internal/ratelimit"
+	log "github.com/sirupsen/logrus"
+)
+
+// circuitOutages tracks since when the circuit of each account/region has been open
+type circuitOutages struct {
+	since map[string]time.Time // account + "/" + region -> time the circuit opened
+	mu    sync.Mutex
+}
+
+// onCircuitChange handles a state transition of a region's ECS circuit breaker.
+// Only the first opening and the final closing are announced; reopening after a
+// failed half-open probe is logged but not notified.
+func (m *Monitor) onCircuitChange(accountLabel, regionID string, from, to ratelimit.State) {
+	m.recordCircuitMetrics(accountLabel, regionID, to)
+
+	key := accountLabel + "/" + regionID
+	timeout := time.Duration(m.cfg.CircuitBreakerTimeout) * time.Second
+
+	switch {
+	case from == ratelimit.StateClosed && to == ratelimit.StateOpen:
+		m.circuitOutages.mu.Lock()
+		m.circuitOutages.since[key] = time.Now()
+		m.circuitOutages.mu.Unlock()
+
+		log.Warnf("[%s] Circuit opened for region %s after %d consecutive API failures, skipping it for %s",
+			accountLabel, regionID, m.cfg.CircuitBreakerThreshold, timeout)
+		if m.notifier != nil {
+			if err := m.notifier.NotifyCircuitOpened(accountLabel, regionID, m.cfg.CircuitBreakerThreshold, timeout); err != nil {
+				log.Warnf("[%s] Failed to send circuit opened notification: %v", accountLabel, err)
+			}
+		}
+
+	case to == ratelimit.StateClosed:
+		m.circuitOutages.mu.Lock()
+		since, ok := m.circuitOutages.since[key]
+		delete(m.circuitOutages.since, key)
+		m.circuitOutages.mu.Unlock()
+		if !ok {
+			return
+		}
+
+		log.Infof("[%s] Circuit closed for region %s after %s", accountLabel, regionID, time.Since(since).Round(time.Second))
+		if m.notifier != nil {
+			if err := m.notifier.NotifyCircuitClosed(accountLabel, regionID, time.Since(since)); err != nil {
+				log.Warnf("[%s] Failed to send circuit closed notification: %v", accountLabel, err)
+			}
+		}
+
+	case to == ratelimit.StateHalfOpen:
+		log.Infof("[%s] Circuit half-open for region %s, sending probe request", accountLabel, regionID)
+
+	default:
+		log.Warnf("[%s] Probe request to region %s failed, circuit reopened for %s", accountLabel, regionID, timeout)
+	}
+}
+
+// recordCircuitMetrics sets the state gauges of a region's circuit and counts the transition
+func (m *Monitor) recordCircuitMetrics(accountLabel, regionID string, to ratelimit.State) {
+	if m.metrics == nil {
+		return
+	}
+
+	for _, s := range []ratelimit.State{ratelimit.StateClosed, ratelimit.StateOpen, ratelimit.StateHalfOpen} {
+		value := 0.0
+		if s == to {
+			value = 1
+		}
+		m.metrics.Set(metricCircuitBreakerState, circuitLabels(accountLabel, regionID, s), value)
+	}
+	m.metrics.Add(metricCircuitBreakerTransitions, circuitLabels(accountLabel, regionID, to), 1)
+}
+
+func circuitLabels(accountLabel, regionID string, s ratelimit.State) metrics.Labels {
+	return metrics.Labels{
+		"account":   accountLabel,
+		"region_id": regionID,
+		"state":     s.String(),
+	}
+}
@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	log "github.com/sirupsen/logrus"
+)
+
+// cbwpStatusRegion holds the bandwidth packages of one account in one region
+type cbwpStatusRegion struct {
+	accountLabel string
+	regionID     string
+
+	packages    []*aliyun.BandwidthPackage
+	packagesErr error
+	usage       map[string]*bandwidthUsage // bandwidth package ID -> utilization
+}
+
+// bandwidthUsage is the recent CloudMonitor traffic of a bandwidth package
+type bandwidthUsage struct {
+	peakMbps float64
+	avgMbps  float64
+	err      error
+}
+
+// cbwpStatusKeyboard is the refresh button of the /cbwp-status message
+var cbwpStatusKeyboard = [][]notify.InlineKeyboardButton{
+	{{Text: "🔄 刷新", CallbackData: "cbwp|status"}},
+}
+
+// sendCBWPStatus handles /cbwp-status: sends all bandwidth packages in the regions with
+// monitored instances, with a refresh button that re-queries them
+func (m *Monitor) sendCBWPStatus() error {
+	if m.botHandler == nil {
+		return fmt.Errorf("bot handler not initialized")
+	}
+	return m.botHandler.SendMessageWithKeyboard(m.buildCBWPStatus(), cbwpStatusKeyboard)
+}
+
+// handleCBWPStatusRefresh re-queries the bandwidth packages and updates the /cbwp-status message
+func (m *Monitor) handleCBWPStatusRefresh(callbackID string, msg notify.MessageRef) error {
+	_ = m.botHandler.AnswerCallbackQuery(callbackID, "刷新中...", false)
+	return m.botHandler.EditMessageText(msg, m.buildCBWPStatus(), cbwpStatusKeyboard)
+}
+
+// buildCBWPStatus queries the bandwidth packages of each region, their utilization and their
+// cost this month concurrently and renders the report
+func (m *Monitor) buildCBWPStatus() string {
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	m.mu.RLock()
+	seen := make(map[string]bool)
+	accounts := make(map[string]bool)
+	var regions []*cbwpStatusRegion
+	for _, inst := range m.instances {
+		accounts[inst.AccountLabel] = true
+		key := inst.AccountLabel + "/" + inst.RegionID
+		if !seen[key] {
+			seen[key] = true
+			regions = append(regions, &cbwpStatusRegion{accountLabel: inst.AccountLabel, regionID: inst.RegionID})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].regionID != regions[j].regionID {
+			return regions[i].regionID < regions[j].regionID
+		}
+		return regions[i].accountLabel < regions[j].accountLabel
+	})
+
+	var wg sync.WaitGroup
+	for _, r := range regions {
+		cbwpClient := m.getCBWPClientByLabel(r.accountLabel)
+		if cbwpClient == nil {
+			r.packagesErr = fmt.Errorf("未找到该账号的客户端")
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.packages, r.packagesErr = cbwpClient.DescribeCommonBandwidthPackages(r.regionID)
+			r.usage = make(map[string]*bandwidthUsage, len(r.packages))
+			for _, pkg := range r.packages {
+				usage := &bandwidthUsage{}
+				usage.peakMbps, usage.avgMbps, usage.err = cbwpClient.GetBandwidthPackageMetrics(r.regionID, pkg.BandwidthPackageID)
+				r.usage[pkg.BandwidthPackageID] = usage
+			}
+		}()
+	}
+
+	// Bandwidth package costs come from one bill query per account
+	costs := make(map[string]map[string]float64)
+	costErrs := make(map[string]error)
+	var costMu sync.Mutex
+	for _, acc := range m.aliyunClients {
+		if acc.BillingClient == nil || !accounts[acc.Account.Label] {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accountCosts, err := acc.BillingClient.QueryBandwidthPackageCosts(ctx)
+			if err != nil {
+				log.Warnf("[%s] Failed to query bandwidth package costs: %v", acc.Account.Label, err)
+			}
+			costMu.Lock()
+			costs[acc.Account.Label], costErrs[acc.Account.Label] = accountCosts, err
+			costMu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	var sb strings.Builder
+	sb.WriteString("📶 <b>共享带宽包状态</b>\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	if len(regions) == 0 {
+		sb.WriteString("\n暂无监控的实例\n")
+	}
+
+	for _, r := range regions {
+		label := ""
+		if r.accountLabel != "" {
+			label = fmt.Sprintf(" [%s]", html.EscapeString(r.accountLabel))
+		}
+		sb.WriteString(fmt.Sprintf("\n📍 <b>%s</b>%s\n", r.regionID, label))
+
+		switch {
+		case r.packagesErr != nil:
+			sb.WriteString(fmt.Sprintf("   ⚠️ %s\n", html.EscapeString(r.packagesErr.Error())))
+			continue
+		case len(r.packages) == 0:
+			sb.WriteString("   暂无共享带宽包\n")
+			continue
+		}
+
+		accountCosts, hasBilling := costs[r.accountLabel]
+		costErr := costErrs[r.accountLabel]
+		for _, pkg := range r.packages {
+			name := pkg.Name
+			if name == "" {
+				name = pkg.BandwidthPackageID
+			}
+			sb.WriteString(fmt.Sprintf("• %s (<code>%s</code>)\n", html.EscapeString(name), pkg.BandwidthPackageID))
+			sb.WriteString(fmt.Sprintf("   带宽: %s Mbps | EIP: %d 个\n", pkg.Bandwidth, len(pkg.MemberIPs)))
+			sb.WriteString(formatBandwidthUsage(pkg, r.usage[pkg.BandwidthPackageID]))
+			switch {
+			case costErr != nil:
+				sb.WriteString("   本月费用: ⚠️ 查询失败\n")
+			case hasBilling:
+				sb.WriteString(fmt.Sprintf("   本月费用: ¥%.2f\n", accountCosts[pkg.BandwidthPackageID]))
+			}
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n<i>更新于 %s，利用率为最近 %d 分钟平均值和峰值</i>",
+		time.Now().In(m.cfg.Location).Format("15:04:05"), int(aliyun.BandwidthMetricWindow.Minutes())))
+	return truncateTelegramMessage(sb.String())
+}
+
+// formatBandwidthUsage renders the utilization line of a bandwidth package
+func formatBandwidthUsage(pkg *aliyun.BandwidthPackage, usage *bandwidthUsage) string {
+	if usage == nil || usage.err != nil {
+		return "   利用率: - (无法获取云监控数据)\n"
+	}
+	capacity, err := strconv.ParseFloat(pkg.Bandwidth, 64)
+	if err != nil || capacity <= 0 {
+		return fmt.Sprintf("   流量: 平均 %.1f Mbps，峰值 %.1f Mbps\n", usage.avgMbps, usage.peakMbps)
+	}
+	return fmt.Sprintf("   利用率: %.0f%% (峰值 %.0f%%，%.1f / %.1f Mbps)\n",
+		usage.avgMbps/capacity*100, usage.peakMbps/capacity*100, usage.avgMbps, usage.peakMbps)
+}
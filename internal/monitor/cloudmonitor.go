@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun/cloudmonitor"
+	log "github.com/sirupsen/logrus"
+)
+
+// cloudMonitorCheckDelay is how long after a start the CloudMonitor metrics are checked:
+// the 5-minute metric window plus one minute of reporting delay
+const cloudMonitorCheckDelay = 6 * time.Minute
+
+// CheckInstanceHealth checks the CPU, memory and disk usage of a freshly started instance
+// against the CLOUDMONITOR_ALERT_* thresholds and sends a warning when any is exceeded.
+// It waits for the metric window first, so it is run in its own goroutine.
+func (m *Monitor) CheckInstanceHealth(inst *aliyun.SpotInstance) {
+	defer m.recoverAndNotify("CloudMonitor health check")
+
+	checks := []struct {
+		metric    string
+		label     string
+		threshold float64
+	}{
+		{cloudmonitor.MetricCPU, "CPU", m.cfg.CloudMonitorAlertCPU},
+		{cloudmonitor.MetricMemory, "内存", m.cfg.CloudMonitorAlertMemory},
+		{cloudmonitor.MetricDisk, "磁盘", m.cfg.CloudMonitorAlertDisk},
+	}
+
+	var client *cloudmonitor.CloudMonitorClient
+	for _, c := range m.aliyunClients {
+		if c.Account.Label == inst.AccountLabel {
+			client = c.CloudMonitorClient
+		}
+	}
+	if client == nil || m.notifier == nil {
+		return
+	}
+	enabled := false
+	for _, check := range checks {
+		enabled = enabled || check.threshold > 0
+	}
+	if !enabled {
+		return
+	}
+
+	time.Sleep(cloudMonitorCheckDelay)
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	var exceeded []string
+	for _, check := range checks {
+		if check.threshold <= 0 {
+			continue
+		}
+		value, err := client.GetInstanceMetrics(ctx, inst.RegionID, inst.InstanceID, check.metric)
+		if err != nil {
+			if errors.Is(err, cloudmonitor.ErrNoData) {
+				log.Debugf("[%s] Skipping %s check for instance %s: %v", inst.AccountLabel, check.metric, inst.InstanceID, err)
+			} else {
+				log.Warnf("[%s] Failed to query %s of instance %s: %v", inst.AccountLabel, check.metric, inst.InstanceID, err)
+			}
+			continue
+		}
+		log.Debugf("[%s] Instance %s %s: %.1f%%", inst.AccountLabel, inst.InstanceID, check.metric, value)
+		if value >= check.threshold {
+			exceeded = append(exceeded, fmt.Sprintf("%s: <b>%.1f%%</b> (阈值 %g%%)", check.label, value, check.threshold))
+		}
+	}
+	if len(exceeded) == 0 {
+		return
+	}
+
+	log.Warnf("[%s] Instance %s exceeds CloudMonitor thresholds after startup", inst.AccountLabel, inst.InstanceID)
+	accountTitle := ""
+	if inst.AccountLabel != "" {
+		accountTitle = fmt.Sprintf(" [%s]", inst.AccountLabel)
+	}
+	message := fmt.Sprintf(`⚠️ <b>实例资源使用率过高%s</b>
+━━━━━━━━━━━━━━━
+实例: %s
+ID: %s
+区域: %s
+━━━━━━━━━━━━━━━
+%s
+
+<i>启动 %d 分钟后查询的最近 5 分钟平均值</i>`,
+		accountTitle, inst.InstanceName, inst.InstanceID, inst.RegionID,
+		strings.Join(exceeded, "\n"), int(cloudMonitorCheckDelay.Minutes()))
+	if err := m.notifier.Send(message); err != nil {
+		log.Warnf("[%s] Failed to send CloudMonitor warning: %v", inst.AccountLabel, err)
+	}
+}
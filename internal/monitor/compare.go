@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/i18n"
+	log "github.com/sirupsen/logrus"
+)
+
+// compareRow is the spend of one instance in both months of a /compare report
+type compareRow struct {
+	name     string
+	current  float64
+	previous float64
+}
+
+// sendMonthComparison handles /compare: compares the month-to-date spend of each account with
+// the same days of the previous month and projects the month-end total from the current burn rate
+func (m *Monitor) sendMonthComparison() error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+	// On e.g. March 31st the previous month has fewer days, so compare with all of it
+	lastMonthDays := min(now.Day(), thisMonth.AddDate(0, 0, -1).Day())
+	instancesByAccount := m.billingInstancesByAccount()
+
+	sent := false
+	for _, acc := range m.aliyunClients {
+		instanceInfos := instancesByAccount[acc.Account.Label]
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
+			continue
+		}
+
+		current, err := acc.BillingClient.QueryBillingForDays(m.runCtx, instanceInfos, thisMonth, now.Day())
+		if err == nil {
+			var previous *aliyun.BillingSummary
+			if previous, err = acc.BillingClient.QueryBillingForDays(m.runCtx, instanceInfos, lastMonth, lastMonthDays); err == nil {
+				err = m.reply().Send(formatMonthComparison(m.tr, acc.Account.Label, current, previous, now))
+				sent = true
+			}
+		}
+		if err != nil {
+			log.Errorf("[%s] Failed to compare monthly billing: %v", acc.Account.Label, err)
+			if sendErr := m.reply().Send(m.tr.T("compare.query_failed",
+				html.EscapeString(acc.Account.Label), html.EscapeString(err.Error()))); sendErr != nil {
+				log.Warnf("Failed to send /compare error: %v", sendErr)
+			}
+			sent = true
+		}
+	}
+
+	if !sent {
+		return m.reply().Send(m.tr.T("compare.empty"))
+	}
+	return nil
+}
+
+// formatMonthComparison renders a /compare report for one account
+func formatMonthComparison(tr i18n.Translator, accountLabel string, current, previous *aliyun.BillingSummary, now time.Time) string {
+	rows := make(map[string]*compareRow)
+	row := func(inst aliyun.InstanceBillingSummary) *compareRow {
+		r, ok := rows[inst.InstanceID]
+		if !ok {
+			name := inst.InstanceName
+			if name == "" {
+				name = inst.InstanceID
+			}
+			r = &compareRow{name: name}
+			rows[inst.InstanceID] = r
+		}
+		return r
+	}
+	for _, inst := range current.Instances {
+		row(inst).current += inst.TotalAmount
+	}
+	for _, inst := range previous.Instances {
+		row(inst).previous += inst.TotalAmount
+	}
+
+	sorted := make([]*compareRow, 0, len(rows))
+	for _, r := range rows {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].current > sorted[j].current })
+
+	accountTitle := ""
+	if accountLabel != "" {
+		accountTitle = fmt.Sprintf(" [%s]", html.EscapeString(accountLabel))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("compare.header", accountTitle) + "\n")
+	sb.WriteString(tr.T("compare.period", current.BillingCycle, previous.BillingCycle, now.Day()) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n<pre>")
+	sb.WriteString(fmt.Sprintf("%-16s %9s %9s %10s\n", tr.T("common.instance"), tr.T("compare.current"), tr.T("compare.previous"), tr.T("compare.change")))
+	for _, r := range sorted {
+		sb.WriteString(fmt.Sprintf("%-16s %9.2f %9.2f %10s\n", html.EscapeString(truncateRunes(r.name, 16)),
+			r.current, r.previous, formatCostDelta(r.current-r.previous)))
+	}
+	sb.WriteString(fmt.Sprintf("%-16s %9.2f %9.2f %10s\n", tr.T("common.total"),
+		current.TotalAmount, previous.TotalAmount, formatCostDelta(current.TotalAmount-previous.TotalAmount)))
+	sb.WriteString("</pre>\n")
+
+	// Project the month-end total from the spend per elapsed day so far
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	if elapsed := now.Sub(monthStart).Hours() / 24; elapsed > 0 {
+		dailyRate := current.TotalAmount / elapsed
+		sb.WriteString(tr.T("compare.daily_rate", dailyRate) + "\n")
+		sb.WriteString(tr.T("compare.projected", dailyRate*monthEnd.Sub(monthStart).Hours()/24) + "\n")
+	}
+	sb.WriteString("\n" + tr.T("compare.note"))
+
+	return truncateTelegramMessage(sb.String())
+}
+
+// formatCostDelta renders a cost change as ▲¥X (more), ▼¥X (less) or - (unchanged)
+func formatCostDelta(delta float64) string {
+	switch {
+	case delta >= 0.005:
+		return fmt.Sprintf("▲¥%.2f", delta)
+	case delta <= -0.005:
+		return fmt.Sprintf("▼¥%.2f", -delta)
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,220 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// allocateEIPConfirmTimeout is how long an /allocate-eip confirmation dialog stays valid
+	allocateEIPConfirmTimeout = 60 * time.Second
+	// eipHourlyFeeCNY is the configuration fee of a pay-by-traffic EIP; traffic is billed separately
+	eipHourlyFeeCNY = 0.02
+)
+
+// sendAllocateEIPConfirm handles /allocate-eip <instance>: asks for confirmation before
+// allocating an EIP for an instance that has no public IP
+func (m *Monitor) sendAllocateEIPConfirm(args []string) error {
+	if m.botHandler == nil {
+		return fmt.Errorf("bot handler not initialized")
+	}
+
+	if len(args) == 0 {
+		return m.reply().Send(m.tr.T("eip.usage"))
+	}
+
+	inst := m.findInstance(args[0])
+	if inst == nil {
+		return m.reply().Send(m.tr.T("eip.not_found", html.EscapeString(args[0])))
+	}
+
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	cbwpClient := m.getCBWPClientByLabel(inst.AccountLabel)
+	if ecsClient == nil || cbwpClient == nil {
+		return m.reply().Send(m.tr.T("eip.no_client"))
+	}
+
+	// The cached address may predate the last restart
+	ctx, cancel := m.operationContext()
+	defer cancel()
+	current, err := ecsClient.GetInstance(ctx, inst.RegionID, inst.InstanceID, inst.AccountLabel)
+	if err != nil {
+		return m.reply().Send(m.tr.T("eip.query_failed", err))
+	}
+	if current.PublicIPAddress != "" {
+		return m.reply().Send(m.tr.T("eip.has_public_ip", inst.InstanceName, current.PublicIPAddress))
+	}
+
+	m.pendingEIPsMu.Lock()
+	m.pendingEIPs[inst.InstanceID] = time.Now()
+	m.pendingEIPsMu.Unlock()
+
+	text := m.tr.T("eip.confirm", inst.InstanceID, inst.InstanceName, inst.RegionID, m.cfg.DefaultEIPBandwidth,
+		eipHourlyFeeCNY*24, int(allocateEIPConfirmTimeout.Seconds()))
+	keyboard := [][]notify.InlineKeyboardButton{
+		{
+			{Text: m.tr.T("common.confirm"), CallbackData: "eip:confirm:" + inst.InstanceID},
+			{Text: m.tr.T("common.cancel"), CallbackData: "eip:cancel:" + inst.InstanceID},
+		},
+	}
+	return m.botHandler.SendMessageWithKeyboard(text, keyboard)
+}
+
+// handleAllocateEIPCallback handles eip:confirm:<id> and eip:cancel:<id> callbacks
+func (m *Monitor) handleAllocateEIPCallback(callbackID, data string, msg notify.MessageRef) error {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+	action, instanceID := parts[1], parts[2]
+
+	// A confirmation can only be used once
+	m.pendingEIPsMu.Lock()
+	sentAt, pending := m.pendingEIPs[instanceID]
+	delete(m.pendingEIPs, instanceID)
+	m.pendingEIPsMu.Unlock()
+
+	if action == "cancel" {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, m.tr.T("common.cancelled"), false)
+		return m.botHandler.EditMessageText(msg, m.tr.T("eip.cancelled", instanceID), nil)
+	}
+	if action != "confirm" {
+		return nil
+	}
+
+	if !pending || time.Since(sentAt) > allocateEIPConfirmTimeout {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, m.tr.T("common.expired"), true)
+		return m.botHandler.EditMessageText(msg, m.tr.T("eip.expired", instanceID), nil)
+	}
+
+	inst := m.findInstance(instanceID)
+	if inst == nil {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		return m.botHandler.EditMessageText(msg, m.tr.T("eip.not_found", instanceID), nil)
+	}
+	cbwpClient := m.getCBWPClientByLabel(inst.AccountLabel)
+	if cbwpClient == nil {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		return m.botHandler.EditMessageText(msg, m.tr.T("eip.no_client"), nil)
+	}
+
+	_ = m.botHandler.AnswerCallbackQuery(callbackID, m.tr.T("eip.allocating"), false)
+
+	// Waiting for the association takes a while; run it in the background so other commands stay responsive
+	go func() {
+		defer m.recoverAndNotify("EIP allocation")
+		m.allocateEIP(cbwpClient, inst, msg)
+	}()
+
+	return nil
+}
+
+// allocateEIP allocates an EIP and associates it with an instance, editing progress into the
+// confirmation message. The bandwidth packages of the region are offered for the new EIP.
+func (m *Monitor) allocateEIP(cbwpClient *aliyun.CBWPClient, inst *aliyun.SpotInstance, msg notify.MessageRef) {
+	title := m.tr.T("eip.title", inst.InstanceName, inst.InstanceID)
+	progress := func(step string, keyboard [][]notify.InlineKeyboardButton) {
+		if err := m.botHandler.EditMessageText(msg, title+step, keyboard); err != nil {
+			log.Warnf("[%s] Failed to update EIP allocation progress: %v", inst.AccountLabel, err)
+		}
+	}
+
+	log.Infof("[%s] EIP allocation for instance %s (%s) requested via Telegram", inst.AccountLabel, inst.InstanceName, inst.InstanceID)
+
+	progress(m.tr.T("eip.step_allocating"), nil)
+	eip, err := cbwpClient.AllocateEipAddress(inst.RegionID, m.cfg.DefaultEIPBandwidth)
+	if err != nil {
+		log.Errorf("[%s] Failed to allocate EIP for instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		progress(m.tr.T("eip.allocate_failed", err), nil)
+		return
+	}
+	if m.cfg.DryRun {
+		progress(m.tr.T("eip.dry_run"), nil)
+		return
+	}
+
+	// An EIP left unassociated would keep being billed, so it is released on failure
+	fail := func(err error) {
+		log.Errorf("[%s] Failed to associate EIP %s with instance %s: %v", inst.AccountLabel, eip.AllocationID, inst.InstanceID, err)
+		if relErr := m.releaseEIP(cbwpClient, inst.RegionID, eip.AllocationID, inst.InstanceID); relErr != nil {
+			log.Errorf("[%s] Failed to release EIP %s: %v", inst.AccountLabel, eip.AllocationID, relErr)
+			progress(m.tr.T("eip.associate_failed", eip.IPAddress, eip.AllocationID, err, relErr), nil)
+			return
+		}
+		progress(m.tr.T("eip.associate_failed_released", eip.IPAddress, eip.AllocationID, err), nil)
+	}
+
+	progress(m.tr.T("eip.step_associating", eip.IPAddress), nil)
+	if err := cbwpClient.AssociateEipAddress(inst.RegionID, eip.AllocationID, inst.InstanceID); err != nil {
+		fail(err)
+		return
+	}
+
+	getStatus := func(ctx context.Context, regionID, allocationID string) (string, error) {
+		eips, err := cbwpClient.DescribeEipAddresses(regionID, inst.InstanceID)
+		if err != nil {
+			return "", err
+		}
+		for _, e := range eips {
+			if e.AllocationID == allocationID {
+				return e.Status, nil
+			}
+		}
+		return "", nil
+	}
+	if err := waitForInstanceStatus(m.runCtx, getStatus, inst.RegionID, eip.AllocationID, "InUse", time.Minute); err != nil {
+		fail(err)
+		return
+	}
+
+	log.Infof("[%s] EIP %s (%s) associated with instance %s", inst.AccountLabel, eip.AllocationID, eip.IPAddress, inst.InstanceID)
+	result := m.tr.T("eip.done", eip.IPAddress, eip.AllocationID, m.cfg.DefaultEIPBandwidth)
+
+	// Offer the bandwidth packages of the region; the cbwp|bind callback adds the instance's
+	// EIP that is not yet in a package, which is the new one
+	bwps, err := cbwpClient.DescribeCommonBandwidthPackages(inst.RegionID)
+	if err != nil {
+		log.Warnf("[%s] Failed to query bandwidth packages in %s: %v", inst.AccountLabel, inst.RegionID, err)
+	}
+	var keyboard [][]notify.InlineKeyboardButton
+	for _, bwp := range bwps {
+		bwpLabel := bwp.BandwidthPackageID
+		if bwp.Name != "" {
+			bwpLabel = bwp.Name
+		}
+		keyboard = append(keyboard, []notify.InlineKeyboardButton{
+			{
+				Text:         m.tr.T("eip.bind_button", bwpLabel, bwp.Bandwidth),
+				CallbackData: fmt.Sprintf("cbwp|bind|%s|%s|%s", inst.InstanceID, inst.AccountLabel, bwp.BandwidthPackageID),
+			},
+		})
+	}
+	if len(keyboard) > 0 {
+		result += "\n\n" + m.tr.T("eip.bind_offer")
+	}
+	progress(result, keyboard)
+}
+
+// releaseEIP releases an EIP whose association failed. An EIP that is associated, or still
+// associating, cannot be released, so on failure it is unassociated and released once more.
+func (m *Monitor) releaseEIP(cbwpClient *aliyun.CBWPClient, regionID, allocationID, instanceID string) error {
+	err := cbwpClient.ReleaseEipAddress(regionID, allocationID)
+	if err == nil {
+		return nil
+	}
+	if unErr := cbwpClient.UnassociateEipAddress(regionID, allocationID, instanceID); unErr != nil {
+		log.Warnf("Failed to unassociate EIP %s before releasing it: %v", allocationID, unErr)
+	}
+	// Unassociating completes asynchronously as well
+	if sleepErr := sleepContext(m.runCtx, 10*time.Second); sleepErr != nil {
+		return err
+	}
+	return cbwpClient.ReleaseEipAddress(regionID, allocationID)
+}
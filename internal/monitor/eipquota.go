@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// eipQuotaConsoleURL is the Quota Center page where VPC / EIP quota increases are requested
+const eipQuotaConsoleURL = "https://quotas.console.aliyun.com/products/vpc/quotas"
+
+// CheckEIPQuota checks the EIP usage of every region with monitored instances and warns when
+// it reaches EIP_QUOTA_WARN_PERCENT of the quota, since a restarted instance may need a new EIP.
+// Warnings per region share the notification cooldown.
+func (m *Monitor) CheckEIPQuota() error {
+	if m.cfg.EIPQuotaWarnPercent <= 0 || m.notifier == nil {
+		return nil
+	}
+
+	regionsByAccount := make(map[string]map[string]bool)
+	m.mu.RLock()
+	for _, inst := range m.instances {
+		if regionsByAccount[inst.AccountLabel] == nil {
+			regionsByAccount[inst.AccountLabel] = make(map[string]bool)
+		}
+		regionsByAccount[inst.AccountLabel][inst.RegionID] = true
+	}
+	m.mu.RUnlock()
+
+	for _, acc := range m.aliyunClients {
+		if acc.CBWPClient == nil {
+			continue
+		}
+
+		regions := make([]string, 0, len(regionsByAccount[acc.Account.Label]))
+		for regionID := range regionsByAccount[acc.Account.Label] {
+			regions = append(regions, regionID)
+		}
+		sort.Strings(regions)
+
+		for _, regionID := range regions {
+			used, limit, err := acc.CBWPClient.DescribeEipQuota(regionID)
+			if err != nil {
+				log.Warnf("[%s] Failed to check EIP quota in region %s: %v", acc.Account.Label, regionID, err)
+				continue
+			}
+			if limit <= 0 || float64(used)/float64(limit) < m.cfg.EIPQuotaWarnPercent {
+				continue
+			}
+
+			notifyKey := fmt.Sprintf("eip-quota:%s:%s", acc.Account.Label, regionID)
+			if !m.canNotify(notifyKey) {
+				continue
+			}
+
+			log.Warnf("[%s] EIP quota in region %s nearly exhausted: %d/%d", acc.Account.Label, regionID, used, limit)
+			accountTitle := ""
+			if acc.Account.Label != "" {
+				accountTitle = fmt.Sprintf(" [%s]", acc.Account.Label)
+			}
+			message := fmt.Sprintf(`⚠️ <b>EIP 配额即将用尽%s</b>
+
+📍 区域: %s
+📊 已用: %d / %d (%.0f%%)
+
+实例重启时可能无法分配 EIP，请及时<a href="%s">申请提升配额</a>`,
+				accountTitle, regionID, used, limit, float64(used)/float64(limit)*100, eipQuotaConsoleURL)
+			if err := m.notifier.Send(message); err != nil {
+				log.Errorf("[%s] Failed to send EIP quota warning: %v", acc.Account.Label, err)
+				continue
+			}
+			m.updateNotifyTime(notifyKey)
+		}
+	}
+
+	return nil
+}
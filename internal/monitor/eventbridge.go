@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	"github.com/iliyian/aliyun-spot-manager/internal/webhook"
+	log "github.com/sirupsen/logrus"
+)
+
+// reclaimNoticeLead is how long before the stop EventBridge emits a spot interruption event
+const reclaimNoticeLead = 5 * time.Minute
+
+// startEventServer receives EventBridge spot interruption events until ctx is cancelled. Events
+// are handled on checkCtx, so a shutdown lets running pre-stop hooks finish.
+func (m *Monitor) startEventServer(ctx, checkCtx context.Context, wg *sync.WaitGroup) {
+	server := webhook.NewServer(m.cfg.WebhookAddr, m.cfg.WebhookSecret, m.cfg.WebhookTLSCert, m.cfg.WebhookTLSKey,
+		func(event webhook.Event) {
+			// Answer EventBridge right away, the hook may take minutes. Shutting the server
+			// down waits for this handler, so wg cannot reach zero before the Add.
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.handleReclaimEvent(checkCtx, event)
+			}()
+		})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer m.recoverAndNotify("EventBridge webhook server")
+		if err := server.Serve(ctx); err != nil {
+			log.Errorf("EventBridge webhook server: %v", err)
+		}
+	}()
+}
+
+// handleReclaimEvent runs the pre-warning flow of a spot interruption event: the pre-stop
+// hook, the notification and the state / history update
+func (m *Monitor) handleReclaimEvent(ctx context.Context, event webhook.Event) {
+	defer m.recoverAndNotify("EventBridge event")
+
+	inst := m.findInstance(event.InstanceID)
+	if inst == nil {
+		log.Infof("Ignoring EventBridge event %s for unmonitored instance %s", event.ID, event.InstanceID)
+		return
+	}
+
+	// EventBridge redelivers an event it got no timely answer for; a redelivery carries the
+	// same event time, so it must not run the hook again
+	duplicate := false
+	if err := m.state.Update(inst.InstanceID, func(st *state.InstanceState) {
+		if st.LastReclaimNoticeAt.Equal(event.Time) {
+			duplicate = true
+			return
+		}
+		st.LastReclaimNoticeAt = event.Time
+	}); err != nil {
+		log.Warnf("[%s] Failed to persist reclaim notice of %s: %v", inst.AccountLabel, inst.InstanceID, err)
+	}
+	if duplicate {
+		log.Infof("Ignoring duplicate EventBridge event %s for instance %s", event.ID, event.InstanceID)
+		return
+	}
+
+	stopAt := event.Time.Add(reclaimNoticeLead)
+	log.Warnf("[%s] Reclaim notice: instance %s (%s) will be stopped around %s",
+		inst.AccountLabel, inst.InstanceID, inst.InstanceName, stopAt.Local().Format("2006-01-02 15:04:05"))
+	m.recordIncident(storage.EventReclaimNotice, inst.InstanceID, inst.InstanceName, inst.RegionID, 0, nil)
+
+	hookResult := ""
+	if m.cfg.PreStopHook != "" {
+		if m.cfg.DryRun {
+			log.Warnf("[DRY RUN] Would run pre-stop hook %s for %s", m.cfg.PreStopHook, inst.InstanceID)
+			hookResult = "🧪 DRY RUN 模式，未执行停止前钩子"
+		} else {
+			hookResult = m.runPreStopHook(ctx, inst.InstanceID, inst.InstanceName, inst.RegionID, stopAt)
+		}
+	}
+
+	if m.notifier != nil {
+		if err := m.notifier.NotifyReclaimWarning(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, stopAt, hookResult); err != nil {
+			log.Warnf("[%s] Failed to send reclaim notice notification: %v", inst.AccountLabel, err)
+		}
+	}
+}
+
+// runPreStopHook runs PRE_STOP_HOOK, which must finish before the instance is stopped, and
+// returns a short result for the notification
+func (m *Monitor) runPreStopHook(ctx context.Context, instanceID, instanceName, regionID string, stopAt time.Time) string {
+	ctx, cancel := context.WithDeadline(ctx, stopAt)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, m.cfg.PreStopHook)
+	cmd.Env = append(os.Environ(),
+		"INSTANCE_ID="+instanceID,
+		"INSTANCE_NAME="+instanceName,
+		"REGION_ID="+regionID,
+		"STOP_TIME="+stopAt.Format(time.RFC3339),
+	)
+
+	log.Infof("Running pre-stop hook %s for %s", m.cfg.PreStopHook, instanceID)
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		log.Infof("Pre-stop hook output: %s", truncateRunes(string(output), 1000))
+	}
+	if err != nil {
+		log.Errorf("Pre-stop hook for %s failed: %v", instanceID, err)
+		return "❌ 停止前钩子执行失败: " + err.Error()
+	}
+
+	log.Infof("Pre-stop hook for %s finished in %s", instanceID, time.Since(start).Round(time.Second))
+	return "✅ 停止前钩子已执行"
+}
@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// eventsLookbackDays is how far back /events queries instance events
+	eventsLookbackDays = 30
+	// eventsLimit is the number of events /events shows
+	eventsLimit = 10
+)
+
+// sendInstanceEvents handles /events [instance]: sends the last eventsLimit system, maintenance
+// and scheduled events (ECS DescribeInstanceHistoryEvents) of an instance, all monitored
+// instances if none is given
+func (m *Monitor) sendInstanceEvents(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	m.mu.RLock()
+	instances := make([]*aliyun.SpotInstance, len(m.instances))
+	copy(instances, m.instances)
+	m.mu.RUnlock()
+
+	title := "所有实例"
+	if len(args) > 0 {
+		inst := m.findInstance(args[0])
+		if inst == nil {
+			return m.reply().Send(fmt.Sprintf("❌ 未找到实例: %s\n\n用法: <code>/events [实例名称或ID]</code>", html.EscapeString(args[0])))
+		}
+		instances = []*aliyun.SpotInstance{inst}
+		title = inst.InstanceName
+		if title == "" {
+			title = inst.InstanceID
+		}
+	}
+	if len(instances) == 0 {
+		return m.reply().Send("📋 <b>实例事件</b>\n\n暂无监控的实例")
+	}
+
+	byID := make(map[string]*aliyun.SpotInstance, len(instances))
+	regionSet := make(map[string]bool)
+	for _, inst := range instances {
+		byID[inst.InstanceID] = inst
+		regionSet[inst.AccountLabel+"|"+inst.RegionID] = true
+	}
+	keys := make([]string, 0, len(regionSet))
+	for key := range regionSet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// A single instance is queried by ID, otherwise all events of each region are filtered
+	instanceID := ""
+	if len(args) > 0 {
+		instanceID = instances[0].InstanceID
+	}
+	var events []*aliyun.InstanceEvent
+	var failed []string
+	for _, key := range keys {
+		accountLabel, regionID, _ := strings.Cut(key, "|")
+		ecsClient := m.getECSClientByLabel(accountLabel)
+		if ecsClient == nil {
+			continue
+		}
+		regionEvents, err := ecsClient.GetInstanceEvents(ctx, regionID, instanceID, eventsLookbackDays)
+		if err != nil {
+			log.Warnf("[%s] Failed to query instance events in region %s: %v", accountLabel, regionID, err)
+			failed = append(failed, regionID)
+			continue
+		}
+		for _, event := range regionEvents {
+			if byID[event.InstanceID] != nil {
+				events = append(events, event)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].EventTime.After(events[j].EventTime) })
+	if len(events) > eventsLimit {
+		events = events[:eventsLimit]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📋 <b>实例事件</b> (%s)\n", html.EscapeString(title)))
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	if len(events) == 0 {
+		sb.WriteString(fmt.Sprintf("\n近 %d 天无事件\n", eventsLookbackDays))
+	}
+	for _, event := range events {
+		inst := byID[event.InstanceID]
+		name := inst.InstanceName
+		if name == "" {
+			name = inst.InstanceID
+		}
+		sb.WriteString(fmt.Sprintf("\n• %s <b>%s</b> (%s)\n", event.EventTime.In(m.cfg.Location).Format("01-02 15:04"),
+			html.EscapeString(event.EventType), html.EscapeString(event.Status)))
+		sb.WriteString(fmt.Sprintf("   %s (<code>%s</code>) %s\n", html.EscapeString(name), inst.InstanceID, inst.RegionID))
+		if event.Reason != "" {
+			sb.WriteString(fmt.Sprintf("   原因: %s\n", html.EscapeString(truncateRunes(event.Reason, 120))))
+		}
+	}
+	if len(failed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n⚠️ 查询失败的区域: %s\n", strings.Join(failed, ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("\n<i>最近 %d 条，近 %d 天内的系统事件、维护事件和计划事件</i>", eventsLimit, eventsLookbackDays))
+
+	return m.reply().Send(truncateTelegramMessage(sb.String()))
+}
@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// exportIncidentWindow is the look-back window of the incident count in /export
+const exportIncidentWindow = 24 * time.Hour
+
+// exportDocument is the YAML document sent by /export
+type exportDocument struct {
+	Version         string                     `yaml:"version"`
+	ExportedAt      time.Time                  `yaml:"exported_at"`
+	Config          map[string]any             `yaml:"config"`
+	Instances       []exportInstance           `yaml:"instances"`
+	TrafficShutdown map[string]map[string]bool `yaml:"traffic_shutdown,omitempty"` // account label -> limit scope -> shutdown
+	IncidentsLast24 *int                       `yaml:"incidents_last_24h,omitempty"`
+}
+
+// exportInstance is a monitored instance with its persisted state
+type exportInstance struct {
+	Provider            string    `yaml:"provider"`
+	Account             string    `yaml:"account,omitempty"`
+	InstanceID          string    `yaml:"instance_id"`
+	InstanceName        string    `yaml:"instance_name,omitempty"`
+	Region              string    `yaml:"region"`
+	InstanceType        string    `yaml:"instance_type,omitempty"`
+	Status              string    `yaml:"status"`
+	PublicIP            string    `yaml:"public_ip,omitempty"`
+	ReclaimCount        int       `yaml:"reclaim_count"`
+	LastReclaimAt       time.Time `yaml:"last_reclaim_at,omitempty"`
+	ConsecutiveFailures int       `yaml:"consecutive_failures"`
+	PausedUntil         time.Time `yaml:"paused_until,omitempty"`
+	ManuallyStopped     bool      `yaml:"manually_stopped,omitempty"`
+	ManuallyStoppedAt   time.Time `yaml:"manually_stopped_at,omitempty"`
+	ManuallyStoppedBy   string    `yaml:"manually_stopped_by,omitempty"`
+}
+
+// SetVersion sets the build version reported by /export
+func (m *Monitor) SetVersion(version string) {
+	m.version = version
+}
+
+// sendExport handles /export: sends the sanitized config and current state as a YAML file
+func (m *Monitor) sendExport() error {
+	if m.telegram == nil || m.botHandler == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	doc, err := m.buildExport()
+	if err != nil {
+		return m.reply().Send(fmt.Sprintf("❌ 导出失败: %s", html.EscapeString(err.Error())))
+	}
+	content, err := yaml.Marshal(doc)
+	if err != nil {
+		return m.reply().Send(fmt.Sprintf("❌ 导出失败: %s", html.EscapeString(err.Error())))
+	}
+
+	filename := fmt.Sprintf("spot-monitor-export-%s.yaml", doc.ExportedAt.Format("20060102-150405"))
+	caption := fmt.Sprintf("📦 <b>配置与状态导出</b>\n版本: <code>%s</code>\n实例: %d 个", html.EscapeString(doc.Version), len(doc.Instances))
+	return m.botHandler.SendDocument(filename, content, caption)
+}
+
+// buildExport collects the export document
+func (m *Monitor) buildExport() (*exportDocument, error) {
+	doc := &exportDocument{
+		Version:    m.version,
+		ExportedAt: time.Now(),
+	}
+
+	// Round-trip through JSON so keys keep the Go field names of the config
+	raw, err := json.Marshal(m.cfg.Sanitized())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := json.Unmarshal(raw, &doc.Config); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	delete(doc.Config, "Location") // duplicate of Timezone without exported fields
+
+	m.mu.RLock()
+	for _, inst := range m.instances {
+		st := m.state.Get(inst.InstanceID)
+		doc.Instances = append(doc.Instances, exportInstance{
+			Provider:            "aliyun",
+			Account:             inst.AccountLabel,
+			InstanceID:          inst.InstanceID,
+			InstanceName:        inst.InstanceName,
+			Region:              inst.RegionID,
+			InstanceType:        inst.InstanceType,
+			Status:              inst.Status,
+			PublicIP:            inst.PublicIPAddress,
+			ReclaimCount:        st.ReclaimCount,
+			LastReclaimAt:       st.LastReclaimAt,
+			ConsecutiveFailures: st.ConsecutiveFailures,
+			PausedUntil:         st.PausedUntil,
+			ManuallyStopped:     st.ManuallyStopped,
+			ManuallyStoppedAt:   st.ManuallyStoppedAt,
+			ManuallyStoppedBy:   st.ManuallyStoppedBy,
+		})
+	}
+	for _, inst := range m.gcpInstances {
+		st := m.state.Get("gcp:" + inst.Zone + "/" + inst.InstanceName)
+		doc.Instances = append(doc.Instances, exportInstance{
+			Provider:            "gcp",
+			InstanceID:          inst.InstanceName,
+			Region:              inst.Zone,
+			InstanceType:        inst.MachineType,
+			Status:              inst.Status,
+			PublicIP:            inst.ExternalIP,
+			ReclaimCount:        st.ReclaimCount,
+			LastReclaimAt:       st.LastReclaimAt,
+			ConsecutiveFailures: st.ConsecutiveFailures,
+		})
+	}
+	m.mu.RUnlock()
+
+	m.trafficShutdownMu.RLock()
+	if len(m.trafficShutdown) > 0 {
+		doc.TrafficShutdown = make(map[string]map[string]bool, len(m.trafficShutdown))
+		for account, scopes := range m.trafficShutdown {
+			doc.TrafficShutdown[account] = make(map[string]bool, len(scopes))
+			for scope, shutdown := range scopes {
+				doc.TrafficShutdown[account][scope] = shutdown
+			}
+		}
+	}
+	m.trafficShutdownMu.RUnlock()
+
+	if m.db != nil {
+		count, err := m.db.CountIncidentsSince(doc.ExportedAt.Add(-exportIncidentWindow))
+		if err != nil {
+			log.Warnf("Failed to count incidents for export: %v", err)
+		} else {
+			doc.IncidentsLast24 = &count
+		}
+	}
+
+	return doc, nil
+}
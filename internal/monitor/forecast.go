@@ -0,0 +1,178 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// forecastTopInstances is the number of most expensive instances projected by /forecast
+const forecastTopInstances = 3
+
+// budgetNotifyKey is the notification cooldown key of monthly budget warnings
+const budgetNotifyKey = "budget:monthly"
+
+// forecastInstance is the month-to-date spend of one instance
+type forecastInstance struct {
+	name         string
+	accountLabel string
+	spend        float64
+}
+
+// costForecast is the month-end projection of all accounts at the current daily rate
+type costForecast struct {
+	monthToDate float64
+	elapsedDays float64 // days since the start of the month, fractional
+	monthDays   float64
+	instances   []forecastInstance // sorted by spend, highest first
+}
+
+// dailyRate returns the average spend per elapsed day
+func (f *costForecast) dailyRate() float64 {
+	if f.elapsedDays <= 0 {
+		return 0
+	}
+	return f.monthToDate / f.elapsedDays
+}
+
+// projected returns the estimated month-end total for a month-to-date spend
+func (f *costForecast) projected(spend float64) float64 {
+	if f.elapsedDays <= 0 {
+		return spend
+	}
+	return spend / f.elapsedDays * f.monthDays
+}
+
+// monthlyForecast queries the month-to-date spend of every account. Accounts that fail are
+// skipped; an error is returned only if none succeeded.
+func (m *Monitor) monthlyForecast(ctx context.Context) (*costForecast, error) {
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	forecast := &costForecast{
+		elapsedDays: now.Sub(monthStart).Hours() / 24,
+		monthDays:   monthStart.AddDate(0, 1, 0).Sub(monthStart).Hours() / 24,
+	}
+
+	instancesByAccount := m.billingInstancesByAccount()
+	queried := 0
+	var errs []error
+	for _, acc := range m.aliyunClients {
+		instanceInfos := instancesByAccount[acc.Account.Label]
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
+			continue
+		}
+
+		summary, err := acc.BillingClient.QueryBilling(ctx, instanceInfos, acc.Account.Label)
+		if err != nil {
+			log.Errorf("[%s] Failed to query billing for forecast: %v", acc.Account.Label, err)
+			errs = append(errs, fmt.Errorf("%s: %w", acc.Account.Label, err))
+			continue
+		}
+		queried++
+		forecast.monthToDate += summary.TotalAmount
+		for _, inst := range summary.Instances {
+			name := inst.InstanceName
+			if name == "" {
+				name = inst.InstanceID
+			}
+			forecast.instances = append(forecast.instances, forecastInstance{
+				name:         name,
+				accountLabel: acc.Account.Label,
+				spend:        inst.TotalAmount,
+			})
+		}
+	}
+
+	if queried == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	sort.Slice(forecast.instances, func(i, j int) bool { return forecast.instances[i].spend > forecast.instances[j].spend })
+	return forecast, nil
+}
+
+// sendCostForecast handles /forecast: projects the month-end spend from the month-to-date
+// daily rate and compares it with MONTHLY_BUDGET_CNY
+func (m *Monitor) sendCostForecast() error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	forecast, err := m.monthlyForecast(ctx)
+	if err != nil {
+		return m.reply().Send(m.tr.T("forecast.query_failed", html.EscapeString(err.Error())))
+	}
+	return m.reply().Send(m.formatCostForecast(forecast))
+}
+
+// formatCostForecast renders the /forecast report
+func (m *Monitor) formatCostForecast(f *costForecast) string {
+	projected := f.projected(f.monthToDate)
+
+	var sb strings.Builder
+	sb.WriteString(m.tr.T("forecast.header") + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(m.tr.T("forecast.month_to_date", f.monthToDate, f.elapsedDays, f.monthDays) + "\n")
+	sb.WriteString(m.tr.T("forecast.daily_rate", f.dailyRate()) + "\n")
+	sb.WriteString(m.tr.T("forecast.projected", projected) + "\n")
+
+	if budget := m.cfg.MonthlyBudgetCNY; budget > 0 {
+		if projected > budget {
+			sb.WriteString(m.tr.T("forecast.over_budget", budget, projected-budget, projected/budget*100) + "\n")
+		} else {
+			sb.WriteString(m.tr.T("forecast.within_budget", budget, projected/budget*100) + "\n")
+		}
+	}
+
+	if len(f.instances) > 0 {
+		sb.WriteString("\n" + m.tr.T("forecast.top", min(forecastTopInstances, len(f.instances))) + "\n<pre>")
+		sb.WriteString(fmt.Sprintf("%-16s %9s %9s\n", m.tr.T("common.instance"), m.tr.T("forecast.spend"), m.tr.T("forecast.month_end")))
+		for _, inst := range f.instances[:min(forecastTopInstances, len(f.instances))] {
+			sb.WriteString(fmt.Sprintf("%-16s %9.2f %9.2f\n", html.EscapeString(truncateRunes(inst.name, 16)),
+				inst.spend, f.projected(inst.spend)))
+		}
+		sb.WriteString("</pre>\n")
+	}
+
+	sb.WriteString("\n" + m.tr.T("forecast.note"))
+	return sb.String()
+}
+
+// checkMonthlyBudget warns when the projected month-end spend reaches MONTHLY_BUDGET_WARN_PERCENT
+// of MONTHLY_BUDGET_CNY. Warnings share the notification cooldown.
+func (m *Monitor) checkMonthlyBudget() {
+	budget := m.cfg.MonthlyBudgetCNY
+	if budget <= 0 || m.cfg.MonthlyBudgetWarnPercent <= 0 || m.notifier == nil {
+		return
+	}
+
+	forecast, err := m.monthlyForecast(context.Background())
+	if err != nil {
+		log.Warnf("Failed to query billing for budget check: %v", err)
+		return
+	}
+	projected := forecast.projected(forecast.monthToDate)
+	if projected < budget*m.cfg.MonthlyBudgetWarnPercent || !m.canNotify(budgetNotifyKey) {
+		return
+	}
+
+	log.Warnf("Projected month-end spend ¥%.2f reaches %.0f%% of the ¥%.2f budget", projected, projected/budget*100, budget)
+	title := m.tr.T("forecast.budget_near")
+	if projected > budget {
+		title = m.tr.T("forecast.budget_over")
+	}
+	message := m.tr.T("forecast.budget_warning", title, forecast.monthToDate, projected, projected/budget*100, budget)
+	if err := m.notifier.Send(message); err != nil {
+		log.Warnf("Failed to send budget warning: %v", err)
+		return
+	}
+	m.updateNotifyTime(budgetNotifyKey)
+}
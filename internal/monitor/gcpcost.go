@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/gcp"
+)
+
+const (
+	// gcpCostDays is the window of the GCP cost report
+	gcpCostDays = 30
+	// gcpCostTopServices is the number of services listed in the GCP cost report
+	gcpCostTopServices = 5
+	// gcpCreditTopServices is the number of services listed by credits consumed
+	gcpCreditTopServices = 3
+)
+
+// sendGCPCostReport sends the GCP cost of the last 30 days by service. Without a BigQuery
+// billing export only the linked billing account is shown.
+func (m *Monitor) sendGCPCostReport() error {
+	costs, err := m.gcpCost.QueryCostSummary(gcpCostDays)
+	if errors.Is(err, gcp.ErrCostDataUnavailable) {
+		return m.reply().Send(m.formatGCPBillingAccount())
+	}
+	if err != nil {
+		return m.reply().Send(fmt.Sprintf("❌ 查询 GCP 费用失败: %s", html.EscapeString(err.Error())))
+	}
+	return m.reply().Send(formatGCPCostSummary(costs))
+}
+
+// formatGCPCostSummary renders the top services by cost with their share of the total
+func formatGCPCostSummary(costs []gcp.ServiceCost) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("☁️ <b>GCP 费用</b> (近 %d 天)\n", gcpCostDays))
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	var total, credits float64
+	currency := ""
+	for _, c := range costs {
+		total += c.Cost
+		credits += c.Credits
+		if currency == "" {
+			currency = c.Currency
+		}
+	}
+	if len(costs) == 0 || (total <= 0 && credits <= 0) {
+		sb.WriteString("\n暂无费用记录")
+		return sb.String()
+	}
+	// Fully covered by credits: the credits breakdown below is all there is to show
+	if total <= 0 {
+		sb.WriteString("\n费用已全部由赠金抵扣\n")
+		sb.WriteString(formatGCPCreditsBreakdown(costs, credits))
+		sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		sb.WriteString(fmt.Sprintf("🎁 赠金抵扣: <b>%.2f %s</b> (日均 %.2f %s)", credits, currency, credits/gcpCostDays, currency))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("\n🏆 费用最高的 %d 项服务:\n", min(gcpCostTopServices, len(costs))))
+	for i, c := range costs {
+		if i >= gcpCostTopServices {
+			break
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s: <b>%.2f %s</b> (%.1f%%)\n",
+			i+1, html.EscapeString(c.Service), c.Cost, c.Currency, c.Cost/total*100))
+	}
+	if len(costs) > gcpCostTopServices {
+		var rest float64
+		for _, c := range costs[gcpCostTopServices:] {
+			rest += c.Cost
+		}
+		sb.WriteString(fmt.Sprintf("   其他 %d 项: %.2f %s (%.1f%%)\n", len(costs)-gcpCostTopServices, rest, currency, rest/total*100))
+	}
+
+	if credits > 0 {
+		sb.WriteString(formatGCPCreditsBreakdown(costs, credits))
+	}
+
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(fmt.Sprintf("💰 合计: <b>%.2f %s</b>\n", total, currency))
+	if credits > 0 {
+		sb.WriteString(fmt.Sprintf("🎁 赠金抵扣: %.2f %s (日均 %.2f %s)\n", credits, currency, credits/gcpCostDays, currency))
+	}
+	sb.WriteString("<i>数据来自 BigQuery 账单导出，已扣除抵扣金额</i>")
+	return sb.String()
+}
+
+// formatGCPCreditsBreakdown renders the services consuming the most credits
+func formatGCPCreditsBreakdown(costs []gcp.ServiceCost, credits float64) string {
+	byCredits := make([]gcp.ServiceCost, 0, len(costs))
+	for _, c := range costs {
+		if c.Credits > 0 {
+			byCredits = append(byCredits, c)
+		}
+	}
+	sort.SliceStable(byCredits, func(i, j int) bool { return byCredits[i].Credits > byCredits[j].Credits })
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n🎁 赠金抵扣最多的 %d 项服务:\n", min(gcpCreditTopServices, len(byCredits))))
+	for i, c := range byCredits {
+		if i >= gcpCreditTopServices {
+			break
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s: <b>%.2f %s</b> (%.1f%%)\n",
+			i+1, html.EscapeString(c.Service), c.Credits, c.Currency, c.Credits/credits*100))
+	}
+	return sb.String()
+}
+
+// formatGCPBillingAccount renders the linked billing account when no cost data is available
+func (m *Monitor) formatGCPBillingAccount() string {
+	var sb strings.Builder
+	sb.WriteString("☁️ <b>GCP 费用</b>\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	billing, err := m.gcpClient.GetBillingInfo()
+	switch {
+	case err != nil:
+		sb.WriteString(fmt.Sprintf("💳 结算账号: ⚠️ %s\n", html.EscapeString(err.Error())))
+	case billing.BillingAccountName == "":
+		sb.WriteString(fmt.Sprintf("💳 结算账号: 未关联 (项目 %s)\n", html.EscapeString(billing.ProjectID)))
+	default:
+		enabled := "已启用"
+		if !billing.BillingEnabled {
+			enabled = "未启用"
+		}
+		sb.WriteString(fmt.Sprintf("💳 结算账号: <code>%s</code> (%s)\n", html.EscapeString(billing.BillingAccountName), enabled))
+	}
+
+	sb.WriteString("\n<i>按服务查看费用需启用 BigQuery 账单导出并设置 <code>GCP_BIGQUERY_DATASET</code> / <code>GCP_BIGQUERY_TABLE</code></i>")
+	return sb.String()
+}
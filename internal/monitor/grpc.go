@@ -0,0 +1,205 @@
+package monitor
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/iliyian/aliyun-spot-manager/api/spotmonitorpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcService implements the SpotMonitor gRPC service on top of the monitor
+type grpcService struct {
+	spotmonitorpb.UnimplementedSpotMonitorServer
+	m *Monitor
+	// checkCtx is the context of scheduled check cycles, so a triggered cycle is not
+	// aborted when the client goes away but is cancelled on SHUTDOWN_TIMEOUT
+	checkCtx context.Context
+}
+
+// startGRPCServer serves the gRPC API on GRPC_ADDR until ctx is cancelled
+func (m *Monitor) startGRPCServer(ctx, checkCtx context.Context, wg *sync.WaitGroup) error {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(m.grpcUnaryAuth),
+		grpc.ChainStreamInterceptor(m.grpcStreamAuth),
+	}
+	mode := "plaintext"
+	if m.cfg.GRPCTLSCert != "" {
+		creds, err := credentials.NewServerTLSFromFile(m.cfg.GRPCTLSCert, m.cfg.GRPCTLSKey)
+		if err != nil {
+			return fmt.Errorf("failed to load gRPC TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+		mode = "TLS"
+	}
+
+	lis, err := net.Listen("tcp", m.cfg.GRPCAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", m.cfg.GRPCAddr, err)
+	}
+
+	server := grpc.NewServer(opts...)
+	spotmonitorpb.RegisterSpotMonitorServer(server, &grpcService{m: m, checkCtx: checkCtx})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer m.recoverAndNotify("gRPC server")
+		log.Infof("gRPC server listening on %s (%s)", m.cfg.GRPCAddr, mode)
+		if m.cfg.GRPCToken == "" {
+			log.Warn("GRPC_TOKEN is not set, the gRPC API accepts unauthenticated calls")
+		}
+		if err := server.Serve(lis); err != nil {
+			log.Errorf("gRPC server failed: %v", err)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		// Waits for in-flight calls, a triggered check cycle ends by SHUTDOWN_TIMEOUT at the latest
+		server.GracefulStop()
+		log.Info("gRPC server stopped")
+	}()
+
+	return nil
+}
+
+// grpcAuthorize checks the bearer token of a call when GRPC_TOKEN is set
+func (m *Monitor) grpcAuthorize(ctx context.Context) error {
+	if m.cfg.GRPCToken == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(m.cfg.GRPCToken)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid token")
+}
+
+func (m *Monitor) grpcUnaryAuth(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := m.grpcAuthorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (m *Monitor) grpcStreamAuth(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := m.grpcAuthorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// GetInstances streams the status of every monitored instance
+func (s *grpcService) GetInstances(_ *spotmonitorpb.GetInstancesRequest, stream grpc.ServerStreamingServer[spotmonitorpb.InstanceStatus]) error {
+	for _, inst := range s.m.InstanceStatuses() {
+		if err := stream.Send(&spotmonitorpb.InstanceStatus{
+			Provider:        inst.Provider,
+			AccountLabel:    inst.AccountLabel,
+			InstanceId:      inst.InstanceID,
+			InstanceName:    inst.InstanceName,
+			Region:          inst.Region,
+			InstanceType:    inst.InstanceType,
+			Status:          inst.Status,
+			PublicIp:        inst.PublicIP,
+			ManuallyStopped: inst.ManuallyStopped,
+			TrafficShutdown: inst.TrafficShutdown,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBillingSummary returns the billing summary of each Aliyun account
+func (s *grpcService) GetBillingSummary(ctx context.Context, req *spotmonitorpb.GetBillingSummaryRequest) (*spotmonitorpb.GetBillingSummaryResponse, error) {
+	if req.GetHours() < 0 || req.GetHours() > maxLookbackHours {
+		return nil, status.Errorf(codes.InvalidArgument, "hours must be between 0 and %d", maxLookbackHours)
+	}
+
+	summaries, err := s.m.BillingSummaries(ctx, int(req.GetHours()))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to query billing: %v", err)
+	}
+
+	resp := &spotmonitorpb.GetBillingSummaryResponse{}
+	for _, summary := range summaries {
+		account := &spotmonitorpb.AccountBilling{
+			AccountLabel:    summary.AccountLabel,
+			BillingCycle:    summary.BillingCycle,
+			StartTime:       timestamppb.New(summary.StartTime),
+			EndTime:         timestamppb.New(summary.EndTime),
+			TotalAmount:     summary.TotalAmount,
+			MonthlyEstimate: summary.MonthlyEstimate,
+			TotalSavings:    summary.TotalSavings,
+		}
+		for _, inst := range summary.Instances {
+			account.Instances = append(account.Instances, &spotmonitorpb.InstanceBilling{
+				InstanceId:   inst.InstanceID,
+				InstanceName: inst.InstanceName,
+				Region:       inst.Region,
+				InstanceSpec: inst.InstanceSpec,
+				TotalAmount:  inst.TotalAmount,
+				RunningHours: inst.RunningHours,
+				HourlyCost:   inst.HourlyCost,
+				Savings:      inst.Savings,
+			})
+		}
+		resp.Accounts = append(resp.Accounts, account)
+	}
+	return resp, nil
+}
+
+// TriggerCheck runs a check cycle right away
+func (s *grpcService) TriggerCheck(_ context.Context, _ *spotmonitorpb.TriggerCheckRequest) (*spotmonitorpb.TriggerCheckResponse, error) {
+	started, finished, err := s.m.TriggerCheck(s.checkCtx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "check failed: %v", err)
+	}
+	return &spotmonitorpb.TriggerCheckResponse{
+		StartedAt:  timestamppb.New(started),
+		FinishedAt: timestamppb.New(finished),
+	}, nil
+}
+
+// GetTrafficSummary returns the traffic of each Aliyun account this month
+func (s *grpcService) GetTrafficSummary(_ context.Context, _ *spotmonitorpb.GetTrafficSummaryRequest) (*spotmonitorpb.GetTrafficSummaryResponse, error) {
+	traffic, err := s.m.TrafficSummaries()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to query traffic: %v", err)
+	}
+
+	resp := &spotmonitorpb.GetTrafficSummaryResponse{}
+	for _, acc := range traffic {
+		account := &spotmonitorpb.AccountTraffic{
+			AccountLabel: acc.Summary.AccountLabel,
+			BillingCycle: acc.Summary.BillingCycle,
+			TotalGb:      acc.Summary.TotalTrafficGB,
+		}
+		for _, scope := range acc.Scopes {
+			account.Scopes = append(account.Scopes, &spotmonitorpb.ScopeTraffic{
+				Scope:    scope.Scope,
+				UsedGb:   scope.UsedGB,
+				LimitGb:  scope.LimitGB,
+				Shutdown: scope.Shutdown,
+			})
+		}
+		resp.Accounts = append(resp.Accounts, account)
+	}
+	return resp, nil
+}
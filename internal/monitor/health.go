@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// healthState tracks process health for the liveness and readiness probes
+type healthState struct {
+	ready       bool      // initial instance discovery succeeded
+	lastCheck   time.Time // completion time of the last Check cycle
+	checkFailed bool      // the last Check cycle panicked
+	mu          sync.RWMutex
+}
+
+// healthResponse is the JSON body of /healthz and /readyz
+type healthResponse struct {
+	Status    string `json:"status"`
+	LastCheck string `json:"last_check"`
+}
+
+// markReady marks the monitor ready to serve after initial discovery
+func (m *Monitor) markReady() {
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	m.health.ready = true
+}
+
+// recordCheckCycle records the outcome of a Check cycle for the liveness probe
+func (m *Monitor) recordCheckCycle(panicked bool) {
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	m.health.checkFailed = panicked
+	if !panicked {
+		m.health.lastCheck = time.Now()
+	}
+}
+
+// StartHealthServer serves /healthz, /readyz and the /stream/status event stream on
+// HEALTH_ADDR until ctx is cancelled.
+// The returned channel is closed once the server has shut down.
+func (m *Monitor) StartHealthServer(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		m.health.mu.RLock()
+		ok := !m.health.checkFailed
+		m.health.mu.RUnlock()
+		m.writeHealth(w, ok, "check cycle panicked")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		m.health.mu.RLock()
+		ok := m.health.ready
+		m.health.mu.RUnlock()
+		m.writeHealth(w, ok, "initial discovery pending")
+	})
+	mux.HandleFunc(streamPath, m.handleStatusStream)
+
+	server := &http.Server{
+		Addr:              m.cfg.HealthAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		defer close(done)
+
+		errCh := make(chan error, 1)
+		go func() {
+			log.Infof("Health server listening on %s (/healthz, /readyz, %s)", m.cfg.HealthAddr, streamPath)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+			close(errCh)
+		}()
+
+		select {
+		case err := <-errCh:
+			if err != nil {
+				log.Errorf("Health server failed: %v", err)
+			}
+			return
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Warnf("Failed to shut down health server: %v", err)
+		}
+	}()
+
+	return done
+}
+
+// writeHealth writes a probe response, 200 when ok and 503 otherwise
+func (m *Monitor) writeHealth(w http.ResponseWriter, ok bool, reason string) {
+	m.health.mu.RLock()
+	lastCheck := m.health.lastCheck
+	m.health.mu.RUnlock()
+
+	resp := healthResponse{Status: "ok"}
+	if !lastCheck.IsZero() {
+		resp.LastCheck = lastCheck.Format(time.RFC3339)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		resp.Status = reason
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Debugf("Failed to write health response: %v", err)
+	}
+}
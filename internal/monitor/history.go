@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// historyLimit is the number of incidents /history shows
+const historyLimit = 10
+
+// incidentLabelKeys are the message keys of the short display names of incident event types
+var incidentLabelKeys = map[string]string{
+	storage.EventReclaimDetected:     "history.event.reclaim_detected",
+	storage.EventReclaimNotice:       "history.event.reclaim_notice",
+	storage.EventStartAttempted:      "history.event.start_attempted",
+	storage.EventStartSucceeded:      "history.event.start_succeeded",
+	storage.EventStartFailed:         "history.event.start_failed",
+	storage.EventTrafficLimitHit:     "history.event.traffic_limit_hit",
+	storage.EventTrafficShutdown:     "history.event.traffic_shutdown",
+	storage.EventTrafficLimitChanged: "history.event.traffic_limit_changed",
+}
+
+// recordIncident writes an incident to the history database, if enabled
+func (m *Monitor) recordIncident(eventType, instanceID, instanceName, region string, duration time.Duration, err error) {
+	if m.db == nil {
+		return
+	}
+
+	inc := storage.Incident{
+		EventType:    eventType,
+		InstanceID:   instanceID,
+		InstanceName: instanceName,
+		Region:       region,
+		Duration:     duration,
+	}
+	if err != nil {
+		inc.Error = err.Error()
+	}
+	if err := m.db.RecordIncident(inc); err != nil {
+		log.Warnf("Failed to record %s incident for %s: %v", eventType, instanceID, err)
+	}
+}
+
+// sendIncidentHistory handles /history: sends the most recent incidents
+func (m *Monitor) sendIncidentHistory() error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if m.db == nil {
+		return m.reply().Send(m.tr.T("history.disabled"))
+	}
+
+	incidents, err := m.db.RecentIncidents(historyLimit)
+	if err != nil {
+		return m.reply().Send(m.tr.T("history.query_failed", html.EscapeString(err.Error())))
+	}
+	if len(incidents) == 0 {
+		return m.reply().Send(m.tr.T("history.empty"))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.tr.T("history.header", len(incidents)) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n<pre>")
+	for _, inc := range incidents {
+		label := inc.EventType
+		if key, ok := incidentLabelKeys[inc.EventType]; ok {
+			label = m.tr.T(key)
+		}
+		name := inc.InstanceName
+		if name == "" {
+			name = inc.InstanceID
+		}
+
+		line := fmt.Sprintf("%s  %s  %s", inc.Time.In(m.cfg.Location).Format("01-02 15:04"), label, name)
+		if inc.Region != "" {
+			line += " (" + inc.Region + ")"
+		}
+		if inc.Duration > 0 {
+			line += fmt.Sprintf(" %.0fs", inc.Duration.Seconds())
+		}
+		sb.WriteString(html.EscapeString(line) + "\n")
+		if inc.Error != "" {
+			sb.WriteString("             ↳ " + html.EscapeString(truncateRunes(inc.Error, 80)) + "\n")
+		}
+	}
+	sb.WriteString("</pre>")
+
+	return m.reply().Send(sb.String())
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookTimeout bounds each post-start webhook request
+	webhookTimeout = 15 * time.Second
+	// webhookAttempts is the number of tries before a webhook failure is reported
+	webhookAttempts = 2
+	// webhookRetryDelay is the pause between webhook attempts
+	webhookRetryDelay = 5 * time.Second
+)
+
+// startWebhookPayload is the JSON body POSTed to an instance's webhook after it started
+type startWebhookPayload struct {
+	InstanceID      string  `json:"instance_id"`
+	InstanceName    string  `json:"instance_name"`
+	RegionID        string  `json:"region_id"`
+	PublicIP        string  `json:"public_ip"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Timestamp       string  `json:"timestamp"`
+}
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// callStartWebhook POSTs the start event of an instance to its configured webhook,
+// retrying once and reporting a final failure
+func (m *Monitor) callStartWebhook(webhookURL string, inst *aliyun.SpotInstance, duration time.Duration) {
+	defer m.recoverAndNotify("instance webhook")
+
+	body, err := json.Marshal(startWebhookPayload{
+		InstanceID:      inst.InstanceID,
+		InstanceName:    inst.InstanceName,
+		RegionID:        inst.RegionID,
+		PublicIP:        inst.PublicIPAddress,
+		DurationSeconds: duration.Seconds(),
+		Timestamp:       time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Warnf("[%s] Failed to encode webhook payload for %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		return
+	}
+
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(webhookRetryDelay)
+		}
+
+		err = postWebhook(webhookURL, body)
+		if err == nil {
+			log.Infof("[%s] Post-start webhook of instance %s delivered", inst.AccountLabel, inst.InstanceID)
+			return
+		}
+		log.Warnf("[%s] Post-start webhook of instance %s failed (attempt %d/%d): %v",
+			inst.AccountLabel, inst.InstanceID, attempt, webhookAttempts, err)
+	}
+
+	if m.notifier != nil {
+		if err := m.notifier.NotifyWebhookFailed(inst.AccountLabel, inst.InstanceID, inst.InstanceName, webhookURL, webhookAttempts, err); err != nil {
+			log.Warnf("[%s] Failed to send webhook failure notification: %v", inst.AccountLabel, err)
+		}
+	}
+}
+
+// postWebhook sends a JSON body and treats any non-2xx response as a failure
+func postWebhook(webhookURL string, body []byte) error {
+	resp, err := webhookClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// httpCheckTimeout bounds each request of the HTTP health check
+const httpCheckTimeout = 30 * time.Second
+
+var (
+	httpCheckClient         = newHTTPCheckClient(false)
+	httpCheckInsecureClient = newHTTPCheckClient(true)
+)
+
+// newHTTPCheckClient returns a client that reports redirects instead of following them
+func newHTTPCheckClient(skipVerify bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if skipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{
+		Timeout:   httpCheckTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkHTTPHealth polls the INSTANCE_HEALTH_URLS endpoint of a started instance until it answers
+// with a 2xx status or HEALTH_CHECK_TIMEOUT expires, and returns a line for the started notification
+func (m *Monitor) checkHTTPHealth(instanceKey, rawURL, publicIP, accountLabel string) string {
+	if publicIP == "" && strings.Contains(rawURL, "{ip}") {
+		log.Warnf("[%s] Skipping HTTP health check of %s: instance has no public IP", accountLabel, instanceKey)
+		return "🩺 HTTP 检查: ⚠️ 实例无公网IP，已跳过"
+	}
+
+	checkURL := strings.ReplaceAll(rawURL, "{ip}", publicIP)
+	client := httpCheckClient
+	if m.tlsSkipVerifyFor(instanceKey) {
+		client = httpCheckInsecureClient
+	}
+
+	interval := time.Duration(m.cfg.HealthCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(m.cfg.HealthCheckTimeout) * time.Second)
+
+	for {
+		status, err := getHealthStatus(client, checkURL)
+		if err == nil {
+			log.Infof("[%s] HTTP health check of %s passed: %s returned %d", accountLabel, instanceKey, checkURL, status)
+			return fmt.Sprintf("🩺 HTTP 检查: ✅ %d <code>%s</code>", status, html.EscapeString(checkURL))
+		}
+		log.Debugf("[%s] HTTP health check of %s not passing yet: %v", accountLabel, instanceKey, err)
+
+		if time.Now().Add(interval).After(deadline) {
+			log.Warnf("[%s] HTTP health check of %s failed within %ds: %v", accountLabel, instanceKey, m.cfg.HealthCheckTimeout, err)
+			return fmt.Sprintf("🩺 HTTP 检查: ❌ <code>%s</code> %d 秒内未就绪: %s",
+				html.EscapeString(checkURL), m.cfg.HealthCheckTimeout, html.EscapeString(err.Error()))
+		}
+		time.Sleep(interval)
+	}
+}
+
+// getHealthStatus sends a GET request and treats any non-2xx response as a failure
+func getHealthStatus(client *http.Client, checkURL string) (int, error) {
+	resp, err := client.Get(checkURL)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
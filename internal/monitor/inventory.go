@@ -0,0 +1,234 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/gcp"
+)
+
+// inventoryCacheTTL is how long a rendered /inventory report is reused
+const inventoryCacheTTL = 60 * time.Second
+
+// inventoryCache holds the last rendered /inventory report
+type inventoryCache struct {
+	message   string
+	fetchedAt time.Time
+	mu        sync.Mutex
+}
+
+// inventoryRegion holds the resources of one account in one region
+type inventoryRegion struct {
+	accountLabel string
+	regionID     string
+	hasCBWP      bool // EIPs and bandwidth packages were queried
+
+	instances    []*aliyun.SpotInstance
+	instancesErr error
+	eips         []*aliyun.EIPInfo
+	eipsErr      error
+	packages     []*aliyun.BandwidthPackage
+	packagesErr  error
+}
+
+// sendInventory handles /inventory: sends all spot instances, EIPs and bandwidth packages
+// of the monitored regions, grouped by region, plus GCP instances and billing account
+func (m *Monitor) sendInventory() error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	m.inventory.mu.Lock()
+	defer m.inventory.mu.Unlock()
+	if m.inventory.message == "" || time.Since(m.inventory.fetchedAt) >= inventoryCacheTTL {
+		m.inventory.message = m.buildInventory()
+		m.inventory.fetchedAt = time.Now()
+	}
+
+	return m.reply().Send(m.inventory.message)
+}
+
+// buildInventory queries all resources concurrently and renders the report
+func (m *Monitor) buildInventory() string {
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	m.mu.RLock()
+	seen := make(map[string]bool)
+	var regions []*inventoryRegion
+	for _, inst := range m.instances {
+		key := inst.AccountLabel + "/" + inst.RegionID
+		if !seen[key] {
+			seen[key] = true
+			regions = append(regions, &inventoryRegion{accountLabel: inst.AccountLabel, regionID: inst.RegionID})
+		}
+	}
+	gcpInstances := make([]*gcp.PreemptibleInstance, len(m.gcpInstances))
+	copy(gcpInstances, m.gcpInstances)
+	m.mu.RUnlock()
+
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].regionID != regions[j].regionID {
+			return regions[i].regionID < regions[j].regionID
+		}
+		return regions[i].accountLabel < regions[j].accountLabel
+	})
+
+	var wg sync.WaitGroup
+	for _, r := range regions {
+		if ecsClient := m.getECSClientByLabel(r.accountLabel); ecsClient != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.instances, r.instancesErr = ecsClient.GetSpotInstances(ctx, r.regionID, r.accountLabel)
+			}()
+		}
+		if cbwpClient := m.getCBWPClientByLabel(r.accountLabel); cbwpClient != nil {
+			r.hasCBWP = true
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				r.eips, r.eipsErr = cbwpClient.DescribeAllEipAddresses(r.regionID)
+			}()
+			go func() {
+				defer wg.Done()
+				r.packages, r.packagesErr = cbwpClient.DescribeCommonBandwidthPackages(r.regionID)
+			}()
+		}
+	}
+
+	var billing *gcp.BillingInfo
+	var billingErr error
+	if m.gcpClient != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			billing, billingErr = m.gcpClient.GetBillingInfo()
+		}()
+	}
+	wg.Wait()
+
+	var sb strings.Builder
+	sb.WriteString("🗂 <b>资源清单</b>\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	if len(regions) == 0 && m.gcpClient == nil {
+		sb.WriteString("\n暂无监控的实例")
+	}
+
+	for _, r := range regions {
+		label := ""
+		if r.accountLabel != "" {
+			label = fmt.Sprintf(" [%s]", html.EscapeString(r.accountLabel))
+		}
+		sb.WriteString(fmt.Sprintf("\n📍 <b>%s</b>%s\n", r.regionID, label))
+		writeInventoryInstances(&sb, r, len(m.cfg.InstanceVPCFilter) > 0)
+		writeInventoryEIPs(&sb, r)
+		writeInventoryPackages(&sb, r)
+	}
+
+	if m.gcpClient != nil {
+		sb.WriteString("\n☁️ <b>GCP</b>\n")
+		switch {
+		case billingErr != nil:
+			sb.WriteString(fmt.Sprintf("💳 结算账号: ⚠️ %s\n", html.EscapeString(billingErr.Error())))
+		case billing.BillingAccountName == "":
+			sb.WriteString(fmt.Sprintf("💳 结算账号: 未关联 (项目 %s)\n", html.EscapeString(billing.ProjectID)))
+		default:
+			enabled := "已启用"
+			if !billing.BillingEnabled {
+				enabled = "未启用"
+			}
+			sb.WriteString(fmt.Sprintf("💳 结算账号: <code>%s</code> (%s, 项目 %s)\n",
+				html.EscapeString(billing.BillingAccountName), enabled, html.EscapeString(billing.ProjectID)))
+		}
+		sb.WriteString(fmt.Sprintf("🖥 实例 (%d):\n", len(gcpInstances)))
+		for _, inst := range gcpInstances {
+			sb.WriteString(fmt.Sprintf("   • %s (%s) %s %s\n",
+				html.EscapeString(inst.InstanceName), inst.Zone, inst.Status, inst.ExternalIP))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n<i>缓存 %d 秒</i>", int(inventoryCacheTTL.Seconds())))
+	return truncateTelegramMessage(sb.String())
+}
+
+// writeInventoryInstances renders the spot instances of a region, with their VPC if showVPC
+func writeInventoryInstances(sb *strings.Builder, r *inventoryRegion, showVPC bool) {
+	if r.instancesErr != nil {
+		sb.WriteString(fmt.Sprintf("🖥 实例: ⚠️ %s\n", html.EscapeString(r.instancesErr.Error())))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("🖥 实例 (%d):\n", len(r.instances)))
+	for _, inst := range r.instances {
+		name := inst.InstanceName
+		if name == "" {
+			name = inst.InstanceID
+		}
+		sb.WriteString(fmt.Sprintf("   • %s (<code>%s</code>) %s %s\n",
+			html.EscapeString(name), inst.InstanceID, inst.Status, inst.PublicIPAddress))
+		if showVPC {
+			sb.WriteString(fmt.Sprintf("     VPC: <code>%s</code>\n", inst.VpcID))
+		}
+	}
+}
+
+// writeInventoryEIPs renders the EIPs of a region
+func writeInventoryEIPs(sb *strings.Builder, r *inventoryRegion) {
+	if !r.hasCBWP {
+		return
+	}
+	if r.eipsErr != nil {
+		sb.WriteString(fmt.Sprintf("🌐 EIP: ⚠️ %s\n", html.EscapeString(r.eipsErr.Error())))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("🌐 EIP (%d):\n", len(r.eips)))
+	for _, eip := range r.eips {
+		target := "未绑定"
+		if eip.InstanceID != "" {
+			target = "→ " + eip.InstanceID
+		}
+		sb.WriteString(fmt.Sprintf("   • %s %s (%s)\n", eip.IPAddress, target, eip.Status))
+	}
+}
+
+// writeInventoryPackages renders the bandwidth packages of a region with their member EIPs
+func writeInventoryPackages(sb *strings.Builder, r *inventoryRegion) {
+	if r.packagesErr != nil {
+		sb.WriteString(fmt.Sprintf("📶 共享带宽包: ⚠️ %s\n", html.EscapeString(r.packagesErr.Error())))
+		return
+	}
+	if len(r.packages) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("📶 共享带宽包 (%d):\n", len(r.packages)))
+	for _, pkg := range r.packages {
+		name := pkg.Name
+		if name == "" {
+			name = pkg.BandwidthPackageID
+		}
+		members := "无 EIP"
+		if len(pkg.MemberIPs) > 0 {
+			members = strings.Join(pkg.MemberIPs, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("   • %s (<code>%s</code>) %s Mbps: %s\n",
+			html.EscapeString(name), pkg.BandwidthPackageID, pkg.Bandwidth, members))
+	}
+}
+
+// truncateTelegramMessage cuts a message at a line boundary to fit telegramMessageLimit
+func truncateTelegramMessage(message string) string {
+	const suffix = "\n…（内容过长，已截断）"
+	if len(message) <= telegramMessageLimit {
+		return message
+	}
+	cut := strings.LastIndex(message[:telegramMessageLimit-len(suffix)], "\n")
+	if cut < 0 {
+		cut = telegramMessageLimit - len(suffix)
+	}
+	return message[:cut] + suffix
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/logbuf"
+)
+
+const (
+	// defaultLogLines is the number of lines /logs sends without an argument
+	defaultLogLines = 50
+	// telegramMessageLimit is the maximum length of a Telegram message
+	telegramMessageLimit = 4096
+)
+
+// SetLogBuffer attaches the in-memory log buffer served by /logs
+func (m *Monitor) SetLogBuffer(buf *logbuf.Buffer) {
+	m.logBuffer = buf
+}
+
+// sendRecentLogs handles /logs [N]: sends the last N captured log lines
+func (m *Monitor) sendRecentLogs(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if m.logBuffer == nil {
+		return m.reply().Send(m.tr.T("logs.disabled"))
+	}
+
+	n := defaultLogLines
+	if len(args) > 0 {
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 1 {
+			return m.reply().Send(m.tr.T("logs.invalid_lines", html.EscapeString(args[0]), m.logBuffer.Size()))
+		}
+		n = v
+	}
+
+	lines := m.logBuffer.Last(n)
+	if len(lines) == 0 {
+		return m.reply().Send(m.tr.T("logs.empty"))
+	}
+
+	// Drop the oldest lines until the message fits into a single Telegram message
+	header := func(n int) string { return m.tr.T("logs.header", n) + "\n<pre>" }
+	const footer = "</pre>"
+	truncatedNote := func(n int) string { return "\n" + m.tr.T("logs.truncated", n) }
+
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = html.EscapeString(line)
+	}
+
+	start := 0
+	for ; start < len(escaped); start++ {
+		body := strings.Join(escaped[start:], "\n")
+		size := len(header(len(escaped)-start)) + len(body) + len(footer)
+		if start > 0 {
+			size += len(truncatedNote(start))
+		}
+		if size <= telegramMessageLimit {
+			break
+		}
+	}
+	if start == len(escaped) {
+		// A single line too long for a message: cut it
+		start = len(escaped) - 1
+		escaped[start] = html.EscapeString(truncateRunes(lines[start], telegramMessageLimit/2))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header(len(escaped) - start))
+	sb.WriteString(strings.Join(escaped[start:], "\n"))
+	sb.WriteString(footer)
+	if start > 0 {
+		sb.WriteString(truncatedNote(start))
+	}
+
+	return m.reply().Send(sb.String())
+}
+
+// truncateRunes shortens s to at most max runes
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max]) + "…"
+}
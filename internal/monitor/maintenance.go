@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/config"
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+// maintenanceWindow is a configured maintenance window with its parsed schedule
+type maintenanceWindow struct {
+	config.MaintenanceWindow
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// newMaintenanceWindows parses the schedules of the configured windows
+func newMaintenanceWindows(windows []config.MaintenanceWindow) ([]*maintenanceWindow, error) {
+	result := make([]*maintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		schedule, err := cron.ParseStandard(w.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window cron %q: %w", w.Cron, err)
+		}
+		result = append(result, &maintenanceWindow{
+			MaintenanceWindow: w,
+			schedule:          schedule,
+			duration:          time.Duration(w.DurationMinutes) * time.Minute,
+		})
+	}
+	return result, nil
+}
+
+// matches reports whether the window covers the instance
+func (w *maintenanceWindow) matches(instanceID, regionID string) bool {
+	return (len(w.InstanceIDs) == 0 || slices.Contains(w.InstanceIDs, instanceID)) &&
+		(len(w.Regions) == 0 || slices.Contains(w.Regions, regionID))
+}
+
+// activeAt reports whether the window is open at t and when it closes. A window is open
+// if its schedule fired within the last duration.
+func (w *maintenanceWindow) activeAt(t time.Time) (time.Time, bool) {
+	start := w.schedule.Next(t.Add(-w.duration))
+	if start.After(t) {
+		return time.Time{}, false
+	}
+	return start.Add(w.duration), true
+}
+
+// describe returns the instances and regions the window applies to, for notifications
+func (w *maintenanceWindow) describe() (instances, regions string) {
+	instances, regions = "全部实例", "全部区域"
+	if len(w.InstanceIDs) > 0 {
+		instances = strings.Join(w.InstanceIDs, ", ")
+	}
+	if len(w.Regions) > 0 {
+		regions = strings.Join(w.Regions, ", ")
+	}
+	return instances, regions
+}
+
+// maintenanceUntil reports whether an instance is in an open maintenance window and when
+// the last of its open windows closes
+func (m *Monitor) maintenanceUntil(instanceID, regionID string) (time.Time, bool) {
+	now := time.Now()
+	var until time.Time
+	for _, w := range m.maintenanceWindows {
+		if !w.matches(instanceID, regionID) {
+			continue
+		}
+		if end, ok := w.activeAt(now); ok && end.After(until) {
+			until = end
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// scheduleMaintenanceWindows registers the start/end notifications of all windows with c
+func (m *Monitor) scheduleMaintenanceWindows(c *cron.Cron) error {
+	for _, w := range m.maintenanceWindows {
+		if _, err := c.AddFunc(w.Cron, func() {
+			defer m.recoverAndNotify("maintenance window")
+			m.notifyMaintenanceStarted(w)
+			time.AfterFunc(w.duration, func() {
+				defer m.recoverAndNotify("maintenance window")
+				m.notifyMaintenanceEnded(w)
+			})
+		}); err != nil {
+			return fmt.Errorf("failed to setup maintenance window cron %q: %w", w.Cron, err)
+		}
+
+		instances, regions := w.describe()
+		log.Infof("Maintenance window scheduled: %s for %d minutes (instances: %s, regions: %s)",
+			w.Cron, w.DurationMinutes, instances, regions)
+	}
+	return nil
+}
+
+func (m *Monitor) notifyMaintenanceStarted(w *maintenanceWindow) {
+	instances, regions := w.describe()
+	end := time.Now().Add(w.duration)
+	log.Infof("Maintenance window started (instances: %s, regions: %s), auto-start suppressed until %s",
+		instances, regions, end.Format("15:04:05"))
+
+	if m.notifier == nil {
+		return
+	}
+	if err := m.notifier.NotifyMaintenanceStarted(instances, regions, end); err != nil {
+		log.Warnf("Failed to send maintenance window notification: %v", err)
+	}
+}
+
+func (m *Monitor) notifyMaintenanceEnded(w *maintenanceWindow) {
+	instances, regions := w.describe()
+	log.Infof("Maintenance window ended (instances: %s, regions: %s), auto-start resumed", instances, regions)
+
+	if m.notifier == nil {
+		return
+	}
+	if err := m.notifier.NotifyMaintenanceEnded(instances, regions); err != nil {
+		log.Warnf("Failed to send maintenance window notification: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun/metadata"
+	log "github.com/sirupsen/logrus"
+)
+
+// startMetadataPoller watches the metadata service of the instance the monitor runs on
+// for a spot reclaim notice until ctx is cancelled
+func (m *Monitor) startMetadataPoller(ctx context.Context, wg *sync.WaitGroup) {
+	interval := time.Duration(m.cfg.MetadataPollInterval) * time.Second
+	poller := metadata.NewMetadataPoller(interval)
+
+	instanceID, err := poller.InstanceID(ctx)
+	if err != nil {
+		log.Warnf("Metadata polling enabled but the metadata service is unreachable (not running on ECS?): %v", err)
+		instanceID = "unknown"
+	}
+	log.Infof("Polling spot termination notice of instance %s every %s", instanceID, interval)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer m.recoverAndNotify("metadata poller")
+		poller.Run(ctx, func(terminationTime time.Time) {
+			m.handleTerminationNotice(ctx, instanceID, terminationTime)
+		})
+	}()
+}
+
+// handleTerminationNotice announces the impending reclaim of this instance and runs the
+// configured shutdown script
+func (m *Monitor) handleTerminationNotice(ctx context.Context, instanceID string, terminationTime time.Time) {
+	log.Warnf("Spot reclaim notice: instance %s will be released at %s (in %s)",
+		instanceID, terminationTime.Local().Format("2006-01-02 15:04:05"), time.Until(terminationTime).Round(time.Second))
+
+	scriptResult := ""
+	if m.cfg.SpotTerminationScript != "" {
+		scriptResult = m.runTerminationScript(ctx, instanceID, terminationTime)
+	}
+
+	if m.notifier != nil {
+		if err := m.notifier.NotifySpotTermination(instanceID, terminationTime, scriptResult); err != nil {
+			log.Warnf("Failed to send spot termination notification: %v", err)
+		}
+	}
+}
+
+// runTerminationScript runs SPOT_TERMINATION_SCRIPT, which must finish before the instance
+// is released, and returns a short result for the notification
+func (m *Monitor) runTerminationScript(ctx context.Context, instanceID string, terminationTime time.Time) string {
+	ctx, cancel := context.WithDeadline(ctx, terminationTime)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, m.cfg.SpotTerminationScript)
+	cmd.Env = append(os.Environ(),
+		"INSTANCE_ID="+instanceID,
+		"TERMINATION_TIME="+terminationTime.Format(time.RFC3339),
+	)
+
+	log.Infof("Running spot termination script %s", m.cfg.SpotTerminationScript)
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		log.Infof("Spot termination script output: %s", truncateRunes(string(output), 1000))
+	}
+	if err != nil {
+		log.Errorf("Spot termination script failed: %v", err)
+		return "❌ 关机脚本执行失败: " + err.Error()
+	}
+
+	log.Infof("Spot termination script finished in %s", time.Since(start).Round(time.Second))
+	return "✅ 关机脚本已执行"
+}
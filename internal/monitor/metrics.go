@@ -0,0 +1,157 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+// Metric names exposed on /metrics
+const (
+	metricInstanceStatus        = "spot_instance_status"
+	metricInstanceReclaimTotal  = "spot_instance_reclaim_total"
+	metricInstanceStartDuration = "spot_instance_start_duration_seconds"
+	metricTrafficUsedGB         = "traffic_used_gb"
+	metricBillingTotalCNY       = "billing_total_cny"
+
+	metricCircuitBreakerState       = "circuit_breaker_state"
+	metricCircuitBreakerTransitions = "circuit_breaker_transitions_total"
+)
+
+// costMetricsInterval limits how often billing (and, without traffic shutdown, traffic)
+// metrics are refreshed from the Check cycle, as those APIs are slow and rate limited
+const costMetricsInterval = 10 * time.Minute
+
+// costMetricsState tracks background refreshes of billing and traffic metrics
+type costMetricsState struct {
+	lastRefresh time.Time
+	running     bool
+	mu          sync.Mutex
+}
+
+// newMetricsRegistry declares all monitor metrics
+func newMetricsRegistry() *metrics.Registry {
+	r := metrics.NewRegistry()
+	r.Register(metricInstanceStatus, "Spot instance status (1 = Running, 0 = otherwise).", metrics.TypeGauge, nil)
+	r.Register(metricInstanceReclaimTotal, "Number of times the instance was found reclaimed.", metrics.TypeCounter, nil)
+	r.Register(metricInstanceStartDuration, "Time taken to bring a reclaimed instance back to Running.", metrics.TypeHistogram,
+		[]float64{15, 30, 60, 90, 120, 180, 300, 600})
+	r.Register(metricTrafficUsedGB, "Internet traffic used this month in GB.", metrics.TypeGauge, nil)
+	r.Register(metricBillingTotalCNY, "Instance cost this month in CNY.", metrics.TypeGauge, nil)
+	r.Register(metricCircuitBreakerState, "ECS API circuit breaker state of a region (1 = current state).", metrics.TypeGauge, nil)
+	r.Register(metricCircuitBreakerTransitions, "Number of times a region's ECS API circuit breaker entered the state.", metrics.TypeCounter, nil)
+	return r
+}
+
+// instanceLabels returns the identifying metric labels of an instance
+func instanceLabels(instanceID, instanceName, regionID string) metrics.Labels {
+	return metrics.Labels{
+		"instance_id":   instanceID,
+		"instance_name": instanceName,
+		"region_id":     regionID,
+	}
+}
+
+// recordInstanceMetrics updates the status and reclaim count of an instance.
+// stateKey is the key of the instance in the state store.
+func (m *Monitor) recordInstanceMetrics(stateKey, instanceID, instanceName, regionID string, running bool) {
+	if m.metrics == nil {
+		return
+	}
+
+	labels := instanceLabels(instanceID, instanceName, regionID)
+	value := 0.0
+	if running {
+		value = 1
+	}
+	m.metrics.Set(metricInstanceStatus, labels, value)
+	m.metrics.Set(metricInstanceReclaimTotal, labels, float64(m.state.Get(stateKey).ReclaimCount))
+}
+
+// observeStartDuration records how long an instance took to start
+func (m *Monitor) observeStartDuration(instanceID, instanceName, regionID string, d time.Duration) {
+	if m.metrics == nil {
+		return
+	}
+	m.metrics.Observe(metricInstanceStartDuration, instanceLabels(instanceID, instanceName, regionID), d.Seconds())
+}
+
+// forgetInstanceMetrics drops all series of an instance that is no longer tracked
+func (m *Monitor) forgetInstanceMetrics(instanceID string) {
+	if m.metrics == nil {
+		return
+	}
+	for _, name := range []string{metricInstanceStatus, metricInstanceReclaimTotal, metricInstanceStartDuration, metricBillingTotalCNY} {
+		m.metrics.DeleteMatching(name, "instance_id", instanceID)
+	}
+}
+
+// recordTrafficMetrics updates traffic gauges from a traffic summary
+func (m *Monitor) recordTrafficMetrics(summary *aliyun.TrafficSummary) {
+	if m.metrics == nil || summary == nil {
+		return
+	}
+	m.metrics.Set(metricTrafficUsedGB, metrics.Labels{"account": summary.AccountLabel, "region_group": "china"}, summary.ChinaMainland.TrafficGB)
+	m.metrics.Set(metricTrafficUsedGB, metrics.Labels{"account": summary.AccountLabel, "region_group": "non_china"}, summary.NonChinaMainland.TrafficGB)
+}
+
+// maybeRefreshCostMetrics refreshes billing and traffic metrics in the background,
+// at most once per costMetricsInterval
+func (m *Monitor) maybeRefreshCostMetrics() {
+	if m.metrics == nil {
+		return
+	}
+
+	m.costMetrics.mu.Lock()
+	if m.costMetrics.running || time.Since(m.costMetrics.lastRefresh) < costMetricsInterval {
+		m.costMetrics.mu.Unlock()
+		return
+	}
+	m.costMetrics.running = true
+	m.costMetrics.mu.Unlock()
+
+	go func() {
+		defer m.recoverAndNotify("cost metrics refresh")
+		defer func() {
+			m.costMetrics.mu.Lock()
+			m.costMetrics.running = false
+			m.costMetrics.lastRefresh = time.Now()
+			m.costMetrics.mu.Unlock()
+		}()
+		m.refreshCostMetrics()
+	}()
+}
+
+// refreshCostMetrics queries billing (and traffic when not checked elsewhere) for every account
+func (m *Monitor) refreshCostMetrics() {
+	instancesByAccount := m.billingInstancesByAccount()
+
+	for _, acc := range m.aliyunClients {
+		// CheckTraffic already records traffic metrics when traffic shutdown is enabled
+		if !m.cfg.TrafficShutdownEnabled && acc.TrafficClient != nil {
+			summary, err := acc.TrafficClient.QueryInternetTraffic(acc.Account.Label)
+			if err != nil {
+				log.Debugf("[%s] Failed to query traffic for metrics: %v", acc.Account.Label, err)
+			} else {
+				m.recordTrafficMetrics(summary)
+			}
+		}
+
+		instanceInfos := instancesByAccount[acc.Account.Label]
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
+			continue
+		}
+		summary, err := acc.BillingClient.QueryBilling(context.Background(), instanceInfos, acc.Account.Label)
+		if err != nil {
+			log.Debugf("[%s] Failed to query billing for metrics: %v", acc.Account.Label, err)
+			continue
+		}
+		for _, inst := range summary.Instances {
+			m.metrics.Set(metricBillingTotalCNY, metrics.Labels{"instance_id": inst.InstanceID}, inst.TotalAmount)
+		}
+	}
+}
@@ -1,89 +1,284 @@
 package monitor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"html"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun/cloudmonitor"
+	"github.com/iliyian/aliyun-spot-manager/internal/aws"
 	"github.com/iliyian/aliyun-spot-manager/internal/config"
 	"github.com/iliyian/aliyun-spot-manager/internal/gcp"
+	"github.com/iliyian/aliyun-spot-manager/internal/i18n"
+	"github.com/iliyian/aliyun-spot-manager/internal/logbuf"
+	"github.com/iliyian/aliyun-spot-manager/internal/metrics"
 	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	"github.com/iliyian/aliyun-spot-manager/internal/ratelimit"
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // AliyunAccountClients holds all clients for a single Aliyun account
 type AliyunAccountClients struct {
-	Account       config.AliyunAccount
-	ECSClient     *aliyun.ECSClient
-	BillingClient *aliyun.BillingClient
-	TrafficClient *aliyun.TrafficClient
-	CBWPClient    *aliyun.CBWPClient
+	Account            config.AliyunAccount
+	ECSClient          *aliyun.ECSClient
+	BillingClient      *aliyun.BillingClient
+	TrafficClient      *aliyun.TrafficClient
+	CBWPClient         *aliyun.CBWPClient
+	CloudMonitorClient *cloudmonitor.CloudMonitorClient
 }
 
+// tracer creates the root span of every check cycle, exported when tracing is enabled
+var tracer = otel.Tracer("github.com/iliyian/aliyun-spot-manager/internal/monitor")
+
+// maxLookbackHours caps look-back windows accepted by bot commands (30 days)
+const maxLookbackHours = 720
+
 // Monitor monitors spot instances and auto-starts them when stopped
 type Monitor struct {
 	cfg           *config.Config
+	loadedCfg     *config.Config // configuration as last loaded, compared on reload
+	reloadMu      sync.RWMutex   // guards the cfg fields changed by a reload
+	checkJobs     []*scheduledCheck
 	aliyunClients []*AliyunAccountClients
 	gcpClient     *gcp.ComputeClient
-	notifier      *notify.TelegramNotifier
+	gcpCost       gcp.CostSummaryQuerier   // BigQuery billing export, or gcpClient when not configured
+	awsClient     *aws.EC2Client           // nil unless AWS_ACCESS_KEY_ID is set
+	providers     []CloudProvider          // Aliyun accounts, then AWS
+	notifier      notify.Notifier          // every configured notification channel, nil if none
+	telegram      *notify.TelegramNotifier // bot command replies, nil if Telegram is disabled
 	botHandler    *notify.BotHandler
+	tr            i18n.Translator // language of notifications, /help and /status (BOT_LANGUAGE)
+
+	// Context of API operations outside check cycles (bot commands, traffic shutdown), set by
+	// Run and cancelled when SHUTDOWN_TIMEOUT expires after a shutdown signal
+	runCtx context.Context
 
 	// Tracked instances
 	instances    []*aliyun.SpotInstance
 	gcpInstances []*gcp.PreemptibleInstance
+	awsInstances []*aws.SpotInstance
 	mu           sync.RWMutex
 
-	// Notification cooldown tracking
-	lastNotify   map[string]time.Time
-	lastNotifyMu sync.RWMutex
+	// Persistent per-instance state (reclaim counts, notification cooldowns)
+	state *state.Store
+
+	// Incident history database, nil when DB_PATH is empty
+	db *storage.DB
+
+	// Scheduled windows in which auto-start is suppressed
+	maintenanceWindows []*maintenanceWindow
+
+	// Liveness / readiness state for the health server
+	health healthState
+
+	// Subscribers of the /stream/status event stream
+	broadcaster statusBroadcaster
+
+	// Recent log lines for /logs, nil when not attached
+	logBuffer *logbuf.Buffer
+
+	// Build version reported by /export
+	version string
+
+	// Prometheus metrics, nil when disabled
+	metrics     *metrics.Registry
+	costMetrics costMetricsState
+
+	// Regions whose ECS circuit breaker is open
+	circuitOutages circuitOutages
+
+	// Recently queried spot prices for /price
+	prices priceCache
+
+	// Last /inventory report
+	inventory inventoryCache
+
+	// Last recorded status of each instance for /uptime
+	statuses statusTracker
+
+	// Per-instance check/retry overrides and last check time, keyed by instance ID (GCP: name)
+	overrides     map[string]config.InstanceOverride
+	lastChecked   map[string]time.Time
+	lastCheckedMu sync.Mutex
+
+	// Instances under a manual operation (e.g. /restart) that auto-start must not touch
+	manualOps   map[string]bool
+	manualOpsMu sync.RWMutex
+
+	// Pending /restart confirmations: instance ID -> time the dialog was sent
+	pendingRestarts   map[string]time.Time
+	pendingRestartsMu sync.Mutex
+
+	// Pending /allocate-eip confirmations: instance ID -> time the dialog was sent
+	pendingEIPs   map[string]time.Time
+	pendingEIPsMu sync.Mutex
+
+	// Instances with a start in progress: instance key -> name, listed when shutdown times out
+	starting   map[string]string
+	startingMu sync.Mutex
 
 	// NoStock tracking - instances that cannot start due to resource sold out
 	noStockInstances   map[string]bool
 	noStockInstancesMu sync.RWMutex
 
-	// Traffic shutdown tracking (per-account, independent for China/non-China)
-	chinaShutdown     map[string]bool // account label -> shutdown state
-	nonChinaShutdown  map[string]bool // account label -> shutdown state
+	// Traffic shutdown tracking (per-account, independent for each limit scope)
+	trafficShutdown   map[string]map[string]bool // account label -> limit scope -> shutdown state
+	trafficWarned     map[string]int             // "cycle|account|scope" -> highest warning threshold sent
+	trafficRestarts   map[string]time.Time       // instance ID -> earliest restart after the monthly traffic reset
 	trafficShutdownMu sync.RWMutex
+
+	// Runtime traffic limits (/setlimit)
+	limits trafficLimitState
+
+	// Per-chat notification types (/notify)
+	notifyPrefs notifyPreferences
+
+	// Scheduled system events already warned about
+	reclaimEvents reclaimEventWarnings
 }
 
 // New creates a new monitor
 func New(cfg *config.Config) (*Monitor, error) {
 	m := &Monitor{
 		cfg:              cfg,
-		lastNotify:       make(map[string]time.Time),
+		loadedCfg:        snapshotConfig(cfg),
+		runCtx:           context.Background(),
+		tr:               i18n.New(cfg.BotLanguage),
 		noStockInstances: make(map[string]bool),
-		chinaShutdown:    make(map[string]bool),
-		nonChinaShutdown: make(map[string]bool),
+		overrides:        cfg.InstanceOverrides,
+		lastChecked:      make(map[string]time.Time),
+		manualOps:        make(map[string]bool),
+		starting:         make(map[string]string),
+		pendingRestarts:  make(map[string]time.Time),
+		pendingEIPs:      make(map[string]time.Time),
+		trafficShutdown:  make(map[string]map[string]bool),
+		trafficWarned:    make(map[string]int),
+		trafficRestarts:  make(map[string]time.Time),
+		circuitOutages:   circuitOutages{since: make(map[string]time.Time)},
+		prices:           priceCache{entries: make(map[string]priceCacheEntry)},
+		statuses:         statusTracker{last: make(map[string]string)},
+		limits:           trafficLimitState{pending: make(map[string]pendingLimit)},
+		notifyPrefs:      notifyPreferences{enabled: make(map[string]map[string]bool)},
+		reclaimEvents:    reclaimEventWarnings{sent: make(map[string]time.Time)},
+	}
+
+	if cfg.DBPath != "" {
+		db, err := storage.Open(cfg.DBPath)
+		if err != nil {
+			return nil, err
+		}
+		m.db = db
 	}
 
+	// An explicit STATE_FILE keeps the JSON state file, otherwise state lives in the database
+	var store *state.Store
+	var err error
+	if cfg.StateFile == "" && m.db != nil {
+		store, err = state.OpenBackend(m.db)
+	} else {
+		store, err = state.Open(cfg.StateFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	m.state = store
+	if cfg.StateFile != "" {
+		log.Infof("Loaded state for %d instance(s) from %s", store.Len(), cfg.StateFile)
+	} else if m.db != nil {
+		log.Infof("Loaded state for %d instance(s) from %s", store.Len(), cfg.DBPath)
+	}
+	m.loadTrafficLimitOverrides()
+	m.loadNotifyPreferences()
+
+	if cfg.MetricsEnabled {
+		m.metrics = newMetricsRegistry()
+	}
+
+	notify.SetLocation(cfg.Location)
+	var notifiers []notify.Notifier
+	// The notifier and the bot handler talk to the same API, so they share one connection pool
+	telegramClient := notify.NewHTTPClient()
 	if cfg.TelegramEnabled {
-		m.notifier = notify.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID)
+		m.telegram = notify.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatIDs, telegramClient)
+		m.telegram.SetBatching(time.Duration(cfg.NotifyBatchWindow)*time.Second, cfg.NotifyBatchThreshold)
+		m.telegram.SetPreferences(&m.notifyPrefs)
+		m.telegram.SetParseMode(cfg.TelegramParseMode)
+		m.telegram.SetTranslator(m.tr)
+		notifiers = append(notifiers, m.telegram)
+	}
+	if cfg.DiscordWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewDiscordNotifier(cfg.DiscordWebhookURL))
+	}
+	if cfg.SlackWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewSlackNotifier(cfg.SlackWebhookURL, cfg.SlackUsername, cfg.SlackIconEmoji))
+	}
+	if cfg.DingTalkWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewDingTalkNotifier(cfg.DingTalkWebhookURL, cfg.DingTalkSecret))
 	}
+	if cfg.WeChatWorkKey != "" {
+		notifiers = append(notifiers, notify.NewWeChatWorkNotifier(cfg.WeChatWorkKey))
+	}
+	m.notifier = notify.NewMultiNotifier(notifiers...)
 
 	// Initialize Aliyun clients for each account
 	for _, acc := range cfg.AliyunAccounts {
-		clients := &AliyunAccountClients{
-			Account:   acc,
-			ECSClient: aliyun.NewECSClient(acc.AccessKeyID, acc.AccessKeySecret),
+		cred := aliyun.Credential{
+			AccessKeyID:     acc.AccessKeyID,
+			AccessKeySecret: acc.AccessKeySecret,
+			RAMRoleName:     acc.RAMRoleName,
 		}
-
-		if cfg.TelegramEnabled {
-			billingClient, err := aliyun.NewBillingClient(acc.AccessKeyID, acc.AccessKeySecret)
+		ecsClient := aliyun.NewECSClient(cred)
+		clients := &AliyunAccountClients{
+			Account:            acc,
+			ECSClient:          ecsClient,
+			CloudMonitorClient: cloudmonitor.NewCloudMonitorClient(ecsClient),
+		}
+		clients.ECSClient.SetTagFilter(cfg.InstanceFilterTags)
+		clients.ECSClient.SetNameFilter(cfg.InstanceNamePatterns, cfg.InstanceNameDenyPatterns)
+		clients.ECSClient.SetTypeFilter(cfg.InstanceTypeAllowlist, cfg.InstanceTypeDenylist)
+		clients.ECSClient.SetVPCFilter(cfg.InstanceVPCFilter)
+		clients.ECSClient.SetSecurityGroupFilter(cfg.InstanceSGFilter)
+		clients.ECSClient.SetResourceGroup(cfg.ResourceGroupID)
+		clients.ECSClient.SetDryRun(cfg.DryRun)
+		clients.ECSClient.SetRateLimit(cfg.ECSAPIRPS)
+		if cfg.CircuitBreakerThreshold > 0 {
+			label := acc.Label
+			clients.ECSClient.SetCircuitBreaker(cfg.CircuitBreakerThreshold, time.Duration(cfg.CircuitBreakerTimeout)*time.Second,
+				func(regionID string, from, to ratelimit.State) { m.onCircuitChange(label, regionID, from, to) })
+		}
+
+		// Billing client for bot commands, the billing digest or billing metrics
+		if m.notifier != nil || cfg.MetricsEnabled {
+			billingClient, err := aliyun.NewBillingClient(cred)
 			if err != nil {
 				log.Warnf("[%s] Failed to create billing client: %v", acc.Label, err)
 			} else {
+				billingClient.SetOnDemandPricer(clients.ECSClient)
+				billingClient.SetCacheTTL(time.Duration(cfg.BillingCacheTTL) * time.Second)
 				clients.BillingClient = billingClient
 			}
+		}
 
-			clients.CBWPClient = aliyun.NewCBWPClient(acc.AccessKeyID, acc.AccessKeySecret)
+		// CBWP client for bot commands or auto-unbind on traffic shutdown
+		if cfg.TelegramEnabled || cfg.CBWPAutoUnbindOnShutdown || len(cfg.AutoBindBWP) > 0 {
+			clients.CBWPClient = aliyun.NewCBWPClient(cred)
+			clients.CBWPClient.SetDryRun(cfg.DryRun)
 		}
 
-		// Traffic client for bot commands or traffic shutdown
-		if cfg.TelegramEnabled || cfg.TrafficShutdownEnabled {
-			trafficClient, err := aliyun.NewTrafficClient(acc.AccessKeyID, acc.AccessKeySecret)
+		// Traffic client for bot commands, traffic shutdown or traffic metrics
+		if cfg.TelegramEnabled || cfg.TrafficShutdownEnabled || cfg.MetricsEnabled {
+			trafficClient, err := aliyun.NewTrafficClient(cred)
 			if err != nil {
 				log.Warnf("[%s] Failed to create traffic client: %v", acc.Label, err)
 			} else {
@@ -94,11 +289,16 @@ func New(cfg *config.Config) (*Monitor, error) {
 		m.aliyunClients = append(m.aliyunClients, clients)
 	}
 
+	if err := m.validateAliyunCredentials(); err != nil {
+		return nil, err
+	}
+
 	// Initialize bot handler for commands
 	if cfg.TelegramEnabled {
-		m.botHandler = notify.NewBotHandler(cfg.TelegramBotToken, cfg.TelegramChatID)
+		m.botHandler = notify.NewBotHandler(cfg.TelegramBotToken, cfg.TelegramChatIDs, telegramClient)
 		m.botHandler.SetCommandHandler(m.handleBotCommand)
 		m.botHandler.SetCallbackHandler(m.handleCallbackQuery)
+		m.botHandler.SetParseMode(cfg.TelegramParseMode)
 	}
 
 	// Initialize GCP client
@@ -107,12 +307,82 @@ func New(cfg *config.Config) (*Monitor, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GCP client: %w", err)
 		}
+		gcpClient.SetDryRun(cfg.DryRun)
 		m.gcpClient = gcpClient
+		m.gcpCost = gcpClient
+
+		if cfg.GCPBigQueryDataset != "" {
+			bqClient, err := gcp.NewBigQueryBillingClient(cfg.GCPBigQueryProject, cfg.GCPBigQueryDataset, cfg.GCPBigQueryTable, cfg.GCPCredentialsJSON)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create GCP BigQuery client: %w", err)
+			}
+			m.gcpCost = bqClient
+		}
+	}
+
+	// Initialize AWS client
+	if cfg.AWSEnabled {
+		m.awsClient = aws.NewEC2Client(cfg.AWSAccessKeyID, cfg.AWSSecretAccessKey)
+		m.awsClient.SetDryRun(cfg.DryRun)
+	}
+	m.providers = m.newCloudProviders()
+
+	windows, err := newMaintenanceWindows(cfg.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+	m.maintenanceWindows = windows
+
+	if cfg.DryRun {
+		log.Warn("DRY RUN mode enabled: instances and bandwidth packages will not be modified")
 	}
 
 	return m, nil
 }
 
+// credentialCheckTimeout bounds the startup credential check of all Aliyun accounts
+const credentialCheckTimeout = 30 * time.Second
+
+// validateAliyunCredentials checks the credentials of every Aliyun account at startup, so a
+// misconfigured AccessKey is reported right away rather than at the first reclaim. Rejected
+// ECS credentials or permissions and invalid keys fail startup; other failures (network, a
+// missing BSS permission, which only the billing commands need) are logged.
+func (m *Monitor) validateAliyunCredentials() error {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialCheckTimeout)
+	defer cancel()
+
+	for _, acc := range m.aliyunClients {
+		if err := acc.ECSClient.ValidateCredentials(ctx); err != nil {
+			if aliyun.IsCredentialError(err) {
+				return fmt.Errorf("Aliyun credentials are invalid or missing required permissions: [%s] %w", acc.Account.Label, err)
+			}
+			log.Warnf("[%s] Could not validate ECS credentials: %v", acc.Account.Label, err)
+		}
+
+		if acc.BillingClient == nil {
+			continue
+		}
+		if err := acc.BillingClient.ValidateBillingCredentials(); err != nil {
+			if aliyun.IsInvalidKeyError(err) {
+				return fmt.Errorf("Aliyun credentials are invalid or missing required permissions: [%s] %w", acc.Account.Label, err)
+			}
+			log.Warnf("[%s] Could not validate billing credentials, billing reports may fail: %v", acc.Account.Label, err)
+		}
+	}
+	return nil
+}
+
+// Close sends pending batched notifications and releases the history database
+func (m *Monitor) Close() error {
+	if m.notifier != nil {
+		m.notifier.Flush()
+	}
+	if m.db == nil {
+		return nil
+	}
+	return m.db.Close()
+}
+
 // getECSClientByLabel returns the ECS client for a specific account label
 func (m *Monitor) getECSClientByLabel(label string) *aliyun.ECSClient {
 	for _, c := range m.aliyunClients {
@@ -133,36 +403,105 @@ func (m *Monitor) getCBWPClientByLabel(label string) *aliyun.CBWPClient {
 	return nil
 }
 
-// StartBot starts the Telegram bot polling and registers commands
-func (m *Monitor) StartBot() {
-	if m.botHandler != nil {
-		// Register bot commands with Telegram (same functionality only registers one command)
-		commands := []notify.BotCommand{
-			{Command: "status", Description: "查看实例状态"},
-			{Command: "billing", Description: "查询本月扣费汇总"},
-			{Command: "traffic", Description: "查询本月流量统计"},
-			{Command: "cbwp", Description: "管理共享带宽包"},
-			{Command: "help", Description: "显示帮助信息"},
-		}
-		if err := m.botHandler.SetMyCommands(commands); err != nil {
-			log.Warnf("Failed to register bot commands: %v", err)
-		}
+// registerBotCommands registers bot commands with Telegram so they appear in the command menu
+func (m *Monitor) registerBotCommands() {
+	// Same functionality only registers one command
+	names := []string{
+		"status", "billing", "billingall", "compare", "forecast", "traffic", "traffichistory", "cbwp",
+		"cbwpstatus", "network", "restart", "allocateeip", "unpause", "stop", "snapshot", "start", "price",
+		"history", "events", "logs", "inventory", "sgroups", "export", "uptime", "setlimit", "auditlog",
+		"notify", "help",
+	}
+	commands := make([]notify.BotCommand, 0, len(names))
+	for _, name := range names {
+		commands = append(commands, notify.BotCommand{Command: name, Description: m.tr.T("command." + name)})
+	}
+	if err := m.botHandler.SetMyCommands(commands); err != nil {
+		log.Warnf("Failed to register bot commands: %v", err)
+	}
+}
 
-		m.botHandler.StartPolling()
+// reply returns the notifier that answers the bot command being handled: it sends to the
+// chat the command came from only
+func (m *Monitor) reply() *notify.TelegramNotifier {
+	if m.telegram == nil || m.botHandler == nil {
+		return m.telegram
 	}
+	return m.telegram.ForChat(m.botHandler.ReplyChat())
 }
 
-// handleBotCommand handles bot commands
-func (m *Monitor) handleBotCommand(command string) error {
+// handleBotCommand handles bot commands, recording each in the audit log
+func (m *Monitor) handleBotCommand(ctx notify.CommandContext, command string, args []string) error {
+	id := m.auditStart(ctx, strings.TrimSpace("/"+command+" "+strings.Join(args, " ")))
+	err := m.runBotCommand(ctx, command, args)
+	m.auditFinish(id, err)
+	return err
+}
+
+// runBotCommand dispatches a bot command to its handler
+func (m *Monitor) runBotCommand(ctx notify.CommandContext, command string, args []string) error {
 	switch command {
 	case "billing", "cost", "fee":
+		if len(args) > 0 {
+			hours, err := parseLookbackHours(args)
+			if err != nil {
+				return m.reply().Send(fmt.Sprintf("❌ %v\n\n用法: <code>/billing last 24h</code> (最多 %d 小时)", err, maxLookbackHours))
+			}
+			return m.SendBillingReportByHours(hours)
+		}
 		return m.SendBillingReport()
+	case "billingall":
+		return m.sendAllProductBilling(args)
+	case "compare":
+		return m.sendMonthComparison()
+	case "forecast":
+		return m.sendCostForecast()
 	case "traffic", "flow", "bandwidth":
 		return m.SendTrafficReport()
+	case "traffichistory":
+		return m.sendTrafficHistory(args)
 	case "status":
 		return m.sendStatusReport()
 	case "cbwp":
 		return m.sendCBWPInstanceList()
+	case "cbwp-status", "cbwpstatus":
+		return m.sendCBWPStatus()
+	case "network", "net":
+		return m.sendNetworkStats(args)
+	case "restart", "reboot":
+		return m.sendRestartConfirm(args)
+	case "allocate-eip", "allocateeip":
+		return m.sendAllocateEIPConfirm(args)
+	case "unpause", "resume":
+		return m.unpauseAutoStart(args)
+	case "stop":
+		return m.sendStopInstanceList()
+	case "snapshot":
+		return m.sendSnapshot(args)
+	case "start":
+		return m.startManuallyStopped(args)
+	case "price":
+		return m.sendSpotPrices(args)
+	case "history":
+		return m.sendIncidentHistory()
+	case "events":
+		return m.sendInstanceEvents(args)
+	case "logs", "log":
+		return m.sendRecentLogs(args)
+	case "inventory":
+		return m.sendInventory()
+	case "sgroups":
+		return m.sendSecurityGroups()
+	case "export":
+		return m.sendExport()
+	case "uptime":
+		return m.sendUptime(args)
+	case "setlimit":
+		return m.sendSetLimit(args)
+	case "auditlog", "audit":
+		return m.sendAuditLog(args)
+	case "notify":
+		return m.sendNotifyPreferences(ctx, args)
 	case "help":
 		return m.sendHelpMessage()
 	default:
@@ -173,59 +512,70 @@ func (m *Monitor) handleBotCommand(command string) error {
 
 // sendStatusReport sends a status report
 func (m *Monitor) sendStatusReport() error {
-	if m.notifier == nil {
+	if m.telegram == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
+	ctx, cancel := m.operationContext()
+	defer cancel()
 
 	m.mu.RLock()
-	instances := make([]*aliyun.SpotInstance, len(m.instances))
-	copy(instances, m.instances)
 	gcpInstances := make([]*gcp.PreemptibleInstance, len(m.gcpInstances))
 	copy(gcpInstances, m.gcpInstances)
 	m.mu.RUnlock()
 
-	if len(instances) == 0 && len(gcpInstances) == 0 {
-		return m.notifier.Send("📊 <b>实例状态</b>\n\n暂无监控的实例")
+	// Aliyun accounts first, then AWS
+	instancesByProvider := make([][]*ProviderInstance, len(m.providers))
+	total := len(gcpInstances)
+	for i, p := range m.providers {
+		instancesByProvider[i] = p.Instances()
+		total += len(instancesByProvider[i])
 	}
 
-	var sb strings.Builder
-	sb.WriteString("📊 <b>实例状态</b>\n")
-	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	tagNote := ""
+	if len(m.cfg.InstanceFilterTags) > 0 {
+		tagNote = m.tr.T("status.tag_filter", html.EscapeString(formatTagFilter(m.cfg.InstanceFilterTags))) + "\n"
+	}
+	dryRunNote := ""
+	if m.cfg.DryRun {
+		dryRunNote = notify.DryRunBanner + "\n\n"
+	}
 
-	// Group instances by account label
-	instancesByAccount := make(map[string][]*aliyun.SpotInstance)
-	for _, inst := range instances {
-		instancesByAccount[inst.AccountLabel] = append(instancesByAccount[inst.AccountLabel], inst)
+	if total == 0 {
+		return m.reply().Send(dryRunNote + m.tr.T("status.header") + "\n\n" + tagNote + m.tr.T("status.empty"))
 	}
 
-	for _, acc := range m.aliyunClients {
-		label := acc.Account.Label
-		insts, ok := instancesByAccount[label]
-		if !ok {
+	var sb strings.Builder
+	sb.WriteString(dryRunNote)
+	sb.WriteString(m.tr.T("status.header") + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(tagNote)
+	sb.WriteString("\n")
+
+	for i, p := range m.providers {
+		insts := instancesByProvider[i]
+		if len(insts) == 0 {
 			continue
 		}
 
-		if label != "" {
-			sb.WriteString(fmt.Sprintf("👤 <b>账号: %s</b>\n", label))
+		if label := p.AccountLabel(); label != "" {
+			sb.WriteString(m.tr.T("status.account", label) + "\n")
 		}
 
 		for _, inst := range insts {
-			status, err := acc.ECSClient.GetInstanceStatus(inst.RegionID, inst.InstanceID)
-			if err != nil {
-				status = "Unknown"
+			status := "Unknown"
+			if current, err := p.GetInstance(ctx, inst.RegionID, inst.InstanceID); err == nil {
+				status = current.Status
 			}
 
-			statusEmoji := "🟢"
-			if status == "Stopped" {
-				statusEmoji = "🔴"
-			} else if status == "Starting" || status == "Stopping" {
-				statusEmoji = "🟡"
-			}
-
-			sb.WriteString(fmt.Sprintf("%s <b>%s</b>\n", statusEmoji, inst.InstanceName))
+			sb.WriteString(fmt.Sprintf("%s %s <b>%s</b>\n", p.Icon(), providerStatusEmoji(status), inst.InstanceName))
 			sb.WriteString(fmt.Sprintf("   ID: <code>%s</code>\n", inst.InstanceID))
-			sb.WriteString(fmt.Sprintf("   区域: %s\n", inst.RegionID))
-			sb.WriteString(fmt.Sprintf("   状态: %s\n\n", status))
+			sb.WriteString(m.tr.T("status.region", inst.RegionID) + "\n")
+			if len(m.cfg.InstanceVPCFilter) > 0 && inst.VpcID != "" {
+				sb.WriteString(fmt.Sprintf("   VPC: <code>%s</code>\n", inst.VpcID))
+			}
+			sb.WriteString(m.tr.T("status.state", status) + "\n")
+			sb.WriteString(formatReclaimStats(m.tr, m.state.Get(inst.StateKey), m.cfg.Location))
+			sb.WriteString("\n")
 		}
 	}
 
@@ -249,45 +599,143 @@ func (m *Monitor) sendStatusReport() error {
 			}
 
 			sb.WriteString(fmt.Sprintf("%s <b>%s</b>\n", statusEmoji, inst.InstanceName))
-			sb.WriteString(fmt.Sprintf("   区域: %s\n", inst.Zone))
-			sb.WriteString(fmt.Sprintf("   状态: %s\n\n", status))
+			sb.WriteString(m.tr.T("status.region", inst.Zone) + "\n")
+			sb.WriteString(m.tr.T("status.state", status) + "\n")
+			sb.WriteString(formatReclaimStats(m.tr, m.state.Get("gcp:"+inst.Zone+"/"+inst.InstanceName), m.cfg.Location))
+			sb.WriteString("\n")
+		}
+	}
+
+	return m.reply().Send(sb.String())
+}
+
+// formatReclaimStats renders the reclaim history line of an instance for status reports,
+// with times shown in loc
+func formatReclaimStats(tr i18n.Translator, st state.InstanceState, loc *time.Location) string {
+	if st.ReclaimCount == 0 {
+		if st.ManuallyStopped {
+			return tr.T("status.reclaims_none") + "\n" + formatManualStop(tr, st, loc)
 		}
+		return tr.T("status.reclaims_none") + "\n"
+	}
+	line := tr.T("status.reclaims", st.ReclaimCount, st.LastReclaimAt.In(loc).Format("01-02 15:04")) + "\n"
+	if st.ConsecutiveFailures > 0 {
+		line += tr.T("status.start_failures", st.ConsecutiveFailures) + "\n"
+	}
+	if time.Now().Before(st.PausedUntil) {
+		line += tr.T("status.paused", st.PausedUntil.In(loc).Format("01-02 15:04")) + "\n"
 	}
+	if st.ManuallyStopped {
+		line += formatManualStop(tr, st, loc)
+	}
+	return line
+}
 
-	return m.notifier.Send(sb.String())
+// formatManualStop renders the manual stop line of an instance for status reports
+func formatManualStop(tr i18n.Translator, st state.InstanceState, loc *time.Location) string {
+	line := tr.T("status.manual_stop")
+	if !st.ManuallyStoppedAt.IsZero() {
+		line += fmt.Sprintf(" (%s", st.ManuallyStoppedAt.In(loc).Format("01-02 15:04"))
+		if st.ManuallyStoppedBy != "" {
+			line += " by " + html.EscapeString(st.ManuallyStoppedBy)
+		}
+		line += ")"
+	}
+	return line + "\n"
+}
+
+// formatTagFilter renders a tag filter as "k1=v1, k2=v2" sorted by key
+func formatTagFilter(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, tags[k]))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // sendHelpMessage sends a help message
 func (m *Monitor) sendHelpMessage() error {
-	if m.notifier == nil {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	return m.reply().Send(m.tr.T("help"))
+}
+
+// findInstance finds a tracked Aliyun instance by instance ID or name
+func (m *Monitor) findInstance(idOrName string) *aliyun.SpotInstance {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, inst := range m.instances {
+		if inst.InstanceID == idOrName {
+			return inst
+		}
+	}
+	for _, inst := range m.instances {
+		if inst.InstanceName == idOrName {
+			return inst
+		}
+	}
+	return nil
+}
+
+// sendNetworkStats sends network bandwidth statistics for an instance
+// Usage: /network <instanceID|name> [hours]
+func (m *Monitor) sendNetworkStats(args []string) error {
+	if m.telegram == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
 
-	message := `🤖 <b>可用命令</b>
-━━━━━━━━━━━━━━━━━━━━━━━━
+	if len(args) == 0 {
+		return m.reply().Send("❌ 用法: /network &lt;实例ID&gt; [小时]\n\n示例: <code>/network i-xxx 24</code>")
+	}
 
-/billing - 查询本月扣费汇总
-/traffic - 查询本月流量统计
-/status - 查看实例状态
-/cbwp - 管理共享带宽包
-/help - 显示帮助信息
+	hours := 24
+	if len(args) > 1 {
+		h, err := strconv.Atoi(strings.TrimSuffix(args[1], "h"))
+		if err != nil || h <= 0 || h > maxLookbackHours {
+			return m.reply().Send(fmt.Sprintf("❌ 无效的小时数: %s (范围 1-%d)", html.EscapeString(args[1]), maxLookbackHours))
+		}
+		hours = h
+	}
 
-━━━━━━━━━━━━━━━━
-<i>别名: /cost, /fee, /flow, /bandwidth</i>`
+	inst := m.findInstance(args[0])
+	if inst == nil {
+		return m.reply().Send(fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(args[0])))
+	}
 
-	return m.notifier.Send(message)
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	if ecsClient == nil {
+		return fmt.Errorf("no ECS client found for account %s", inst.AccountLabel)
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+	stats, err := ecsClient.GetNetworkUsageStats(ctx, inst.RegionID, inst.InstanceID, hours)
+	if err != nil {
+		log.Errorf("[%s] Failed to query network stats for %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		return m.reply().Send(fmt.Sprintf("❌ 查询网络带宽失败: %v", err))
+	}
+
+	return m.reply().NotifyNetworkStats(inst.InstanceName, stats)
 }
 
 // refreshInstances re-discovers spot instances and updates the tracked list.
-func (m *Monitor) refreshInstances() error {
-	var allInstances []*aliyun.SpotInstance
-	for _, acc := range m.aliyunClients {
-		instances, err := acc.ECSClient.DiscoverAllSpotInstances(acc.Account.Label)
-		if err != nil {
-			log.Warnf("[%s] Failed to discover instances: %v", acc.Account.Label, err)
-			continue
-		}
-		allInstances = append(allInstances, instances...)
+// The list is kept when ctx is cancelled during discovery.
+func (m *Monitor) refreshInstances(ctx context.Context) error {
+	allInstances, discovered := m.discoverAliyunInstances(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if discovered {
+		m.markReady()
 	}
 
 	m.mu.Lock()
@@ -308,6 +756,7 @@ func (m *Monitor) refreshInstances() error {
 	for _, inst := range m.instances {
 		if !newMap[inst.InstanceID] {
 			log.Infof("[%s] Instance removed: %s (%s) in %s", inst.AccountLabel, inst.InstanceName, inst.InstanceID, inst.RegionID)
+			m.forgetInstanceMetrics(inst.InstanceID)
 		}
 	}
 
@@ -318,6 +767,9 @@ func (m *Monitor) refreshInstances() error {
 	if m.gcpClient != nil {
 		m.refreshGCPInstances()
 	}
+	if m.awsClient != nil {
+		m.refreshAWSInstances(ctx)
+	}
 
 	return nil
 }
@@ -352,22 +804,54 @@ func (m *Monitor) refreshGCPInstances() {
 		key := inst.Zone + "/" + inst.InstanceName
 		if !newMap[key] {
 			log.Infof("GCP: Instance removed: %s in %s", inst.InstanceName, inst.Zone)
+			m.forgetInstanceMetrics(inst.InstanceName)
 		}
 	}
 
 	m.gcpInstances = gcpInstances
 }
 
-// DiscoverInstances discovers all spot instances across all accounts and regions
-func (m *Monitor) DiscoverInstances() error {
-	var allInstances []*aliyun.SpotInstance
-	for _, acc := range m.aliyunClients {
-		instances, err := acc.ECSClient.DiscoverAllSpotInstances(acc.Account.Label)
-		if err != nil {
-			log.Warnf("[%s] Failed to discover instances: %v", acc.Account.Label, err)
+// discoverAliyunInstances discovers spot instances of all Aliyun accounts.
+// ok is false only when every account failed.
+func (m *Monitor) discoverAliyunInstances(ctx context.Context) (instances []*aliyun.SpotInstance, ok bool) {
+	ok = len(m.aliyunClients) == 0
+
+	// Accounts are scanned concurrently; results are merged in account order
+	results := make([][]*aliyun.SpotInstance, len(m.aliyunClients))
+	errs := make([]error, len(m.aliyunClients))
+	var wg sync.WaitGroup
+	for i, acc := range m.aliyunClients {
+		wg.Add(1)
+		go func(i int, acc *AliyunAccountClients) {
+			defer wg.Done()
+			// Left in place when discovery panics, so the account is not treated as having no instances
+			errs[i] = fmt.Errorf("instance discovery panicked")
+			defer m.recoverAndNotify("instance discovery")
+			results[i], errs[i] = acc.ECSClient.DiscoverAllSpotInstances(ctx, acc.Account.Label)
+		}(i, acc)
+	}
+	wg.Wait()
+
+	for i, acc := range m.aliyunClients {
+		if errs[i] != nil {
+			log.Warnf("[%s] Failed to discover instances: %v", acc.Account.Label, errs[i])
 			continue
 		}
-		allInstances = append(allInstances, instances...)
+		ok = true
+		instances = append(instances, results[i]...)
+	}
+	return instances, ok
+}
+
+// DiscoverInstances discovers all spot instances across all accounts and regions
+func (m *Monitor) DiscoverInstances(ctx context.Context) error {
+	if len(m.cfg.InstanceFilterTags) > 0 {
+		log.Infof("Instance tag filter active: %s", formatTagFilter(m.cfg.InstanceFilterTags))
+	}
+
+	allInstances, discovered := m.discoverAliyunInstances(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	m.mu.Lock()
@@ -375,6 +859,25 @@ func (m *Monitor) DiscoverInstances() error {
 	m.mu.Unlock()
 
 	log.Infof("Discovered total %d spot instances", len(allInstances))
+	if len(m.cfg.InstanceNamePatterns) > 0 {
+		log.Infof("Filtered to %d instances matching patterns: %s", len(allInstances), strings.Join(m.cfg.InstanceNamePatterns, ","))
+	}
+	if len(m.cfg.InstanceNameDenyPatterns) > 0 {
+		log.Infof("Instances matching deny patterns excluded: %s", strings.Join(m.cfg.InstanceNameDenyPatterns, ","))
+	}
+	if len(m.cfg.InstanceTypeAllowlist) > 0 || len(m.cfg.InstanceTypeDenylist) > 0 {
+		log.Infof("Instance type filter active: allow=%s deny=%s",
+			strings.Join(m.cfg.InstanceTypeAllowlist, ","), strings.Join(m.cfg.InstanceTypeDenylist, ","))
+	}
+	if len(m.cfg.InstanceVPCFilter) > 0 {
+		log.Infof("Instances limited to VPCs: %s", strings.Join(m.cfg.InstanceVPCFilter, ","))
+	}
+	if len(m.cfg.InstanceSGFilter) > 0 {
+		log.Infof("Instances limited to security groups: %s", strings.Join(m.cfg.InstanceSGFilter, ","))
+	}
+	if m.cfg.ResourceGroupID != "" {
+		log.Infof("Instances limited to resource group: %s", m.cfg.ResourceGroupID)
+	}
 	for _, inst := range allInstances {
 		log.Infof("[%s]  - %s (%s) in %s [%s]", inst.AccountLabel, inst.InstanceName, inst.InstanceID, inst.RegionID, inst.Status)
 	}
@@ -396,6 +899,11 @@ func (m *Monitor) DiscoverInstances() error {
 		}
 	}
 
+	// Discover AWS instances
+	if m.awsClient != nil {
+		m.discoverAWSInstances(ctx)
+	}
+
 	// Send notification
 	if m.notifier != nil {
 		totalCount := len(allInstances)
@@ -410,55 +918,129 @@ func (m *Monitor) DiscoverInstances() error {
 
 		m.mu.RLock()
 		gcpInsts := m.gcpInstances
+		awsInsts := m.awsInstances
 		m.mu.RUnlock()
 
 		for _, inst := range gcpInsts {
 			totalCount++
 			instanceList = append(instanceList, fmt.Sprintf("[GCP] %s - %s", inst.InstanceName, inst.Zone))
 		}
+		for _, inst := range awsInsts {
+			totalCount++
+			instanceList = append(instanceList, fmt.Sprintf("[AWS] %s (%s) - %s", inst.InstanceName, inst.InstanceID, inst.Region))
+		}
 
 		if totalCount > 0 {
-			if err := m.notifier.NotifyMonitorStarted(totalCount, instanceList); err != nil {
+			if err := m.notifier.NotifyMonitorStarted(totalCount, instanceList, m.cfg.DryRun); err != nil {
 				log.Warnf("Failed to send monitor started notification: %v", err)
 			}
 		}
 	}
 
+	if discovered {
+		m.markReady()
+	} else {
+		log.Warn("Initial discovery failed for all Aliyun accounts, readiness probe stays unready until a refresh succeeds")
+	}
+
 	return nil
 }
 
-// Check checks all instances and starts stopped ones
-func (m *Monitor) Check() error {
+// Check checks all instances and starts stopped ones. Cancelling ctx aborts in-progress starts.
+func (m *Monitor) Check(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "monitor.Check")
+	defer span.End()
+
 	// Re-discover instances to pick up newly added or removed ones
-	if err := m.refreshInstances(); err != nil {
-		log.Warnf("Failed to refresh instances, using cached list: %v", err)
+	if err := m.refreshInstances(ctx); err != nil {
+		log.WithContext(ctx).Warnf("Failed to refresh instances, using cached list: %v", err)
 	}
 
 	m.mu.RLock()
 	instances := make([]*aliyun.SpotInstance, len(m.instances))
 	copy(instances, m.instances)
-	gcpInstances := make([]*gcp.PreemptibleInstance, len(m.gcpInstances))
-	copy(gcpInstances, m.gcpInstances)
+	gcpCount := len(m.gcpInstances)
 	m.mu.RUnlock()
 
 	for _, inst := range instances {
-		if err := m.checkInstance(inst); err != nil {
-			log.Errorf("[%s] Failed to check instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		m.recordStatus(inst.InstanceID, inst.Status)
+	}
+
+	m.checkReclaimEvents(ctx, instances)
+
+	due := make([]*aliyun.SpotInstance, 0, len(instances))
+	for _, inst := range instances {
+		if m.isCheckDue(inst.InstanceID) {
+			due = append(due, inst)
 		}
 	}
+	span.SetAttributes(
+		attribute.Int("instances", len(instances)+gcpCount),
+		attribute.Int("instances_due", len(due)),
+	)
 
-	// Check GCP instances
-	for _, inst := range gcpInstances {
-		if err := m.checkGCPInstance(inst); err != nil {
-			log.Errorf("Failed to check GCP instance %s: %v", inst.InstanceName, err)
+	if m.cfg.InstancePriorityParallel {
+		var wg sync.WaitGroup
+		for _, inst := range due {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.runInstanceCheck(ctx, inst)
+			}()
+		}
+		wg.Wait()
+	} else {
+		// checkInstance waits for a started instance to be running, so sorting by priority
+		// brings stopped instances up one after another, most important first
+		sort.SliceStable(due, func(i, j int) bool {
+			return m.cfg.PriorityOf(due[i].InstanceID) < m.cfg.PriorityOf(due[j].InstanceID)
+		})
+		for _, inst := range due {
+			m.runInstanceCheck(ctx, inst)
+		}
+	}
+
+	// Check the instances of the other providers; Aliyun instances were checked above
+	providers := m.providers
+	if m.gcpClient != nil {
+		providers = append(providers[:len(providers):len(providers)], &gcpProvider{m: m, client: m.gcpClient})
+	}
+	for _, p := range providers {
+		if _, ok := p.(*aliyunProvider); ok {
+			continue
+		}
+		for _, inst := range p.Instances() {
+			m.recordStatus(inst.StateKey, inst.Status)
+			if !m.isCheckDue(inst.InstanceID) {
+				continue
+			}
+			if err := m.checkProviderInstance(ctx, p, inst); err != nil {
+				log.WithContext(ctx).Errorf("[%s] Failed to check instance %s: %v", p.Name(), inst.InstanceID, err)
+			}
 		}
 	}
 
+	m.maybeRefreshCostMetrics()
+
 	return nil
 }
 
+// runInstanceCheck runs checkInstance and logs its error
+func (m *Monitor) runInstanceCheck(ctx context.Context, inst *aliyun.SpotInstance) {
+	// A panic checking one instance must not abort the checks of the others
+	defer m.recoverAndNotify("instance check " + inst.InstanceID)
+
+	if err := m.checkInstance(ctx, inst); err != nil {
+		if errors.Is(err, ratelimit.ErrCircuitOpen) {
+			log.Debugf("[%s] Instance %s skipped: circuit open for region %s", inst.AccountLabel, inst.InstanceID, inst.RegionID)
+			return
+		}
+		log.Errorf("[%s] Failed to check instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+	}
+}
+
 // checkInstance checks a single instance and starts it if stopped
-func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
+func (m *Monitor) checkInstance(ctx context.Context, inst *aliyun.SpotInstance) error {
 	// Find the correct client for this account
 	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
 	if ecsClient == nil {
@@ -467,9 +1049,8 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 
 	// Check if this instance is blocked by traffic shutdown
 	m.trafficShutdownMu.RLock()
-	isChina := aliyun.IsChinaMainlandRegion(inst.RegionID)
-	// Traffic shutdown is now per-account
-	blocked := (isChina && m.chinaShutdown[inst.AccountLabel]) || (!isChina && m.nonChinaShutdown[inst.AccountLabel])
+	// Traffic shutdown is per-account and per limit scope
+	blocked := m.trafficShutdown[inst.AccountLabel][aliyun.TrafficScope(inst.RegionID, m.trafficLimits())]
 	m.trafficShutdownMu.RUnlock()
 
 	if blocked {
@@ -478,13 +1059,25 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 		return nil
 	}
 
+	if at, ok := m.trafficRestartTime(inst.InstanceID); ok && time.Now().Before(at) {
+		log.Debugf("[%s] Instance %s (%s) skipped: restart after monthly traffic reset scheduled at %s",
+			inst.AccountLabel, inst.InstanceName, inst.InstanceID, at.Format("15:04:05"))
+		return nil
+	}
+
+	if reason, blocked := m.autoStartBlocked(inst.InstanceID, inst.InstanceID, inst.RegionID); blocked {
+		log.Debugf("[%s] Instance %s (%s) skipped: %s", inst.AccountLabel, inst.InstanceName, inst.InstanceID, reason)
+		return nil
+	}
+
 	// Get current status
-	status, err := ecsClient.GetInstanceStatus(inst.RegionID, inst.InstanceID)
+	status, err := ecsClient.GetInstanceStatus(ctx, inst.RegionID, inst.InstanceID)
 	if err != nil {
 		return fmt.Errorf("failed to get status: %w", err)
 	}
 
 	log.Debugf("[%s] Instance %s (%s) status: %s", inst.AccountLabel, inst.InstanceName, inst.InstanceID, status)
+	m.recordInstanceMetrics(inst.InstanceID, inst.InstanceID, inst.InstanceName, inst.RegionID, status == "Running")
 
 	// If instance is running, clear NoStock flag if it was set
 	if status == "Running" {
@@ -513,7 +1106,26 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 		return nil
 	}
 
+	if until, paused := m.autoStartPausedUntil(inst.InstanceID); paused {
+		log.Debugf("[%s] Instance %s (%s) skipped: auto-start paused until %s after rapid reclaims",
+			inst.AccountLabel, inst.InstanceName, inst.InstanceID, until.Format("15:04:05"))
+		return nil
+	}
+
+	// A dry run starts nothing, so the instance is still stopped at the next check; counting
+	// it again would invent reclaims, incidents and rapid-reclaim pauses
+	if !m.cfg.DryRun {
+		if m.state.Get(inst.InstanceID).ConsecutiveFailures == 0 {
+			m.recordIncident(storage.EventReclaimDetected, inst.InstanceID, inst.InstanceName, inst.RegionID, 0, nil)
+		}
+		if recent, paused := m.recordReclaim(inst.InstanceID); paused {
+			m.notifyRapidReclaim(inst.InstanceID, inst.InstanceName, inst.RegionID, inst.AccountLabel, recent)
+			return nil
+		}
+	}
+
 	log.Warnf("[%s] Instance %s (%s) is stopped, attempting to start", inst.AccountLabel, inst.InstanceName, inst.InstanceID)
+	m.broadcastStatus(streamEventReclaim, inst.InstanceID, inst.InstanceName, inst.RegionID, status)
 
 	// Check notification cooldown
 	if !m.canNotify(inst.InstanceID) {
@@ -521,26 +1133,43 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 	} else {
 		// Send reclaimed notification
 		if m.notifier != nil {
-			if err := m.notifier.NotifyInstanceReclaimed(inst.InstanceID, inst.InstanceName, inst.RegionID); err != nil {
+			if err := m.notifier.NotifyInstanceReclaimed(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID); err != nil {
 				log.Warnf("[%s] Failed to send reclaimed notification: %v", inst.AccountLabel, err)
 			}
 		}
 		m.updateNotifyTime(inst.InstanceID)
 	}
 
+	// Public IP before the start, compared to the IP after it
+	previousIP := inst.PublicIPAddress
+	if previousIP == "" {
+		previousIP = m.state.Get(inst.InstanceID).PublicIP
+	}
+
 	// Try to start the instance with retries
+	m.markStarting(inst.InstanceID, inst.InstanceName)
+	defer m.clearStarting(inst.InstanceID)
+	m.broadcastStatus(streamEventStarting, inst.InstanceID, inst.InstanceName, inst.RegionID, "Starting")
 	startTime := time.Now()
 	var lastErr error
 	noStockDetected := false
 	attemptCount := 0
-	for i := 0; i < m.cfg.RetryCount; i++ {
+	retryCount := m.retryCountFor(inst.InstanceID)
+	for i := 0; i < retryCount; i++ {
 		attemptCount = i + 1
 		if i > 0 {
-			log.Infof("[%s] Retry %d/%d for instance %s", inst.AccountLabel, i+1, m.cfg.RetryCount, inst.InstanceID)
-			time.Sleep(time.Duration(m.cfg.RetryInterval) * time.Second)
+			delay := m.startRetryDelay(inst.InstanceID, i)
+			log.Infof("[%s] Retry %d/%d for instance %s in %s", inst.AccountLabel, i+1, retryCount, inst.InstanceID, delay.Round(time.Second))
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("start of instance %s aborted: %w", inst.InstanceID, err)
+			}
 		}
 
-		if err := ecsClient.StartInstance(inst.RegionID, inst.InstanceID); err != nil {
+		err := ecsClient.StartInstance(ctx, inst.RegionID, inst.InstanceID)
+		if !m.cfg.DryRun {
+			m.recordIncident(storage.EventStartAttempted, inst.InstanceID, inst.InstanceName, inst.RegionID, 0, err)
+		}
+		if err != nil {
 			lastErr = err
 			log.Warnf("[%s] Failed to start instance %s (attempt %d): %v", inst.AccountLabel, inst.InstanceID, i+1, err)
 
@@ -555,35 +1184,104 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 		}
 
 		log.Infof("[%s] Start command sent for instance %s", inst.AccountLabel, inst.InstanceID)
+		if m.cfg.DryRun {
+			// Nothing was started, so there is no state change to wait for
+			return nil
+		}
 
 		// Wait for instance to be running (using Aliyun API)
-		if err := m.waitForRunning(ecsClient, inst.RegionID, inst.InstanceID, inst.AccountLabel); err != nil {
+		if err := m.waitForRunning(ctx, ecsClient, inst.RegionID, inst.InstanceID, inst.AccountLabel); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("start of instance %s aborted: %w", inst.InstanceID, err)
+			}
 			lastErr = err
 			log.Warnf("[%s] Instance %s did not reach running state: %v", inst.AccountLabel, inst.InstanceID, err)
 			continue
 		}
+		// Spot capacity can be reclaimed right after a start, so optionally require the instance to stay up
+		if err := m.waitMinUptime(ctx, "Running", func() (string, error) {
+			return ecsClient.GetInstanceStatus(ctx, inst.RegionID, inst.InstanceID)
+		}); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("start of instance %s aborted: %w", inst.InstanceID, err)
+			}
+			lastErr = err
+			log.Warnf("[%s] Instance %s did not stay running: %v", inst.AccountLabel, inst.InstanceID, err)
+			continue
+		}
+		m.recordStatus(inst.InstanceID, "Running")
 
 		// Get updated instance info for IP
-		updatedInst, err := ecsClient.GetInstance(inst.RegionID, inst.InstanceID, inst.AccountLabel)
+		updatedInst, err := ecsClient.GetInstance(ctx, inst.RegionID, inst.InstanceID, inst.AccountLabel)
 		if err != nil {
 			log.Warnf("[%s] Failed to get updated instance info: %v", inst.AccountLabel, err)
 		} else {
 			inst = updatedInst
 		}
+		ipChange := m.trackPublicIP(inst, previousIP)
+
+		// The instance only counts as up once SSH accepts connections
+		var sshErr error
+		sshPort := m.sshPortFor(inst.InstanceID)
+		if m.cfg.HealthCheckSSH {
+			if sshErr = m.waitForSSH(inst.PublicIPAddress, sshPort); sshErr != nil {
+				log.Warnf("[%s] Instance %s is running but SSH is not reachable: %v", inst.AccountLabel, inst.InstanceID, sshErr)
+			}
+		}
 
 		// Success!
 		duration := time.Since(startTime)
-		log.Infof("[%s] Instance %s started successfully in %.0f seconds", inst.AccountLabel, inst.InstanceID, duration.Seconds())
+		afterReset := m.takeTrafficRestart(inst.InstanceID)
+		if afterReset {
+			log.Infof("[%s] Instance %s restarted after monthly traffic reset in %.0f seconds", inst.AccountLabel, inst.InstanceID, duration.Seconds())
+		} else {
+			log.Infof("[%s] Instance %s started successfully in %.0f seconds", inst.AccountLabel, inst.InstanceID, duration.Seconds())
+		}
+
+		extraInfo := ipChange
+		if bwpID, ok := m.cfg.AutoBindBWP[inst.InstanceID]; ok {
+			extraInfo = joinLines(extraInfo, m.bindInstanceEIPs(inst, bwpID))
+		}
+		if healthURL, ok := m.cfg.InstanceHealthURLs[inst.InstanceID]; ok {
+			extraInfo = joinLines(extraInfo, m.checkHTTPHealth(inst.InstanceID, healthURL, inst.PublicIPAddress, inst.AccountLabel))
+		}
 
 		if m.notifier != nil {
-			if err := m.notifier.NotifyInstanceStarted(inst.InstanceID, inst.InstanceName, inst.RegionID, inst.PublicIPAddress, duration); err != nil {
+			var err error
+			if sshErr != nil {
+				err = m.notifier.NotifySSHUnreachable(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, inst.PublicIPAddress, sshPort, duration, extraInfo)
+			} else if afterReset {
+				err = m.notifier.NotifyTrafficResetRestart(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, inst.PublicIPAddress, duration, extraInfo)
+			} else {
+				err = m.notifier.NotifyInstanceStarted(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, inst.PublicIPAddress, duration, extraInfo)
+			}
+			if err != nil {
 				log.Warnf("[%s] Failed to send started notification: %v", inst.AccountLabel, err)
 			}
 		}
 
+		if webhookURL, ok := m.cfg.InstanceWebhooks[inst.InstanceID]; ok {
+			go m.callStartWebhook(webhookURL, inst, duration)
+		}
+
+		m.recordStartResult(inst.InstanceID, true)
+		m.recordIncident(storage.EventStartSucceeded, inst.InstanceID, inst.InstanceName, inst.RegionID, duration, nil)
+		m.broadcastStatus(streamEventStarted, inst.InstanceID, inst.InstanceName, inst.RegionID, "Running")
+		m.recordInstanceMetrics(inst.InstanceID, inst.InstanceID, inst.InstanceName, inst.RegionID, true)
+		m.observeStartDuration(inst.InstanceID, inst.InstanceName, inst.RegionID, duration)
+
+		if m.cfg.HealthCheckEnabled {
+			go m.checkDiskIOAfterStart(ctx, ecsClient, inst)
+		}
+		go m.CheckInstanceHealth(inst)
+
 		return nil
 	}
 
+	m.recordStartResult(inst.InstanceID, false)
+	m.recordIncident(storage.EventStartFailed, inst.InstanceID, inst.InstanceName, inst.RegionID, time.Since(startTime), lastErr)
+	m.broadcastStatus(streamEventFailed, inst.InstanceID, inst.InstanceName, inst.RegionID, "Stopped")
+
 	// Handle NoStock: set flag and send specific notification, stop auto-restart
 	if noStockDetected {
 		m.noStockInstancesMu.Lock()
@@ -592,7 +1290,7 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 
 		log.Errorf("[%s] Instance %s marked as NoStock, auto-restart paused", inst.AccountLabel, inst.InstanceID)
 		if m.notifier != nil {
-			if err := m.notifier.NotifyInstanceNoStock(inst.InstanceID, inst.InstanceName, inst.RegionID, attemptCount); err != nil {
+			if err := m.notifier.NotifyInstanceNoStock(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, attemptCount); err != nil {
 				log.Warnf("[%s] Failed to send NoStock notification: %v", inst.AccountLabel, err)
 			}
 		}
@@ -601,9 +1299,9 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 	}
 
 	// All retries failed (non-NoStock errors)
-	log.Errorf("[%s] Failed to start instance %s after %d retries", inst.AccountLabel, inst.InstanceID, m.cfg.RetryCount)
+	log.Errorf("[%s] Failed to start instance %s after %d retries", inst.AccountLabel, inst.InstanceID, retryCount)
 	if m.notifier != nil {
-		if err := m.notifier.NotifyInstanceStartFailed(inst.InstanceID, inst.InstanceName, inst.RegionID, m.cfg.RetryCount, lastErr); err != nil {
+		if err := m.notifier.NotifyInstanceStartFailed(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, retryCount, lastErr); err != nil {
 			log.Warnf("[%s] Failed to send failure notification: %v", inst.AccountLabel, err)
 		}
 	}
@@ -611,19 +1309,57 @@ func (m *Monitor) checkInstance(inst *aliyun.SpotInstance) error {
 	return lastErr
 }
 
-// waitForRunning waits for an instance to reach running state
-func (m *Monitor) waitForRunning(ecsClient *aliyun.ECSClient, regionID, instanceID, accountLabel string) error {
+// diskIOCheckMinutes is the window after startup in which zero disk I/O is treated as an anomaly
+const diskIOCheckMinutes = 2
+
+// checkDiskIOAfterStart verifies that a freshly started instance shows disk activity,
+// ruling out disk problems caused by the unclean shutdown of a reclaim
+func (m *Monitor) checkDiskIOAfterStart(ctx context.Context, ecsClient *aliyun.ECSClient, inst *aliyun.SpotInstance) {
+	defer m.recoverAndNotify("disk I/O health check")
+
+	// Wait for the check window to pass plus one minute of CloudMonitor reporting delay
+	if err := sleepContext(ctx, (diskIOCheckMinutes+1)*time.Minute); err != nil {
+		return
+	}
+
+	readIOPS, writeIOPS, _, _, err := ecsClient.GetDiskIOPS(ctx, inst.RegionID, inst.InstanceID, "", diskIOCheckMinutes)
+	if err != nil {
+		log.Debugf("[%s] Skipping disk I/O health check for instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		return
+	}
+
+	if readIOPS > 0 || writeIOPS > 0 {
+		log.Debugf("[%s] Instance %s disk I/O normal: read=%.2f IOPS, write=%.2f IOPS",
+			inst.AccountLabel, inst.InstanceID, readIOPS, writeIOPS)
+		return
+	}
+
+	log.Warnf("[%s] Instance %s shows no disk I/O %d minutes after startup", inst.AccountLabel, inst.InstanceID, diskIOCheckMinutes)
+	if m.notifier != nil {
+		if err := m.notifier.NotifyDiskIOAnomaly(inst.AccountLabel, inst.InstanceID, inst.InstanceName, inst.RegionID, diskIOCheckMinutes, readIOPS, writeIOPS); err != nil {
+			log.Warnf("[%s] Failed to send disk I/O anomaly notification: %v", inst.AccountLabel, err)
+		}
+	}
+}
+
+// waitForRunning waits for an instance to reach running state, returning early when ctx is cancelled
+func (m *Monitor) waitForRunning(ctx context.Context, ecsClient *aliyun.ECSClient, regionID, instanceID, accountLabel string) error {
 	timeout := time.After(2 * time.Minute)
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return ctx.Err()
 		case <-timeout:
 			return fmt.Errorf("timeout waiting for instance to start")
 		case <-ticker.C:
-			status, err := ecsClient.GetInstanceStatus(regionID, instanceID)
+			status, err := ecsClient.GetInstanceStatus(ctx, regionID, instanceID)
 			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
 				log.Warnf("[%s] Failed to get instance status: %v", accountLabel, err)
 				continue
 			}
@@ -635,168 +1371,273 @@ func (m *Monitor) waitForRunning(ecsClient *aliyun.ECSClient, regionID, instance
 	}
 }
 
-// checkGCPInstance checks a single GCP instance and starts it if stopped/terminated
-func (m *Monitor) checkGCPInstance(inst *gcp.PreemptibleInstance) error {
-	// Get current status
-	status, err := m.gcpClient.GetInstanceStatus(inst.Zone, inst.InstanceName)
+// waitMinUptime waits MIN_UPTIME_SECONDS after an instance reached running state and checks it
+// is still running, so a start that is immediately reclaimed is retried instead of reported
+func (m *Monitor) waitMinUptime(ctx context.Context, running string, getStatus func() (string, error)) error {
+	if m.cfg.MinUptimeSeconds <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, time.Duration(m.cfg.MinUptimeSeconds)*time.Second); err != nil {
+		return err
+	}
+	status, err := getStatus()
 	if err != nil {
-		return fmt.Errorf("failed to get GCP instance status: %w", err)
+		return fmt.Errorf("failed to get status after %ds: %w", m.cfg.MinUptimeSeconds, err)
 	}
-
-	log.Debugf("GCP instance %s (%s) status: %s", inst.InstanceName, inst.Zone, status)
-
-	// Only handle stopped/terminated instances
-	if status != "TERMINATED" && status != "STOPPED" {
-		return nil
+	if status != running {
+		return fmt.Errorf("instance is %s %ds after starting", status, m.cfg.MinUptimeSeconds)
 	}
+	return nil
+}
 
-	log.Warnf("GCP instance %s (%s) is %s, attempting to start", inst.InstanceName, inst.Zone, status)
-
-	// Notification key uses "gcp:" prefix to avoid collision with Aliyun instance IDs
-	notifyKey := "gcp:" + inst.Zone + "/" + inst.InstanceName
+// waitForInstanceStatus polls the instance status until it matches want or the timeout expires
+func waitForInstanceStatus(ctx context.Context, getStatus func(ctx context.Context, regionID, instanceID string) (string, error), regionID, instanceID, want string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
 
-	if !m.canNotify(notifyKey) {
-		log.Debugf("Notification cooldown active for GCP instance %s", inst.InstanceName)
-	} else {
-		if m.notifier != nil {
-			if err := m.notifier.NotifyInstanceReclaimed(inst.InstanceName, inst.InstanceName, "GCP/"+inst.Zone); err != nil {
-				log.Warnf("Failed to send GCP reclaimed notification: %v", err)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			return fmt.Errorf("timeout waiting for status %s", want)
+		case <-ticker.C:
+			status, err := getStatus(ctx, regionID, instanceID)
+			if err != nil {
+				log.Warnf("Failed to get instance status: %v", err)
+				continue
+			}
+			if status == want {
+				return nil
 			}
 		}
-		m.updateNotifyTime(notifyKey)
 	}
+}
 
-	// Try to start the instance with retries
-	startTime := time.Now()
-	var lastErr error
-	for i := 0; i < m.cfg.RetryCount; i++ {
-		if i > 0 {
-			log.Infof("GCP: Retry %d/%d for instance %s", i+1, m.cfg.RetryCount, inst.InstanceName)
-			time.Sleep(time.Duration(m.cfg.RetryInterval) * time.Second)
-		}
+// canNotify checks if we can send a notification for the given instance
+func (m *Monitor) canNotify(instanceID string) bool {
+	return time.Now().After(m.state.Get(instanceID).NotifyCooldownUntil)
+}
 
-		if err := m.gcpClient.StartInstance(inst.Zone, inst.InstanceName); err != nil {
-			lastErr = err
-			log.Warnf("Failed to start GCP instance %s (attempt %d): %v", inst.InstanceName, i+1, err)
-			continue
+// updateNotifyTime starts the notification cooldown for an instance
+func (m *Monitor) updateNotifyTime(instanceID string) {
+	m.reloadMu.RLock()
+	until := time.Now().Add(time.Duration(m.cfg.NotifyCooldown) * time.Second)
+	m.reloadMu.RUnlock()
+	m.updateState(instanceID, func(st *state.InstanceState) {
+		st.NotifyCooldownUntil = until
+	})
+}
+
+// recordReclaim counts a reclaim of an instance found stopped.
+// Checks that keep finding the instance stopped after failed starts belong to the same reclaim.
+// When the instance was reclaimed more than RAPID_RECLAIM_COUNT times within RAPID_RECLAIM_WINDOW,
+// auto-start is paused and paused is true; recent is the number of reclaims in the window.
+func (m *Monitor) recordReclaim(instanceID string) (recent int, paused bool) {
+	now := time.Now()
+	window := time.Duration(m.cfg.RapidReclaimWindow) * time.Second
+	m.updateState(instanceID, func(st *state.InstanceState) {
+		if st.ConsecutiveFailures > 0 {
+			return
+		}
+		st.ReclaimCount++
+		st.LastReclaimAt = now
+
+		// Keep only reclaims inside the detection window
+		kept := st.RecentReclaims[:0]
+		for _, t := range st.RecentReclaims {
+			if now.Sub(t) < window {
+				kept = append(kept, t)
+			}
 		}
+		st.RecentReclaims = append(kept, now)
+		recent = len(st.RecentReclaims)
 
-		// Wait for instance to be running
-		if err := m.waitForGCPRunning(inst.Zone, inst.InstanceName); err != nil {
-			lastErr = err
-			log.Warnf("GCP instance %s did not reach running state: %v", inst.InstanceName, err)
-			continue
+		if m.cfg.RapidReclaimCount > 0 && recent > m.cfg.RapidReclaimCount {
+			st.PausedUntil = now.Add(time.Duration(m.cfg.RapidReclaimPause) * time.Second)
+			st.RecentReclaims = nil
+			paused = true
 		}
+	})
+	return recent, paused
+}
 
-		// Get updated instance info
-		updatedInst, err := m.gcpClient.GetInstance(inst.Zone, inst.InstanceName)
-		if err != nil {
-			log.Warnf("Failed to get updated GCP instance info: %v", err)
+// recordStartResult tracks consecutive start failures of an instance
+func (m *Monitor) recordStartResult(instanceID string, success bool) {
+	m.updateState(instanceID, func(st *state.InstanceState) {
+		if success {
+			st.ConsecutiveFailures = 0
 		} else {
-			inst = updatedInst
+			st.ConsecutiveFailures++
 		}
+	})
+}
 
-		duration := time.Since(startTime)
-		log.Infof("GCP instance %s started successfully in %.0f seconds", inst.InstanceName, duration.Seconds())
-
-		if m.notifier != nil {
-			if err := m.notifier.NotifyInstanceStarted(inst.InstanceName, inst.InstanceName, "GCP/"+inst.Zone, inst.ExternalIP, duration); err != nil {
-				log.Warnf("Failed to send GCP started notification: %v", err)
-			}
-		}
+// updateState updates persistent instance state, logging flush failures
+func (m *Monitor) updateState(instanceID string, fn func(st *state.InstanceState)) {
+	if err := m.state.Update(instanceID, fn); err != nil {
+		log.Warnf("Failed to save state for %s: %v", instanceID, err)
+	}
+}
 
-		return nil
+// trackPublicIP stores the public IP of a started instance in the instance list and the state
+// store. It returns a warning line for the started notification when the IP differs from
+// previousIP, e.g. because the EIP was released while the instance was stopped.
+func (m *Monitor) trackPublicIP(inst *aliyun.SpotInstance, previousIP string) string {
+	ip := inst.PublicIPAddress
+	if ip == "" {
+		return ""
 	}
 
-	// All retries failed
-	log.Errorf("Failed to start GCP instance %s after %d retries", inst.InstanceName, m.cfg.RetryCount)
-	if m.notifier != nil {
-		if err := m.notifier.NotifyInstanceStartFailed(inst.InstanceName, inst.InstanceName, "GCP/"+inst.Zone, m.cfg.RetryCount, lastErr); err != nil {
-			log.Warnf("Failed to send GCP failure notification: %v", err)
+	m.mu.Lock()
+	for _, cached := range m.instances {
+		if cached.InstanceID == inst.InstanceID {
+			cached.PublicIPAddress = ip
 		}
 	}
+	m.mu.Unlock()
+	m.updateState(inst.InstanceID, func(st *state.InstanceState) { st.PublicIP = ip })
 
-	return lastErr
+	if previousIP == "" || previousIP == ip {
+		return ""
+	}
+	log.Warnf("[%s] Instance %s public IP changed: %s -> %s", inst.AccountLabel, inst.InstanceID, previousIP, ip)
+	return fmt.Sprintf("⚠️ <b>IP 已变更: %s → %s</b>\n请更新 DNS 记录、防火墙规则和 VPN 配置", previousIP, ip)
 }
 
-// waitForGCPRunning waits for a GCP instance to reach RUNNING state
-func (m *Monitor) waitForGCPRunning(zone, instanceName string) error {
-	timeout := time.After(2 * time.Minute)
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-timeout:
-			return fmt.Errorf("timeout waiting for GCP instance to start")
-		case <-ticker.C:
-			status, err := m.gcpClient.GetInstanceStatus(zone, instanceName)
-			if err != nil {
-				log.Warnf("Failed to get GCP instance status: %v", err)
-				continue
-			}
-			if status == "RUNNING" {
-				return nil
-			}
-			log.Debugf("GCP instance %s status: %s, waiting...", instanceName, status)
+// joinLines joins the non-empty lines of notification extra info
+func joinLines(lines ...string) string {
+	kept := lines[:0]
+	for _, l := range lines {
+		if l != "" {
+			kept = append(kept, l)
 		}
 	}
+	return strings.Join(kept, "\n")
 }
 
-// canNotify checks if we can send a notification for the given instance
-func (m *Monitor) canNotify(instanceID string) bool {
-	m.lastNotifyMu.RLock()
-	defer m.lastNotifyMu.RUnlock()
+// parseLookbackHours parses "/billing last <N>h" style arguments, e.g.
+// ["last", "6h"], ["last", "24"], ["last", "48", "hours"]
+func parseLookbackHours(args []string) (int, error) {
+	if len(args) < 2 || args[0] != "last" {
+		return 0, fmt.Errorf("无法识别的参数: %s", strings.Join(args, " "))
+	}
+	if len(args) > 3 || (len(args) == 3 && args[2] != "h" && args[2] != "hour" && args[2] != "hours") {
+		return 0, fmt.Errorf("无法识别的参数: %s", strings.Join(args, " "))
+	}
 
-	lastTime, ok := m.lastNotify[instanceID]
-	if !ok {
-		return true
+	value := strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(args[1]), "hours"), "h")
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours <= 0 {
+		return 0, fmt.Errorf("无效的小时数: %s", args[1])
+	}
+	if hours > maxLookbackHours {
+		return 0, fmt.Errorf("回溯时间不能超过 %d 小时", maxLookbackHours)
 	}
 
-	return time.Since(lastTime) > time.Duration(m.cfg.NotifyCooldown)*time.Second
+	return hours, nil
 }
 
-// updateNotifyTime updates the last notification time for an instance
-func (m *Monitor) updateNotifyTime(instanceID string) {
-	m.lastNotifyMu.Lock()
-	defer m.lastNotifyMu.Unlock()
-	m.lastNotify[instanceID] = time.Now()
+// SendBillingReport replies to /billing with billing reports for all accounts for the current month
+func (m *Monitor) SendBillingReport() error {
+	return m.sendBillingReport(0)
 }
 
-// SendBillingReport sends billing reports for all accounts
-func (m *Monitor) SendBillingReport() error {
-	if m.notifier == nil {
+// SendBillingReportByHours sends billing reports for all accounts for the last N hours
+func (m *Monitor) SendBillingReportByHours(hours int) error {
+	return m.sendBillingReport(hours)
+}
+
+// sendBillingReport sends billing reports for all accounts; hours == 0 means the current month
+func (m *Monitor) sendBillingReport(hours int) error {
+	if m.telegram == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
 
-	m.mu.RLock()
-	instancesByAccount := make(map[string][]aliyun.InstanceInfo)
-	for _, inst := range m.instances {
-		instancesByAccount[inst.AccountLabel] = append(instancesByAccount[inst.AccountLabel], aliyun.InstanceInfo{
-			InstanceID:   inst.InstanceID,
-			InstanceName: inst.InstanceName,
-			RegionID:     inst.RegionID,
-		})
+	summaries, err := m.BillingSummaries(context.Background(), hours)
+	if err != nil {
+		log.Errorf("Failed to query billing: %v", err)
+	}
+	if hours == 0 {
+		m.fillBillingTrend(context.Background(), summaries)
+	}
+	for _, summary := range summaries {
+		if err := m.reply().NotifyBillingSummary(summary); err != nil {
+			log.Errorf("[%s] Failed to send billing notification: %v", summary.AccountLabel, err)
+		}
 	}
-	m.mu.RUnlock()
 
-	for _, acc := range m.aliyunClients {
-		if acc.BillingClient == nil {
-			log.Warnf("[%s] Billing client not initialized", acc.Account.Label)
-			continue
+	if m.gcpCost != nil && hours == 0 {
+		if err := m.sendGCPCostReport(); err != nil {
+			log.Errorf("Failed to send GCP cost report: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// billingTrendDays is the number of days in the cost sparkline of billing reports
+const billingTrendDays = 7
+
+// fillBillingTrend adds the daily totals of the last billingTrendDays days to each summary.
+// Failures only drop the sparkline from the report.
+func (m *Monitor) fillBillingTrend(ctx context.Context, summaries []*aliyun.BillingSummary) {
+	instancesByAccount := m.billingInstancesByAccount()
+	for _, summary := range summaries {
+		for _, acc := range m.aliyunClients {
+			if acc.Account.Label != summary.AccountLabel || acc.BillingClient == nil {
+				continue
+			}
+			totals, err := acc.BillingClient.QueryDailyTotals(ctx, instancesByAccount[acc.Account.Label], billingTrendDays)
+			if err != nil {
+				log.Warnf("[%s] Failed to query daily billing trend: %v", acc.Account.Label, err)
+				break
+			}
+			summary.DailyTotals = totals
+			break
 		}
+	}
+}
 
+// billingDigestDays is the window of the rolling daily average in scheduled billing digests
+const billingDigestDays = 7
+
+// SendBillingDigest sends the scheduled billing report: month-to-date total,
+// rolling average daily spend and month-end projection. Query failures are reported to Telegram.
+func (m *Monitor) SendBillingDigest() error {
+	if m.notifier == nil {
+		return fmt.Errorf("no notifier configured")
+	}
+
+	instancesByAccount := m.billingInstancesByAccount()
+
+	for _, acc := range m.aliyunClients {
 		instanceInfos := instancesByAccount[acc.Account.Label]
-		if len(instanceInfos) == 0 {
-			log.Debugf("[%s] No instances to query billing for", acc.Account.Label)
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
 			continue
 		}
 
-		log.Infof("[%s] Querying billing for %d instances...", acc.Account.Label, len(instanceInfos))
-
-		summary, err := acc.BillingClient.QueryBilling(instanceInfos, acc.Account.Label)
+		summary, err := acc.BillingClient.QueryBilling(context.Background(), instanceInfos, acc.Account.Label)
+		if err == nil {
+			var avg float64
+			if avg, err = acc.BillingClient.QueryRollingDailyAverage(context.Background(), instanceInfos, billingDigestDays); err == nil {
+				now := time.Now()
+				monthEnd := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
+				summary.RollingDays = billingDigestDays
+				summary.RollingDailyAverage = avg
+				summary.MonthEndProjection = summary.TotalAmount + avg*monthEnd.Sub(now).Hours()/24
+			}
+		}
 		if err != nil {
-			log.Errorf("[%s] Failed to query billing: %v", acc.Account.Label, err)
+			log.Errorf("[%s] Scheduled billing report failed: %v", acc.Account.Label, err)
+			accountTitle := ""
+			if acc.Account.Label != "" {
+				accountTitle = fmt.Sprintf(" [%s]", acc.Account.Label)
+			}
+			if sendErr := m.notifier.Send(fmt.Sprintf("❌ <b>定时扣费报告查询失败%s</b>\n\n%s", accountTitle, html.EscapeString(err.Error()))); sendErr != nil {
+				log.Warnf("[%s] Failed to send billing error notification: %v", acc.Account.Label, sendErr)
+			}
 			continue
 		}
 
@@ -808,9 +1649,25 @@ func (m *Monitor) SendBillingReport() error {
 	return nil
 }
 
-// SendTrafficReport sends traffic reports for all accounts
+// billingInstancesByAccount groups tracked Aliyun instances by account for billing queries
+func (m *Monitor) billingInstancesByAccount() map[string][]aliyun.InstanceInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	instancesByAccount := make(map[string][]aliyun.InstanceInfo)
+	for _, inst := range m.instances {
+		instancesByAccount[inst.AccountLabel] = append(instancesByAccount[inst.AccountLabel], aliyun.InstanceInfo{
+			InstanceID:   inst.InstanceID,
+			InstanceName: inst.InstanceName,
+			RegionID:     inst.RegionID,
+		})
+	}
+	return instancesByAccount
+}
+
+// SendTrafficReport replies to /traffic with traffic reports for all accounts
 func (m *Monitor) SendTrafficReport() error {
-	if m.notifier == nil {
+	if m.telegram == nil {
 		return fmt.Errorf("telegram notifier not initialized")
 	}
 
@@ -830,17 +1687,17 @@ func (m *Monitor) SendTrafficReport() error {
 
 		if m.cfg.TrafficShutdownEnabled {
 			m.trafficShutdownMu.RLock()
-			chinaSD := m.chinaShutdown[acc.Account.Label]
-			nonChinaSD := m.nonChinaShutdown[acc.Account.Label]
+			shutdown := make(map[string]bool, len(m.trafficShutdown[acc.Account.Label]))
+			for scope, sd := range m.trafficShutdown[acc.Account.Label] {
+				shutdown[scope] = sd
+			}
 			m.trafficShutdownMu.RUnlock()
 
-			if err := m.notifier.NotifyTrafficSummaryWithLimits(summary,
-				m.cfg.TrafficLimitChinaGB, m.cfg.TrafficLimitNonChinaGB,
-				chinaSD, nonChinaSD); err != nil {
+			if err := m.reply().NotifyTrafficSummaryWithLimits(summary, m.trafficLimits(), shutdown); err != nil {
 				log.Errorf("[%s] Failed to send traffic notification: %v", acc.Account.Label, err)
 			}
 		} else {
-			if err := m.notifier.NotifyTrafficSummary(summary); err != nil {
+			if err := m.reply().NotifyTrafficSummary(summary); err != nil {
 				log.Errorf("[%s] Failed to send traffic notification: %v", acc.Account.Label, err)
 			}
 		}
@@ -868,40 +1725,44 @@ func (m *Monitor) CheckTraffic() error {
 			continue
 		}
 
-		chinaTrafficGB := summary.ChinaMainland.TrafficGB
-		nonChinaTrafficGB := summary.NonChinaMainland.TrafficGB
+		m.recordTrafficMetrics(summary)
 
-		log.Debugf("[%s] Traffic check: China=%.2f/%.0f GB, Non-China=%.2f/%.0f GB",
-			acc.Account.Label, chinaTrafficGB, m.cfg.TrafficLimitChinaGB,
-			nonChinaTrafficGB, m.cfg.TrafficLimitNonChinaGB)
+		limits := m.trafficLimits()
+		usage := summary.ScopeTrafficGB(limits)
+		scopes := make([]string, 0, len(usage))
+		for scope := range usage {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+
+		for _, scope := range scopes {
+			m.checkTrafficWarnings(summary, scope, usage[scope], limits[scope])
+		}
 
 		m.trafficShutdownMu.Lock()
-		// Check China mainland traffic
-		if chinaTrafficGB >= m.cfg.TrafficLimitChinaGB {
-			if !m.chinaShutdown[acc.Account.Label] {
-				m.chinaShutdown[acc.Account.Label] = true
-				log.Warnf("[%s] China mainland traffic %.2f GB exceeded limit %.0f GB, shutting down China instances",
-					acc.Account.Label, chinaTrafficGB, m.cfg.TrafficLimitChinaGB)
-				go m.shutdownRegionInstances(acc.Account.Label, "china", chinaTrafficGB, m.cfg.TrafficLimitChinaGB)
-			}
-		} else if m.chinaShutdown[acc.Account.Label] {
-			log.Infof("[%s] China mainland traffic %.2f GB is below limit %.0f GB, clearing shutdown flag",
-				acc.Account.Label, chinaTrafficGB, m.cfg.TrafficLimitChinaGB)
-			m.chinaShutdown[acc.Account.Label] = false
-		}
-
-		// Check non-China traffic
-		if nonChinaTrafficGB >= m.cfg.TrafficLimitNonChinaGB {
-			if !m.nonChinaShutdown[acc.Account.Label] {
-				m.nonChinaShutdown[acc.Account.Label] = true
-				log.Warnf("[%s] Non-China traffic %.2f GB exceeded limit %.0f GB, shutting down non-China instances",
-					acc.Account.Label, nonChinaTrafficGB, m.cfg.TrafficLimitNonChinaGB)
-				go m.shutdownRegionInstances(acc.Account.Label, "non-china", nonChinaTrafficGB, m.cfg.TrafficLimitNonChinaGB)
+		shutdown := m.trafficShutdown[acc.Account.Label]
+		if shutdown == nil {
+			shutdown = make(map[string]bool)
+			m.trafficShutdown[acc.Account.Label] = shutdown
+		}
+		for _, scope := range scopes {
+			usedGB, limitGB := usage[scope], limits[scope]
+			log.Debugf("[%s] Traffic check: %s=%.2f/%.0f GB", acc.Account.Label, scope, usedGB, limitGB)
+
+			if usedGB >= limitGB {
+				if !shutdown[scope] {
+					shutdown[scope] = true
+					log.Warnf("[%s] %s traffic %.2f GB exceeded limit %.0f GB, shutting down its instances",
+						acc.Account.Label, scope, usedGB, limitGB)
+					m.recordIncident(storage.EventTrafficLimitHit, "", acc.Account.Label, scope, 0,
+						fmt.Errorf("traffic %.2f GB exceeded limit %.0f GB", usedGB, limitGB))
+					go m.shutdownRegionInstances(acc.Account.Label, scope, usedGB, limitGB)
+				}
+			} else if shutdown[scope] {
+				log.Infof("[%s] %s traffic %.2f GB is below limit %.0f GB, clearing shutdown flag",
+					acc.Account.Label, scope, usedGB, limitGB)
+				shutdown[scope] = false
 			}
-		} else if m.nonChinaShutdown[acc.Account.Label] {
-			log.Infof("[%s] Non-China traffic %.2f GB is below limit %.0f GB, clearing shutdown flag",
-				acc.Account.Label, nonChinaTrafficGB, m.cfg.TrafficLimitNonChinaGB)
-			m.nonChinaShutdown[acc.Account.Label] = false
 		}
 		m.trafficShutdownMu.Unlock()
 	}
@@ -909,12 +1770,66 @@ func (m *Monitor) CheckTraffic() error {
 	return nil
 }
 
-// shutdownRegionInstances stops all running instances for a specific account in the specified region group
-func (m *Monitor) shutdownRegionInstances(accountLabel string, region string, trafficGB, limitGB float64) {
+// checkTrafficWarnings sends a warning when traffic of a limit scope crosses a TRAFFIC_WARN_PERCENT threshold.
+// Only the highest newly crossed threshold is reported, once per billing cycle.
+func (m *Monitor) checkTrafficWarnings(summary *aliyun.TrafficSummary, scope string, usedGB, limitGB float64) {
+	if m.notifier == nil || limitGB <= 0 || len(m.cfg.TrafficWarnPercents) == 0 {
+		return
+	}
+
+	percent := usedGB / limitGB * 100
+	crossed := 0
+	for _, threshold := range m.cfg.TrafficWarnPercents {
+		if percent >= float64(threshold) {
+			crossed = threshold
+		}
+	}
+	// Reaching the limit is reported by the shutdown notification instead
+	if crossed == 0 || percent >= 100 {
+		return
+	}
+
+	warnKey := fmt.Sprintf("%s|%s|%s", summary.BillingCycle, summary.AccountLabel, scope)
+	m.trafficShutdownMu.Lock()
+	if m.trafficWarned[warnKey] >= crossed {
+		m.trafficShutdownMu.Unlock()
+		return
+	}
+	m.trafficShutdownMu.Unlock()
+
+	notifyKey := fmt.Sprintf("traffic-warn:%s:%d", warnKey, crossed)
+	if !m.canNotify(notifyKey) {
+		return
+	}
+
+	// Average daily burn rate since the start of the billing cycle
+	daysLeft := -1.0
+	elapsedDays := summary.EndTime.Sub(summary.StartTime).Hours() / 24
+	if elapsedDays > 0 && usedGB > 0 {
+		daysLeft = (limitGB - usedGB) / (usedGB / elapsedDays)
+	}
+
+	log.Warnf("[%s] %s traffic %.2f GB reached %d%% of limit %.0f GB", summary.AccountLabel, scope, usedGB, crossed, limitGB)
+	if err := m.notifier.NotifyTrafficWarning(summary.AccountLabel, scope, usedGB, limitGB, percent, daysLeft); err != nil {
+		log.Warnf("[%s] Failed to send traffic warning: %v", summary.AccountLabel, err)
+		return
+	}
+
+	m.updateNotifyTime(notifyKey)
+	m.trafficShutdownMu.Lock()
+	m.trafficWarned[warnKey] = crossed
+	m.trafficShutdownMu.Unlock()
+}
+
+// shutdownRegionInstances stops all running instances for a specific account in the specified limit scope
+func (m *Monitor) shutdownRegionInstances(accountLabel string, scope string, trafficGB, limitGB float64) {
+	defer m.recoverAndNotify("traffic shutdown")
+
 	ecsClient := m.getECSClientByLabel(accountLabel)
 	if ecsClient == nil {
 		return
 	}
+	ctx := m.runCtx
 
 	m.mu.RLock()
 	var instances []*aliyun.SpotInstance
@@ -928,13 +1843,12 @@ func (m *Monitor) shutdownRegionInstances(accountLabel string, region string, tr
 	var stoppedInstances []string
 
 	for _, inst := range instances {
-		isChina := aliyun.IsChinaMainlandRegion(inst.RegionID)
-		if (region == "china" && !isChina) || (region == "non-china" && isChina) {
+		if aliyun.TrafficScope(inst.RegionID, m.trafficLimits()) != scope {
 			continue
 		}
 
 		// Check if instance is running
-		status, err := ecsClient.GetInstanceStatus(inst.RegionID, inst.InstanceID)
+		status, err := ecsClient.GetInstanceStatus(ctx, inst.RegionID, inst.InstanceID)
 		if err != nil {
 			log.Errorf("[%s] Failed to get status for instance %s: %v", accountLabel, inst.InstanceID, err)
 			continue
@@ -944,24 +1858,125 @@ func (m *Monitor) shutdownRegionInstances(accountLabel string, region string, tr
 			continue
 		}
 
+		// Release bandwidth package slots before stopping
+		var unboundIPs []string
+		if m.cfg.CBWPAutoUnbindOnShutdown {
+			unboundIPs = m.unbindInstanceEIPs(inst)
+		}
+
+		// The snapshot completes in the background; a failure does not delay the shutdown
+		snapshot := ""
+		if m.cfg.AutoSnapshotBeforeStop {
+			if snapshotID, err := m.createSnapshot(inst); err != nil {
+				snapshot = " [快照创建失败]"
+			} else if snapshotID != "" {
+				snapshot = fmt.Sprintf(" [快照: %s]", snapshotID)
+			}
+		}
+
 		log.Warnf("[%s] Stopping instance %s (%s) due to traffic limit exceeded", accountLabel, inst.InstanceName, inst.InstanceID)
-		if err := ecsClient.StopInstance(inst.RegionID, inst.InstanceID, "StopCharging"); err != nil {
+		if err := ecsClient.StopInstance(ctx, inst.RegionID, inst.InstanceID, "StopCharging"); err != nil {
 			log.Errorf("[%s] Failed to stop instance %s: %v", accountLabel, inst.InstanceID, err)
 			continue
 		}
 
-		stoppedInstances = append(stoppedInstances, fmt.Sprintf("%s (%s) - %s",
-			inst.InstanceName, inst.InstanceID, aliyun.GetRegionDisplayName(inst.RegionID)))
+		m.recordIncident(storage.EventTrafficShutdown, inst.InstanceID, inst.InstanceName, inst.RegionID, 0, nil)
+
+		desc := fmt.Sprintf("%s (%s) - %s", inst.InstanceName, inst.InstanceID, aliyun.GetRegionDisplayName(inst.RegionID))
+		if len(unboundIPs) > 0 {
+			desc += fmt.Sprintf(" [已移出共享带宽: %s]", strings.Join(unboundIPs, ", "))
+		}
+		desc += snapshot
+		stoppedInstances = append(stoppedInstances, desc)
 	}
 
 	// Send notification
 	if m.notifier != nil && len(stoppedInstances) > 0 {
-		if err := m.notifier.NotifyTrafficShutdown(accountLabel, region, trafficGB, limitGB, stoppedInstances); err != nil {
+		if err := m.notifier.NotifyTrafficShutdown(accountLabel, scope, trafficGB, limitGB, stoppedInstances); err != nil {
 			log.Errorf("[%s] Failed to send traffic shutdown notification: %v", accountLabel, err)
 		}
 	}
 }
 
+// bindInstanceEIPs adds the EIPs of a started instance to its AUTO_BIND_BWP bandwidth package
+// and returns a summary line for the started notification
+func (m *Monitor) bindInstanceEIPs(inst *aliyun.SpotInstance, bwpID string) string {
+	cbwpClient := m.getCBWPClientByLabel(inst.AccountLabel)
+	if cbwpClient == nil {
+		log.Warnf("[%s] No CBWP client, skipping EIP auto-bind for instance %s", inst.AccountLabel, inst.InstanceID)
+		return "🔗 共享带宽: ❌ 未初始化 CBWP 客户端"
+	}
+
+	eips, err := cbwpClient.DescribeEipAddresses(inst.RegionID, inst.InstanceID)
+	if err != nil {
+		log.Errorf("[%s] Failed to query EIPs for instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		return fmt.Sprintf("🔗 共享带宽: ❌ 查询 EIP 失败: %s", html.EscapeString(err.Error()))
+	}
+	if len(eips) == 0 {
+		log.Warnf("[%s] Instance %s has no EIP to add to bandwidth package %s", inst.AccountLabel, inst.InstanceID, bwpID)
+		return fmt.Sprintf("🔗 共享带宽: ⚠️ 实例未绑定 EIP，无法加入 <code>%s</code>", bwpID)
+	}
+
+	var lines []string
+	for _, eip := range eips {
+		switch eip.BandwidthPackageID {
+		case bwpID:
+			log.Debugf("[%s] EIP %s of instance %s already in bandwidth package %s", inst.AccountLabel, eip.IPAddress, inst.InstanceID, bwpID)
+			lines = append(lines, fmt.Sprintf("🔗 共享带宽: %s 已在 <code>%s</code>", eip.IPAddress, bwpID))
+		case "":
+			if err := cbwpClient.AddCommonBandwidthPackageIp(inst.RegionID, bwpID, eip.AllocationID); err != nil {
+				log.Errorf("[%s] Failed to add EIP %s of instance %s to bandwidth package %s: %v",
+					inst.AccountLabel, eip.IPAddress, inst.InstanceID, bwpID, err)
+				lines = append(lines, fmt.Sprintf("🔗 共享带宽: ❌ %s 加入 <code>%s</code> 失败: %s", eip.IPAddress, bwpID, html.EscapeString(err.Error())))
+				continue
+			}
+			log.Infof("[%s] Added EIP %s of instance %s to bandwidth package %s", inst.AccountLabel, eip.IPAddress, inst.InstanceID, bwpID)
+			lines = append(lines, fmt.Sprintf("🔗 共享带宽: ✅ %s 已加入 <code>%s</code>", eip.IPAddress, bwpID))
+		default:
+			// Moving between packages is left to the operator
+			log.Warnf("[%s] EIP %s of instance %s is in bandwidth package %s, not %s; leaving it unchanged",
+				inst.AccountLabel, eip.IPAddress, inst.InstanceID, eip.BandwidthPackageID, bwpID)
+			lines = append(lines, fmt.Sprintf("🔗 共享带宽: ⚠️ %s 已在其他共享带宽包 <code>%s</code>，未变更", eip.IPAddress, eip.BandwidthPackageID))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// unbindInstanceEIPs removes all EIPs of an instance from their bandwidth packages
+// and returns the IP addresses that were removed
+func (m *Monitor) unbindInstanceEIPs(inst *aliyun.SpotInstance) []string {
+	cbwpClient := m.getCBWPClientByLabel(inst.AccountLabel)
+	if cbwpClient == nil {
+		log.Warnf("[%s] No CBWP client, skipping EIP unbind for instance %s", inst.AccountLabel, inst.InstanceID)
+		return nil
+	}
+
+	eips, err := cbwpClient.DescribeEipAddresses(inst.RegionID, inst.InstanceID)
+	if err != nil {
+		log.Errorf("[%s] Failed to query EIPs for instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		return nil
+	}
+
+	var unboundIPs []string
+	for _, eip := range eips {
+		if eip.BandwidthPackageID == "" {
+			continue
+		}
+
+		log.Infof("[%s] Removing EIP %s of instance %s from bandwidth package %s before shutdown",
+			inst.AccountLabel, eip.IPAddress, inst.InstanceID, eip.BandwidthPackageID)
+		if err := cbwpClient.RemoveCommonBandwidthPackageIp(inst.RegionID, eip.BandwidthPackageID, eip.AllocationID); err != nil {
+			log.Errorf("[%s] Failed to remove EIP %s from bandwidth package %s: %v",
+				inst.AccountLabel, eip.AllocationID, eip.BandwidthPackageID, err)
+			continue
+		}
+		unboundIPs = append(unboundIPs, eip.IPAddress)
+	}
+
+	return unboundIPs
+}
+
 // sendCBWPInstanceList sends the instance list with inline keyboard for CBWP management
 func (m *Monitor) sendCBWPInstanceList() error {
 	if m.botHandler == nil {
@@ -974,7 +1989,7 @@ func (m *Monitor) sendCBWPInstanceList() error {
 	m.mu.RUnlock()
 
 	if len(instances) == 0 {
-		return m.notifier.Send("🌐 <b>共享带宽管理</b>\n\n暂无监控的实例")
+		return m.reply().Send("🌐 <b>共享带宽管理</b>\n\n暂无监控的实例")
 	}
 
 	var keyboard [][]notify.InlineKeyboardButton
@@ -995,8 +2010,29 @@ func (m *Monitor) sendCBWPInstanceList() error {
 	return m.botHandler.SendMessageWithKeyboard(text, keyboard)
 }
 
-// handleCallbackQuery handles inline keyboard callback queries
-func (m *Monitor) handleCallbackQuery(callbackID, data string, messageID int64) error {
+// handleCallbackQuery handles inline keyboard callback queries, recording each in the audit log
+func (m *Monitor) handleCallbackQuery(ctx notify.CommandContext, callbackID, data string, msg notify.MessageRef) error {
+	id := m.auditStart(ctx, "callback:"+data)
+	err := m.runCallbackQuery(ctx, callbackID, data, msg)
+	m.auditFinish(id, err)
+	return err
+}
+
+// runCallbackQuery dispatches an inline keyboard callback query to its handler
+func (m *Monitor) runCallbackQuery(ctx notify.CommandContext, callbackID, data string, msg notify.MessageRef) error {
+	if strings.HasPrefix(data, "restart:") {
+		return m.handleRestartCallback(callbackID, data, msg)
+	}
+	if strings.HasPrefix(data, "eip:") {
+		return m.handleAllocateEIPCallback(callbackID, data, msg)
+	}
+	if strings.HasPrefix(data, "stop:") {
+		return m.handleStopCallback(callbackID, data, msg, ctx.User)
+	}
+	if strings.HasPrefix(data, "setlimit:") {
+		return m.handleSetLimitCallback(callbackID, data, msg)
+	}
+
 	parts := strings.Split(data, "|")
 	if len(parts) < 2 || parts[0] != "cbwp" {
 		return nil
@@ -1011,7 +2047,7 @@ func (m *Monitor) handleCallbackQuery(callbackID, data string, messageID int64)
 		}
 		instanceID := parts[2]
 		accountLabel := parts[3]
-		return m.handleCBWPSelectInstance(callbackID, instanceID, accountLabel, messageID)
+		return m.handleCBWPSelectInstance(callbackID, instanceID, accountLabel, msg)
 
 	case "bind":
 		if len(parts) < 5 {
@@ -1020,7 +2056,7 @@ func (m *Monitor) handleCallbackQuery(callbackID, data string, messageID int64)
 		instanceID := parts[2]
 		accountLabel := parts[3]
 		bwpID := parts[4]
-		return m.handleCBWPBind(callbackID, instanceID, accountLabel, bwpID, messageID)
+		return m.handleCBWPBind(callbackID, instanceID, accountLabel, bwpID, msg)
 
 	case "unbind":
 		if len(parts) < 5 {
@@ -1029,11 +2065,14 @@ func (m *Monitor) handleCallbackQuery(callbackID, data string, messageID int64)
 		instanceID := parts[2]
 		accountLabel := parts[3]
 		bwpID := parts[4]
-		return m.handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID, messageID)
+		return m.handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID, msg)
 
 	case "back":
 		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
-		return m.handleCBWPBackToList(messageID)
+		return m.handleCBWPBackToList(msg)
+
+	case "status":
+		return m.handleCBWPStatusRefresh(callbackID, msg)
 
 	default:
 		return nil
@@ -1041,12 +2080,12 @@ func (m *Monitor) handleCallbackQuery(callbackID, data string, messageID int64)
 }
 
 // handleCBWPSelectInstance handles instance selection for CBWP management
-func (m *Monitor) handleCBWPSelectInstance(callbackID, instanceID, accountLabel string, messageID int64) error {
+func (m *Monitor) handleCBWPSelectInstance(callbackID, instanceID, accountLabel string, msg notify.MessageRef) error {
 	_ = m.botHandler.AnswerCallbackQuery(callbackID, "查询中...", false)
 
 	cbwpClient := m.getCBWPClientByLabel(accountLabel)
 	if cbwpClient == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到该账号的客户端", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该账号的客户端", nil)
 	}
 
 	// Find the instance
@@ -1061,21 +2100,21 @@ func (m *Monitor) handleCBWPSelectInstance(callbackID, instanceID, accountLabel
 	m.mu.RUnlock()
 
 	if inst == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到该实例", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该实例", nil)
 	}
 
 	// Query EIPs for this instance
 	eips, err := cbwpClient.DescribeEipAddresses(inst.RegionID, instanceID)
 	if err != nil {
 		log.Errorf("[%s] Failed to query EIPs for instance %s: %v", accountLabel, instanceID, err)
-		return m.botHandler.EditMessageText(messageID, fmt.Sprintf("❌ 查询 EIP 失败: %v", err), nil)
+		return m.botHandler.EditMessageText(msg, fmt.Sprintf("❌ 查询 EIP 失败: %v", err), nil)
 	}
 
 	if len(eips) == 0 {
 		keyboard := [][]notify.InlineKeyboardButton{
 			{{Text: "« 返回", CallbackData: "cbwp|back"}},
 		}
-		return m.botHandler.EditMessageText(messageID,
+		return m.botHandler.EditMessageText(msg,
 			fmt.Sprintf("🌐 <b>%s</b>\n\n该实例没有绑定 EIP，无法操作共享带宽包", inst.InstanceName),
 			keyboard)
 	}
@@ -1084,14 +2123,14 @@ func (m *Monitor) handleCBWPSelectInstance(callbackID, instanceID, accountLabel
 	bwps, err := cbwpClient.DescribeCommonBandwidthPackages(inst.RegionID)
 	if err != nil {
 		log.Errorf("[%s] Failed to query bandwidth packages in region %s: %v", accountLabel, inst.RegionID, err)
-		return m.botHandler.EditMessageText(messageID, fmt.Sprintf("❌ 查询共享带宽包失败: %v", err), nil)
+		return m.botHandler.EditMessageText(msg, fmt.Sprintf("❌ 查询共享带宽包失败: %v", err), nil)
 	}
 
 	if len(bwps) == 0 {
 		keyboard := [][]notify.InlineKeyboardButton{
 			{{Text: "« 返回", CallbackData: "cbwp|back"}},
 		}
-		return m.botHandler.EditMessageText(messageID,
+		return m.botHandler.EditMessageText(msg,
 			fmt.Sprintf("🌐 <b>%s</b>\n\n该地域 (%s) 没有共享带宽包", inst.InstanceName, inst.RegionID),
 			keyboard)
 	}
@@ -1153,16 +2192,16 @@ func (m *Monitor) handleCBWPSelectInstance(callbackID, instanceID, accountLabel
 		{Text: "« 返回", CallbackData: "cbwp|back"},
 	})
 
-	return m.botHandler.EditMessageText(messageID, sb.String(), keyboard)
+	return m.botHandler.EditMessageText(msg, sb.String(), keyboard)
 }
 
 // handleCBWPBind handles binding an EIP to a bandwidth package
-func (m *Monitor) handleCBWPBind(callbackID, instanceID, accountLabel, bwpID string, messageID int64) error {
+func (m *Monitor) handleCBWPBind(callbackID, instanceID, accountLabel, bwpID string, msg notify.MessageRef) error {
 	_ = m.botHandler.AnswerCallbackQuery(callbackID, "正在加入共享带宽包...", false)
 
 	cbwpClient := m.getCBWPClientByLabel(accountLabel)
 	if cbwpClient == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到该账号的客户端", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该账号的客户端", nil)
 	}
 
 	// Find the instance
@@ -1177,13 +2216,13 @@ func (m *Monitor) handleCBWPBind(callbackID, instanceID, accountLabel, bwpID str
 	m.mu.RUnlock()
 
 	if inst == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到该实例", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该实例", nil)
 	}
 
 	// Get EIPs
 	eips, err := cbwpClient.DescribeEipAddresses(inst.RegionID, instanceID)
 	if err != nil || len(eips) == 0 {
-		return m.botHandler.EditMessageText(messageID, "❌ 查询 EIP 失败", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 查询 EIP 失败", nil)
 	}
 
 	// Find the first unbound EIP
@@ -1196,7 +2235,7 @@ func (m *Monitor) handleCBWPBind(callbackID, instanceID, accountLabel, bwpID str
 	}
 
 	if targetEIP == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 没有可用的 EIP（所有 EIP 已在带宽包中）", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 没有可用的 EIP（所有 EIP 已在带宽包中）", nil)
 	}
 
 	// Execute bind
@@ -1205,7 +2244,7 @@ func (m *Monitor) handleCBWPBind(callbackID, instanceID, accountLabel, bwpID str
 		keyboard := [][]notify.InlineKeyboardButton{
 			{{Text: "« 返回", CallbackData: "cbwp|back"}},
 		}
-		return m.botHandler.EditMessageText(messageID,
+		return m.botHandler.EditMessageText(msg,
 			fmt.Sprintf("❌ <b>加入失败</b>\n\nEIP: %s\n错误: %v", targetEIP.IPAddress, err),
 			keyboard)
 	}
@@ -1213,19 +2252,19 @@ func (m *Monitor) handleCBWPBind(callbackID, instanceID, accountLabel, bwpID str
 	keyboard := [][]notify.InlineKeyboardButton{
 		{{Text: "« 返回实例列表", CallbackData: "cbwp|back"}},
 	}
-	return m.botHandler.EditMessageText(messageID,
+	return m.botHandler.EditMessageText(msg,
 		fmt.Sprintf("✅ <b>已加入共享带宽</b>\n━━━━━━━━━━━━━━━━\n实例: %s\nEIP: <code>%s</code>\n带宽包: <code>%s</code>\n时间: %s",
-			inst.InstanceName, targetEIP.IPAddress, bwpID, time.Now().Format("2006-01-02 15:04:05")),
+			inst.InstanceName, targetEIP.IPAddress, bwpID, time.Now().In(m.cfg.Location).Format("2006-01-02 15:04:05")),
 		keyboard)
 }
 
 // handleCBWPUnbind handles removing an EIP from a bandwidth package
-func (m *Monitor) handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID string, messageID int64) error {
+func (m *Monitor) handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID string, msg notify.MessageRef) error {
 	_ = m.botHandler.AnswerCallbackQuery(callbackID, "正在移出共享带宽包...", false)
 
 	cbwpClient := m.getCBWPClientByLabel(accountLabel)
 	if cbwpClient == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到该账号的客户端", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该账号的客户端", nil)
 	}
 
 	// Find the instance
@@ -1240,13 +2279,13 @@ func (m *Monitor) handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID s
 	m.mu.RUnlock()
 
 	if inst == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到该实例", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该实例", nil)
 	}
 
 	// Get EIPs
 	eips, err := cbwpClient.DescribeEipAddresses(inst.RegionID, instanceID)
 	if err != nil || len(eips) == 0 {
-		return m.botHandler.EditMessageText(messageID, "❌ 查询 EIP 失败", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 查询 EIP 失败", nil)
 	}
 
 	// Find the EIP in this bandwidth package
@@ -1259,7 +2298,7 @@ func (m *Monitor) handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID s
 	}
 
 	if targetEIP == nil {
-		return m.botHandler.EditMessageText(messageID, "❌ 未找到在该带宽包中的 EIP", nil)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到在该带宽包中的 EIP", nil)
 	}
 
 	// Execute unbind
@@ -1268,7 +2307,7 @@ func (m *Monitor) handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID s
 		keyboard := [][]notify.InlineKeyboardButton{
 			{{Text: "« 返回", CallbackData: "cbwp|back"}},
 		}
-		return m.botHandler.EditMessageText(messageID,
+		return m.botHandler.EditMessageText(msg,
 			fmt.Sprintf("❌ <b>移出失败</b>\n\nEIP: %s\n错误: %v", targetEIP.IPAddress, err),
 			keyboard)
 	}
@@ -1276,21 +2315,21 @@ func (m *Monitor) handleCBWPUnbind(callbackID, instanceID, accountLabel, bwpID s
 	keyboard := [][]notify.InlineKeyboardButton{
 		{{Text: "« 返回实例列表", CallbackData: "cbwp|back"}},
 	}
-	return m.botHandler.EditMessageText(messageID,
+	return m.botHandler.EditMessageText(msg,
 		fmt.Sprintf("✅ <b>已移出共享带宽</b>\n━━━━━━━━━━━━━━━━\n实例: %s\nEIP: <code>%s</code>\n带宽包: <code>%s</code>\n时间: %s",
-			inst.InstanceName, targetEIP.IPAddress, bwpID, time.Now().Format("2006-01-02 15:04:05")),
+			inst.InstanceName, targetEIP.IPAddress, bwpID, time.Now().In(m.cfg.Location).Format("2006-01-02 15:04:05")),
 		keyboard)
 }
 
 // handleCBWPBackToList handles going back to the instance list
-func (m *Monitor) handleCBWPBackToList(messageID int64) error {
+func (m *Monitor) handleCBWPBackToList(msg notify.MessageRef) error {
 	m.mu.RLock()
 	instances := make([]*aliyun.SpotInstance, len(m.instances))
 	copy(instances, m.instances)
 	m.mu.RUnlock()
 
 	if len(instances) == 0 {
-		return m.botHandler.EditMessageText(messageID, "🌐 <b>共享带宽管理</b>\n\n暂无监控的实例", nil)
+		return m.botHandler.EditMessageText(msg, "🌐 <b>共享带宽管理</b>\n\n暂无监控的实例", nil)
 	}
 
 	var keyboard [][]notify.InlineKeyboardButton
@@ -1308,5 +2347,5 @@ func (m *Monitor) handleCBWPBackToList(messageID int64) error {
 	}
 
 	text := "🌐 <b>共享带宽管理</b>\n━━━━━━━━━━━━━━━━\n\n请选择要操作的实例："
-	return m.botHandler.EditMessageText(messageID, text, keyboard)
+	return m.botHandler.EditMessageText(msg, text, keyboard)
 }
@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	log "github.com/sirupsen/logrus"
+)
+
+// notifyPreferences holds the /notify settings of each Telegram chat. Event types without
+// a setting are enabled, so chats receive everything until they opt out.
+type notifyPreferences struct {
+	enabled map[string]map[string]bool // chat ID -> event type -> enabled
+	mu      sync.RWMutex
+}
+
+// Enabled implements notify.ChatPreferences
+func (p *notifyPreferences) Enabled(chatID, eventType string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	enabled, ok := p.enabled[chatID][eventType]
+	return !ok || enabled
+}
+
+// set records the setting of an event type for a chat
+func (p *notifyPreferences) set(chatID, eventType string, enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enabled[chatID] == nil {
+		p.enabled[chatID] = make(map[string]bool)
+	}
+	p.enabled[chatID][eventType] = enabled
+}
+
+// loadNotifyPreferences restores the /notify settings saved in the database
+func (m *Monitor) loadNotifyPreferences() {
+	if m.db == nil {
+		return
+	}
+	prefs, err := m.db.NotifyPreferences()
+	if err != nil {
+		log.Warnf("Failed to load notification preferences: %v", err)
+		return
+	}
+	for chatID, events := range prefs {
+		for eventType, enabled := range events {
+			m.notifyPrefs.set(chatID, eventType, enabled)
+		}
+	}
+}
+
+// sendNotifyPreferences handles /notify on|off [event_type] and /notify status: turns
+// notification types on or off for the chat the command was sent from, all types when
+// event_type is omitted
+func (m *Monitor) sendNotifyPreferences(ctx notify.CommandContext, args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	usage := m.tr.T("notifyprefs.usage", strings.Join(notify.EventTypes, ", "))
+	if len(args) == 0 || strings.EqualFold(args[0], "status") {
+		return m.reply().Send(m.formatNotifyPreferences(ctx.ChatID))
+	}
+
+	var enabled bool
+	switch strings.ToLower(args[0]) {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return m.reply().Send(m.tr.T("notifyprefs.invalid_arg", html.EscapeString(args[0]), usage))
+	}
+
+	events := notify.EventTypes
+	if len(args) > 1 {
+		eventType := strings.ToLower(args[1])
+		if !slices.Contains(notify.EventTypes, eventType) {
+			return m.reply().Send(m.tr.T("notifyprefs.invalid_type", html.EscapeString(args[1]), usage))
+		}
+		events = []string{eventType}
+	}
+
+	chatID := strconv.FormatInt(ctx.ChatID, 10)
+	for _, eventType := range events {
+		m.notifyPrefs.set(chatID, eventType, enabled)
+		if m.db != nil {
+			if err := m.db.SetNotifyPreference(chatID, eventType, enabled); err != nil {
+				return m.reply().Send(m.tr.T("notifyprefs.save_failed", html.EscapeString(err.Error())))
+			}
+		}
+	}
+	log.Infof("Notifications %s turned %s for chat %s", strings.Join(events, ", "), args[0], chatID)
+
+	key := "notifyprefs.turned_off"
+	if enabled {
+		key = "notifyprefs.turned_on"
+	}
+	message := m.tr.T(key, chatID, strings.Join(events, ", "))
+	if m.db == nil {
+		message += "\n\n" + m.tr.T("notifyprefs.not_saved")
+	}
+	return m.reply().Send(message)
+}
+
+// formatNotifyPreferences renders the /notify status table of all configured chats, marking
+// the chat the command was sent from
+func (m *Monitor) formatNotifyPreferences(currentChatID int64) string {
+	chats := m.cfg.TelegramChatIDs
+
+	var sb strings.Builder
+	sb.WriteString(m.tr.T("notifyprefs.header") + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n<pre>")
+	sb.WriteString(fmt.Sprintf("%-8s", m.tr.T("notifyprefs.type")))
+	for i := range chats {
+		sb.WriteString(fmt.Sprintf(" %4s", fmt.Sprintf("#%d", i+1)))
+	}
+	sb.WriteString("\n")
+	for _, eventType := range notify.EventTypes {
+		sb.WriteString(fmt.Sprintf("%-8s", eventType))
+		for _, chatID := range chats {
+			state := "on"
+			if !m.notifyPrefs.Enabled(chatID, eventType) {
+				state = "off"
+			}
+			sb.WriteString(fmt.Sprintf(" %4s", state))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</pre>\n")
+
+	current := strconv.FormatInt(currentChatID, 10)
+	for i, chatID := range chats {
+		sb.WriteString(fmt.Sprintf("#%d: <code>%s</code>", i+1, html.EscapeString(chatID)))
+		if chatID == current {
+			sb.WriteString(m.tr.T("notifyprefs.current_chat"))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n" + m.tr.T("notifyprefs.note"))
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"time"
+)
+
+// checkDueSlack tolerates scheduler jitter so an instance due exactly on a tick is not skipped
+const checkDueSlack = time.Second
+
+// checkIntervalFor returns the check interval of an instance, honouring INSTANCE_OVERRIDES
+func (m *Monitor) checkIntervalFor(instanceKey string) time.Duration {
+	if o, ok := m.overrides[instanceKey]; ok && o.CheckInterval > 0 {
+		return time.Duration(o.CheckInterval) * time.Second
+	}
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return time.Duration(m.cfg.CheckInterval) * time.Second
+}
+
+// retryCountFor returns the number of start attempts for an instance
+func (m *Monitor) retryCountFor(instanceKey string) int {
+	if o, ok := m.overrides[instanceKey]; ok && o.RetryCount > 0 {
+		return o.RetryCount
+	}
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return m.cfg.RetryCount
+}
+
+// retryIntervalFor returns the base retry interval of an instance
+func (m *Monitor) retryIntervalFor(instanceKey string) time.Duration {
+	if o, ok := m.overrides[instanceKey]; ok && o.RetryInterval > 0 {
+		return time.Duration(o.RetryInterval) * time.Second
+	}
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return time.Duration(m.cfg.RetryInterval) * time.Second
+}
+
+// isCheckDue reports whether an instance should be checked on this tick and, if so,
+// records the check time. The scheduler ticks at the shortest configured interval.
+func (m *Monitor) isCheckDue(instanceKey string) bool {
+	m.lastCheckedMu.Lock()
+	defer m.lastCheckedMu.Unlock()
+
+	now := time.Now()
+	if last, ok := m.lastChecked[instanceKey]; ok && now.Sub(last) < m.checkIntervalFor(instanceKey)-checkDueSlack {
+		return false
+	}
+	m.lastChecked[instanceKey] = now
+	return true
+}
+
+// sshPortFor returns the port dialled by the SSH health check of an instance
+func (m *Monitor) sshPortFor(instanceKey string) int {
+	if o, ok := m.overrides[instanceKey]; ok && o.SSHPort > 0 {
+		return o.SSHPort
+	}
+	return defaultSSHPort
+}
+
+// tlsSkipVerifyFor reports whether the HTTP health check of an instance skips certificate verification
+func (m *Monitor) tlsSkipVerifyFor(instanceKey string) bool {
+	return m.overrides[instanceKey].TLSSkipVerify
+}
@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	log "github.com/sirupsen/logrus"
+)
+
+// autoStartPausedUntil reports whether auto-start of an instance is paused after rapid reclaims
+func (m *Monitor) autoStartPausedUntil(stateKey string) (time.Time, bool) {
+	until := m.state.Get(stateKey).PausedUntil
+	return until, time.Now().Before(until)
+}
+
+// autoStartBlocked reports why the checks of checkInstance and checkProviderInstance must leave
+// an instance alone: a manual operation in progress, a stop via /stop or an open maintenance
+// window. stateKey is the key of the instance in the state file.
+func (m *Monitor) autoStartBlocked(stateKey, instanceID, regionID string) (string, bool) {
+	if m.isManualOp(stateKey) {
+		return "manual operation in progress", true
+	}
+	if m.state.Get(stateKey).ManuallyStopped {
+		return "manually stopped via /stop", true
+	}
+	if until, ok := m.maintenanceUntil(instanceID, regionID); ok {
+		return "maintenance window active until " + until.Format("15:04:05"), true
+	}
+	return "", false
+}
+
+// notifyRapidReclaim logs and announces that auto-start of an instance was paused
+func (m *Monitor) notifyRapidReclaim(stateKey, instanceName, region, accountLabel string, recent int) {
+	until, _ := m.autoStartPausedUntil(stateKey)
+	log.Warnf("[%s] Instance %s reclaimed %d times within %ds, auto-start paused until %s",
+		accountLabel, instanceName, recent, m.cfg.RapidReclaimWindow, until.Format("2006-01-02 15:04:05"))
+
+	if m.notifier == nil {
+		return
+	}
+	instanceID := stateKey
+	if inst := m.findInstance(stateKey); inst != nil {
+		instanceID = inst.InstanceID
+	}
+	if err := m.notifier.NotifyRapidReclaim(accountLabel, instanceID, instanceName, region, recent,
+		time.Duration(m.cfg.RapidReclaimWindow)*time.Second, until); err != nil {
+		log.Warnf("[%s] Failed to send rapid reclaim notification: %v", accountLabel, err)
+	}
+}
+
+// resolveStateKey maps an Aliyun or AWS instance ID/name or a GCP instance name to its state key and display name
+func (m *Monitor) resolveStateKey(idOrName string) (key, name string, ok bool) {
+	if inst := m.findInstance(idOrName); inst != nil {
+		return inst.InstanceID, inst.InstanceName, true
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, inst := range m.gcpInstances {
+		if inst.InstanceName == idOrName {
+			return gcpStateKey(inst.Zone, inst.InstanceName), inst.InstanceName, true
+		}
+	}
+	for _, inst := range m.awsInstances {
+		if inst.InstanceID == idOrName || inst.InstanceName == idOrName {
+			return awsStateKey(inst.Region, inst.InstanceID), inst.InstanceName, true
+		}
+	}
+	return "", "", false
+}
+
+// unpauseAutoStart handles /unpause, resuming auto-start of an instance paused after rapid
+// reclaims or stopped via /stop
+func (m *Monitor) unpauseAutoStart(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if len(args) == 0 {
+		return m.reply().Send("❌ 请指定实例\n\n用法: <code>/unpause &lt;实例ID或名称&gt;</code>")
+	}
+
+	key, name, ok := m.resolveStateKey(args[0])
+	if !ok {
+		return m.reply().Send(fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(args[0])))
+	}
+
+	_, paused := m.autoStartPausedUntil(key)
+	if !paused && !m.state.Get(key).ManuallyStopped {
+		return m.reply().Send(fmt.Sprintf("ℹ️ 实例 <b>%s</b> 的自动启动未被暂停", name))
+	}
+
+	m.updateState(key, func(st *state.InstanceState) {
+		st.PausedUntil = time.Time{}
+		st.RecentReclaims = nil
+		st.ManuallyStopped = false
+		st.ManuallyStoppedAt = time.Time{}
+		st.ManuallyStoppedBy = ""
+	})
+	log.Infof("Auto-start of instance %s resumed via Telegram", name)
+
+	return m.reply().Send(fmt.Sprintf("▶️ 已恢复实例 <b>%s</b> 的自动启动，将在下个检测周期处理", name))
+}
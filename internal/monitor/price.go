@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// priceCacheTTL is how long spot prices are reused to stay clear of API rate limits
+	priceCacheTTL = 5 * time.Minute
+	// priceHistoryWindow is the window the average spot price is computed over
+	priceHistoryWindow = 24 * time.Hour
+)
+
+// priceCache caches zone price summaries keyed by region + "/" + instance type
+type priceCache struct {
+	entries map[string]priceCacheEntry
+	mu      sync.Mutex
+}
+
+type priceCacheEntry struct {
+	fetchedAt time.Time
+	zones     []aliyun.ZoneSpotPrice
+}
+
+// spotPrices returns the per-zone spot prices of an instance type, cheapest first
+func (m *Monitor) spotPrices(ctx context.Context, ecsClient *aliyun.ECSClient, regionID, instanceType string) ([]aliyun.ZoneSpotPrice, error) {
+	key := regionID + "/" + instanceType
+
+	m.prices.mu.Lock()
+	entry, ok := m.prices.entries[key]
+	m.prices.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < priceCacheTTL {
+		return entry.zones, nil
+	}
+
+	points, err := ecsClient.DescribeSpotPriceHistory(ctx, regionID, instanceType, time.Now().Add(-priceHistoryWindow))
+	if err != nil {
+		return nil, err
+	}
+	zones := aliyun.SummarizeSpotPrices(points)
+
+	m.prices.mu.Lock()
+	m.prices.entries[key] = priceCacheEntry{fetchedAt: time.Now(), zones: zones}
+	m.prices.mu.Unlock()
+
+	return zones, nil
+}
+
+// priceQuery is one region / instance type to show prices for
+type priceQuery struct {
+	accountLabel string
+	regionID     string
+	instanceType string
+	zoneID       string   // zone of the monitored instance, empty for explicit queries
+	instances    []string // names of monitored instances of this type in this zone
+}
+
+// sendSpotPrices handles /price [region] [instance-type]
+func (m *Monitor) sendSpotPrices(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if len(m.aliyunClients) == 0 {
+		return m.reply().Send(m.tr.T("price.no_account"))
+	}
+
+	var queries []*priceQuery
+	switch len(args) {
+	case 0:
+		queries = m.monitoredPriceQueries()
+		if len(queries) == 0 {
+			return m.reply().Send(m.tr.T("price.empty"))
+		}
+	case 2:
+		queries = []*priceQuery{{
+			accountLabel: m.aliyunClients[0].Account.Label,
+			regionID:     args[0],
+			instanceType: args[1],
+		}}
+	default:
+		return m.reply().Send(m.tr.T("price.usage"))
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(m.tr.T("price.header") + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for _, q := range queries {
+		sb.WriteString(fmt.Sprintf("🖥️ <b>%s</b> - %s\n", html.EscapeString(q.instanceType), aliyun.GetRegionDisplayName(q.regionID)))
+		if len(q.instances) > 0 {
+			sb.WriteString(m.tr.T("price.instances", html.EscapeString(strings.Join(q.instances, ", "))) + "\n")
+		}
+
+		ecsClient := m.getECSClientByLabel(q.accountLabel)
+		zones, err := m.spotPrices(ctx, ecsClient, q.regionID, q.instanceType)
+		if err != nil {
+			log.Warnf("[%s] Failed to query spot price of %s in %s: %v", q.accountLabel, q.instanceType, q.regionID, err)
+			sb.WriteString(m.tr.T("price.query_failed", html.EscapeString(err.Error())) + "\n\n")
+			continue
+		}
+		if len(zones) == 0 {
+			sb.WriteString(m.tr.T("price.no_data") + "\n\n")
+			continue
+		}
+
+		// Show the instance's own zone if known, otherwise the cheapest one
+		shown := zones[0]
+		if q.zoneID != "" {
+			for _, z := range zones {
+				if z.ZoneID == q.zoneID {
+					shown = z
+					break
+				}
+			}
+		}
+
+		sb.WriteString(m.tr.T("price.zone", shown.ZoneID) + "\n")
+		sb.WriteString(m.tr.T("price.current", shown.CurrentPrice) + "\n")
+		sb.WriteString(m.tr.T("price.average", shown.AveragePrice) + "\n")
+		if shown.OriginPrice > 0 {
+			sb.WriteString(m.tr.T("price.on_demand", shown.OriginPrice, shown.DiscountPercent()) + "\n")
+		}
+		if len(zones) > 1 {
+			cheapest := zones[0]
+			if cheapest.ZoneID == shown.ZoneID {
+				sb.WriteString(m.tr.T("price.cheapest_here") + "\n")
+			} else {
+				sb.WriteString(m.tr.T("price.cheapest", cheapest.ZoneID, cheapest.CurrentPrice) + "\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(m.tr.T("price.refresh", int(priceCacheTTL.Minutes())))
+	return m.reply().Send(sb.String())
+}
+
+// monitoredPriceQueries returns one price query per distinct account, zone and instance type
+// of the monitored instances
+func (m *Monitor) monitoredPriceQueries() []*priceQuery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var queries []*priceQuery
+	byKey := make(map[string]*priceQuery)
+	for _, inst := range m.instances {
+		if inst.InstanceType == "" {
+			continue
+		}
+		key := inst.AccountLabel + "/" + inst.ZoneID + "/" + inst.InstanceType
+		q, ok := byKey[key]
+		if !ok {
+			q = &priceQuery{
+				accountLabel: inst.AccountLabel,
+				regionID:     inst.RegionID,
+				instanceType: inst.InstanceType,
+				zoneID:       inst.ZoneID,
+			}
+			byKey[key] = q
+			queries = append(queries, q)
+		}
+		q.instances = append(q.instances, inst.InstanceName)
+	}
+	return queries
+}
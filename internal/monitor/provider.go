@@ -0,0 +1,270 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/aws"
+	"github.com/iliyian/aliyun-spot-manager/internal/gcp"
+)
+
+// Instance states reported by CloudProvider, in the Aliyun spelling
+const (
+	providerStatusRunning    = "Running"
+	providerStatusStarting   = "Starting"
+	providerStatusStopping   = "Stopping"
+	providerStatusStopped    = "Stopped"
+	providerStatusTerminated = "Terminated"
+)
+
+// ProviderInstance is a spot instance as listed by a CloudProvider
+type ProviderInstance struct {
+	InstanceID   string
+	InstanceName string
+	RegionID     string
+	VpcID        string // empty when the provider does not report it
+	PublicIP     string
+	Status       string // one of the providerStatus values
+	StateKey     string // key of the instance in the state file
+}
+
+// CloudProvider is a cloud account whose spot instances the monitor watches. The Aliyun
+// accounts, AWS EC2 and GCP implement it; m.providers lists them in the order shown in
+// /status, except GCP.
+type CloudProvider interface {
+	// Name identifies the provider in logs and notifications, e.g. "AWS"
+	Name() string
+	// Icon prefixes the provider's instances in /status
+	Icon() string
+	// AccountLabel returns the label of the account, empty for a single account
+	AccountLabel() string
+	// Instances returns the instances found by the last discovery
+	Instances() []*ProviderInstance
+	// GetInstance returns the current state and addresses of an instance
+	GetInstance(ctx context.Context, regionID, instanceID string) (*ProviderInstance, error)
+	StartInstance(ctx context.Context, regionID, instanceID string) error
+	StopInstance(ctx context.Context, regionID, instanceID string) error
+}
+
+// aliyunProvider is the CloudProvider of an Aliyun account. Its instances are checked by
+// checkInstance, which also handles bandwidth packages, health checks and traffic limits.
+type aliyunProvider struct {
+	m   *Monitor
+	acc *AliyunAccountClients
+}
+
+func (p *aliyunProvider) Name() string         { return "Aliyun" }
+func (p *aliyunProvider) Icon() string         { return "☁️" }
+func (p *aliyunProvider) AccountLabel() string { return p.acc.Account.Label }
+
+func (p *aliyunProvider) Instances() []*ProviderInstance {
+	p.m.mu.RLock()
+	defer p.m.mu.RUnlock()
+
+	var instances []*ProviderInstance
+	for _, inst := range p.m.instances {
+		if inst.AccountLabel == p.acc.Account.Label {
+			instances = append(instances, aliyunProviderInstance(inst))
+		}
+	}
+	return instances
+}
+
+func (p *aliyunProvider) GetInstance(ctx context.Context, regionID, instanceID string) (*ProviderInstance, error) {
+	inst, err := p.acc.ECSClient.GetInstance(ctx, regionID, instanceID, p.acc.Account.Label)
+	if err != nil {
+		return nil, err
+	}
+	return aliyunProviderInstance(inst), nil
+}
+
+func (p *aliyunProvider) StartInstance(ctx context.Context, regionID, instanceID string) error {
+	return p.acc.ECSClient.StartInstance(ctx, regionID, instanceID)
+}
+
+// StopInstance stops in the economical mode (StopCharging) the monitor uses for all stops
+func (p *aliyunProvider) StopInstance(ctx context.Context, regionID, instanceID string) error {
+	return p.acc.ECSClient.StopInstance(ctx, regionID, instanceID, "StopCharging")
+}
+
+// aliyunProviderInstance converts an Aliyun spot instance
+func aliyunProviderInstance(inst *aliyun.SpotInstance) *ProviderInstance {
+	return &ProviderInstance{
+		InstanceID:   inst.InstanceID,
+		InstanceName: inst.InstanceName,
+		RegionID:     inst.RegionID,
+		VpcID:        inst.VpcID,
+		PublicIP:     inst.PublicIPAddress,
+		Status:       inst.Status,
+		StateKey:     inst.InstanceID,
+	}
+}
+
+// awsProvider is the CloudProvider of the AWS account (AWS_ACCESS_KEY_ID)
+type awsProvider struct {
+	m      *Monitor
+	client *aws.EC2Client
+}
+
+func (p *awsProvider) Name() string         { return "AWS" }
+func (p *awsProvider) Icon() string         { return "🟠" }
+func (p *awsProvider) AccountLabel() string { return "" }
+
+func (p *awsProvider) Instances() []*ProviderInstance {
+	p.m.mu.RLock()
+	defer p.m.mu.RUnlock()
+
+	instances := make([]*ProviderInstance, 0, len(p.m.awsInstances))
+	for _, inst := range p.m.awsInstances {
+		instances = append(instances, awsProviderInstance(inst))
+	}
+	return instances
+}
+
+func (p *awsProvider) GetInstance(ctx context.Context, regionID, instanceID string) (*ProviderInstance, error) {
+	inst, err := p.client.GetInstance(ctx, regionID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return awsProviderInstance(inst), nil
+}
+
+func (p *awsProvider) StartInstance(ctx context.Context, regionID, instanceID string) error {
+	return p.client.StartInstance(ctx, regionID, instanceID)
+}
+
+func (p *awsProvider) StopInstance(ctx context.Context, regionID, instanceID string) error {
+	return p.client.StopInstance(ctx, regionID, instanceID)
+}
+
+// awsProviderInstance converts an EC2 spot instance
+func awsProviderInstance(inst *aws.SpotInstance) *ProviderInstance {
+	return &ProviderInstance{
+		InstanceID:   inst.InstanceID,
+		InstanceName: inst.InstanceName,
+		RegionID:     inst.Region,
+		PublicIP:     inst.PublicIP,
+		Status:       awsStatus(inst.Status),
+		StateKey:     awsStateKey(inst.Region, inst.InstanceID),
+	}
+}
+
+// awsStatus maps an EC2 state name to its providerStatus value
+func awsStatus(state string) string {
+	switch state {
+	case aws.StateRunning:
+		return providerStatusRunning
+	case aws.StatePending:
+		return providerStatusStarting
+	case aws.StateStopping:
+		return providerStatusStopping
+	case aws.StateStopped:
+		return providerStatusStopped
+	default: // shutting-down, terminated
+		return providerStatusTerminated
+	}
+}
+
+// awsStateKey returns the state key of an AWS instance; the "aws:" prefix avoids collisions
+// with Aliyun instance IDs
+func awsStateKey(region, instanceID string) string {
+	return "aws:" + region + "/" + instanceID
+}
+
+// gcpProvider is the CloudProvider of the GCP project. It is not in m.providers: /status
+// lists GCP instances on its own, so it only serves checkProviderInstance.
+type gcpProvider struct {
+	m      *Monitor
+	client *gcp.ComputeClient
+}
+
+func (p *gcpProvider) Name() string         { return "GCP" }
+func (p *gcpProvider) Icon() string         { return "☁️" }
+func (p *gcpProvider) AccountLabel() string { return "" }
+
+func (p *gcpProvider) Instances() []*ProviderInstance {
+	p.m.mu.RLock()
+	defer p.m.mu.RUnlock()
+
+	instances := make([]*ProviderInstance, 0, len(p.m.gcpInstances))
+	for _, inst := range p.m.gcpInstances {
+		instances = append(instances, gcpProviderInstance(inst))
+	}
+	return instances
+}
+
+// GetInstance takes the zone as regionID and the instance name as instanceID
+func (p *gcpProvider) GetInstance(ctx context.Context, regionID, instanceID string) (*ProviderInstance, error) {
+	inst, err := p.client.GetInstance(ctx, regionID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return gcpProviderInstance(inst), nil
+}
+
+func (p *gcpProvider) StartInstance(ctx context.Context, regionID, instanceID string) error {
+	return p.client.StartInstance(ctx, regionID, instanceID)
+}
+
+func (p *gcpProvider) StopInstance(ctx context.Context, regionID, instanceID string) error {
+	return p.client.StopInstance(ctx, regionID, instanceID)
+}
+
+// gcpProviderInstance converts a GCP instance. GCP instances have no separate ID, so
+// overrides, health URLs and incidents use the instance name.
+func gcpProviderInstance(inst *gcp.PreemptibleInstance) *ProviderInstance {
+	return &ProviderInstance{
+		InstanceID:   inst.InstanceName,
+		InstanceName: inst.InstanceName,
+		RegionID:     inst.Zone,
+		PublicIP:     inst.ExternalIP,
+		Status:       gcpStatus(inst.Status),
+		StateKey:     gcpStateKey(inst.Zone, inst.InstanceName),
+	}
+}
+
+// gcpStatus maps a GCP instance status to its providerStatus value
+func gcpStatus(status string) string {
+	switch status {
+	case "RUNNING":
+		return providerStatusRunning
+	case "PROVISIONING", "STAGING":
+		return providerStatusStarting
+	case "STOPPING", "SUSPENDING":
+		return providerStatusStopping
+	case "STOPPED", "TERMINATED": // a preempted instance is TERMINATED
+		return providerStatusStopped
+	default: // SUSPENDED and REPAIRING cannot be started
+		return providerStatusTerminated
+	}
+}
+
+// gcpStateKey returns the state key of a GCP instance; the "gcp:" prefix avoids collisions
+// with Aliyun instance IDs
+func gcpStateKey(zone, instanceName string) string {
+	return "gcp:" + zone + "/" + instanceName
+}
+
+// newCloudProviders returns the providers of the configured Aliyun accounts and AWS
+func (m *Monitor) newCloudProviders() []CloudProvider {
+	providers := make([]CloudProvider, 0, len(m.aliyunClients)+1)
+	for _, acc := range m.aliyunClients {
+		providers = append(providers, &aliyunProvider{m: m, acc: acc})
+	}
+	if m.awsClient != nil {
+		providers = append(providers, &awsProvider{m: m, client: m.awsClient})
+	}
+	return providers
+}
+
+// providerStatusEmoji returns the /status marker of a providerStatus value
+func providerStatusEmoji(status string) string {
+	switch status {
+	case providerStatusStopped, providerStatusTerminated:
+		return "🔴"
+	case providerStatusStarting, providerStatusStopping:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// reclaimEventWarnings remembers the scheduled system events already warned about
+type reclaimEventWarnings struct {
+	sent map[string]time.Time // "instance ID|event time" -> event time
+	mu   sync.Mutex
+}
+
+// markSent records an event and reports whether it was new. Events that are past are forgotten.
+func (w *reclaimEventWarnings) markSent(event *aliyun.ReclaimEvent, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for key, eventTime := range w.sent {
+		if eventTime.Before(now.Add(-time.Hour)) {
+			delete(w.sent, key)
+		}
+	}
+
+	key := event.InstanceID + "|" + event.EventTime.UTC().Format(time.RFC3339)
+	if _, ok := w.sent[key]; ok {
+		return false
+	}
+	w.sent[key] = event.EventTime
+	return true
+}
+
+// checkReclaimEvents warns once per event about monitored instances with a scheduled system
+// event (ECS DescribeInstanceHistoryEvents) within RECLAIM_WARN_MINUTES, ahead of the reclaim
+// the regular check reacts to
+func (m *Monitor) checkReclaimEvents(ctx context.Context, instances []*aliyun.SpotInstance) {
+	if m.cfg.ReclaimWarnMinutes <= 0 || m.notifier == nil {
+		return
+	}
+
+	byAccount := make(map[string]map[string]*aliyun.SpotInstance)
+	for _, inst := range instances {
+		if byAccount[inst.AccountLabel] == nil {
+			byAccount[inst.AccountLabel] = make(map[string]*aliyun.SpotInstance)
+		}
+		byAccount[inst.AccountLabel][inst.InstanceID] = inst
+	}
+
+	window := time.Duration(m.cfg.ReclaimWarnMinutes) * time.Minute
+	for _, acc := range m.aliyunClients {
+		monitored := byAccount[acc.Account.Label]
+		if len(monitored) == 0 {
+			continue
+		}
+
+		regionSet := make(map[string]bool)
+		for _, inst := range monitored {
+			regionSet[inst.RegionID] = true
+		}
+		regions := make([]string, 0, len(regionSet))
+		for regionID := range regionSet {
+			regions = append(regions, regionID)
+		}
+		sort.Strings(regions)
+
+		for _, regionID := range regions {
+			events, err := acc.ECSClient.GetPendingReclaimEvents(ctx, regionID)
+			if err != nil {
+				log.WithContext(ctx).Warnf("[%s] Failed to query scheduled events in region %s: %v", acc.Account.Label, regionID, err)
+				continue
+			}
+
+			now := time.Now()
+			for _, event := range events {
+				inst, ok := monitored[event.InstanceID]
+				if !ok || event.EventTime.After(now.Add(window)) {
+					continue
+				}
+				if !m.reclaimEvents.markSent(event, now) {
+					continue
+				}
+
+				log.Warnf("[%s] Scheduled event %s (%s) for instance %s (%s) at %s",
+					acc.Account.Label, event.EventType, event.Status, inst.InstanceID, inst.InstanceName,
+					event.EventTime.In(m.cfg.Location).Format("2006-01-02 15:04:05"))
+				m.recordIncident(storage.EventReclaimNotice, inst.InstanceID, inst.InstanceName, inst.RegionID, 0, nil)
+				if err := m.notifier.Send(formatReclaimEventWarning(inst, event, now, m.cfg.Location)); err != nil {
+					log.Warnf("[%s] Failed to send scheduled reclaim warning: %v", acc.Account.Label, err)
+				}
+			}
+		}
+	}
+}
+
+// formatReclaimEventWarning renders the warning about a scheduled system event of an instance
+func formatReclaimEventWarning(inst *aliyun.SpotInstance, event *aliyun.ReclaimEvent, now time.Time, loc *time.Location) string {
+	accountTitle := ""
+	if inst.AccountLabel != "" {
+		accountTitle = fmt.Sprintf(" [%s]", html.EscapeString(inst.AccountLabel))
+	}
+
+	when := "即将执行"
+	if minutes := int(math.Ceil(event.EventTime.Sub(now).Minutes())); minutes > 0 {
+		when = fmt.Sprintf("%d 分钟后", minutes)
+	}
+
+	return fmt.Sprintf(`⏰ <b>实例计划回收%s</b>
+━━━━━━━━━━━━━━━
+实例 <code>%s</code> 计划于 <b>%s</b> 回收（%s）
+名称: %s
+区域: %s
+事件: %s (%s)
+━━━━━━━━━━━━━━━
+<i>来自 ECS 计划事件，停止后将自动尝试启动</i>`,
+		accountTitle, inst.InstanceID, event.EventTime.In(loc).Format("15:04"), when,
+		html.EscapeString(inst.InstanceName), inst.RegionID, event.EventType, event.Status)
+}
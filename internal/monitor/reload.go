@@ -0,0 +1,220 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/config"
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+// liveConfigFields are the settings applied on SIGHUP without a restart; changes to any
+// other setting are reported as requiring a restart
+var liveConfigFields = []string{
+	"CheckInterval", "RetryCount", "RetryInterval", "NotifyCooldown",
+	"TrafficLimitChinaGB", "TrafficLimitNonChinaGB", "TrafficLimits",
+}
+
+// reloadIgnoredFields are derived or informational settings left out of the reload diff
+var reloadIgnoredFields = []string{"ConfigFile", "EnvOverrides", "CronSchedule", "Location"}
+
+// scheduledCheck is a cron job that runs on the instance check schedule (CronSchedule) and
+// is rescheduled when CHECK_INTERVAL is reloaded
+type scheduledCheck struct {
+	id  cron.EntryID
+	job func()
+}
+
+// addCheckJob schedules a job on the instance check schedule
+func (m *Monitor) addCheckJob(c *cron.Cron, job func()) error {
+	id, err := c.AddFunc(m.cfg.CronSchedule, job)
+	if err != nil {
+		return err
+	}
+	m.checkJobs = append(m.checkJobs, &scheduledCheck{id: id, job: job})
+	return nil
+}
+
+// snapshotConfig copies the settings compared on reload, so runtime changes such as
+// /setlimit do not show up as configuration changes
+func snapshotConfig(cfg *config.Config) *config.Config {
+	snapshot := *cfg
+	snapshot.TrafficLimits = maps.Clone(cfg.TrafficLimits)
+	return &snapshot
+}
+
+// watchReload reloads the configuration on SIGHUP until ctx is cancelled
+func (m *Monitor) watchReload(ctx context.Context, wg *sync.WaitGroup, c *cron.Cron) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				log.Info("Received SIGHUP, reloading configuration")
+				m.reloadConfig(c)
+			}
+		}
+	}()
+}
+
+// reloadConfig loads the configuration again (.env, config file and environment), applies
+// the live settings and reports the changes, including those that need a restart
+func (m *Monitor) reloadConfig(c *cron.Cron) {
+	if err := config.LoadDotEnv(config.DotEnvFile); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to reload %s: %v", config.DotEnvFile, err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		log.Errorf("Config reload failed, keeping the current configuration: %v", err)
+		m.sendReloadMessage(fmt.Sprintf("❌ Config reload failed, keeping the current configuration:\n%s", html.EscapeString(err.Error())))
+		return
+	}
+
+	applied, restart := diffConfig(m.loadedCfg, cfg)
+	if len(applied) == 0 && len(restart) == 0 {
+		log.Info("Config reloaded: no changes")
+		m.sendReloadMessage("⚙️ Config reloaded: no changes")
+		return
+	}
+
+	m.applyLiveConfig(cfg, c)
+
+	var sb strings.Builder
+	if len(applied) > 0 {
+		log.Infof("Config reloaded: %s", strings.Join(applied, ", "))
+		sb.WriteString(fmt.Sprintf("⚙️ Config reloaded: %s.", html.EscapeString(strings.Join(applied, ", "))))
+	} else {
+		sb.WriteString("⚙️ Config reloaded: no live changes.")
+	}
+	if len(restart) > 0 {
+		log.Warnf("Config changes requiring a restart were not applied: %s", strings.Join(restart, ", "))
+		sb.WriteString(fmt.Sprintf("\n⚠️ Requires restart (not applied): %s", strings.Join(restart, ", ")))
+	}
+	m.sendReloadMessage(sb.String())
+}
+
+// applyLiveConfig applies the live settings of a reloaded configuration
+func (m *Monitor) applyLiveConfig(cfg *config.Config, c *cron.Cron) {
+	m.reloadMu.Lock()
+	scheduleChanged := m.cfg.CronSchedule != cfg.CronSchedule
+	m.cfg.CheckInterval = cfg.CheckInterval
+	m.cfg.CronSchedule = cfg.CronSchedule
+	m.cfg.RetryCount = cfg.RetryCount
+	m.cfg.RetryInterval = cfg.RetryInterval
+	m.cfg.NotifyCooldown = cfg.NotifyCooldown
+	m.reloadMu.Unlock()
+
+	if scheduleChanged {
+		for _, sc := range m.checkJobs {
+			c.Remove(sc.id)
+			id, err := c.AddFunc(cfg.CronSchedule, sc.job)
+			if err != nil {
+				log.Errorf("Failed to reschedule instance check with %s: %v", cfg.CronSchedule, err)
+				continue
+			}
+			sc.id = id
+		}
+		log.Infof("Instance check rescheduled: %s", cfg.CronSchedule)
+	}
+
+	// Limits changed at runtime with /setlimit stay in effect
+	for scope, gb := range cfg.TrafficLimits {
+		if gb == m.loadedCfg.TrafficLimits[scope] {
+			continue
+		}
+		if m.state.Get(trafficLimitStateKey(scope)).TrafficLimitGB > 0 {
+			log.Infof("Traffic limit of %s changed to %.0f GB in the configuration, keeping the /setlimit value", scope, gb)
+			continue
+		}
+		m.setTrafficLimit(scope, gb)
+	}
+
+	live := reflect.ValueOf(m.loadedCfg).Elem()
+	reloaded := reflect.ValueOf(cfg).Elem()
+	for _, name := range liveConfigFields {
+		live.FieldByName(name).Set(reloaded.FieldByName(name))
+	}
+	m.loadedCfg.TrafficLimits = maps.Clone(cfg.TrafficLimits)
+}
+
+// diffConfig compares two configurations. applied describes the changed live settings
+// ("CheckInterval 60→30"); restart lists the names of other changed settings, without
+// values since they may be credentials.
+func diffConfig(old, cfg *config.Config) (applied, restart []string) {
+	oldValue := reflect.ValueOf(old).Elem()
+	newValue := reflect.ValueOf(cfg).Elem()
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if slices.Contains(reloadIgnoredFields, name) {
+			continue
+		}
+		a, b := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+		switch {
+		case name == "TrafficLimits":
+			applied = append(applied, diffTrafficLimits(old.TrafficLimits, cfg.TrafficLimits)...)
+		case slices.Contains(liveConfigFields, name):
+			applied = append(applied, fmt.Sprintf("%s %v→%v", name, a, b))
+		default:
+			restart = append(restart, name)
+		}
+	}
+	if old.Location.String() != cfg.Location.String() {
+		restart = append(restart, "Location")
+	}
+	return applied, restart
+}
+
+// diffTrafficLimits describes the changed per-region traffic limits; the China and non-China
+// limits are reported as TrafficLimitChinaGB / TrafficLimitNonChinaGB
+func diffTrafficLimits(old, limits map[string]float64) []string {
+	scopes := make(map[string]bool)
+	for scope := range old {
+		scopes[scope] = true
+	}
+	for scope := range limits {
+		scopes[scope] = true
+	}
+
+	var changes []string
+	for scope := range scopes {
+		if scope == aliyun.TrafficScopeChina || scope == aliyun.TrafficScopeNonChina || old[scope] == limits[scope] {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("TrafficLimits[%s] %v→%v", scope, old[scope], limits[scope]))
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// sendReloadMessage reports a config reload to the notification channels
+func (m *Monitor) sendReloadMessage(message string) {
+	if m.notifier == nil {
+		return
+	}
+	if err := m.notifier.Send(message); err != nil {
+		log.Warnf("Failed to send config reload notification: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	log "github.com/sirupsen/logrus"
+)
+
+// restartConfirmTimeout is how long a /restart confirmation dialog stays valid
+const restartConfirmTimeout = 60 * time.Second
+
+// isManualOp reports whether an instance is under a manual operation
+func (m *Monitor) isManualOp(instanceID string) bool {
+	m.manualOpsMu.RLock()
+	defer m.manualOpsMu.RUnlock()
+	return m.manualOps[instanceID]
+}
+
+// setManualOp marks or unmarks an instance as under a manual operation.
+// It returns false if the instance was already marked.
+func (m *Monitor) setManualOp(instanceID string, active bool) bool {
+	m.manualOpsMu.Lock()
+	defer m.manualOpsMu.Unlock()
+	if active && m.manualOps[instanceID] {
+		return false
+	}
+	if active {
+		m.manualOps[instanceID] = true
+	} else {
+		delete(m.manualOps, instanceID)
+	}
+	return true
+}
+
+// sendRestartConfirm asks for confirmation before restarting a running instance
+func (m *Monitor) sendRestartConfirm(args []string) error {
+	if m.botHandler == nil {
+		return fmt.Errorf("bot handler not initialized")
+	}
+
+	if len(args) == 0 {
+		return m.reply().Send("❌ 请指定实例\n\n用法: <code>/restart &lt;实例ID或名称&gt;</code>")
+	}
+
+	inst := m.findInstance(args[0])
+	if inst == nil {
+		return m.reply().Send(fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(args[0])))
+	}
+
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	if ecsClient == nil {
+		return m.reply().Send("❌ 未找到该账号的客户端")
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+	status, err := ecsClient.GetInstanceStatus(ctx, inst.RegionID, inst.InstanceID)
+	if err != nil {
+		return m.reply().Send(fmt.Sprintf("❌ 查询实例状态失败: %v", err))
+	}
+	if status != "Running" {
+		return m.reply().Send(fmt.Sprintf("❌ 实例 <b>%s</b> 当前状态为 %s，仅可重启运行中的实例", inst.InstanceName, status))
+	}
+
+	m.pendingRestartsMu.Lock()
+	m.pendingRestarts[inst.InstanceID] = time.Now()
+	m.pendingRestartsMu.Unlock()
+
+	text := fmt.Sprintf("⚠️ 确认重启 <code>%s</code> (%s)？\n\n<i>%d 秒内有效</i>",
+		inst.InstanceID, inst.InstanceName, int(restartConfirmTimeout.Seconds()))
+	keyboard := [][]notify.InlineKeyboardButton{
+		{
+			{Text: "✅ 确认", CallbackData: "restart:confirm:" + inst.InstanceID},
+			{Text: "❌ 取消", CallbackData: "restart:cancel:" + inst.InstanceID},
+		},
+	}
+	return m.botHandler.SendMessageWithKeyboard(text, keyboard)
+}
+
+// handleRestartCallback handles restart:confirm:<id> and restart:cancel:<id> callbacks
+func (m *Monitor) handleRestartCallback(callbackID, data string, msg notify.MessageRef) error {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+	action, instanceID := parts[1], parts[2]
+
+	// A confirmation can only be used once
+	m.pendingRestartsMu.Lock()
+	sentAt, pending := m.pendingRestarts[instanceID]
+	delete(m.pendingRestarts, instanceID)
+	m.pendingRestartsMu.Unlock()
+
+	if action == "cancel" {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "已取消", false)
+		return m.botHandler.EditMessageText(msg, fmt.Sprintf("❌ 已取消重启 <code>%s</code>", html.EscapeString(instanceID)), nil)
+	}
+	if action != "confirm" {
+		return nil
+	}
+
+	if !pending || time.Since(sentAt) > restartConfirmTimeout {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "确认已过期", true)
+		return m.botHandler.EditMessageText(msg,
+			fmt.Sprintf("⌛ 重启确认已过期，请重新发送 <code>/restart %s</code>", html.EscapeString(instanceID)), nil)
+	}
+
+	inst := m.findInstance(instanceID)
+	if inst == nil {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		return m.botHandler.EditMessageText(msg, fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(instanceID)), nil)
+	}
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	if ecsClient == nil {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		return m.botHandler.EditMessageText(msg, "❌ 未找到该账号的客户端", nil)
+	}
+
+	if !m.setManualOp(instanceID, true) {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "该实例正在执行其他操作", true)
+		return nil
+	}
+
+	_ = m.botHandler.AnswerCallbackQuery(callbackID, "正在重启...", false)
+
+	// Restarting takes minutes; run it in the background so other commands stay responsive
+	go func() {
+		defer m.recoverAndNotify("instance restart")
+		defer m.setManualOp(instanceID, false)
+		m.restartInstance(ecsClient, inst, msg)
+	}()
+
+	return nil
+}
+
+// restartInstance stops and starts an instance, editing progress into the confirmation message.
+// On failure the instance is left in whatever state it reached.
+func (m *Monitor) restartInstance(ecsClient *aliyun.ECSClient, inst *aliyun.SpotInstance, msg notify.MessageRef) {
+	title := fmt.Sprintf("🔄 <b>重启实例</b> %s\n   ID: <code>%s</code>\n━━━━━━━━━━━━━━━━\n\n", inst.InstanceName, inst.InstanceID)
+	progress := func(step string) {
+		if err := m.botHandler.EditMessageText(msg, title+step, nil); err != nil {
+			log.Warnf("[%s] Failed to update restart progress: %v", inst.AccountLabel, err)
+		}
+	}
+	fail := func(step string, err error) {
+		log.Errorf("[%s] Manual restart of instance %s failed while %s: %v", inst.AccountLabel, inst.InstanceID, step, err)
+		progress(fmt.Sprintf("❌ %s失败: %v\n\n<i>实例保持当前状态，请手动检查</i>", step, err))
+	}
+
+	log.Infof("[%s] Manual restart of instance %s (%s) requested via Telegram", inst.AccountLabel, inst.InstanceName, inst.InstanceID)
+	startTime := time.Now()
+	// Each step has its own timeout, the run context only aborts the restart on shutdown
+	ctx := m.runCtx
+
+	progress("⏳ 正在停止实例...")
+	if err := ecsClient.StopInstance(ctx, inst.RegionID, inst.InstanceID, "StopCharging"); err != nil {
+		fail("停止实例", err)
+		return
+	}
+	if m.cfg.DryRun {
+		progress("🧪 DRY RUN 模式：未实际重启实例")
+		return
+	}
+	if err := waitForInstanceStatus(ctx, ecsClient.GetInstanceStatus, inst.RegionID, inst.InstanceID, "Stopped", 2*time.Minute); err != nil {
+		fail("等待实例停止", err)
+		return
+	}
+
+	progress("✅ 实例已停止\n⏳ 正在启动实例...")
+	if err := ecsClient.StartInstance(ctx, inst.RegionID, inst.InstanceID); err != nil {
+		fail("启动实例", err)
+		return
+	}
+	if err := m.waitForRunning(ctx, ecsClient, inst.RegionID, inst.InstanceID, inst.AccountLabel); err != nil {
+		fail("等待实例启动", err)
+		return
+	}
+
+	publicIP := inst.PublicIPAddress
+	if updated, err := ecsClient.GetInstance(ctx, inst.RegionID, inst.InstanceID, inst.AccountLabel); err == nil {
+		publicIP = updated.PublicIPAddress
+	}
+
+	log.Infof("[%s] Instance %s restarted in %.0f seconds", inst.AccountLabel, inst.InstanceID, time.Since(startTime).Seconds())
+	progress(fmt.Sprintf("✅ 实例已停止\n✅ 实例已启动\n\n   公网IP: <code>%s</code>\n   耗时: %.0f 秒",
+		publicIP, time.Since(startTime).Seconds()))
+}
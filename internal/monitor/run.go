@@ -0,0 +1,291 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+// runCheckCycle runs one scheduled Check. A panic is reported and recorded as a failed
+// cycle, and the scheduler goes on with the next one.
+func (m *Monitor) runCheckCycle(ctx context.Context) {
+	defer m.recoverAndNotify("instance check")
+	// Runs before the recover above, so a panic is still recorded as a failed cycle
+	panicked := true
+	defer func() { m.recordCheckCycle(panicked) }()
+
+	if err := m.Check(ctx); err != nil {
+		log.Errorf("Check failed: %v", err)
+	}
+	panicked = false
+}
+
+// Run starts all background components (scheduled checks and bot polling)
+// and blocks until ctx is cancelled, then shuts them down gracefully. In-progress starts get
+// SHUTDOWN_TIMEOUT to finish; ErrShutdownTimeout is returned when they do not.
+func (m *Monitor) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	// Check cycles run on their own context so a shutdown signal lets in-progress starts finish;
+	// it is cancelled only when SHUTDOWN_TIMEOUT expires
+	checkCtx, cancelChecks := context.WithCancel(context.Background())
+	defer cancelChecks()
+	m.runCtx = checkCtx
+
+	// Setup cron scheduler
+	// Schedules fire at the wall clock time of TIMEZONE
+	c := cron.New(cron.WithLocation(m.cfg.Location))
+	err := m.addCheckJob(c, func() { m.runCheckCycle(checkCtx) })
+	if err != nil {
+		return fmt.Errorf("failed to setup cron: %w", err)
+	}
+
+	// Setup EIP quota check on the instance check schedule
+	if m.cfg.EIPQuotaWarnPercent > 0 && m.notifier != nil {
+		err = m.addCheckJob(c, func() {
+			defer m.recoverAndNotify("EIP quota check")
+			if err := m.CheckEIPQuota(); err != nil {
+				log.Errorf("EIP quota check failed: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup EIP quota check cron: %w", err)
+		}
+		log.Infof("EIP quota warning enabled at %.0f%% of the quota", m.cfg.EIPQuotaWarnPercent*100)
+	}
+
+	// Setup bandwidth package utilization check
+	if m.cfg.BandwidthWarnPercent > 0 && m.notifier != nil {
+		_, err = c.AddFunc(fmt.Sprintf("@every %ds", m.cfg.BandwidthCheckInterval), func() {
+			defer m.recoverAndNotify("bandwidth utilization check")
+			if err := m.CheckBandwidthUtilization(); err != nil {
+				log.Errorf("Bandwidth utilization check failed: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup bandwidth utilization check cron: %w", err)
+		}
+		log.Infof("Bandwidth package warning enabled at %.0f%% of the capacity, check every %ds",
+			m.cfg.BandwidthWarnPercent*100, m.cfg.BandwidthCheckInterval)
+	}
+
+	// Setup traffic check cron if enabled
+	if m.cfg.TrafficShutdownEnabled {
+		trafficSchedule := fmt.Sprintf("@every %ds", m.cfg.TrafficCheckInterval)
+		_, err = c.AddFunc(trafficSchedule, func() {
+			defer m.recoverAndNotify("traffic check")
+			if err := m.CheckTraffic(); err != nil {
+				log.Errorf("Traffic check failed: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup traffic check cron: %w", err)
+		}
+		limits := m.trafficLimits()
+		log.Infof("Traffic shutdown enabled: China limit=%.0f GB, Non-China limit=%.0f GB, check every %ds",
+			limits[aliyun.TrafficScopeChina], limits[aliyun.TrafficScopeNonChina], m.cfg.TrafficCheckInterval)
+		for scope, limit := range limits {
+			if scope != aliyun.TrafficScopeChina && scope != aliyun.TrafficScopeNonChina {
+				log.Infof("Traffic shutdown: %s has its own limit of %.0f GB", scope, limit)
+			}
+		}
+
+		// Clear the shutdown flags when the month (and with it the traffic counters) rolls over,
+		// then restart the instances that were shut down
+		_, err = c.AddFunc(m.trafficResetSchedule(), func() {
+			defer m.recoverAndNotify("monthly traffic reset")
+			m.ResetMonthlyTraffic(checkCtx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup monthly traffic reset cron: %w", err)
+		}
+	}
+
+	// Setup scheduled billing digest (standard 5-field cron expression)
+	if m.cfg.BillingReportSchedule != "" && m.notifier != nil {
+		_, err = c.AddFunc(m.cfg.BillingReportSchedule, func() {
+			defer m.recoverAndNotify("scheduled billing report")
+			if err := m.SendBillingDigest(); err != nil {
+				log.Errorf("Scheduled billing report failed: %v", err)
+			}
+			if err := m.CheckCostAnomaly(); err != nil {
+				log.Errorf("Cost anomaly check failed: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup billing report cron %q: %w", m.cfg.BillingReportSchedule, err)
+		}
+		log.Infof("Scheduled billing report enabled: %s", m.cfg.BillingReportSchedule)
+	}
+
+	// Setup weekly billing comparison
+	if m.cfg.BillingWeeklySchedule != "" && m.notifier != nil {
+		_, err = c.AddFunc(m.cfg.BillingWeeklySchedule, func() {
+			defer m.recoverAndNotify("weekly billing comparison")
+			if err := m.SendWeeklyBillingComparison(); err != nil {
+				log.Errorf("Weekly billing comparison failed: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup weekly billing cron %q: %w", m.cfg.BillingWeeklySchedule, err)
+		}
+		log.Infof("Weekly billing comparison enabled: %s", m.cfg.BillingWeeklySchedule)
+	}
+
+	// Setup the daily traffic snapshot for /traffichistory
+	if m.db != nil && m.hasTrafficClient() {
+		_, err = c.AddFunc(m.trafficSnapshotSchedule(), func() {
+			defer m.recoverAndNotify("traffic snapshot")
+			if err := m.SnapshotDailyTraffic(); err != nil {
+				log.Errorf("Traffic snapshot failed: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to setup traffic snapshot cron: %w", err)
+		}
+		log.Infof("Daily traffic snapshot enabled at midnight (%s)", m.cfg.Location)
+	}
+
+	// Setup maintenance window notifications
+	if err := m.scheduleMaintenanceWindows(c); err != nil {
+		return err
+	}
+
+	// Start Telegram bot for commands
+	if m.botHandler != nil {
+		m.registerBotCommands()
+
+		if m.cfg.TelegramWebhookURL != "" {
+			if err := m.startWebhook(ctx, &wg); err != nil {
+				return err
+			}
+		} else {
+			// A webhook left over from an earlier run makes getUpdates fail
+			if err := m.botHandler.DeleteWebhook(); err != nil {
+				log.Warnf("Failed to clear Telegram webhook: %v", err)
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Restart polling after a recovered panic until shutdown
+				for ctx.Err() == nil {
+					func() {
+						defer m.recoverAndNotify("Telegram bot polling")
+						m.botHandler.Poll(ctx)
+					}()
+				}
+			}()
+		}
+	}
+
+	// Watch this instance's metadata for a spot reclaim notice
+	if m.cfg.MetadataPollEnabled {
+		m.startMetadataPoller(ctx, &wg)
+	}
+
+	// Receive EventBridge spot interruption events
+	if m.cfg.WebhookSecret != "" {
+		m.startEventServer(ctx, checkCtx, &wg)
+	}
+
+	// Serve Prometheus metrics
+	if m.metrics != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer m.recoverAndNotify("metrics server")
+			if err := m.metrics.Serve(ctx, m.cfg.MetricsAddr); err != nil {
+				log.Errorf("Metrics server: %v", err)
+			}
+		}()
+	}
+
+	// Serve the gRPC API
+	if m.cfg.GRPCEnabled {
+		if err := m.startGRPCServer(ctx, checkCtx, &wg); err != nil {
+			log.Errorf("gRPC server: %v", err)
+		}
+	}
+
+	// Apply configuration changes on SIGHUP
+	m.watchReload(ctx, &wg, c)
+
+	c.Start()
+	log.Infof("Scheduler started, checking every %d seconds", m.cfg.CheckInterval)
+	if len(m.overrides) > 0 {
+		log.Infof("Per-instance overrides active for %d instance(s), scheduler ticks every %d seconds",
+			len(m.overrides), m.cfg.MinCheckInterval())
+	}
+
+	<-ctx.Done()
+
+	log.Info("Shutting down...")
+	// Stop scheduling new cycles and wait for running jobs, including in-progress starts
+	timeout := time.Duration(m.cfg.ShutdownTimeout) * time.Second
+	var shutdownErr error
+	var abandoned []string
+	select {
+	case <-c.Stop().Done():
+	case <-time.After(timeout):
+		abandoned = m.startingInstances()
+		log.Errorf("Shutdown timed out after %s, instances still starting: %s", timeout, strings.Join(abandoned, ", "))
+		shutdownErr = fmt.Errorf("%w after %s", ErrShutdownTimeout, timeout)
+		cancelChecks()
+	}
+	m.notifyShutdown(abandoned)
+	wg.Wait()
+
+	return shutdownErr
+}
+
+// startWebhook registers the Telegram webhook and serves it until ctx is cancelled
+func (m *Monitor) startWebhook(ctx context.Context, wg *sync.WaitGroup) error {
+	secret := m.cfg.TelegramWebhookSecret
+	if secret == "" {
+		var err error
+		if secret, err = notify.GenerateWebhookSecret(); err != nil {
+			return err
+		}
+	}
+
+	if err := m.botHandler.SetWebhook(notify.WebhookURL(m.cfg.TelegramWebhookURL), secret); err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer m.recoverAndNotify("Telegram webhook server")
+		if err := m.botHandler.ServeWebhook(ctx, m.cfg.TelegramWebhookPort, secret,
+			m.cfg.TelegramWebhookCertFile, m.cfg.TelegramWebhookKeyFile); err != nil {
+			log.Errorf("Telegram webhook server: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// recoverAndNotify recovers from a panic in a background component and sends a Telegram alert.
+// It must be called directly via defer.
+func (m *Monitor) recoverAndNotify(component string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	log.Errorf("Panic in %s: %v\n%s", component, r, debug.Stack())
+	if m.notifier != nil {
+		if err := m.notifier.NotifyPanic(component, fmt.Sprint(r)); err != nil {
+			log.Warnf("Failed to send panic notification: %v", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/config"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	"github.com/robfig/cron/v3"
+)
+
+// panickyProvider is a CloudProvider with one instance whose first status query panics.
+// Methods not overridden panic through the nil embedded interface.
+type panickyProvider struct {
+	CloudProvider
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *panickyProvider) Name() string { return "Fake" }
+
+func (p *panickyProvider) Instances() []*ProviderInstance {
+	return []*ProviderInstance{p.instance()}
+}
+
+func (p *panickyProvider) GetInstance(context.Context, string, string) (*ProviderInstance, error) {
+	p.mu.Lock()
+	p.calls++
+	first := p.calls == 1
+	p.mu.Unlock()
+	if first {
+		panic("provider exploded")
+	}
+	return p.instance(), nil
+}
+
+func (p *panickyProvider) instance() *ProviderInstance {
+	return &ProviderInstance{
+		InstanceID:   "i-fake",
+		InstanceName: "fake",
+		RegionID:     "fake-region",
+		Status:       providerStatusRunning,
+		StateKey:     "fake:i-fake",
+	}
+}
+
+func (p *panickyProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// panicRecorder records panic notifications
+type panicRecorder struct {
+	notify.Notifier
+
+	mu     sync.Mutex
+	panics []string
+}
+
+func (n *panicRecorder) NotifyPanic(component, _ string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.panics = append(n.panics, component)
+	return nil
+}
+
+func (n *panicRecorder) recorded() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.panics...)
+}
+
+func TestCheckPanicKeepsSchedulerRunning(t *testing.T) {
+	store, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &panickyProvider{}
+	notifier := &panicRecorder{}
+	m := &Monitor{
+		cfg:         &config.Config{CronSchedule: "@every 1s", CheckInterval: 1},
+		state:       store,
+		notifier:    notifier,
+		providers:   []CloudProvider{provider},
+		lastChecked: make(map[string]time.Time),
+	}
+
+	// Scheduled the way Run schedules it; cron.New has no recover wrapper, so an unrecovered
+	// panic would crash the test binary
+	c := cron.New()
+	if err := m.addCheckJob(c, func() { m.runCheckCycle(context.Background()) }); err != nil {
+		t.Fatal(err)
+	}
+	c.Start()
+	defer c.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for provider.callCount() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("instance checked %d times after a panic, want a later cycle to check it again", provider.callCount())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if panics := notifier.recorded(); len(panics) != 1 || panics[0] != "instance check" {
+		t.Fatalf("panic notifications = %v, want [instance check]", panics)
+	}
+	m.health.mu.Lock()
+	lastCheck := m.health.lastCheck
+	m.health.mu.Unlock()
+	if lastCheck.IsZero() {
+		t.Error("no check cycle recorded as completed after the panic")
+	}
+}
@@ -0,0 +1,174 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	log "github.com/sirupsen/logrus"
+)
+
+// The methods in this file are the query and control operations offered to API clients
+// (the gRPC server). They return plain data and leave the formatting to the caller.
+
+// InstanceStatus is the state of a monitored Aliyun or GCP instance
+type InstanceStatus struct {
+	Provider        string // "aliyun" or "gcp"
+	AccountLabel    string
+	InstanceID      string // GCP: the instance name
+	InstanceName    string
+	Region          string // Aliyun region ID or GCP zone
+	InstanceType    string
+	Status          string
+	PublicIP        string
+	ManuallyStopped bool
+	TrafficShutdown bool
+}
+
+// AccountTraffic is the traffic of one Aliyun account with the usage of each limit scope
+type AccountTraffic struct {
+	Summary *aliyun.TrafficSummary
+	Scopes  []ScopeTraffic
+}
+
+// ScopeTraffic is the usage of one traffic limit scope
+type ScopeTraffic struct {
+	Scope    string
+	UsedGB   float64
+	LimitGB  float64 // 0 = no limit
+	Shutdown bool
+}
+
+// InstanceStatuses returns the last known status of every monitored instance
+func (m *Monitor) InstanceStatuses() []InstanceStatus {
+	m.mu.RLock()
+	instances := make([]*aliyun.SpotInstance, len(m.instances))
+	copy(instances, m.instances)
+	statuses := make([]InstanceStatus, 0, len(m.instances)+len(m.gcpInstances))
+	for _, inst := range m.gcpInstances {
+		statuses = append(statuses, InstanceStatus{
+			Provider:     "gcp",
+			InstanceID:   inst.InstanceName,
+			InstanceName: inst.InstanceName,
+			Region:       inst.Zone,
+			InstanceType: inst.MachineType,
+			Status:       inst.Status,
+			PublicIP:     inst.ExternalIP,
+		})
+	}
+	m.mu.RUnlock()
+
+	limits := m.trafficLimits()
+	m.trafficShutdownMu.RLock()
+	defer m.trafficShutdownMu.RUnlock()
+	aliyunStatuses := make([]InstanceStatus, 0, len(instances))
+	for _, inst := range instances {
+		aliyunStatuses = append(aliyunStatuses, InstanceStatus{
+			Provider:        "aliyun",
+			AccountLabel:    inst.AccountLabel,
+			InstanceID:      inst.InstanceID,
+			InstanceName:    inst.InstanceName,
+			Region:          inst.RegionID,
+			InstanceType:    inst.InstanceType,
+			Status:          inst.Status,
+			PublicIP:        inst.PublicIPAddress,
+			ManuallyStopped: m.state.Get(inst.InstanceID).ManuallyStopped,
+			TrafficShutdown: m.trafficShutdown[inst.AccountLabel][aliyun.TrafficScope(inst.RegionID, limits)],
+		})
+	}
+	return append(aliyunStatuses, statuses...)
+}
+
+// BillingSummaries queries the billing summary of each Aliyun account over the last hours,
+// or the current month when hours is 0. An error is returned only if no account succeeded.
+func (m *Monitor) BillingSummaries(ctx context.Context, hours int) ([]*aliyun.BillingSummary, error) {
+	instancesByAccount := m.billingInstancesByAccount()
+
+	var summaries []*aliyun.BillingSummary
+	var errs []error
+	for _, acc := range m.aliyunClients {
+		instanceInfos := instancesByAccount[acc.Account.Label]
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
+			continue
+		}
+
+		log.Infof("[%s] Querying billing for %d instances...", acc.Account.Label, len(instanceInfos))
+		var summary *aliyun.BillingSummary
+		var err error
+		if hours > 0 {
+			summary, err = acc.BillingClient.QueryBillingByHours(ctx, instanceInfos, hours)
+		} else {
+			summary, err = acc.BillingClient.QueryBilling(ctx, instanceInfos, acc.Account.Label)
+		}
+		if err != nil {
+			log.Errorf("[%s] Failed to query billing: %v", acc.Account.Label, err)
+			errs = append(errs, fmt.Errorf("%s: %w", acc.Account.Label, err))
+			continue
+		}
+		summary.AccountLabel = acc.Account.Label
+		summaries = append(summaries, summary)
+	}
+
+	if len(summaries) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return summaries, nil
+}
+
+// TrafficSummaries queries the traffic of each Aliyun account this month. An error is
+// returned only if no account succeeded.
+func (m *Monitor) TrafficSummaries() ([]AccountTraffic, error) {
+	limits := m.trafficLimits()
+
+	var traffic []AccountTraffic
+	var errs []error
+	for _, acc := range m.aliyunClients {
+		if acc.TrafficClient == nil {
+			continue
+		}
+
+		summary, err := acc.TrafficClient.QueryInternetTraffic(acc.Account.Label)
+		if err != nil {
+			log.Errorf("[%s] Failed to query traffic: %v", acc.Account.Label, err)
+			errs = append(errs, fmt.Errorf("%s: %w", acc.Account.Label, err))
+			continue
+		}
+
+		usage := summary.ScopeTrafficGB(limits)
+		scopes := make([]ScopeTraffic, 0, len(usage))
+		m.trafficShutdownMu.RLock()
+		for scope, usedGB := range usage {
+			scopes = append(scopes, ScopeTraffic{
+				Scope:    scope,
+				UsedGB:   usedGB,
+				LimitGB:  limits[scope],
+				Shutdown: m.trafficShutdown[acc.Account.Label][scope],
+			})
+		}
+		m.trafficShutdownMu.RUnlock()
+		sort.Slice(scopes, func(i, j int) bool { return scopes[i].Scope < scopes[j].Scope })
+
+		traffic = append(traffic, AccountTraffic{Summary: summary, Scopes: scopes})
+	}
+
+	if len(traffic) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return traffic, nil
+}
+
+// TriggerCheck runs a check cycle right away, including instances checked within their
+// check interval, and returns when it has finished
+func (m *Monitor) TriggerCheck(ctx context.Context) (started, finished time.Time, err error) {
+	m.lastCheckedMu.Lock()
+	m.lastChecked = make(map[string]time.Time)
+	m.lastCheckedMu.Unlock()
+
+	started = time.Now()
+	log.Info("Check cycle triggered via API")
+	err = m.Check(ctx)
+	return started, time.Now(), err
+}
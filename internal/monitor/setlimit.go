@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// setLimitConfirmTimeout is how long a /setlimit confirmation dialog stays valid
+const setLimitConfirmTimeout = 60 * time.Second
+
+// trafficLimitStateKey is the state store key of a runtime traffic limit override
+func trafficLimitStateKey(scope string) string {
+	return "traffic-limit:" + scope
+}
+
+// setLimitScopes maps /setlimit arguments to limit scopes
+var setLimitScopes = map[string]string{
+	"china":     aliyun.TrafficScopeChina,
+	"non-china": aliyun.TrafficScopeNonChina,
+	"nonchina":  aliyun.TrafficScopeNonChina,
+}
+
+// trafficLimitState holds the runtime traffic limits and pending /setlimit confirmations
+type trafficLimitState struct {
+	pending map[string]pendingLimit // limit scope -> limit awaiting confirmation
+	mu      sync.RWMutex            // guards cfg.TrafficLimits and the China / non-China limits
+}
+
+// pendingLimit is a /setlimit below current usage awaiting confirmation
+type pendingLimit struct {
+	limitGB float64
+	sentAt  time.Time
+}
+
+// trafficLimits returns a copy of the traffic limits by scope
+func (m *Monitor) trafficLimits() map[string]float64 {
+	m.limits.mu.RLock()
+	defer m.limits.mu.RUnlock()
+
+	limits := make(map[string]float64, len(m.cfg.TrafficLimits))
+	for scope, gb := range m.cfg.TrafficLimits {
+		limits[scope] = gb
+	}
+	return limits
+}
+
+// setTrafficLimit updates the limit of a scope and returns the previous one
+func (m *Monitor) setTrafficLimit(scope string, limitGB float64) float64 {
+	m.limits.mu.Lock()
+	defer m.limits.mu.Unlock()
+
+	old := m.cfg.TrafficLimits[scope]
+	m.cfg.TrafficLimits[scope] = limitGB
+	switch scope {
+	case aliyun.TrafficScopeChina:
+		m.cfg.TrafficLimitChinaGB = limitGB
+	case aliyun.TrafficScopeNonChina:
+		m.cfg.TrafficLimitNonChinaGB = limitGB
+	}
+	return old
+}
+
+// loadTrafficLimitOverrides applies the limits persisted by /setlimit
+func (m *Monitor) loadTrafficLimitOverrides() {
+	for _, scope := range []string{aliyun.TrafficScopeChina, aliyun.TrafficScopeNonChina} {
+		if gb := m.state.Get(trafficLimitStateKey(scope)).TrafficLimitGB; gb > 0 {
+			old := m.setTrafficLimit(scope, gb)
+			log.Infof("Traffic limit of %s set to %.0f GB via /setlimit (configured: %.0f GB)", scope, gb, old)
+		}
+	}
+}
+
+// sendSetLimit handles /setlimit <china|non-china> <GB>: changes a traffic limit at runtime,
+// asking for confirmation first when current usage already exceeds the new limit or cannot be queried
+func (m *Monitor) sendSetLimit(args []string) error {
+	if m.botHandler == nil {
+		return fmt.Errorf("bot handler not initialized")
+	}
+
+	usage := "用法: <code>/setlimit china &lt;GB&gt;</code> 或 <code>/setlimit non-china &lt;GB&gt;</code>"
+	if !m.cfg.TrafficShutdownEnabled {
+		return m.reply().Send("❌ 流量超额关机未启用（<code>TRAFFIC_SHUTDOWN_ENABLED=false</code>）")
+	}
+	if len(args) != 2 {
+		return m.reply().Send("❌ 参数错误\n\n" + usage)
+	}
+	scope, ok := setLimitScopes[strings.ToLower(args[0])]
+	if !ok {
+		return m.reply().Send(fmt.Sprintf("❌ 无效的范围: %s\n\n%s", html.EscapeString(args[0]), usage))
+	}
+	limitGB, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToUpper(args[1]), "GB"), 64)
+	if err != nil || limitGB <= 0 {
+		return m.reply().Send(fmt.Sprintf("❌ 无效的限额: %s\n\n%s", html.EscapeString(args[1]), usage))
+	}
+
+	usedGB, err := m.highestScopeUsage(scope)
+	if err == nil && usedGB < limitGB {
+		return m.applyTrafficLimit(scope, limitGB)
+	}
+	warning := fmt.Sprintf("⚠️ 当前用量 (%.2f GB) 已超过该限额，确认后将立即触发关机", usedGB)
+	if err != nil {
+		// Without the current usage the new limit may already be exceeded, so ask as if it were
+		log.Warnf("Failed to query traffic for /setlimit: %v", err)
+		warning = fmt.Sprintf("⚠️ 无法查询当前用量: %s\n若已超过该限额，确认后将立即触发关机", html.EscapeString(err.Error()))
+	}
+
+	m.limits.mu.Lock()
+	m.limits.pending[scope] = pendingLimit{limitGB: limitGB, sentAt: time.Now()}
+	m.limits.mu.Unlock()
+
+	text := fmt.Sprintf("%s\n\n确认将 %s 限额设为 <b>%.0f GB</b>？\n\n<i>%d 秒内有效</i>",
+		warning, scopeDisplayName(scope), limitGB, int(setLimitConfirmTimeout.Seconds()))
+	keyboard := [][]notify.InlineKeyboardButton{
+		{
+			{Text: "✅ 确认", CallbackData: "setlimit:confirm:" + scope},
+			{Text: "❌ 取消", CallbackData: "setlimit:cancel:" + scope},
+		},
+	}
+	return m.botHandler.SendMessageWithKeyboard(text, keyboard)
+}
+
+// handleSetLimitCallback handles setlimit:confirm:<scope> and setlimit:cancel:<scope> callbacks
+func (m *Monitor) handleSetLimitCallback(callbackID, data string, msg notify.MessageRef) error {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+	action, scope := parts[1], parts[2]
+
+	// A confirmation can only be used once
+	m.limits.mu.Lock()
+	pending, ok := m.limits.pending[scope]
+	delete(m.limits.pending, scope)
+	m.limits.mu.Unlock()
+
+	if action == "cancel" {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "已取消", false)
+		return m.botHandler.EditMessageText(msg, "❌ 已取消修改流量限额", nil)
+	}
+	if action != "confirm" {
+		return nil
+	}
+	if !ok || time.Since(pending.sentAt) > setLimitConfirmTimeout {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "确认已过期", true)
+		return m.botHandler.EditMessageText(msg, "⌛ 确认已过期，请重新发送 <code>/setlimit</code>", nil)
+	}
+
+	_ = m.botHandler.AnswerCallbackQuery(callbackID, "已确认", false)
+	if err := m.botHandler.EditMessageText(msg, fmt.Sprintf("✅ 已确认将 %s 限额设为 %.0f GB", scopeDisplayName(scope), pending.limitGB), nil); err != nil {
+		log.Warnf("Failed to update /setlimit confirmation: %v", err)
+	}
+	if err := m.applyTrafficLimit(scope, pending.limitGB); err != nil {
+		return err
+	}
+
+	// Usage already exceeds the new limit, so shut down now instead of at the next traffic check
+	go func() {
+		defer m.recoverAndNotify("traffic check")
+		if err := m.CheckTraffic(); err != nil {
+			log.Errorf("Traffic check failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// applyTrafficLimit sets, persists and audits a new limit, then confirms it to the user
+func (m *Monitor) applyTrafficLimit(scope string, limitGB float64) error {
+	old := m.setTrafficLimit(scope, limitGB)
+	m.updateState(trafficLimitStateKey(scope), func(st *state.InstanceState) { st.TrafficLimitGB = limitGB })
+
+	log.WithFields(log.Fields{
+		"audit":  "setlimit",
+		"scope":  scope,
+		"old_gb": old,
+		"new_gb": limitGB,
+	}).Warnf("Traffic limit of %s changed via /setlimit: %.0f GB -> %.0f GB", scope, old, limitGB)
+	m.recordIncident(storage.EventTrafficLimitChanged, "", fmt.Sprintf("%.0f → %.0f GB", old, limitGB), scope, 0, nil)
+
+	return m.reply().Send(fmt.Sprintf("✅ <b>流量限额已更新</b>\n\n%s: %.0f GB → <b>%.0f GB</b>\n\n<i>已保存，重启后仍然生效</i>",
+		scopeDisplayName(scope), old, limitGB))
+}
+
+// highestScopeUsage returns the highest traffic of a limit scope across accounts in GB
+func (m *Monitor) highestScopeUsage(scope string) (float64, error) {
+	limits := m.trafficLimits()
+	var highest float64
+	var lastErr error
+	for _, acc := range m.aliyunClients {
+		if acc.TrafficClient == nil {
+			continue
+		}
+		summary, err := acc.TrafficClient.QueryInternetTraffic(acc.Account.Label)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		highest = max(highest, summary.ScopeTrafficGB(limits)[scope])
+	}
+	return highest, lastErr
+}
+
+// scopeDisplayName returns the display name of the China / non-China limit scopes
+func scopeDisplayName(scope string) string {
+	switch scope {
+	case aliyun.TrafficScopeChina:
+		return "中国大陆"
+	case aliyun.TrafficScopeNonChina:
+		return "非中国大陆"
+	}
+	return scope
+}
@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+)
+
+// instanceSecurityGroups is the /sgroups result of one instance
+type instanceSecurityGroups struct {
+	inst   *aliyun.SpotInstance
+	groups []aliyun.SecurityGroupInfo
+	err    error
+}
+
+// sendSecurityGroups handles /sgroups: lists the security groups attached to each monitored
+// Aliyun instance and highlights instances whose groups differ from EXPECTED_SECURITY_GROUPS
+func (m *Monitor) sendSecurityGroups() error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	m.mu.RLock()
+	results := make([]*instanceSecurityGroups, 0, len(m.instances))
+	for _, inst := range m.instances {
+		results = append(results, &instanceSecurityGroups{inst: inst})
+	}
+	m.mu.RUnlock()
+
+	if len(results) == 0 {
+		return m.reply().Send("🛡 <b>安全组</b>\n\n暂无监控的实例")
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, r := range results {
+		ecsClient := m.getECSClientByLabel(r.inst.AccountLabel)
+		if ecsClient == nil {
+			r.err = fmt.Errorf("account %s not found", r.inst.AccountLabel)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.groups, r.err = ecsClient.GetInstanceSecurityGroups(ctx, r.inst.RegionID, r.inst.InstanceID)
+		}()
+	}
+	wg.Wait()
+
+	var sb strings.Builder
+	sb.WriteString("🛡 <b>安全组</b>\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	drifted := 0
+	for _, r := range results {
+		name := r.inst.InstanceName
+		if name == "" {
+			name = r.inst.InstanceID
+		}
+		expected, hasExpected := m.cfg.ExpectedSecurityGroups[r.inst.InstanceID]
+
+		if r.err != nil {
+			sb.WriteString(fmt.Sprintf("\n⚠️ <b>%s</b> (<code>%s</code>)\n  查询失败: %s\n", html.EscapeString(name),
+				r.inst.InstanceID, html.EscapeString(r.err.Error())))
+			continue
+		}
+
+		var missing, extra []string
+		if hasExpected {
+			missing, extra = securityGroupDrift(expected, r.groups)
+		}
+		icon := "⚪"
+		switch {
+		case len(missing) > 0 || len(extra) > 0:
+			icon = "🔴"
+			drifted++
+		case hasExpected:
+			icon = "🟢"
+		}
+
+		sb.WriteString(fmt.Sprintf("\n%s <b>%s</b> (<code>%s</code>)\n", icon, html.EscapeString(name), r.inst.InstanceID))
+		sb.WriteString(fmt.Sprintf("  [%s] %s\n", html.EscapeString(r.inst.AccountLabel), r.inst.RegionID))
+		if len(r.groups) == 0 {
+			sb.WriteString("  (未绑定安全组)\n")
+		}
+		for _, sg := range r.groups {
+			marker := ""
+			if slices.Contains(extra, sg.ID) {
+				marker = "🔴 "
+			}
+			sb.WriteString(fmt.Sprintf("  %s<code>%s</code> %s\n", marker, sg.ID, html.EscapeString(sg.Name)))
+		}
+		if len(missing) > 0 {
+			sb.WriteString(fmt.Sprintf("  🔴 缺少: <code>%s</code>\n", strings.Join(missing, "</code>, <code>")))
+		}
+		if len(extra) > 0 {
+			sb.WriteString(fmt.Sprintf("  🔴 多出: <code>%s</code>\n", strings.Join(extra, "</code>, <code>")))
+		}
+	}
+
+	if len(m.cfg.ExpectedSecurityGroups) > 0 {
+		sb.WriteString("\n━━━━━━━━━━━━━━━━\n")
+		if drifted > 0 {
+			sb.WriteString(fmt.Sprintf("🔴 %d 个实例的安全组与 <code>EXPECTED_SECURITY_GROUPS</code> 不一致", drifted))
+		} else {
+			sb.WriteString("🟢 所有已配置实例的安全组均与预期一致")
+		}
+	}
+
+	return m.reply().Send(truncateTelegramMessage(sb.String()))
+}
+
+// securityGroupDrift returns the expected security group IDs that are not attached and the
+// attached ones that are not expected
+func securityGroupDrift(expected []string, actual []aliyun.SecurityGroupInfo) (missing, extra []string) {
+	attached := make(map[string]bool, len(actual))
+	for _, sg := range actual {
+		attached[sg.ID] = true
+	}
+	for _, id := range expected {
+		if !attached[id] {
+			missing = append(missing, id)
+		}
+	}
+	for _, sg := range actual {
+		if !slices.Contains(expected, sg.ID) {
+			extra = append(extra, sg.ID)
+		}
+	}
+	return missing, extra
+}
@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrShutdownTimeout is returned by Run when in-progress starts did not finish within SHUTDOWN_TIMEOUT
+var ErrShutdownTimeout = errors.New("shutdown timed out")
+
+// markStarting records that a start of the instance is in progress
+func (m *Monitor) markStarting(key, name string) {
+	m.startingMu.Lock()
+	defer m.startingMu.Unlock()
+	m.starting[key] = name
+}
+
+// clearStarting records that the start of the instance has finished
+func (m *Monitor) clearStarting(key string) {
+	m.startingMu.Lock()
+	defer m.startingMu.Unlock()
+	delete(m.starting, key)
+}
+
+// startingInstances returns the instances with a start in progress as "name (key)", sorted
+func (m *Monitor) startingInstances() []string {
+	m.startingMu.Lock()
+	defer m.startingMu.Unlock()
+
+	instances := make([]string, 0, len(m.starting))
+	for key, name := range m.starting {
+		instances = append(instances, name+" ("+key+")")
+	}
+	sort.Strings(instances)
+	return instances
+}
+
+// sleepContext sleeps for d, returning ctx.Err() early when ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// operationTimeout bounds the ECS API calls of an operation outside check cycles, such as a
+// bot command, so an unresponsive endpoint cannot block it indefinitely
+const operationTimeout = 2 * time.Minute
+
+// operationContext returns the context of an operation outside check cycles: it ends after
+// operationTimeout or when SHUTDOWN_TIMEOUT expires after a shutdown signal
+func (m *Monitor) operationContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(m.runCtx, operationTimeout)
+}
+
+// notifyShutdown sends the shutdown notification, listing the instances whose start was abandoned
+func (m *Monitor) notifyShutdown(abandoned []string) {
+	if m.notifier == nil {
+		return
+	}
+	if err := m.notifier.NotifyMonitorStopping(abandoned); err != nil {
+		log.Warnf("Failed to send shutdown notification: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build integration
+
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SimulateReclaim stops an instance the way a spot reclaim does (StopCharging) and
+// blocks until the monitor's next check cycle has started it again.
+// The monitor must be running (see Run) for the auto-restart to happen.
+// Cancelling ctx stops waiting. Only available in integration builds.
+func (m *Monitor) SimulateReclaim(ctx context.Context, instanceID string) error {
+	inst := m.findInstance(instanceID)
+	if inst == nil {
+		return fmt.Errorf("instance %s is not tracked by the monitor", instanceID)
+	}
+
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	if ecsClient == nil {
+		return fmt.Errorf("no ECS client found for account %s", inst.AccountLabel)
+	}
+
+	log.Warnf("[%s] Simulating reclaim of instance %s (%s)", inst.AccountLabel, inst.InstanceName, inst.InstanceID)
+	if err := ecsClient.StopInstance(ctx, inst.RegionID, inst.InstanceID, "StopCharging"); err != nil {
+		return fmt.Errorf("failed to stop instance: %w", err)
+	}
+
+	if err := waitForInstanceStatus(ctx, ecsClient.GetInstanceStatus, inst.RegionID, inst.InstanceID, "Stopped", 5*time.Minute); err != nil {
+		return fmt.Errorf("instance did not stop: %w", err)
+	}
+	log.Infof("[%s] Instance %s stopped, waiting for auto-restart", inst.AccountLabel, inst.InstanceID)
+
+	// One check interval until the next cycle, plus the worst case of all start retries
+	restartTimeout := m.checkIntervalFor(inst.InstanceID) +
+		time.Duration(m.retryCountFor(inst.InstanceID))*(time.Duration(m.cfg.MaxRetryInterval)*time.Second+2*time.Minute)
+	if err := waitForInstanceStatus(ctx, ecsClient.GetInstanceStatus, inst.RegionID, inst.InstanceID, "Running", restartTimeout); err != nil {
+		return fmt.Errorf("instance was not restarted by the monitor: %w", err)
+	}
+
+	log.Infof("[%s] Instance %s was restarted by the monitor", inst.AccountLabel, inst.InstanceID)
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package monitor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/config"
+)
+
+// TestMonitorRestartsReclaimedInstance stops a live spot instance the way a reclaim does and
+// expects the running monitor to start it again. It needs ALIYUN_ACCESS_KEY_ID,
+// ALIYUN_ACCESS_KEY_SECRET and the ID of a disposable instance in TEST_INSTANCE_ID:
+//
+//	go test -tags integration -run TestMonitorRestartsReclaimedInstance -timeout 30m ./internal/monitor
+func TestMonitorRestartsReclaimedInstance(t *testing.T) {
+	instanceID := os.Getenv("TEST_INSTANCE_ID")
+	if os.Getenv("ALIYUN_ACCESS_KEY_ID") == "" || os.Getenv("ALIYUN_ACCESS_KEY_SECRET") == "" || instanceID == "" {
+		t.Skip("ALIYUN_ACCESS_KEY_ID, ALIYUN_ACCESS_KEY_SECRET and TEST_INSTANCE_ID are required")
+	}
+
+	// Keep the test away from the deployment's state, listeners and chats
+	dir := t.TempDir()
+	t.Setenv("STATE_FILE", filepath.Join(dir, "state.json"))
+	t.Setenv("DB_PATH", filepath.Join(dir, "state.db"))
+	t.Setenv("TELEGRAM_ENABLED", "false")
+	t.Setenv("METRICS_ENABLED", "false")
+	t.Setenv("DRY_RUN", "false")
+	t.Setenv("CHECK_INTERVAL", "30")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Minute)
+	defer cancel()
+	if err := m.DiscoverInstances(ctx); err != nil {
+		t.Fatalf("Failed to discover instances: %v", err)
+	}
+	if m.findInstance(instanceID) == nil {
+		t.Fatalf("Instance %s was not discovered; check INSTANCE_FILTER_TAGS and the regions", instanceID)
+	}
+
+	runCtx, stopRun := context.WithCancel(ctx)
+	runDone := make(chan error, 1)
+	go func() { runDone <- m.Run(runCtx) }()
+	t.Cleanup(func() {
+		stopRun()
+		if err := <-runDone; err != nil {
+			t.Errorf("Monitor stopped with error: %v", err)
+		}
+	})
+
+	if err := m.SimulateReclaim(ctx, instanceID); err != nil {
+		t.Fatalf("SimulateReclaim: %v", err)
+	}
+
+	if st := m.state.Get(instanceID); st.LastReclaimAt.IsZero() {
+		t.Errorf("reclaim of %s was not recorded in the state", instanceID)
+	}
+}
@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// snapshotPollInterval is how often a snapshot being created is checked
+	snapshotPollInterval = 30 * time.Second
+	// snapshotPollTimeout is how long a snapshot is waited for before giving up
+	snapshotPollTimeout = 2 * time.Hour
+)
+
+// sendSnapshot handles /snapshot <instance>: starts a snapshot of the instance's system disk
+// and replies with the snapshot ID right away; a notification follows when it completes
+func (m *Monitor) sendSnapshot(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if len(args) == 0 {
+		return m.reply().Send("用法: <code>/snapshot &lt;实例ID或名称&gt;</code>")
+	}
+
+	inst := m.findInstance(args[0])
+	if inst == nil {
+		return m.reply().Send(fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(args[0])))
+	}
+
+	snapshotID, err := m.createSnapshot(inst)
+	if err != nil {
+		return m.reply().Send(fmt.Sprintf("❌ 创建 <b>%s</b> 的快照失败: %s", html.EscapeString(inst.InstanceName), html.EscapeString(err.Error())))
+	}
+	if snapshotID == "" {
+		return m.reply().Send(fmt.Sprintf("📸 [DRY RUN] 未创建 <b>%s</b> 的快照", html.EscapeString(inst.InstanceName)))
+	}
+	return m.reply().Send(fmt.Sprintf("📸 已开始创建 <b>%s</b> 的系统盘快照: <code>%s</code>\n\n<i>快照完成后将另行通知</i>",
+		html.EscapeString(inst.InstanceName), snapshotID))
+}
+
+// createSnapshot starts a snapshot of the system disk of an instance and watches it in the
+// background until it completes. The snapshot ID is empty in dry-run mode.
+func (m *Monitor) createSnapshot(inst *aliyun.SpotInstance) (string, error) {
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	if ecsClient == nil {
+		return "", fmt.Errorf("no ECS client for account %q", inst.AccountLabel)
+	}
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+	snapshotID, err := ecsClient.CreateSnapshot(ctx, inst.RegionID, inst.InstanceID)
+	if err != nil {
+		log.Errorf("[%s] Failed to create snapshot of instance %s: %v", inst.AccountLabel, inst.InstanceID, err)
+		return "", err
+	}
+	if snapshotID != "" {
+		go m.watchSnapshot(m.runCtx, ecsClient, inst, snapshotID)
+	}
+	return snapshotID, nil
+}
+
+// watchSnapshot polls a snapshot until it is accomplished or failed, or snapshotPollTimeout
+// passes, and notifies the result
+func (m *Monitor) watchSnapshot(ctx context.Context, ecsClient *aliyun.ECSClient, inst *aliyun.SpotInstance, snapshotID string) {
+	defer m.recoverAndNotify("snapshot watcher")
+
+	deadline := time.After(snapshotPollTimeout)
+	ticker := time.NewTicker(snapshotPollInterval)
+	defer ticker.Stop()
+
+	var message string
+	for message == "" {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			log.Warnf("[%s] Snapshot %s of instance %s not completed after %s", inst.AccountLabel, snapshotID, inst.InstanceID, snapshotPollTimeout)
+			message = fmt.Sprintf("⚠️ 快照 <code>%s</code> (%s) %.0f 小时后仍未完成，停止跟踪",
+				snapshotID, html.EscapeString(inst.InstanceName), snapshotPollTimeout.Hours())
+		case <-ticker.C:
+			status, progress, err := ecsClient.GetSnapshotStatus(ctx, inst.RegionID, snapshotID)
+			if err != nil {
+				log.Warnf("[%s] Failed to get status of snapshot %s: %v", inst.AccountLabel, snapshotID, err)
+				continue
+			}
+			log.Debugf("[%s] Snapshot %s: %s %s", inst.AccountLabel, snapshotID, status, progress)
+			switch status {
+			case "accomplished":
+				log.Infof("[%s] Snapshot %s of instance %s completed", inst.AccountLabel, snapshotID, inst.InstanceID)
+				message = fmt.Sprintf("✅ 快照 <code>%s</code> 已完成\n实例: %s (<code>%s</code>)",
+					snapshotID, html.EscapeString(inst.InstanceName), inst.InstanceID)
+			case "failed":
+				log.Errorf("[%s] Snapshot %s of instance %s failed", inst.AccountLabel, snapshotID, inst.InstanceID)
+				message = fmt.Sprintf("❌ 快照 <code>%s</code> 创建失败\n实例: %s (<code>%s</code>)",
+					snapshotID, html.EscapeString(inst.InstanceName), inst.InstanceID)
+			}
+		}
+	}
+
+	if m.notifier != nil {
+		if err := m.notifier.Send(message); err != nil {
+			log.Warnf("[%s] Failed to send snapshot notification: %v", inst.AccountLabel, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultSSHPort is dialled when INSTANCE_OVERRIDES sets no ssh_port
+	defaultSSHPort = 22
+	// sshDialTimeout bounds each TCP connect attempt of the SSH health check
+	sshDialTimeout = 5 * time.Second
+)
+
+// waitForSSH dials the SSH port of a started instance every HEALTH_CHECK_INTERVAL
+// until it accepts a TCP connection or HEALTH_CHECK_TIMEOUT expires
+func (m *Monitor) waitForSSH(publicIP string, port int) error {
+	if publicIP == "" {
+		return fmt.Errorf("instance has no public IP")
+	}
+
+	addr := net.JoinHostPort(publicIP, strconv.Itoa(port))
+	interval := time.Duration(m.cfg.HealthCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(m.cfg.HealthCheckTimeout) * time.Second)
+
+	for {
+		conn, err := net.DialTimeout("tcp", addr, sshDialTimeout)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		log.Debugf("SSH health check: %s not reachable yet: %v", addr, err)
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("%s not reachable within %ds: %w", addr, m.cfg.HealthCheckTimeout, err)
+		}
+		time.Sleep(interval)
+	}
+}
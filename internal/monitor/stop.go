@@ -0,0 +1,227 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/notify"
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	log "github.com/sirupsen/logrus"
+)
+
+// stopModeLabels maps ECS stopped modes to their button labels
+var stopModeLabels = map[string]string{
+	"StopCharging": "🔴 停机 (StopCharging)",
+	"KeepCharging": "⏸ 挂起 (KeepCharging)",
+}
+
+// sendStopInstanceList shows running instances that can be stopped manually
+func (m *Monitor) sendStopInstanceList() error {
+	if m.botHandler == nil {
+		return fmt.Errorf("bot handler not initialized")
+	}
+
+	m.mu.RLock()
+	instances := make([]*aliyun.SpotInstance, len(m.instances))
+	copy(instances, m.instances)
+	m.mu.RUnlock()
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+
+	var keyboard [][]notify.InlineKeyboardButton
+	for _, inst := range instances {
+		ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+		if ecsClient == nil {
+			continue
+		}
+		status, err := ecsClient.GetInstanceStatus(ctx, inst.RegionID, inst.InstanceID)
+		if err != nil || status != "Running" {
+			continue
+		}
+
+		label := ""
+		if inst.AccountLabel != "" {
+			label = fmt.Sprintf("[%s] ", inst.AccountLabel)
+		}
+		keyboard = append(keyboard, []notify.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s%s (%s)", label, inst.InstanceName, inst.RegionID),
+				CallbackData: "stop:select:" + inst.InstanceID,
+			},
+		})
+	}
+
+	if len(keyboard) == 0 {
+		return m.reply().Send("🛑 <b>手动停止实例</b>\n\n暂无运行中的实例")
+	}
+
+	keyboard = append(keyboard, []notify.InlineKeyboardButton{
+		{Text: "❌ 取消", CallbackData: "stop:cancel"},
+	})
+
+	text := "🛑 <b>手动停止实例</b>\n━━━━━━━━━━━━━━━━\n\n请选择要停止的实例（停止后不会自动启动）："
+	return m.botHandler.SendMessageWithKeyboard(text, keyboard)
+}
+
+// handleStopCallback handles the stop:select / stop:mode / stop:confirm / stop:cancel flow.
+// user is recorded as the operator of a confirmed stop.
+func (m *Monitor) handleStopCallback(callbackID, data string, msg notify.MessageRef, user *notify.TelegramUser) error {
+	parts := strings.Split(data, ":")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	if parts[1] == "cancel" {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "已取消", false)
+		return m.botHandler.EditMessageText(msg, "❌ 已取消停止实例", nil)
+	}
+	if len(parts) < 3 {
+		return nil
+	}
+
+	inst := m.findInstance(parts[2])
+	if inst == nil {
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		return m.botHandler.EditMessageText(msg, fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(parts[2])), nil)
+	}
+
+	switch parts[1] {
+	case "select":
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		text := fmt.Sprintf("🛑 <b>%s</b>\n   ID: <code>%s</code>\n━━━━━━━━━━━━━━━━\n\n请选择停止方式：\n"+
+			"• 停机：不再收取 vCPU/内存费用，重新启动可能因库存不足失败\n"+
+			"• 挂起：保留资源继续计费，可随时启动", inst.InstanceName, inst.InstanceID)
+		keyboard := [][]notify.InlineKeyboardButton{
+			{{Text: stopModeLabels["StopCharging"], CallbackData: "stop:mode:" + inst.InstanceID + ":StopCharging"}},
+			{{Text: stopModeLabels["KeepCharging"], CallbackData: "stop:mode:" + inst.InstanceID + ":KeepCharging"}},
+			{{Text: "❌ 取消", CallbackData: "stop:cancel"}},
+		}
+		return m.botHandler.EditMessageText(msg, text, keyboard)
+
+	case "mode":
+		if len(parts) < 4 || stopModeLabels[parts[3]] == "" {
+			return nil
+		}
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+		text := fmt.Sprintf("⚠️ 确认以 <b>%s</b> 方式停止 <code>%s</code> (%s)？\n\n<i>停止后自动启动将暂停，使用 /start 或 /unpause 恢复</i>",
+			parts[3], inst.InstanceID, inst.InstanceName)
+		keyboard := [][]notify.InlineKeyboardButton{
+			{
+				{Text: "✅ 确认", CallbackData: "stop:confirm:" + inst.InstanceID + ":" + parts[3]},
+				{Text: "❌ 取消", CallbackData: "stop:cancel"},
+			},
+		}
+		return m.botHandler.EditMessageText(msg, text, keyboard)
+
+	case "confirm":
+		if len(parts) < 4 || stopModeLabels[parts[3]] == "" {
+			return nil
+		}
+		ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+		if ecsClient == nil {
+			_ = m.botHandler.AnswerCallbackQuery(callbackID, "", false)
+			return m.botHandler.EditMessageText(msg, "❌ 未找到该账号的客户端", nil)
+		}
+		if !m.setManualOp(inst.InstanceID, true) {
+			_ = m.botHandler.AnswerCallbackQuery(callbackID, "该实例正在执行其他操作", true)
+			return nil
+		}
+		_ = m.botHandler.AnswerCallbackQuery(callbackID, "正在停止...", false)
+
+		stoppedMode := parts[3]
+		stoppedBy := ""
+		if user != nil {
+			stoppedBy = user.String()
+		}
+		go func() {
+			defer m.recoverAndNotify("manual instance stop")
+			defer m.setManualOp(inst.InstanceID, false)
+			m.stopInstanceManually(ecsClient, inst, stoppedMode, stoppedBy, msg)
+		}()
+		return nil
+	}
+
+	return nil
+}
+
+// stopInstanceManually stops an instance and marks it as manually stopped so auto-start skips it
+func (m *Monitor) stopInstanceManually(ecsClient *aliyun.ECSClient, inst *aliyun.SpotInstance, stoppedMode, stoppedBy string, msg notify.MessageRef) {
+	title := fmt.Sprintf("🛑 <b>停止实例</b> %s\n   ID: <code>%s</code>\n━━━━━━━━━━━━━━━━\n\n", inst.InstanceName, inst.InstanceID)
+	progress := func(step string) {
+		if err := m.botHandler.EditMessageText(msg, title+step, nil); err != nil {
+			log.Warnf("[%s] Failed to update stop progress: %v", inst.AccountLabel, err)
+		}
+	}
+
+	log.Infof("[%s] Manual stop (%s) of instance %s (%s) requested via Telegram by %s",
+		inst.AccountLabel, stoppedMode, inst.InstanceName, inst.InstanceID, stoppedBy)
+
+	// The wait below has its own timeout, the run context only aborts the stop on shutdown
+	ctx := m.runCtx
+	progress("⏳ 正在停止实例...")
+	if err := ecsClient.StopInstance(ctx, inst.RegionID, inst.InstanceID, stoppedMode); err != nil {
+		log.Errorf("[%s] Manual stop of instance %s failed: %v", inst.AccountLabel, inst.InstanceID, err)
+		progress(fmt.Sprintf("❌ 停止实例失败: %v", err))
+		return
+	}
+	if m.cfg.DryRun {
+		progress("🧪 DRY RUN 模式：未实际停止实例")
+		return
+	}
+
+	// The stop was requested, so keep auto-start away even if it is slow to complete
+	m.updateState(inst.InstanceID, func(st *state.InstanceState) {
+		st.ManuallyStopped = true
+		st.ManuallyStoppedAt = time.Now()
+		st.ManuallyStoppedBy = stoppedBy
+	})
+
+	if err := waitForInstanceStatus(ctx, ecsClient.GetInstanceStatus, inst.RegionID, inst.InstanceID, "Stopped", 2*time.Minute); err != nil {
+		log.Warnf("[%s] Instance %s did not reach Stopped after manual stop: %v", inst.AccountLabel, inst.InstanceID, err)
+		progress(fmt.Sprintf("⚠️ 已发送停止命令，但等待停止超时: %v\n\n<i>自动启动已暂停，使用 /start %s 恢复</i>", err, inst.InstanceID))
+		return
+	}
+
+	log.Infof("[%s] Instance %s manually stopped, auto-start paused", inst.AccountLabel, inst.InstanceID)
+	progress(fmt.Sprintf("✅ 实例已停止 (%s)\n\n<i>自动启动已暂停，使用 /start %s 恢复</i>", stoppedMode, inst.InstanceID))
+}
+
+// startManuallyStopped handles /start: clears the manual stop flag and starts the instance
+func (m *Monitor) startManuallyStopped(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+
+	// Bare /start is what Telegram sends when a chat with the bot is first opened
+	if len(args) == 0 {
+		return m.sendHelpMessage()
+	}
+
+	inst := m.findInstance(args[0])
+	if inst == nil {
+		return m.reply().Send(fmt.Sprintf("❌ 未找到实例: <code>%s</code>", html.EscapeString(args[0])))
+	}
+	ecsClient := m.getECSClientByLabel(inst.AccountLabel)
+	if ecsClient == nil {
+		return m.reply().Send("❌ 未找到该账号的客户端")
+	}
+
+	m.updateState(inst.InstanceID, func(st *state.InstanceState) {
+		st.ManuallyStopped = false
+		st.ManuallyStoppedAt = time.Time{}
+		st.ManuallyStoppedBy = ""
+	})
+	log.Infof("[%s] Manual stop flag of instance %s cleared via Telegram", inst.AccountLabel, inst.InstanceID)
+
+	ctx, cancel := m.operationContext()
+	defer cancel()
+	if err := ecsClient.StartInstance(ctx, inst.RegionID, inst.InstanceID); err != nil {
+		return m.reply().Send(fmt.Sprintf("⚠️ 已恢复 <b>%s</b> 的自动启动，但启动命令失败: %v\n\n<i>将在下个检测周期重试</i>", inst.InstanceName, err))
+	}
+
+	return m.reply().Send(fmt.Sprintf("▶️ 已发送启动命令并恢复 <b>%s</b> 的自动启动", inst.InstanceName))
+}
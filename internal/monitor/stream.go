@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// streamPath is the Server-Sent Events endpoint of instance status changes
+	streamPath = "/stream/status"
+	// streamWriteTimeout is how long an event waits for a slow subscriber before it is dropped
+	streamWriteTimeout = time.Second
+	// streamBuffer is the number of events queued per subscriber
+	streamBuffer = 16
+	// streamKeepAlive is the interval of SSE comments that keep idle connections open through proxies
+	streamKeepAlive = 30 * time.Second
+)
+
+// Status stream event types
+const (
+	streamEventReclaim  = "reclaim"
+	streamEventStarting = "starting"
+	streamEventStarted  = "started"
+	streamEventFailed   = "failed"
+)
+
+// statusEvent is the JSON payload of a /stream/status event
+type statusEvent struct {
+	EventType    string `json:"event_type"`
+	InstanceID   string `json:"instance_id"`
+	InstanceName string `json:"instance_name"`
+	RegionID     string `json:"region_id"`
+	Status       string `json:"status"`
+	Timestamp    string `json:"timestamp"`
+}
+
+// statusBroadcaster fans status events out to the active /stream/status subscribers
+type statusBroadcaster struct {
+	subscribers map[chan statusEvent]struct{}
+	mu          sync.Mutex
+}
+
+// subscribe registers a subscriber; the channel is closed by unsubscribe
+func (b *statusBroadcaster) subscribe() chan statusEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan statusEvent]struct{})
+	}
+	ch := make(chan statusEvent, streamBuffer)
+	b.subscribers[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe removes a subscriber and closes its channel
+func (b *statusBroadcaster) unsubscribe(ch chan statusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Broadcast sends an event to every subscriber. A subscriber whose queue stays full for
+// streamWriteTimeout misses the event.
+func (b *statusBroadcaster) Broadcast(event statusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		case <-time.After(streamWriteTimeout):
+			log.Debugf("Dropped %s event of %s for a slow status stream subscriber", event.EventType, event.InstanceID)
+		}
+	}
+}
+
+// broadcastStatus publishes a status change of an instance to the status stream
+func (m *Monitor) broadcastStatus(eventType, instanceID, instanceName, regionID, status string) {
+	m.broadcaster.Broadcast(statusEvent{
+		EventType:    eventType,
+		InstanceID:   instanceID,
+		InstanceName: instanceName,
+		RegionID:     regionID,
+		Status:       status,
+		Timestamp:    time.Now().Format(time.RFC3339),
+	})
+}
+
+// handleStatusStream serves /stream/status: a Server-Sent Events stream of instance status
+// changes (reclaim, starting, started, failed) until the client disconnects
+func (m *Monitor) handleStatusStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events := m.broadcaster.subscribe()
+	defer m.broadcaster.unsubscribe(events)
+	log.Debugf("Status stream subscriber connected from %s", r.RemoteAddr)
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Debugf("Status stream subscriber %s disconnected", r.RemoteAddr)
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Warnf("Failed to encode status event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.EventType, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
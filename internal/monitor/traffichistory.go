@@ -0,0 +1,143 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/format"
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultTrafficHistoryDays is the number of days /traffichistory shows without an argument
+	defaultTrafficHistoryDays = 7
+	// maxTrafficHistoryDays caps the days accepted by /traffichistory
+	maxTrafficHistoryDays = 90
+)
+
+// trafficSnapshotSchedule returns the cron spec of the daily traffic snapshot: local midnight
+// in the configured timezone
+func (m *Monitor) trafficSnapshotSchedule() string {
+	return "CRON_TZ=" + m.cfg.Location.String() + " 0 0 * * *"
+}
+
+// hasTrafficClient reports whether any account can query traffic
+func (m *Monitor) hasTrafficClient() bool {
+	for _, acc := range m.aliyunClients {
+		if acc.TrafficClient != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SnapshotDailyTraffic records the traffic of the day that just ended for every account
+func (m *Monitor) SnapshotDailyTraffic() error {
+	if m.db == nil {
+		return fmt.Errorf("traffic history requires DB_PATH")
+	}
+
+	today := time.Now().In(m.cfg.Location)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, m.cfg.Location)
+	start := end.AddDate(0, 0, -1)
+	day := start.Format("2006-01-02")
+
+	var failed int
+	for _, acc := range m.aliyunClients {
+		if acc.TrafficClient == nil {
+			continue
+		}
+
+		summary, err := acc.TrafficClient.QueryInternetTrafficByTimeRange(start.UTC(), end.UTC(), acc.Account.Label)
+		if err != nil {
+			log.Errorf("[%s] Failed to query traffic of %s: %v", acc.Account.Label, day, err)
+			failed++
+			continue
+		}
+
+		err = m.db.RecordTrafficSnapshot(storage.TrafficSnapshot{
+			Day:           day,
+			AccountLabel:  acc.Account.Label,
+			ChinaBytes:    summary.ChinaMainland.Traffic,
+			NonChinaBytes: summary.NonChinaMainland.Traffic,
+		})
+		if err != nil {
+			log.Errorf("[%s] %v", acc.Account.Label, err)
+			failed++
+			continue
+		}
+		log.Infof("[%s] Recorded traffic of %s: China %s, non-China %s", acc.Account.Label, day,
+			aliyun.FormatTrafficSize(summary.ChinaMainland.Traffic), aliyun.FormatTrafficSize(summary.NonChinaMainland.Traffic))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("traffic snapshot of %s failed for %d account(s)", day, failed)
+	}
+	return nil
+}
+
+// sendTrafficHistory handles /traffichistory [days]: a sparkline of daily traffic followed by
+// the per-day China / non-China usage
+func (m *Monitor) sendTrafficHistory(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if m.db == nil {
+		return m.reply().Send(m.tr.T("traffichistory.disabled"))
+	}
+
+	days := defaultTrafficHistoryDays
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > maxTrafficHistoryDays {
+			return m.reply().Send(m.tr.T("traffichistory.invalid_days", html.EscapeString(args[0]), maxTrafficHistoryDays))
+		}
+		days = n
+	}
+
+	today := time.Now().In(m.cfg.Location)
+	since := time.Date(today.Year(), today.Month(), today.Day()-days, 0, 0, 0, 0, m.cfg.Location)
+	snapshots, err := m.db.DailyTraffic(since.Format("2006-01-02"))
+	if err != nil {
+		return m.reply().Send(m.tr.T("traffichistory.query_failed", html.EscapeString(err.Error())))
+	}
+	if len(snapshots) == 0 {
+		return m.reply().Send(m.tr.T("traffichistory.empty"))
+	}
+
+	totals := make([]float64, len(snapshots))
+	for i, s := range snapshots {
+		totals[i] = float64(s.ChinaBytes + s.NonChinaBytes)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.tr.T("traffichistory.header", days) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(fmt.Sprintf("<code>%s</code>\n", format.FormatSparkline(totals, 0)))
+	sb.WriteString(fmt.Sprintf("%s ~ %s\n\n", snapshots[0].Day[5:], snapshots[len(snapshots)-1].Day[5:]))
+
+	sb.WriteString(m.tr.T("traffichistory.columns") + "\n<pre>")
+	var china, nonChina int64
+	for _, s := range snapshots {
+		sb.WriteString(fmt.Sprintf("%s  %10s  %10s\n", s.Day[5:],
+			aliyun.FormatTrafficSize(s.ChinaBytes), aliyun.FormatTrafficSize(s.NonChinaBytes)))
+		china += s.ChinaBytes
+		nonChina += s.NonChinaBytes
+	}
+	sb.WriteString("</pre>\n")
+	sb.WriteString(m.tr.T("traffichistory.total", aliyun.FormatTrafficSize(china), aliyun.FormatTrafficSize(nonChina)))
+
+	if len(m.aliyunClients) > 1 {
+		sb.WriteString("\n" + m.tr.T("traffichistory.all_accounts"))
+	}
+	if len(snapshots) < days {
+		sb.WriteString("\n" + m.tr.T("traffichistory.partial", len(snapshots), days))
+	}
+
+	return m.reply().Send(sb.String())
+}
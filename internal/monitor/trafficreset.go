@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/state"
+	log "github.com/sirupsen/logrus"
+)
+
+// trafficResetStateKey is the state store key holding the time of the last monthly traffic reset
+const trafficResetStateKey = "traffic-reset"
+
+// trafficResetSchedule returns the cron spec of the monthly traffic reset: midnight on the
+// first of each month in the configured timezone
+func (m *Monitor) trafficResetSchedule() string {
+	return "CRON_TZ=" + m.cfg.Location.String() + " 0 0 1 * *"
+}
+
+// ResetMonthlyTraffic clears the traffic shutdown flags and warning thresholds at the start of
+// a new month. Instances stopped for exceeding the previous month's limit are started by an
+// immediate Check cycle POST_RESET_RESTART_DELAY_SECONDS later, which ctx can abort.
+func (m *Monitor) ResetMonthlyTraffic(ctx context.Context) {
+	delay := time.Duration(m.cfg.PostResetRestartDelay) * time.Second
+	restartAt := time.Now().Add(delay)
+
+	m.mu.RLock()
+	instances := make([]*aliyun.SpotInstance, len(m.instances))
+	copy(instances, m.instances)
+	m.mu.RUnlock()
+	limits := m.trafficLimits()
+
+	m.trafficShutdownMu.Lock()
+	var cleared []string
+	for account, scopes := range m.trafficShutdown {
+		for scope, shutdown := range scopes {
+			if shutdown {
+				cleared = append(cleared, formatShutdownScope(account, scope))
+			}
+		}
+	}
+	restarts := make(map[string]time.Time)
+	for _, inst := range instances {
+		if m.trafficShutdown[inst.AccountLabel][aliyun.TrafficScope(inst.RegionID, limits)] {
+			restarts[inst.InstanceID] = restartAt
+		}
+	}
+	m.trafficShutdown = make(map[string]map[string]bool)
+	m.trafficWarned = make(map[string]int)
+	m.trafficRestarts = restarts
+	m.trafficShutdownMu.Unlock()
+	sort.Strings(cleared)
+
+	now := time.Now()
+	m.updateState(trafficResetStateKey, func(st *state.InstanceState) { st.TrafficResetAt = now })
+	log.Infof("New month started, traffic shutdown flags reset (%d cleared, %d instances to restart)", len(cleared), len(restarts))
+
+	if m.notifier != nil {
+		message := "🔄 <b>新的月份开始</b>\n\n流量计数已重置，之前因流量超额关机的实例已恢复自动启动"
+		if len(cleared) > 0 {
+			message += fmt.Sprintf("\n\n已解除关机:\n%s", strings.Join(cleared, "\n"))
+		}
+		if len(restarts) > 0 {
+			message += fmt.Sprintf("\n\n<i>%d 个实例将在 %d 秒后重新启动</i>", len(restarts), m.cfg.PostResetRestartDelay)
+		}
+		if err := m.notifier.Send(message); err != nil {
+			log.Warnf("Failed to send monthly traffic reset notification: %v", err)
+		}
+	}
+
+	if len(restarts) == 0 {
+		return
+	}
+	// Give the billing system time to reset its counters before starting the instances
+	if err := sleepContext(ctx, delay); err != nil {
+		return
+	}
+
+	// Make the instances due even if they were checked moments ago
+	m.lastCheckedMu.Lock()
+	for instanceID := range restarts {
+		delete(m.lastChecked, instanceID)
+	}
+	m.lastCheckedMu.Unlock()
+
+	log.Infof("Restarting %d instances after monthly traffic reset", len(restarts))
+	if err := m.Check(ctx); err != nil {
+		log.Errorf("Check after monthly traffic reset failed: %v", err)
+	}
+}
+
+// trafficRestartTime returns when an instance shut down for traffic is restarted after the
+// monthly reset, if it still awaits that restart
+func (m *Monitor) trafficRestartTime(instanceID string) (time.Time, bool) {
+	m.trafficShutdownMu.RLock()
+	defer m.trafficShutdownMu.RUnlock()
+	at, ok := m.trafficRestarts[instanceID]
+	return at, ok
+}
+
+// takeTrafficRestart reports whether an instance awaited its restart after the monthly reset,
+// so its started notification says so, and forgets it
+func (m *Monitor) takeTrafficRestart(instanceID string) bool {
+	m.trafficShutdownMu.Lock()
+	defer m.trafficShutdownMu.Unlock()
+	_, ok := m.trafficRestarts[instanceID]
+	delete(m.trafficRestarts, instanceID)
+	return ok
+}
+
+// formatShutdownScope renders a cleared traffic shutdown as "• [account] scope"
+func formatShutdownScope(account, scope string) string {
+	if account == "" {
+		return "• " + scopeDisplayName(scope)
+	}
+	return fmt.Sprintf("• [%s] %s", account, scopeDisplayName(scope))
+}
@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultUptimeDays is the window /uptime reports without an argument
+	defaultUptimeDays = 30
+	// maxUptimeDays caps the window accepted by /uptime
+	maxUptimeDays = 365
+)
+
+// statusTracker remembers the last recorded status of each instance so that only changes are stored
+type statusTracker struct {
+	last map[string]string // instance key -> status
+	mu   sync.Mutex
+}
+
+// instanceUptime is the uptime of one instance over a window
+type instanceUptime struct {
+	monitored   time.Duration // part of the window covered by status records
+	up          time.Duration
+	longestUp   time.Duration
+	longestDown time.Duration
+	partial     bool // monitoring began after the start of the window
+}
+
+// percent returns the uptime percentage over the monitored period
+func (u instanceUptime) percent() float64 {
+	if u.monitored <= 0 {
+		return 0
+	}
+	return float64(u.up) / float64(u.monitored) * 100
+}
+
+// recordStatus stores the status of an instance in the history database when it changed
+// since the last recorded status. key is the instance ID (GCP: "gcp:zone/name").
+func (m *Monitor) recordStatus(key, status string) {
+	if m.db == nil || status == "" {
+		return
+	}
+
+	m.statuses.mu.Lock()
+	defer m.statuses.mu.Unlock()
+	if m.statuses.last[key] == status {
+		return
+	}
+	if err := m.db.RecordStatusTransition(storage.StatusTransition{InstanceKey: key, Status: status}); err != nil {
+		log.Warnf("Failed to record status of %s: %v", key, err)
+		return
+	}
+	m.statuses.last[key] = status
+}
+
+// isRunningStatus reports whether an Aliyun or GCP instance status counts as up
+func isRunningStatus(status string) bool {
+	return status == "Running" || status == "RUNNING"
+}
+
+// computeUptime calculates the uptime of one instance from its transitions, ordered by time,
+// over the window [since, now]. A transition before since gives the status at the start of
+// the window; each status lasts until the next transition.
+func computeUptime(transitions []storage.StatusTransition, since, now time.Time) instanceUptime {
+	var u instanceUptime
+	if len(transitions) == 0 {
+		return u
+	}
+
+	start := transitions[0].Time
+	if start.Before(since) {
+		start = since
+	} else {
+		u.partial = true
+	}
+	u.monitored = now.Sub(start)
+
+	var run time.Duration
+	runUp := isRunningStatus(transitions[0].Status)
+	for i, t := range transitions {
+		segStart := t.Time
+		if segStart.Before(since) {
+			segStart = since
+		}
+		segEnd := now
+		if i+1 < len(transitions) {
+			segEnd = transitions[i+1].Time
+		}
+		seg := segEnd.Sub(segStart)
+		if seg < 0 {
+			seg = 0
+		}
+
+		up := isRunningStatus(t.Status)
+		if up != runUp {
+			u.closeRun(runUp, run)
+			runUp, run = up, 0
+		}
+		run += seg
+		if up {
+			u.up += seg
+		}
+	}
+	u.closeRun(runUp, run)
+
+	return u
+}
+
+// closeRun records a finished continuous up or down period
+func (u *instanceUptime) closeRun(up bool, run time.Duration) {
+	if up {
+		u.longestUp = max(u.longestUp, run)
+	} else {
+		u.longestDown = max(u.longestDown, run)
+	}
+}
+
+// formatShortDuration renders a duration as e.g. "3d4h", "5h12m" or "42m"
+func formatShortDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// sendUptime handles /uptime [days]: sends the uptime percentage, longest up and down periods
+// and reclaim count of each monitored instance
+func (m *Monitor) sendUptime(args []string) error {
+	if m.telegram == nil {
+		return fmt.Errorf("telegram notifier not initialized")
+	}
+	if m.db == nil {
+		return m.reply().Send(m.tr.T("uptime.disabled"))
+	}
+
+	days := defaultUptimeDays
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > maxUptimeDays {
+			return m.reply().Send(m.tr.T("uptime.invalid_days", html.EscapeString(args[0]), maxUptimeDays))
+		}
+		days = n
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+	transitions, err := m.db.StatusTransitions(since)
+	if err != nil {
+		return m.reply().Send(m.tr.T("uptime.query_failed", html.EscapeString(err.Error())))
+	}
+	reclaims, err := m.db.CountIncidentsByInstance(storage.EventReclaimDetected, since)
+	if err != nil {
+		return m.reply().Send(m.tr.T("uptime.query_failed", html.EscapeString(err.Error())))
+	}
+
+	byKey := make(map[string][]storage.StatusTransition)
+	for _, t := range transitions {
+		byKey[t.InstanceKey] = append(byKey[t.InstanceKey], t)
+	}
+
+	// Instance key, display name and incident ID (GCP incidents use the instance name)
+	type uptimeRow struct{ key, name, incidentID string }
+	var rows []uptimeRow
+	m.mu.RLock()
+	for _, inst := range m.instances {
+		name := inst.InstanceName
+		if name == "" {
+			name = inst.InstanceID
+		}
+		rows = append(rows, uptimeRow{inst.InstanceID, name, inst.InstanceID})
+	}
+	for _, inst := range m.gcpInstances {
+		rows = append(rows, uptimeRow{"gcp:" + inst.Zone + "/" + inst.InstanceName, inst.InstanceName, inst.InstanceName})
+	}
+	m.mu.RUnlock()
+
+	if len(rows) == 0 {
+		return m.reply().Send(m.tr.T("uptime.empty"))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.tr.T("uptime.header", days) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n<pre>")
+	sb.WriteString(fmt.Sprintf("%-16s %7s %7s %7s %4s\n", m.tr.T("common.instance"), m.tr.T("uptime.uptime"),
+		m.tr.T("uptime.longest_up"), m.tr.T("uptime.longest_down"), m.tr.T("uptime.reclaims")))
+	for _, row := range rows {
+		name := html.EscapeString(truncateRunes(row.name, 16))
+		u := computeUptime(byKey[row.key], since, now)
+		if u.monitored <= 0 {
+			sb.WriteString(fmt.Sprintf("%-16s %7s\n", name, m.tr.T("uptime.no_records")))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%-16s %6.1f%% %7s %7s %4d\n", name, u.percent(),
+			formatShortDuration(u.longestUp), formatShortDuration(u.longestDown), reclaims[row.incidentID]))
+		if u.partial {
+			sb.WriteString(m.tr.T("uptime.partial", formatShortDuration(u.monitored)) + "\n")
+		}
+	}
+	sb.WriteString("</pre>")
+	sb.WriteString("\n" + m.tr.T("uptime.note"))
+
+	return m.reply().Send(truncateTelegramMessage(sb.String()))
+}
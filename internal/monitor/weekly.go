@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/i18n"
+	log "github.com/sirupsen/logrus"
+)
+
+// weeklyWindowDays is the length of each period compared in the weekly billing report
+const weeklyWindowDays = 7
+
+// SendWeeklyBillingComparison sends, per account, each instance's cost over the last 7 complete
+// days next to the 7 days before. Both periods are whole daily bills ending at today's 00:00,
+// so today's partial bill is in neither. Query failures are reported to the notifier.
+func (m *Monitor) SendWeeklyBillingComparison() error {
+	if m.notifier == nil {
+		return fmt.Errorf("no notifier configured")
+	}
+
+	instancesByAccount := m.billingInstancesByAccount()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	currentStart := today.AddDate(0, 0, -weeklyWindowDays)
+	previousStart := currentStart.AddDate(0, 0, -weeklyWindowDays)
+
+	for _, acc := range m.aliyunClients {
+		instanceInfos := instancesByAccount[acc.Account.Label]
+		if acc.BillingClient == nil || len(instanceInfos) == 0 {
+			continue
+		}
+
+		accountTitle := ""
+		if acc.Account.Label != "" {
+			accountTitle = fmt.Sprintf(" [%s]", acc.Account.Label)
+		}
+
+		current, err := acc.BillingClient.QueryBillingForDays(m.runCtx, instanceInfos, currentStart, weeklyWindowDays)
+		var previous *aliyun.BillingSummary
+		if err == nil {
+			previous, err = acc.BillingClient.QueryBillingForDays(m.runCtx, instanceInfos, previousStart, weeklyWindowDays)
+		}
+		if err != nil {
+			log.Errorf("[%s] Weekly billing comparison failed: %v", acc.Account.Label, err)
+			if sendErr := m.notifier.Send(m.tr.T("weekly.query_failed", accountTitle, html.EscapeString(err.Error()))); sendErr != nil {
+				log.Warnf("[%s] Failed to send weekly billing error notification: %v", acc.Account.Label, sendErr)
+			}
+			continue
+		}
+
+		message := formatWeeklyBillingComparison(m.tr, accountTitle, instanceInfos, current, previous, m.cfg.Location)
+		if err := m.notifier.Send(message); err != nil {
+			log.Errorf("[%s] Failed to send weekly billing comparison: %v", acc.Account.Label, err)
+		}
+	}
+
+	return nil
+}
+
+// formatWeeklyBillingComparison renders the weekly comparison of one account, with dates shown in loc.
+// The summaries' EndTime is exclusive, so the last day shown is the day before it.
+func formatWeeklyBillingComparison(tr i18n.Translator, accountTitle string, instances []aliyun.InstanceInfo, current, previous *aliyun.BillingSummary, loc *time.Location) string {
+	currentCosts := make(map[string]float64, len(current.Instances))
+	for _, inst := range current.Instances {
+		currentCosts[inst.InstanceID] = inst.TotalAmount
+	}
+	previousCosts := make(map[string]float64, len(previous.Instances))
+	for _, inst := range previous.Instances {
+		previousCosts[inst.InstanceID] = inst.TotalAmount
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("weekly.header", accountTitle) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(tr.T("weekly.current_range", current.StartTime.In(loc).Format("01-02"), current.EndTime.AddDate(0, 0, -1).In(loc).Format("01-02")) + "\n")
+	sb.WriteString(tr.T("weekly.previous_range", previous.StartTime.In(loc).Format("01-02"), previous.EndTime.AddDate(0, 0, -1).In(loc).Format("01-02")) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	var totalCurrent, totalPrevious float64
+	listed := 0
+	for _, inst := range instances {
+		cur := currentCosts[inst.InstanceID]
+		prev := previousCosts[inst.InstanceID]
+		if cur == 0 && prev == 0 {
+			continue
+		}
+		totalCurrent += cur
+		totalPrevious += prev
+		listed++
+
+		name := inst.InstanceName
+		if name == "" {
+			name = inst.InstanceID
+		}
+		sb.WriteString(fmt.Sprintf("🖥 <b>%s</b> (<code>%s</code>)\n", html.EscapeString(name), inst.InstanceID))
+		switch {
+		case prev == 0:
+			sb.WriteString(tr.T("weekly.costs_no_previous", cur) + "\n\n")
+		case cur == 0:
+			sb.WriteString(tr.T("weekly.costs_no_current", prev) + "\n\n")
+		default:
+			sb.WriteString(tr.T("weekly.costs", cur, prev, formatCostChange(cur, prev)) + "\n\n")
+		}
+	}
+	if listed == 0 {
+		sb.WriteString(tr.T("weekly.empty") + "\n\n")
+	}
+
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(tr.T("weekly.current_total", totalCurrent) + "\n")
+	sb.WriteString(tr.T("weekly.previous_total", totalPrevious) + "\n")
+
+	diff := totalCurrent - totalPrevious
+	switch {
+	case diff >= 0.005:
+		sb.WriteString(tr.T("weekly.more", diff))
+	case diff <= -0.005:
+		sb.WriteString(tr.T("weekly.less", -diff))
+	default:
+		sb.WriteString(tr.T("weekly.same"))
+	}
+
+	return sb.String()
+}
+
+// formatCostChange renders the change from prev to cur as "▲ ¥1.20 (+15.0%)" or "▼ ..."
+func formatCostChange(cur, prev float64) string {
+	diff := cur - prev
+	percent := diff / prev * 100
+	switch {
+	case diff >= 0.005:
+		return fmt.Sprintf("▲ ¥%.2f (+%.1f%%)", diff, percent)
+	case diff <= -0.005:
+		return fmt.Sprintf("▼ ¥%.2f (%.1f%%)", -diff, percent)
+	default:
+		return "➖"
+	}
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxBatchedInstances is the number of instances listed by name in a merged reclaim message
+const maxBatchedInstances = 20
+
+// reclaimNotice is a reclaim notification waiting in the batcher
+type reclaimNotice struct {
+	accountLabel string
+	instanceID   string
+	instanceName string
+	region       string
+	message      string // the individual notification, sent if the batch stays small
+}
+
+// notificationBatcher holds reclaim notifications for a window after the first one arrives.
+// If more than threshold arrive within the window they are merged into a single message,
+// otherwise they are sent individually. It is safe for concurrent use.
+type notificationBatcher struct {
+	window    time.Duration
+	threshold int
+	send      func(message string, events ...string) error
+	merge     func(notices []reclaimNotice) string // renders the merged message
+
+	pending []reclaimNotice
+	timer   *time.Timer
+	mu      sync.Mutex
+}
+
+func newNotificationBatcher(window time.Duration, threshold int, send func(message string, events ...string) error,
+	merge func(notices []reclaimNotice) string) *notificationBatcher {
+	return &notificationBatcher{
+		window:    window,
+		threshold: threshold,
+		send:      send,
+		merge:     merge,
+	}
+}
+
+// add queues a notice, starting the window if it is the first one
+func (b *notificationBatcher) add(n reclaimNotice) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, n)
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flush)
+	}
+}
+
+// flush sends the queued notices, merged if there are more than threshold
+func (b *notificationBatcher) flush() {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	if len(pending) <= b.threshold {
+		for _, n := range pending {
+			if err := b.send(n.message, instanceEvents(EventReclaim, n.region)...); err != nil {
+				log.Warnf("Failed to send reclaimed notification for %s: %v", n.instanceID, err)
+			}
+		}
+		return
+	}
+
+	log.Warnf("Mass reclaim: %d instances reclaimed within %s, sending a single notification", len(pending), b.window)
+	if err := b.send(b.merge(pending), EventReclaim); err != nil {
+		log.Warnf("Failed to send mass reclaim notification: %v", err)
+	}
+}
+
+// formatMassReclaim merges reclaim notices into one message
+func (t *TelegramNotifier) formatMassReclaim(notices []reclaimNotice) string {
+	var sb strings.Builder
+	sb.WriteString(t.tr.T("notify.mass_reclaim.header", len(notices)) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━\n")
+	for i, n := range notices {
+		if i == maxBatchedInstances {
+			sb.WriteString(t.tr.T("common.more", len(notices)-maxBatchedInstances) + "\n")
+			break
+		}
+		sb.WriteString(fmt.Sprintf("• %s%s (<code>%s</code>) - %s\n",
+			accountPrefix(n.accountLabel), html.EscapeString(n.instanceName), n.instanceID, n.region))
+	}
+	sb.WriteString(t.tr.T("common.time", formatTime(time.Now(), "2006-01-02 15:04:05")) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━\n")
+	sb.WriteString(t.tr.T("notify.mass_reclaim.footer"))
+	return sb.String()
+}
+
+// accountPrefix returns the "[label] " prefix of list entries, empty for a single account
+func accountPrefix(accountLabel string) string {
+	if accountLabel == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", html.EscapeString(accountLabel))
+}
@@ -2,45 +2,84 @@ package notify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/iliyian/aliyun-spot-manager/internal/format"
 	log "github.com/sirupsen/logrus"
 )
 
 // BotHandler handles Telegram bot commands
 type BotHandler struct {
 	botToken        string
-	chatID          string
+	chatIDs         []string       // authorized chats, the first one is the default reply chat
+	authorized      map[int64]bool // parsed chatIDs
 	client          *http.Client
-	commandHandler  func(command string) error
-	callbackHandler func(callbackID, data string, messageID int64) error
+	commandHandler  func(ctx CommandContext, command string, args []string) error
+	callbackHandler func(ctx CommandContext, callbackID, data string, msg MessageRef) error
 	lastUpdateID    int64
+	updateMu        sync.Mutex // serializes update handling
+	replyChatID     string     // chat of the update being handled
+	replyMu         sync.Mutex
+	mode            format.ParseMode // messages are written in HTML and converted to mode on send
 }
 
-// NewBotHandler creates a new bot handler
-func NewBotHandler(botToken, chatID string) *BotHandler {
+// CommandContext identifies who sent a command or callback query, and from which chat
+type CommandContext struct {
+	ChatID int64
+	User   *TelegramUser // nil when Telegram omits the sender, e.g. for channel posts
+}
+
+// MessageRef identifies a message in one of the authorized chats
+type MessageRef struct {
+	ChatID    int64
+	MessageID int64
+}
+
+// NewBotHandler creates a new bot handler accepting commands from any of chatIDs. client may be
+// shared with the Telegram notifier; nil creates one with NewHTTPClient.
+func NewBotHandler(botToken string, chatIDs []string, client *http.Client) *BotHandler {
+	if client == nil {
+		client = NewHTTPClient()
+	}
+	authorized := make(map[int64]bool, len(chatIDs))
+	for _, id := range chatIDs {
+		if v, err := strconv.ParseInt(id, 10, 64); err == nil {
+			authorized[v] = true
+		} else {
+			log.Warnf("Ignoring invalid Telegram chat ID %q for bot commands", id)
+		}
+	}
+
 	return &BotHandler{
-		botToken: botToken,
-		chatID:   chatID,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		botToken:     botToken,
+		chatIDs:      chatIDs,
+		authorized:   authorized,
+		client:       client,
 		lastUpdateID: 0,
+		mode:         format.ParseModeHTML,
 	}
 }
 
+// SetParseMode sets the formatting messages are converted to before sending (TELEGRAM_PARSE_MODE)
+func (b *BotHandler) SetParseMode(mode format.ParseMode) {
+	b.mode = mode
+}
+
 // SetCommandHandler sets the command handler function
-func (b *BotHandler) SetCommandHandler(handler func(command string) error) {
+func (b *BotHandler) SetCommandHandler(handler func(ctx CommandContext, command string, args []string) error) {
 	b.commandHandler = handler
 }
 
 // SetCallbackHandler sets the callback query handler function
-func (b *BotHandler) SetCallbackHandler(handler func(callbackID, data string, messageID int64) error) {
+func (b *BotHandler) SetCallbackHandler(handler func(ctx CommandContext, callbackID, data string, msg MessageRef) error) {
 	b.callbackHandler = handler
 }
 
@@ -67,6 +106,15 @@ type TelegramUser struct {
 	Username  string `json:"username"`
 }
 
+// String renders the user as "@username (ID)", falling back to the first name
+func (u *TelegramUser) String() string {
+	name := u.FirstName
+	if u.Username != "" {
+		name = "@" + u.Username
+	}
+	return fmt.Sprintf("%s (%d)", name, u.ID)
+}
+
 // TelegramChat represents a Telegram chat
 type TelegramChat struct {
 	ID   int64  `json:"id"`
@@ -102,7 +150,7 @@ type InlineKeyboardMarkup struct {
 type telegramMessageWithKeyboard struct {
 	ChatID      string                `json:"chat_id"`
 	Text        string                `json:"text"`
-	ParseMode   string                `json:"parse_mode"`
+	ParseMode   string                `json:"parse_mode,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
@@ -111,7 +159,7 @@ type telegramEditMessage struct {
 	ChatID      string                `json:"chat_id"`
 	MessageID   int64                 `json:"message_id"`
 	Text        string                `json:"text"`
-	ParseMode   string                `json:"parse_mode"`
+	ParseMode   string                `json:"parse_mode,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
@@ -128,14 +176,42 @@ type TelegramUpdatesResponse struct {
 	Result []TelegramUpdate `json:"result"`
 }
 
-// SendMessageWithKeyboard sends a message with inline keyboard
+// setReplyChat records the chat of the update being handled, 0 to clear it
+func (b *BotHandler) setReplyChat(chatID int64) {
+	b.replyMu.Lock()
+	defer b.replyMu.Unlock()
+	if chatID == 0 {
+		b.replyChatID = ""
+		return
+	}
+	b.replyChatID = strconv.FormatInt(chatID, 10)
+}
+
+// ReplyChat returns the chat replies go to: the chat of the update being handled,
+// otherwise the first authorized chat
+func (b *BotHandler) ReplyChat() string {
+	b.replyMu.Lock()
+	defer b.replyMu.Unlock()
+	if b.replyChatID != "" {
+		return b.replyChatID
+	}
+	if len(b.chatIDs) > 0 {
+		return b.chatIDs[0]
+	}
+	return ""
+}
+
+// SendMessageWithKeyboard sends a message with inline keyboard to the chat of the command being
+// handled (the first authorized chat outside of command handling)
 func (b *BotHandler) SendMessageWithKeyboard(text string, keyboard [][]InlineKeyboardButton) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", b.botToken)
 
+	chatID := b.ReplyChat()
+
 	msg := telegramMessageWithKeyboard{
-		ChatID:    b.chatID,
-		Text:      text,
-		ParseMode: "HTML",
+		ChatID:    chatID,
+		Text:      format.ConvertHTML(text, b.mode),
+		ParseMode: b.mode.APIValue(),
 		ReplyMarkup: &InlineKeyboardMarkup{
 			InlineKeyboard: keyboard,
 		},
@@ -159,15 +235,58 @@ func (b *BotHandler) SendMessageWithKeyboard(text string, keyboard [][]InlineKey
 	return nil
 }
 
+// SendDocument uploads content as a file named filename to the chat of the command being
+// handled (the first authorized chat outside of command handling). caption is HTML.
+func (b *BotHandler) SendDocument(filename string, content []byte, caption string) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendDocument", b.botToken)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"chat_id": b.ReplyChat(),
+		"caption": format.ConvertHTML(caption, b.mode),
+	}
+	if parseMode := b.mode.APIValue(); parseMode != "" {
+		fields["parse_mode"] = parseMode
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return fmt.Errorf("failed to write form field %s: %w", k, err)
+		}
+	}
+	part, err := w.CreateFormFile("document", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to write document: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finish form: %w", err)
+	}
+
+	resp, err := b.client.Post(url, w.FormDataContentType(), &body)
+	if err != nil {
+		return fmt.Errorf("failed to send document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // EditMessageText edits an existing message text and keyboard
-func (b *BotHandler) EditMessageText(messageID int64, text string, keyboard [][]InlineKeyboardButton) error {
+func (b *BotHandler) EditMessageText(ref MessageRef, text string, keyboard [][]InlineKeyboardButton) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/editMessageText", b.botToken)
 
 	msg := telegramEditMessage{
-		ChatID:    b.chatID,
-		MessageID: messageID,
-		Text:      text,
-		ParseMode: "HTML",
+		ChatID:    strconv.FormatInt(ref.ChatID, 10),
+		MessageID: ref.MessageID,
+		Text:      format.ConvertHTML(text, b.mode),
+		ParseMode: b.mode.APIValue(),
 	}
 
 	if keyboard != nil {
@@ -219,12 +338,21 @@ func (b *BotHandler) AnswerCallbackQuery(callbackID, text string, showAlert bool
 }
 
 // PollUpdates polls for new updates from Telegram
-func (b *BotHandler) PollUpdates() error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?offset=%d&timeout=30", b.botToken, b.lastUpdateID+1)
+func (b *BotHandler) PollUpdates(ctx context.Context) error {
+	b.updateMu.Lock()
+	offset := b.lastUpdateID + 1
+	b.updateMu.Unlock()
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?offset=%d&timeout=30", b.botToken, offset)
 
-	log.Debugf("Polling updates with offset=%d", b.lastUpdateID+1)
+	log.Debugf("Polling updates with offset=%d", offset)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
 
-	resp, err := b.client.Get(url)
+	resp, err := b.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to get updates: %w", err)
 	}
@@ -242,52 +370,77 @@ func (b *BotHandler) PollUpdates() error {
 	log.Debugf("Got %d updates from Telegram", len(updatesResp.Result))
 
 	for _, update := range updatesResp.Result {
-		log.Debugf("Processing update_id=%d, lastUpdateID was %d", update.UpdateID, b.lastUpdateID)
-		b.lastUpdateID = update.UpdateID
-
-		// Handle callback query
-		if update.CallbackQuery != nil {
-			chatIDInt, _ := strconv.ParseInt(b.chatID, 10, 64)
-			if update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat.ID == chatIDInt {
-				log.Infof("Received callback query: %s (update_id=%d)", update.CallbackQuery.Data, update.UpdateID)
-				if b.callbackHandler != nil {
-					if err := b.callbackHandler(update.CallbackQuery.ID, update.CallbackQuery.Data, update.CallbackQuery.Message.MessageID); err != nil {
-						log.Errorf("Failed to handle callback query: %v", err)
-					}
+		b.handleUpdate(update)
+	}
+
+	return nil
+}
+
+// handleUpdate dispatches a single update to the command or callback handler.
+// Updates are processed one at a time, whether they come from polling or the webhook.
+func (b *BotHandler) handleUpdate(update TelegramUpdate) {
+	b.updateMu.Lock()
+	defer b.updateMu.Unlock()
+
+	// Telegram redelivers webhook updates that were not acknowledged in time
+	if update.UpdateID <= b.lastUpdateID {
+		log.Debugf("Skipping already processed update_id=%d", update.UpdateID)
+		return
+	}
+
+	log.Debugf("Processing update_id=%d, lastUpdateID was %d", update.UpdateID, b.lastUpdateID)
+	b.lastUpdateID = update.UpdateID
+
+	// Handle callback query
+	if update.CallbackQuery != nil {
+		if cbMsg := update.CallbackQuery.Message; cbMsg != nil && cbMsg.Chat != nil && b.authorized[cbMsg.Chat.ID] {
+			log.Infof("Received callback query: %s (update_id=%d)", update.CallbackQuery.Data, update.UpdateID)
+			b.setReplyChat(cbMsg.Chat.ID)
+			defer b.setReplyChat(0)
+			if b.callbackHandler != nil {
+				ctx := CommandContext{ChatID: cbMsg.Chat.ID, User: update.CallbackQuery.From}
+				ref := MessageRef{ChatID: cbMsg.Chat.ID, MessageID: cbMsg.MessageID}
+				if err := b.callbackHandler(ctx, update.CallbackQuery.ID, update.CallbackQuery.Data, ref); err != nil {
+					log.Errorf("Failed to handle callback query: %v", err)
 				}
 			}
-			continue
 		}
+		return
+	}
 
-		if update.Message == nil {
-			continue
-		}
+	if update.Message == nil {
+		return
+	}
 
-		// Check if message is from authorized chat
-		chatIDInt, _ := strconv.ParseInt(b.chatID, 10, 64)
-		if update.Message.Chat.ID != chatIDInt {
+	// Check if message is from an authorized chat
+	if update.Message.Chat == nil || !b.authorized[update.Message.Chat.ID] {
+		if update.Message.Chat != nil {
 			log.Debugf("Ignoring message from unauthorized chat: %d", update.Message.Chat.ID)
-			continue
 		}
+		return
+	}
+	b.setReplyChat(update.Message.Chat.ID)
+	defer b.setReplyChat(0)
+
+	// Process command
+	if strings.HasPrefix(update.Message.Text, "/") {
+		fields := strings.Fields(strings.TrimPrefix(update.Message.Text, "/"))
+		if len(fields) == 0 {
+			return
+		}
+		command := strings.Split(fields[0], "@")[0] // Remove bot username if present
+		args := fields[1:]
 
-		// Process command
-		if strings.HasPrefix(update.Message.Text, "/") {
-			command := strings.TrimPrefix(update.Message.Text, "/")
-			command = strings.Split(command, " ")[0] // Get first word
-			command = strings.Split(command, "@")[0] // Remove bot username if present
-
-			log.Infof("Received command: /%s from chat %d (update_id=%d, msg_id=%d)",
-				command, update.Message.Chat.ID, update.UpdateID, update.Message.MessageID)
+		log.Infof("Received command: /%s from chat %d (update_id=%d, msg_id=%d)",
+			command, update.Message.Chat.ID, update.UpdateID, update.Message.MessageID)
 
-			if b.commandHandler != nil {
-				if err := b.commandHandler(command); err != nil {
-					log.Errorf("Failed to handle command /%s: %v", command, err)
-				}
+		if b.commandHandler != nil {
+			ctx := CommandContext{ChatID: update.Message.Chat.ID, User: update.Message.From}
+			if err := b.commandHandler(ctx, command, args); err != nil {
+				log.Errorf("Failed to handle command /%s: %v", command, err)
 			}
 		}
 	}
-
-	return nil
 }
 
 // SetMyCommands registers bot commands with Telegram so they appear in the command menu
@@ -319,16 +472,20 @@ func (b *BotHandler) SetMyCommands(commands []BotCommand) error {
 	return nil
 }
 
-// StartPolling starts polling for updates in a goroutine
-func (b *BotHandler) StartPolling() {
-	go func() {
-		log.Info("Starting Telegram bot polling...")
-		for {
-			if err := b.PollUpdates(); err != nil {
-				log.Warnf("Failed to poll updates: %v", err)
-				time.Sleep(5 * time.Second)
-				continue
+// Poll polls for updates until ctx is cancelled
+func (b *BotHandler) Poll(ctx context.Context) {
+	log.Info("Starting Telegram bot polling...")
+	for ctx.Err() == nil {
+		if err := b.PollUpdates(ctx); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Warnf("Failed to poll updates: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
 			}
 		}
-	}()
+	}
+	log.Info("Telegram bot polling stopped")
 }
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// dingTalkRateLimit is the number of messages a DingTalk robot accepts per minute
+	dingTalkRateLimit = 20
+	// maxDingTalkText is the longest markdown text sent in a single DingTalk message
+	maxDingTalkText = 5000
+)
+
+// DingTalkNotifier sends notifications as markdown messages to a DingTalk custom robot
+type DingTalkNotifier struct {
+	richNotifier
+	webhookURL string
+	secret     string // signs requests when the robot uses the "加签" security setting
+	client     *http.Client
+	limiter    *tokenBucket
+}
+
+// NewDingTalkNotifier creates a new DingTalk notifier. secret may be empty when the robot
+// is secured by keywords or IP allowlist instead of signing.
+func NewDingTalkNotifier(webhookURL, secret string) *DingTalkNotifier {
+	d := &DingTalkNotifier{
+		webhookURL: webhookURL,
+		secret:     secret,
+		client:     NewHTTPClient(),
+		limiter:    newTokenBucket(dingTalkRateLimit, time.Minute),
+	}
+	d.richNotifier = richNotifier{post: d.sendMarkdown, fromHTML: htmlToMarkdown}
+	return d
+}
+
+// Flush is a no-op, DingTalk notifications are never held back
+func (d *DingTalkNotifier) Flush() {}
+
+// dingTalkMessage is the body of a custom robot call
+type dingTalkMessage struct {
+	MsgType  string           `json:"msgtype"`
+	Markdown dingTalkMarkdown `json:"markdown"`
+}
+
+type dingTalkMarkdown struct {
+	Title string `json:"title"` // shown in the conversation list and push notifications
+	Text  string `json:"text"`
+}
+
+// dingTalkResponse is the reply of a custom robot call; errcode 0 means success
+type dingTalkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// sendMarkdown posts a message as markdown: the title as a heading, the description,
+// one line per field, the footer as a quote and a timestamp
+func (d *DingTalkNotifier) sendMarkdown(msg richMessage) error {
+	var sb strings.Builder
+	if msg.Title != "" {
+		sb.WriteString("### " + msg.Title + "\n\n")
+	}
+	if msg.Description != "" {
+		sb.WriteString(msg.Description + "\n\n")
+	}
+	for _, f := range msg.Fields {
+		if f.Inline {
+			sb.WriteString(fmt.Sprintf("**%s**: %s\n", f.Name, f.Value))
+		} else {
+			sb.WriteString(fmt.Sprintf("**%s**\n%s\n\n", f.Name, f.Value))
+		}
+	}
+	if msg.Footer != "" {
+		sb.WriteString("\n> " + msg.Footer + "\n")
+	}
+	sb.WriteString("\n🕐 " + formatTime(time.Now(), "2006-01-02 15:04:05"))
+
+	title := msg.Title
+	if title == "" {
+		title = truncateText(firstLine(msg.Description), 64)
+	}
+
+	body, err := json.Marshal(dingTalkMessage{
+		MsgType: "markdown",
+		Markdown: dingTalkMarkdown{
+			Title: title,
+			Text:  truncateText(dingTalkLineBreaks(sb.String()), maxDingTalkText),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	d.limiter.wait()
+
+	resp, err := d.client.Post(d.signedURL(time.Now()), "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("dingtalk webhook returned status %d", resp.StatusCode)
+	}
+	// Errors such as a bad signature or rate limiting come back as HTTP 200 with an errcode
+	var result dingTalkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode dingtalk response: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("dingtalk webhook returned error %d: %s", result.ErrCode, result.ErrMsg)
+	}
+
+	return nil
+}
+
+// signedURL returns the webhook URL with the timestamp and HMAC-SHA256 signature DingTalk
+// requires when a secret is set
+func (d *DingTalkNotifier) signedURL(now time.Time) string {
+	if d.secret == "" {
+		return d.webhookURL
+	}
+
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(d.secret))
+	mac.Write([]byte(timestamp + "\n" + d.secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	sep := "?"
+	if strings.Contains(d.webhookURL, "?") {
+		sep = "&"
+	}
+	return d.webhookURL + sep + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+}
+
+// dingTalkLineBreaks turns single newlines into markdown hard breaks, which DingTalk
+// otherwise joins into one line
+func dingTalkLineBreaks(text string) string {
+	return strings.ReplaceAll(text, "\n", "  \n")
+}
+
+// firstLine returns text up to its first newline
+func firstLine(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
+
+// tokenBucket limits the rate of requests: up to capacity requests at once, refilled
+// evenly over period
+type tokenBucket struct {
+	capacity float64
+	interval time.Duration // time to refill one token
+	tokens   float64
+	last     time.Time
+	mu       sync.Mutex
+}
+
+// newTokenBucket creates a full bucket allowing capacity requests per period
+func newTokenBucket(capacity int, period time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(capacity),
+		interval: period / time.Duration(capacity),
+		tokens:   float64(capacity),
+		last:     time.Now(),
+	}
+}
+
+// wait blocks until a token is available and takes it
+func (b *tokenBucket) wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		now := time.Now()
+		b.tokens = min(b.capacity, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			return
+		}
+		// Holding the lock keeps waiting senders in line
+		time.Sleep(time.Duration((1 - b.tokens) * float64(b.interval)))
+	}
+}
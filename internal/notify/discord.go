@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Embed colors by message level
+var discordColors = map[messageLevel]int{
+	levelInfo:    0x3498DB, // blue
+	levelSuccess: 0x2ECC71, // green
+	levelWarning: 0xF1C40F, // yellow
+	levelAlert:   0xE74C3C, // red
+}
+
+// maxDiscordDescription is the longest embed description Discord accepts
+const maxDiscordDescription = 4096
+
+// DiscordNotifier sends notifications as embeds to a Discord channel webhook
+type DiscordNotifier struct {
+	richNotifier
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscordNotifier creates a new Discord notifier
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	d := &DiscordNotifier{
+		webhookURL: webhookURL,
+		client:     NewHTTPClient(),
+	}
+	d.richNotifier = richNotifier{post: d.sendEmbed, fromHTML: htmlToMarkdown}
+	return d
+}
+
+// Flush is a no-op, Discord notifications are never held back
+func (d *DiscordNotifier) Flush() {}
+
+// discordMessage is the body of a webhook execution
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// sendEmbed posts a message as a single embed, stamped with the current time
+func (d *DiscordNotifier) sendEmbed(msg richMessage) error {
+	embed := discordEmbed{
+		Title:       msg.Title,
+		Description: truncateText(msg.Description, maxDiscordDescription),
+		Color:       discordColors[msg.Level],
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	for _, f := range msg.Fields {
+		embed.Fields = append(embed.Fields, discordField{Name: f.Name, Value: f.Value, Inline: f.Inline})
+	}
+	if msg.Footer != "" {
+		embed.Footer = &discordFooter{Text: msg.Footer}
+	}
+
+	body, err := json.Marshal(discordMessage{Embeds: []discordEmbed{embed}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	resp, err := d.client.Post(d.webhookURL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Discord answers 204 No Content, or 200 when ?wait=true is set on the URL
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+var (
+	htmlToMarkdownReplacer = strings.NewReplacer(
+		"<b>", "**", "</b>", "**",
+		"<i>", "*", "</i>", "*",
+		"<code>", "`", "</code>", "`",
+		"<pre>", "```\n", "</pre>", "\n```",
+	)
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+)
+
+// htmlToMarkdown converts a Telegram HTML message to Discord markdown
+func htmlToMarkdown(message string) string {
+	message = htmlToMarkdownReplacer.Replace(message)
+	message = htmlTagPattern.ReplaceAllString(message, "")
+	return html.UnescapeString(message)
+}
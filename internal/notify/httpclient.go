@@ -0,0 +1,20 @@
+package notify
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns a client for the notification APIs that keeps idle connections open,
+// so frequent notifications reuse connections instead of opening one per message
+func NewHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 10
+	transport.IdleConnTimeout = 90 * time.Second
+	transport.DisableKeepAlives = false
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}
+}
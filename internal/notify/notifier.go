@@ -0,0 +1,270 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	log "github.com/sirupsen/logrus"
+)
+
+// location is the time zone timestamps in notifications are shown in
+var location = time.Local
+
+// SetLocation sets the time zone timestamps in notifications are shown in (TIMEZONE)
+func SetLocation(loc *time.Location) {
+	if loc != nil {
+		location = loc
+	}
+}
+
+// formatTime formats t in the notification time zone
+func formatTime(t time.Time, layout string) string {
+	return t.In(location).Format(layout)
+}
+
+// Notifier delivers monitor notifications to a chat service
+type Notifier interface {
+	// Send delivers a free-form message formatted with Telegram HTML tags
+	Send(message string) error
+	// Flush delivers any notifications still held back
+	Flush()
+
+	NotifyInstanceReclaimed(accountLabel, instanceID, instanceName, region string) error
+	NotifyReclaimWarning(accountLabel, instanceID, instanceName, region string, stopAt time.Time, hookResult string) error
+	NotifyInstanceStarting(instanceID, instanceName, region string) error
+	NotifyInstanceStarted(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error
+	NotifySSHUnreachable(accountLabel, instanceID, instanceName, region, publicIP string, port int, duration time.Duration, extraInfo string) error
+	NotifyTrafficResetRestart(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error
+	NotifyWebhookFailed(accountLabel, instanceID, instanceName, webhookURL string, attempts int, err error) error
+	NotifyInstanceStartFailed(accountLabel, instanceID, instanceName, region string, retryCount int, err error) error
+	NotifyInstanceNoStock(accountLabel, instanceID, instanceName, region string, attempts int) error
+	NotifyRapidReclaim(accountLabel, instanceID, instanceName, region string, count int, window time.Duration, pausedUntil time.Time) error
+	NotifyMaintenanceStarted(instances, regions string, end time.Time) error
+	NotifyMaintenanceEnded(instances, regions string) error
+	NotifyHealthCheckTimeout(instanceID, instanceName, region, publicIP string, timeout int) error
+	NotifyDiskIOAnomaly(accountLabel, instanceID, instanceName, region string, minutes int, readIOPS, writeIOPS float64) error
+	NotifyMonitorStarted(instanceCount int, instances []string, dryRun bool) error
+	NotifyMonitorStopping(abandonedStarts []string) error
+	NotifyPanic(component, summary string) error
+	NotifySpotTermination(instanceID string, terminationTime time.Time, scriptResult string) error
+	NotifyCircuitOpened(accountLabel, region string, failures int, timeout time.Duration) error
+	NotifyCircuitClosed(accountLabel, region string, downtime time.Duration) error
+	NotifyBillingSummary(summary *aliyun.BillingSummary) error
+	NotifyCostAnomaly(accountLabel, instanceID, instanceName, region, day string, cost, baseline float64, baselineDays int) error
+	NotifyTrafficSummary(summary *aliyun.TrafficSummary) error
+	NotifyNetworkStats(instanceName string, stats *aliyun.NetworkStats) error
+	NotifyTrafficWarning(accountLabel, region string, trafficGB, limitGB, percent, daysLeft float64) error
+	NotifyTrafficShutdown(accountLabel, region string, trafficGB, limitGB float64, stoppedInstances []string) error
+	NotifyTrafficSummaryWithLimits(summary *aliyun.TrafficSummary, limits map[string]float64, shutdown map[string]bool) error
+}
+
+var (
+	_ Notifier = (*TelegramNotifier)(nil)
+	_ Notifier = (*DiscordNotifier)(nil)
+	_ Notifier = (*SlackNotifier)(nil)
+	_ Notifier = multiNotifier(nil)
+)
+
+// NewMultiNotifier returns a Notifier that delivers every notification to all given notifiers.
+// It returns nil when none is given and the notifier itself when there is only one.
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	switch len(notifiers) {
+	case 0:
+		return nil
+	case 1:
+		return notifiers[0]
+	}
+	return multiNotifier(notifiers)
+}
+
+// multiNotifier fans notifications out to several notifiers. A failed notifier does not
+// prevent delivery to the others; an error is returned only if none delivered.
+type multiNotifier []Notifier
+
+func (m multiNotifier) each(fn func(n Notifier) error) error {
+	var failed []error
+	for _, n := range m {
+		if err := fn(n); err != nil {
+			log.Warnf("Failed to deliver notification via %T: %v", n, err)
+			failed = append(failed, fmt.Errorf("%T: %w", n, err))
+		}
+	}
+	if len(failed) == len(m) {
+		return errors.Join(failed...)
+	}
+	return nil
+}
+
+func (m multiNotifier) Send(message string) error {
+	return m.each(func(n Notifier) error { return n.Send(message) })
+}
+
+func (m multiNotifier) Flush() {
+	for _, n := range m {
+		n.Flush()
+	}
+}
+
+func (m multiNotifier) NotifyInstanceReclaimed(accountLabel, instanceID, instanceName, region string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyInstanceReclaimed(accountLabel, instanceID, instanceName, region)
+	})
+}
+
+func (m multiNotifier) NotifyReclaimWarning(accountLabel, instanceID, instanceName, region string, stopAt time.Time, hookResult string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyReclaimWarning(accountLabel, instanceID, instanceName, region, stopAt, hookResult)
+	})
+}
+
+func (m multiNotifier) NotifyInstanceStarting(instanceID, instanceName, region string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyInstanceStarting(instanceID, instanceName, region)
+	})
+}
+
+func (m multiNotifier) NotifyInstanceStarted(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyInstanceStarted(accountLabel, instanceID, instanceName, region, publicIP, duration, extraInfo)
+	})
+}
+
+func (m multiNotifier) NotifySSHUnreachable(accountLabel, instanceID, instanceName, region, publicIP string, port int, duration time.Duration, extraInfo string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifySSHUnreachable(accountLabel, instanceID, instanceName, region, publicIP, port, duration, extraInfo)
+	})
+}
+
+func (m multiNotifier) NotifyTrafficResetRestart(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyTrafficResetRestart(accountLabel, instanceID, instanceName, region, publicIP, duration, extraInfo)
+	})
+}
+
+func (m multiNotifier) NotifyWebhookFailed(accountLabel, instanceID, instanceName, webhookURL string, attempts int, err error) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyWebhookFailed(accountLabel, instanceID, instanceName, webhookURL, attempts, err)
+	})
+}
+
+func (m multiNotifier) NotifyInstanceStartFailed(accountLabel, instanceID, instanceName, region string, retryCount int, err error) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyInstanceStartFailed(accountLabel, instanceID, instanceName, region, retryCount, err)
+	})
+}
+
+func (m multiNotifier) NotifyInstanceNoStock(accountLabel, instanceID, instanceName, region string, attempts int) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyInstanceNoStock(accountLabel, instanceID, instanceName, region, attempts)
+	})
+}
+
+func (m multiNotifier) NotifyRapidReclaim(accountLabel, instanceID, instanceName, region string, count int, window time.Duration, pausedUntil time.Time) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyRapidReclaim(accountLabel, instanceID, instanceName, region, count, window, pausedUntil)
+	})
+}
+
+func (m multiNotifier) NotifyMaintenanceStarted(instances, regions string, end time.Time) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyMaintenanceStarted(instances, regions, end)
+	})
+}
+
+func (m multiNotifier) NotifyMaintenanceEnded(instances, regions string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyMaintenanceEnded(instances, regions)
+	})
+}
+
+func (m multiNotifier) NotifyHealthCheckTimeout(instanceID, instanceName, region, publicIP string, timeout int) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyHealthCheckTimeout(instanceID, instanceName, region, publicIP, timeout)
+	})
+}
+
+func (m multiNotifier) NotifyDiskIOAnomaly(accountLabel, instanceID, instanceName, region string, minutes int, readIOPS, writeIOPS float64) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyDiskIOAnomaly(accountLabel, instanceID, instanceName, region, minutes, readIOPS, writeIOPS)
+	})
+}
+
+func (m multiNotifier) NotifyMonitorStarted(instanceCount int, instances []string, dryRun bool) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyMonitorStarted(instanceCount, instances, dryRun)
+	})
+}
+
+func (m multiNotifier) NotifyMonitorStopping(abandonedStarts []string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyMonitorStopping(abandonedStarts)
+	})
+}
+
+func (m multiNotifier) NotifyPanic(component, summary string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyPanic(component, summary)
+	})
+}
+
+func (m multiNotifier) NotifySpotTermination(instanceID string, terminationTime time.Time, scriptResult string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifySpotTermination(instanceID, terminationTime, scriptResult)
+	})
+}
+
+func (m multiNotifier) NotifyCircuitOpened(accountLabel, region string, failures int, timeout time.Duration) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyCircuitOpened(accountLabel, region, failures, timeout)
+	})
+}
+
+func (m multiNotifier) NotifyCircuitClosed(accountLabel, region string, downtime time.Duration) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyCircuitClosed(accountLabel, region, downtime)
+	})
+}
+
+func (m multiNotifier) NotifyBillingSummary(summary *aliyun.BillingSummary) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyBillingSummary(summary)
+	})
+}
+
+func (m multiNotifier) NotifyCostAnomaly(accountLabel, instanceID, instanceName, region, day string, cost, baseline float64, baselineDays int) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyCostAnomaly(accountLabel, instanceID, instanceName, region, day, cost, baseline, baselineDays)
+	})
+}
+
+func (m multiNotifier) NotifyTrafficSummary(summary *aliyun.TrafficSummary) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyTrafficSummary(summary)
+	})
+}
+
+func (m multiNotifier) NotifyNetworkStats(instanceName string, stats *aliyun.NetworkStats) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyNetworkStats(instanceName, stats)
+	})
+}
+
+func (m multiNotifier) NotifyTrafficWarning(accountLabel, region string, trafficGB, limitGB, percent, daysLeft float64) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyTrafficWarning(accountLabel, region, trafficGB, limitGB, percent, daysLeft)
+	})
+}
+
+func (m multiNotifier) NotifyTrafficShutdown(accountLabel, region string, trafficGB, limitGB float64, stoppedInstances []string) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyTrafficShutdown(accountLabel, region, trafficGB, limitGB, stoppedInstances)
+	})
+}
+
+func (m multiNotifier) NotifyTrafficSummaryWithLimits(summary *aliyun.TrafficSummary, limits map[string]float64, shutdown map[string]bool) error {
+	return m.each(func(n Notifier) error {
+		return n.NotifyTrafficSummaryWithLimits(summary, limits, shutdown)
+	})
+}
@@ -0,0 +1,40 @@
+package notify
+
+import "strings"
+
+// Notification event types a Telegram chat can turn off with /notify
+const (
+	EventReclaim = "reclaim" // instance reclaimed or about to be reclaimed
+	EventStarted = "started" // instance started
+	EventFailed  = "failed"  // start failed or the started instance is unhealthy
+	EventTraffic = "traffic" // traffic warnings, shutdowns and summaries
+	EventBilling = "billing" // billing summaries and cost anomalies
+	EventGCP     = "gcp"     // any notification about a GCP instance
+)
+
+// EventTypes lists the notification event types in display order
+var EventTypes = []string{EventReclaim, EventStarted, EventFailed, EventTraffic, EventBilling, EventGCP}
+
+// ChatPreferences decides which notification event types a chat receives
+type ChatPreferences interface {
+	Enabled(chatID, eventType string) bool
+}
+
+// instanceEvents returns the event types of an instance notification: event, plus EventGCP
+// for GCP instances, whose region is reported as "GCP/<zone>"
+func instanceEvents(event, region string) []string {
+	if strings.HasPrefix(region, "GCP/") {
+		return []string{event, EventGCP}
+	}
+	return []string{event}
+}
+
+// chatEnabled reports whether a chat receives notifications of all the given event types
+func chatEnabled(prefs ChatPreferences, chatID string, events []string) bool {
+	for _, event := range events {
+		if !prefs.Enabled(chatID, event) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,647 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+)
+
+// messageLevel is the severity of a rich message, shown as the embed color on Discord
+type messageLevel int
+
+const (
+	levelInfo    messageLevel = iota // reports and informational messages
+	levelSuccess                     // successful starts, recoveries
+	levelWarning                     // warnings
+	levelAlert                       // reclaims, failures
+)
+
+// maxFieldValue is the longest field value accepted by both Discord and Slack
+const maxFieldValue = 1024
+
+// richMessage is a notification laid out as a title, a description and labelled fields.
+// Text uses **bold** and `code` markdown, which each webhook notifier adapts to its service.
+type richMessage struct {
+	Title       string
+	Description string
+	Level       messageLevel
+	Fields      []richField
+	Footer      string
+}
+
+type richField struct {
+	Name   string
+	Value  string
+	Inline bool // shown side by side with neighbouring inline fields
+}
+
+// richNotifier implements the Notify* methods of webhook notifiers on top of a single
+// post function, so every service renders the same content in its own format
+type richNotifier struct {
+	post     func(msg richMessage) error
+	fromHTML func(message string) string // converts Telegram HTML to the service's markdown
+}
+
+// inlineField returns a field shown side by side with its neighbours
+func inlineField(name, value string) richField {
+	return richField{Name: name, Value: fieldValue(value), Inline: true}
+}
+
+// blockField returns a field that takes a full row
+func blockField(name, value string) richField {
+	return richField{Name: name, Value: fieldValue(value)}
+}
+
+// fieldValue fits a value into a field, which Discord and Slack reject when empty
+func fieldValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return truncateText(value, maxFieldValue)
+}
+
+// codeValue formats an ID as inline code
+func codeValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return "`" + s + "`"
+}
+
+// plainAccountTitle returns the " [label]" suffix of titles, empty for a single account
+func plainAccountTitle(accountLabel string) string {
+	if accountLabel == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", accountLabel)
+}
+
+// truncateText shortens s to at most max runes
+func truncateText(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// Send posts a free-form HTML message, converted to markdown
+func (r *richNotifier) Send(message string) error {
+	return r.post(richMessage{
+		Description: r.fromHTML(message),
+		Level:       levelInfo,
+	})
+}
+
+// NotifyInstanceReclaimed sends a notification when an instance is reclaimed
+func (r *richNotifier) NotifyInstanceReclaimed(accountLabel, instanceID, instanceName, region string) error {
+	return r.post(richMessage{
+		Title:       "🔴 实例被回收" + plainAccountTitle(accountLabel),
+		Description: "正在尝试自动启动...",
+		Level:       levelAlert,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+		},
+	})
+}
+
+// NotifyReclaimWarning sends a notification when EventBridge announced the reclaim of an instance
+func (r *richNotifier) NotifyReclaimWarning(accountLabel, instanceID, instanceName, region string, stopAt time.Time, hookResult string) error {
+	fields := []richField{
+		inlineField("实例", instanceName),
+		inlineField("ID", codeValue(instanceID)),
+		inlineField("区域", region),
+		inlineField("预计停止", formatTime(stopAt, "2006-01-02 15:04:05")),
+	}
+	if hookResult != "" {
+		fields = append(fields, blockField("停止前钩子", hookResult))
+	}
+
+	return r.post(richMessage{
+		Title:       "⏰ 实例即将被回收" + plainAccountTitle(accountLabel),
+		Description: "来自 EventBridge 的回收预告，停止后将自动尝试启动",
+		Level:       levelWarning,
+		Fields:      fields,
+	})
+}
+
+// NotifyInstanceStarting sends a notification when an instance is starting
+func (r *richNotifier) NotifyInstanceStarting(instanceID, instanceName, region string) error {
+	return r.post(richMessage{
+		Title:       "🟡 实例启动中",
+		Description: "正在等待健康检查...",
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+		},
+	})
+}
+
+// NotifyInstanceStarted sends a notification when an instance is successfully started
+func (r *richNotifier) NotifyInstanceStarted(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error {
+	ipInfo := "无公网IP"
+	if publicIP != "" {
+		ipInfo = codeValue(publicIP)
+	}
+
+	return r.post(richMessage{
+		Title:       "✅ 实例已启动" + plainAccountTitle(accountLabel),
+		Description: r.fromHTML(extraInfo),
+		Level:       levelSuccess,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("公网IP", ipInfo),
+			inlineField("启动耗时", fmt.Sprintf("%.0f 秒", duration.Seconds())),
+		},
+	})
+}
+
+// NotifySSHUnreachable sends the started notification of an instance whose SSH port did not
+// accept connections within the health check timeout
+func (r *richNotifier) NotifySSHUnreachable(accountLabel, instanceID, instanceName, region, publicIP string, port int, duration time.Duration, extraInfo string) error {
+	ipInfo := "无公网IP"
+	if publicIP != "" {
+		ipInfo = codeValue(publicIP)
+	}
+
+	description := "实例可能仍在启动或系统异常，请手动检查！"
+	if extraInfo != "" {
+		description += "\n" + r.fromHTML(extraInfo)
+	}
+
+	return r.post(richMessage{
+		Title:       "⚠️ 实例已启动，但 SSH 不可达" + plainAccountTitle(accountLabel),
+		Description: description,
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("公网IP", ipInfo),
+			inlineField("SSH 端口", fmt.Sprintf("%d", port)),
+			inlineField("耗时", fmt.Sprintf("%.0f 秒", duration.Seconds())),
+		},
+	})
+}
+
+// NotifyTrafficResetRestart sends the started notification of an instance that was shut down
+// for exceeding last month's traffic limit and restarted after the monthly reset
+func (r *richNotifier) NotifyTrafficResetRestart(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error {
+	ipInfo := "无公网IP"
+	if publicIP != "" {
+		ipInfo = codeValue(publicIP)
+	}
+
+	description := "上月因流量超额关机，新月份流量已重置"
+	if extraInfo != "" {
+		description += "\n" + r.fromHTML(extraInfo)
+	}
+
+	return r.post(richMessage{
+		Title:       "🔄 流量重置后实例已恢复" + plainAccountTitle(accountLabel),
+		Description: description,
+		Level:       levelSuccess,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("公网IP", ipInfo),
+			inlineField("启动耗时", fmt.Sprintf("%.0f 秒", duration.Seconds())),
+		},
+	})
+}
+
+// NotifyWebhookFailed sends a notification when an instance's post-start webhook failed
+func (r *richNotifier) NotifyWebhookFailed(accountLabel, instanceID, instanceName, webhookURL string, attempts int, err error) error {
+	return r.post(richMessage{
+		Title:       "🪝 启动回调失败" + plainAccountTitle(accountLabel),
+		Description: "实例已正常启动，但外部系统未收到通知",
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("尝试", fmt.Sprintf("%d 次均失败", attempts)),
+			blockField("地址", codeValue(webhookURL)),
+			blockField("错误", err.Error()),
+		},
+	})
+}
+
+// NotifyInstanceStartFailed sends a notification when an instance fails to start
+func (r *richNotifier) NotifyInstanceStartFailed(accountLabel, instanceID, instanceName, region string, retryCount int, err error) error {
+	return r.post(richMessage{
+		Title:       "❌ 启动失败" + plainAccountTitle(accountLabel),
+		Description: "请手动检查！",
+		Level:       levelAlert,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("重试", fmt.Sprintf("%d 次均失败", retryCount)),
+			blockField("错误", err.Error()),
+		},
+	})
+}
+
+// NotifyInstanceNoStock sends a notification when an instance cannot start due to resource sold out
+func (r *richNotifier) NotifyInstanceNoStock(accountLabel, instanceID, instanceName, region string, attempts int) error {
+	return r.post(richMessage{
+		Title:       "🚫 资源售罄 - 已暂停自动重启" + plainAccountTitle(accountLabel),
+		Description: "该可用区资源已售罄 (NoStock)，自动重启已暂停，直到资源恢复可用。\n可尝试更换实例规格或可用区。",
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("尝试", fmt.Sprintf("%d 次", attempts)),
+		},
+	})
+}
+
+// NotifyRapidReclaim sends a notification when auto-start is paused after repeated reclaims
+func (r *richNotifier) NotifyRapidReclaim(accountLabel, instanceID, instanceName, region string, count int, window time.Duration, pausedUntil time.Time) error {
+	return r.post(richMessage{
+		Title:       "🔁 实例频繁被回收" + plainAccountTitle(accountLabel),
+		Description: fmt.Sprintf("该规格或可用区库存紧张，反复重启会浪费 API 配额和不足一小时的费用。\n发送 `/unpause %s` 可立即恢复自动启动。", instanceID),
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("回收次数", fmt.Sprintf("%d 次 / %.0f 分钟", count, window.Minutes())),
+			inlineField("自动启动暂停至", formatTime(pausedUntil, "2006-01-02 15:04:05")),
+		},
+	})
+}
+
+// NotifyMaintenanceStarted sends a notification when a maintenance window opens
+func (r *richNotifier) NotifyMaintenanceStarted(instances, regions string, end time.Time) error {
+	return r.post(richMessage{
+		Title:       "🔧 维护窗口已开始",
+		Description: "维护期间不会自动启动以上实例",
+		Level:       levelInfo,
+		Fields: []richField{
+			blockField("实例", codeValue(instances)),
+			inlineField("区域", regions),
+			inlineField("结束时间", formatTime(end, "2006-01-02 15:04:05")),
+		},
+	})
+}
+
+// NotifyMaintenanceEnded sends a notification when a maintenance window closes
+func (r *richNotifier) NotifyMaintenanceEnded(instances, regions string) error {
+	return r.post(richMessage{
+		Title: "✅ 维护窗口已结束，已恢复自动启动",
+		Level: levelSuccess,
+		Fields: []richField{
+			blockField("实例", codeValue(instances)),
+			inlineField("区域", regions),
+		},
+	})
+}
+
+// NotifyHealthCheckTimeout sends a notification when health check times out
+func (r *richNotifier) NotifyHealthCheckTimeout(instanceID, instanceName, region, publicIP string, timeout int) error {
+	ipInfo := "无公网IP"
+	if publicIP != "" {
+		ipInfo = codeValue(publicIP)
+	}
+
+	return r.post(richMessage{
+		Title:       "⚠️ 健康检查超时",
+		Description: "实例已启动但可能未就绪，请手动检查！",
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("公网IP", ipInfo),
+			inlineField("等待时间", fmt.Sprintf("%d 秒", timeout)),
+		},
+	})
+}
+
+// NotifyDiskIOAnomaly sends a post-start health check notification when no disk I/O is observed
+func (r *richNotifier) NotifyDiskIOAnomaly(accountLabel, instanceID, instanceName, region string, minutes int, readIOPS, writeIOPS float64) error {
+	return r.post(richMessage{
+		Title:       "⚠️ 健康检查: 磁盘 I/O 异常" + plainAccountTitle(accountLabel),
+		Description: fmt.Sprintf("启动后 %d 分钟内读写 I/O 均为 0，实例可能未正常启动或磁盘异常，请手动检查！", minutes),
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("读 IOPS", fmt.Sprintf("%.2f", readIOPS)),
+			inlineField("写 IOPS", fmt.Sprintf("%.2f", writeIOPS)),
+		},
+	})
+}
+
+// NotifyMonitorStarted sends a notification when the monitor starts
+func (r *richNotifier) NotifyMonitorStarted(instanceCount int, instances []string, dryRun bool) error {
+	var sb strings.Builder
+	if dryRun {
+		sb.WriteString(r.fromHTML(DryRunBanner) + "\n\n")
+	}
+	sb.WriteString("**实例列表:**")
+	for _, inst := range instances {
+		sb.WriteString("\n• " + inst)
+	}
+
+	return r.post(richMessage{
+		Title:       "🚀 监控已启动",
+		Description: sb.String(),
+		Level:       levelInfo,
+		Fields: []richField{
+			inlineField("监控实例数", fmt.Sprintf("%d", instanceCount)),
+			inlineField("时区", location.String()),
+		},
+	})
+}
+
+// NotifyMonitorStopping sends a notification when the monitor shuts down
+func (r *richNotifier) NotifyMonitorStopping(abandonedStarts []string) error {
+	msg := richMessage{
+		Title: "🛑 监控正在关闭",
+		Level: levelInfo,
+	}
+	if len(abandonedStarts) > 0 {
+		msg.Description = "⚠️ 等待超时，以下实例启动未完成:\n• " + strings.Join(abandonedStarts, "\n• ")
+		msg.Level = levelWarning
+	}
+	return r.post(msg)
+}
+
+// NotifyPanic sends a notification when a background component recovers from a panic
+func (r *richNotifier) NotifyPanic(component, summary string) error {
+	return r.post(richMessage{
+		Title:       "💥 监控组件异常",
+		Description: "监控仍在运行",
+		Level:       levelAlert,
+		Fields: []richField{
+			inlineField("组件", component),
+			blockField("错误", codeValue(summary)),
+		},
+	})
+}
+
+// NotifySpotTermination sends a notification when the instance running the monitor received a spot reclaim notice
+func (r *richNotifier) NotifySpotTermination(instanceID string, terminationTime time.Time, scriptResult string) error {
+	fields := []richField{
+		inlineField("实例", codeValue(instanceID)),
+		inlineField("回收时间", formatTime(terminationTime, "2006-01-02 15:04:05")),
+		inlineField("剩余", fmt.Sprintf("%.0f 秒", time.Until(terminationTime).Seconds())),
+	}
+	if scriptResult != "" {
+		fields = append(fields, blockField("关机脚本", scriptResult))
+	}
+
+	return r.post(richMessage{
+		Title:       "⏰ 抢占式实例即将被回收",
+		Description: "来自实例元数据的回收预告，请尽快保存数据",
+		Level:       levelAlert,
+		Fields:      fields,
+	})
+}
+
+// NotifyCircuitOpened sends a notification when the ECS API of a region is being skipped after repeated failures
+func (r *richNotifier) NotifyCircuitOpened(accountLabel, region string, failures int, timeout time.Duration) error {
+	return r.post(richMessage{
+		Title:       "⚡ 区域 API 熔断" + plainAccountTitle(accountLabel),
+		Description: "该区域 ECS API 可能异常，期间跳过该区域的实例检测",
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("区域", aliyun.GetRegionDisplayName(region)),
+			inlineField("连续失败", fmt.Sprintf("%d 次", failures)),
+			inlineField("暂停请求", fmt.Sprintf("%.0f 秒后重试", timeout.Seconds())),
+		},
+	})
+}
+
+// NotifyCircuitClosed sends a notification when requests to a region succeed again
+func (r *richNotifier) NotifyCircuitClosed(accountLabel, region string, downtime time.Duration) error {
+	return r.post(richMessage{
+		Title:       "✅ 区域 API 已恢复" + plainAccountTitle(accountLabel),
+		Description: "已恢复该区域的实例检测",
+		Level:       levelSuccess,
+		Fields: []richField{
+			inlineField("区域", aliyun.GetRegionDisplayName(region)),
+			inlineField("熔断时长", fmt.Sprintf("%.0f 秒", downtime.Seconds())),
+		},
+	})
+}
+
+// NotifyBillingSummary sends a billing summary with one line per instance and the totals as fields
+func (r *richNotifier) NotifyBillingSummary(summary *aliyun.BillingSummary) error {
+	if summary == nil {
+		return r.post(richMessage{
+			Title:       "📊 扣费汇总",
+			Description: "暂无扣费记录",
+			Level:       levelInfo,
+		})
+	}
+
+	totalLabel := "本月累计"
+	if summary.WindowHours > 0 {
+		totalLabel = "区间合计"
+	}
+
+	var sb strings.Builder
+	if len(summary.Instances) == 0 {
+		sb.WriteString("暂无扣费记录")
+	}
+	for _, inst := range summary.Instances {
+		sb.WriteString(fmt.Sprintf("🖥 **%s**", inst.InstanceName))
+		if inst.InstanceSpec != "" {
+			sb.WriteString(fmt.Sprintf(" [%s]", inst.InstanceSpec))
+		}
+		sb.WriteString(fmt.Sprintf("\n`%s` | %s\n", inst.InstanceID, inst.Region))
+		if inst.RunningHours > 0 && inst.HourlyCost > 0 {
+			sb.WriteString(fmt.Sprintf("小计: ¥%.4f (%.1fh, ¥%.4f/h)\n", inst.TotalAmount, inst.RunningHours, inst.HourlyCost))
+		} else {
+			sb.WriteString(fmt.Sprintf("小计: ¥%.4f\n", inst.TotalAmount))
+		}
+		if inst.OnDemandHourly > 0 {
+			sb.WriteString(fmt.Sprintf("💰 Saved: ¥%.2f vs on-demand (%.0f%% discount)\n", inst.Savings, inst.DiscountPercent()))
+		}
+		sb.WriteString("\n")
+	}
+
+	fields := []richField{
+		inlineField(totalLabel, fmt.Sprintf("¥%.4f", summary.TotalAmount)),
+		inlineField("月度估算", fmt.Sprintf("¥%.2f", summary.MonthlyEstimate)),
+		inlineField("总运行时长", fmt.Sprintf("%.1f 小时", summary.TotalRunningHours)),
+	}
+	if summary.TotalSavings != 0 {
+		fields = append(fields, inlineField("相比按量付费节省", fmt.Sprintf("¥%.2f", summary.TotalSavings)))
+	}
+	if summary.RollingDays > 0 {
+		fields = append(fields,
+			inlineField(fmt.Sprintf("近 %d 日日均", summary.RollingDays), fmt.Sprintf("¥%.4f", summary.RollingDailyAverage)),
+			inlineField("月末预计", fmt.Sprintf("¥%.2f", summary.MonthEndProjection)),
+		)
+	}
+
+	msg := richMessage{
+		Title:       fmt.Sprintf("📊 扣费汇总%s (%s)", plainAccountTitle(summary.AccountLabel), summary.BillingCycle),
+		Description: sb.String(),
+		Level:       levelInfo,
+		Fields:      fields,
+	}
+	if summary.EstimateMethod != "" {
+		msg.Footer = summary.EstimateMethod
+	}
+	return r.post(msg)
+}
+
+// NotifyCostAnomaly sends a notification when an instance's daily cost is far above its baseline
+func (r *richNotifier) NotifyCostAnomaly(accountLabel, instanceID, instanceName, region, day string, cost, baseline float64, baselineDays int) error {
+	return r.post(richMessage{
+		Title:       "💸 费用异常" + plainAccountTitle(accountLabel),
+		Description: "请检查实例规格、流量或附加资源是否有变化",
+		Level:       levelWarning,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(instanceID)),
+			inlineField("区域", region),
+			inlineField("日期", day),
+			inlineField("当日费用", fmt.Sprintf("¥%.4f", cost)),
+			inlineField(fmt.Sprintf("近 %d 日日均", baselineDays), fmt.Sprintf("¥%.4f", baseline)),
+			inlineField("倍数", fmt.Sprintf("%.1fx", cost/baseline)),
+		},
+	})
+}
+
+// NotifyTrafficSummary sends a traffic summary notification
+func (r *richNotifier) NotifyTrafficSummary(summary *aliyun.TrafficSummary) error {
+	return r.sendTrafficSummary(summary, nil, nil)
+}
+
+// NotifyTrafficSummaryWithLimits sends a traffic summary with threshold info.
+// limits and shutdown are keyed by limit scope (see aliyun.TrafficScope).
+func (r *richNotifier) NotifyTrafficSummaryWithLimits(summary *aliyun.TrafficSummary, limits map[string]float64, shutdown map[string]bool) error {
+	return r.sendTrafficSummary(summary, limits, shutdown)
+}
+
+// sendTrafficSummary sends a traffic summary; limits == nil omits the threshold info
+func (r *richNotifier) sendTrafficSummary(summary *aliyun.TrafficSummary, limits map[string]float64, shutdown map[string]bool) error {
+	if summary == nil {
+		return r.post(richMessage{
+			Title:       "📶 流量统计",
+			Description: "暂无流量数据",
+			Level:       levelInfo,
+		})
+	}
+
+	usage := summary.ScopeTrafficGB(limits)
+	scopeValue := func(scope string, group aliyun.TrafficRegionSummary) string {
+		value := aliyun.FormatTrafficSize(group.Traffic)
+		if group.Traffic == 0 {
+			value = "暂无流量"
+		}
+		if limitGB, ok := limits[scope]; ok {
+			remain := limitGB - usage[scope]
+			if remain < 0 {
+				remain = 0
+			}
+			value += fmt.Sprintf(" / %.0f GB\n剩余额度: %.2f GB", limitGB, remain)
+			if shutdown[scope] {
+				value += "\n🔴 **已超额关机**"
+			}
+		}
+		if group.RegionCount > 0 {
+			value += fmt.Sprintf("\n区域数: %d", group.RegionCount)
+		}
+		return value
+	}
+
+	fields := []richField{
+		inlineField("🇨🇳 中国大陆", scopeValue(aliyun.TrafficScopeChina, summary.ChinaMainland)),
+		inlineField("🌏 非中国大陆", scopeValue(aliyun.TrafficScopeNonChina, summary.NonChinaMainland)),
+	}
+
+	total := aliyun.FormatTrafficSize(summary.TotalTraffic)
+	if summary.TotalTraffic > 0 {
+		chinaPercent := float64(summary.ChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
+		nonChinaPercent := float64(summary.NonChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
+		total += fmt.Sprintf("\n中国大陆: %.1f%% | 非中国大陆: %.1f%%", chinaPercent, nonChinaPercent)
+	}
+	fields = append(fields, blockField("📈 本月总流量", total))
+
+	return r.post(richMessage{
+		Title: fmt.Sprintf("📶 流量统计%s (%s)", plainAccountTitle(summary.AccountLabel), summary.BillingCycle),
+		Description: fmt.Sprintf("统计区间: %s 01日 ~ %s",
+			summary.BillingCycle, formatTime(summary.EndTime, "02日 15:04")),
+		Level:  levelInfo,
+		Fields: fields,
+	})
+}
+
+// NotifyNetworkStats sends per-instance network bandwidth statistics
+func (r *richNotifier) NotifyNetworkStats(instanceName string, stats *aliyun.NetworkStats) error {
+	metric := func(in, out aliyun.NetworkMetric) string {
+		return fmt.Sprintf("⬇️ 入: 平均 %s | 峰值 %s\n⬆️ 出: 平均 %s | 峰值 %s",
+			aliyun.FormatBitRate(in.AvgBps), aliyun.FormatBitRate(in.PeakBps),
+			aliyun.FormatBitRate(out.AvgBps), aliyun.FormatBitRate(out.PeakBps))
+	}
+
+	return r.post(richMessage{
+		Title: fmt.Sprintf("📡 网络带宽统计 (近 %d 小时)", stats.Hours),
+		Level: levelInfo,
+		Fields: []richField{
+			inlineField("实例", instanceName),
+			inlineField("ID", codeValue(stats.InstanceID)),
+			inlineField("区域", aliyun.GetRegionDisplayName(stats.RegionID)),
+			blockField("🌐 公网", metric(stats.InternetIn, stats.InternetOut)),
+			blockField("🏠 内网", metric(stats.IntranetIn, stats.IntranetOut)),
+		},
+		Footer: "数据来源: 云监控，按小时聚合",
+	})
+}
+
+// NotifyTrafficWarning sends a notification when traffic crosses a warning threshold.
+// daysLeft < 0 means the remaining days could not be estimated.
+func (r *richNotifier) NotifyTrafficWarning(accountLabel, region string, trafficGB, limitGB, percent, daysLeft float64) error {
+	fields := []richField{
+		inlineField("区域", trafficScopeLabel(region)),
+		inlineField("当前流量", fmt.Sprintf("%.2f GB / %.2f GB", trafficGB, limitGB)),
+		inlineField("已用比例", fmt.Sprintf("%.1f%%", percent)),
+	}
+	if daysLeft >= 0 {
+		fields = append(fields, inlineField("预计达到阈值", fmt.Sprintf("%.1f 天后", daysLeft)))
+	}
+
+	return r.post(richMessage{
+		Title:       "⚠️ 流量用量预警" + plainAccountTitle(accountLabel),
+		Description: "达到阈值后将自动关机",
+		Level:       levelWarning,
+		Fields:      fields,
+	})
+}
+
+// NotifyTrafficShutdown sends a notification when instances are stopped due to traffic limit
+func (r *richNotifier) NotifyTrafficShutdown(accountLabel, region string, trafficGB, limitGB float64, stoppedInstances []string) error {
+	fields := []richField{
+		inlineField("区域", trafficScopeLabel(region)),
+		inlineField("当前流量", fmt.Sprintf("%.2f GB", trafficGB)),
+		inlineField("流量阈值", fmt.Sprintf("%.2f GB", limitGB)),
+	}
+	if len(stoppedInstances) > 0 {
+		fields = append(fields, blockField("已关闭实例", "• "+strings.Join(stoppedInstances, "\n• ")))
+	}
+
+	return r.post(richMessage{
+		Title:       "🚨 流量超额自动关机" + plainAccountTitle(accountLabel),
+		Description: "使用节省停机模式，不再计费 vCPU/内存\n自动重启已暂停，新月流量重置后恢复",
+		Level:       levelAlert,
+		Fields:      fields,
+	})
+}
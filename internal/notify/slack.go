@@ -0,0 +1,207 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Slack Block Kit limits
+const (
+	maxSlackHeader        = 150
+	maxSlackSectionText   = 3000
+	maxSlackSectionFields = 10
+)
+
+const (
+	// slackMaxAttempts is the number of tries of a rate-limited message
+	slackMaxAttempts = 3
+	// slackMaxRetryAfter caps the Retry-After backoff requested by Slack
+	slackMaxRetryAfter = 30 * time.Second
+)
+
+// SlackNotifier sends notifications as Block Kit messages to a Slack incoming webhook
+type SlackNotifier struct {
+	richNotifier
+	webhookURL string
+	username   string
+	iconEmoji  string
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a new Slack notifier. username and iconEmoji override the
+// webhook's defaults when not empty.
+func NewSlackNotifier(webhookURL, username, iconEmoji string) *SlackNotifier {
+	s := &SlackNotifier{
+		webhookURL: webhookURL,
+		username:   username,
+		iconEmoji:  iconEmoji,
+		client:     NewHTTPClient(),
+	}
+	s.richNotifier = richNotifier{post: s.sendBlocks, fromHTML: htmlToSlackMarkdown}
+	return s
+}
+
+// Flush is a no-op, Slack notifications are never held back
+func (s *SlackNotifier) Flush() {}
+
+// slackMessage is the body of an incoming webhook call
+type slackMessage struct {
+	Username  string       `json:"username,omitempty"`
+	IconEmoji string       `json:"icon_emoji,omitempty"`
+	Text      string       `json:"text"` // fallback shown in push notifications
+	Blocks    []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func mrkdwn(text string) slackText {
+	return slackText{Type: "mrkdwn", Text: text}
+}
+
+// slackFieldText renders a field as a bold label above its value
+func slackFieldText(f richField) string {
+	return fmt.Sprintf("*%s*\n%s", slackEscape(f.Name), slackMarkdown(f.Value))
+}
+
+// sendBlocks posts a message as a header, description and fields sections and a timestamp
+// context, backing off when Slack rate-limits the webhook
+func (s *SlackNotifier) sendBlocks(msg richMessage) error {
+	var blocks []slackBlock
+	if msg.Title != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: truncateText(msg.Title, maxSlackHeader)},
+		})
+	}
+	if msg.Description != "" {
+		text := mrkdwn(truncateText(slackMarkdown(msg.Description), maxSlackSectionText))
+		blocks = append(blocks, slackBlock{Type: "section", Text: &text})
+	}
+
+	// Consecutive inline fields share a two-column section, full-row fields get their own
+	var fields []slackText
+	flushFields := func() {
+		if len(fields) > 0 {
+			blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+			fields = nil
+		}
+	}
+	for _, f := range msg.Fields {
+		if !f.Inline {
+			flushFields()
+			text := mrkdwn(slackFieldText(f))
+			blocks = append(blocks, slackBlock{Type: "section", Text: &text})
+			continue
+		}
+		fields = append(fields, mrkdwn(slackFieldText(f)))
+		if len(fields) == maxSlackSectionFields {
+			flushFields()
+		}
+	}
+	flushFields()
+
+	context := []slackText{mrkdwn("🕐 " + formatTime(time.Now(), "2006-01-02 15:04:05"))}
+	if msg.Footer != "" {
+		context = append(context, mrkdwn(slackEscape(msg.Footer)))
+	}
+	blocks = append(blocks, slackBlock{Type: "context", Elements: context})
+
+	fallback := msg.Title
+	if fallback == "" {
+		fallback = truncateText(msg.Description, maxSlackHeader)
+	}
+
+	body, err := json.Marshal(slackMessage{
+		Username:  s.username,
+		IconEmoji: s.iconEmoji,
+		Text:      fallback,
+		Blocks:    blocks,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.post(body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter == 0 || attempt == slackMaxAttempts {
+			return err
+		}
+		log.Warnf("Slack webhook rate limited, retrying in %s", retryAfter)
+		time.Sleep(retryAfter)
+	}
+}
+
+// post sends a message body once. A rate-limited request returns the backoff requested by
+// Slack's Retry-After header along with the error.
+func (s *SlackNotifier) post(body []byte) (time.Duration, error) {
+	resp, err := s.client.Post(s.webhookURL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := time.Second
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		if retryAfter > slackMaxRetryAfter {
+			retryAfter = slackMaxRetryAfter
+		}
+		return retryAfter, fmt.Errorf("slack webhook rate limited (HTTP 429)")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return 0, nil
+}
+
+var (
+	slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+	htmlToSlackReplacer = strings.NewReplacer(
+		"<b>", "*", "</b>", "*",
+		"<i>", "_", "</i>", "_",
+		"<code>", "`", "</code>", "`",
+		"<pre>", "```\n", "</pre>", "\n```",
+	)
+)
+
+// slackEscape escapes the characters Slack reserves for links and mentions
+func slackEscape(text string) string {
+	return slackEscaper.Replace(text)
+}
+
+// slackMarkdown converts rich message markdown to Slack mrkdwn
+func slackMarkdown(text string) string {
+	return strings.ReplaceAll(slackEscape(text), "**", "*")
+}
+
+// htmlToSlackMarkdown converts a Telegram HTML message to Slack mrkdwn
+func htmlToSlackMarkdown(message string) string {
+	message = htmlToSlackReplacer.Replace(message)
+	message = htmlTagPattern.ReplaceAllString(message, "")
+	return html.UnescapeString(message)
+}
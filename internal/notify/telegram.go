@@ -3,29 +3,86 @@ package notify
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/iliyian/aliyun-spot-manager/internal/aliyun"
+	"github.com/iliyian/aliyun-spot-manager/internal/format"
+	"github.com/iliyian/aliyun-spot-manager/internal/i18n"
+	log "github.com/sirupsen/logrus"
 )
 
 // TelegramNotifier sends notifications via Telegram
 type TelegramNotifier struct {
 	botToken string
-	chatID   string
+	chatIDs  []string // every message is delivered to all chats
 	client   *http.Client
+	batcher  *notificationBatcher // merges reclaim notifications, nil = disabled
+	prefs    ChatPreferences      // event types each chat receives, nil = all
+	mode     format.ParseMode     // messages are written in HTML and converted to mode on send
+	tr       i18n.Translator      // language of the notifications (BOT_LANGUAGE)
 }
 
-// NewTelegramNotifier creates a new Telegram notifier
-func NewTelegramNotifier(botToken, chatID string) *TelegramNotifier {
+// NewTelegramNotifier creates a new Telegram notifier. client may be shared with the bot
+// handler; nil creates one with NewHTTPClient.
+func NewTelegramNotifier(botToken string, chatIDs []string, client *http.Client) *TelegramNotifier {
+	if client == nil {
+		client = NewHTTPClient()
+	}
 	return &TelegramNotifier{
 		botToken: botToken,
-		chatID:   chatID,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		chatIDs:  chatIDs,
+		client:   client,
+		mode:     format.ParseModeHTML,
+		tr:       i18n.New(i18n.ZhCN),
+	}
+}
+
+// SetParseMode sets the formatting messages are converted to before sending (TELEGRAM_PARSE_MODE)
+func (t *TelegramNotifier) SetParseMode(mode format.ParseMode) {
+	t.mode = mode
+}
+
+// SetTranslator sets the language notifications are written in (BOT_LANGUAGE)
+func (t *TelegramNotifier) SetTranslator(tr i18n.Translator) {
+	t.tr = tr
+}
+
+// SetBatching holds reclaim notifications for window after the first one and merges them into
+// a single message when more than threshold arrive. A threshold of 0 disables batching.
+func (t *TelegramNotifier) SetBatching(window time.Duration, threshold int) {
+	if threshold <= 0 || window <= 0 {
+		t.batcher = nil
+		return
+	}
+	t.batcher = newNotificationBatcher(window, threshold, t.sendEvent, t.formatMassReclaim)
+}
+
+// SetPreferences filters notifications of the event types a chat turned off (/notify)
+func (t *TelegramNotifier) SetPreferences(prefs ChatPreferences) {
+	t.prefs = prefs
+}
+
+// ForChat returns a notifier that sends only to chatID, for replies to bot commands. Replies are
+// not notifications, so they are neither batched nor filtered by /notify preferences.
+func (t *TelegramNotifier) ForChat(chatID string) *TelegramNotifier {
+	c := *t
+	c.chatIDs = []string{chatID}
+	c.batcher = nil
+	c.prefs = nil
+	return &c
+}
+
+// Flush sends reclaim notifications still held by the batcher
+func (t *TelegramNotifier) Flush() {
+	if t.batcher != nil {
+		t.batcher.flush()
 	}
 }
 
@@ -33,17 +90,72 @@ func NewTelegramNotifier(botToken, chatID string) *TelegramNotifier {
 type telegramMessage struct {
 	ChatID    string `json:"chat_id"`
 	Text      string `json:"text"`
-	ParseMode string `json:"parse_mode"`
+	ParseMode string `json:"parse_mode,omitempty"`
 }
 
-// Send sends a message via Telegram
+// Send sends a message to all configured chats concurrently. A failed chat does not prevent
+// delivery to the others; an error is returned only if no chat received the message.
 func (t *TelegramNotifier) Send(message string) error {
+	return t.sendToChats(t.chatIDs, message)
+}
+
+// sendEvent sends a notification to the chats that receive all of the given event types
+func (t *TelegramNotifier) sendEvent(message string, events ...string) error {
+	if t.prefs == nil {
+		return t.Send(message)
+	}
+
+	chatIDs := make([]string, 0, len(t.chatIDs))
+	for _, chatID := range t.chatIDs {
+		if chatEnabled(t.prefs, chatID, events) {
+			chatIDs = append(chatIDs, chatID)
+		}
+	}
+	if len(chatIDs) == 0 {
+		log.Debugf("Notification of type %s skipped: turned off in all chats", strings.Join(events, "+"))
+		return nil
+	}
+	return t.sendToChats(chatIDs, message)
+}
+
+// sendToChats sends a message to the given chats concurrently, see Send
+func (t *TelegramNotifier) sendToChats(chatIDs []string, message string) error {
+	if len(chatIDs) == 1 {
+		return t.sendTo(chatIDs[0], message)
+	}
+
+	errs := make([]error, len(chatIDs))
+	var wg sync.WaitGroup
+	for i, chatID := range chatIDs {
+		wg.Add(1)
+		go func(i int, chatID string) {
+			defer wg.Done()
+			errs[i] = t.sendTo(chatID, message)
+		}(i, chatID)
+	}
+	wg.Wait()
+
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			log.Warnf("Failed to send Telegram message to chat %s: %v", chatIDs[i], err)
+			failed = append(failed, fmt.Errorf("chat %s: %w", chatIDs[i], err))
+		}
+	}
+	if len(failed) == len(chatIDs) {
+		return errors.Join(failed...)
+	}
+	return nil
+}
+
+// sendTo sends a message to a single chat
+func (t *TelegramNotifier) sendTo(chatID, message string) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.botToken)
 
 	msg := telegramMessage{
-		ChatID:    t.chatID,
-		Text:      message,
-		ParseMode: "HTML",
+		ChatID:    chatID,
+		Text:      format.ConvertHTML(message, t.mode),
+		ParseMode: t.mode.APIValue(),
 	}
 
 	body, err := json.Marshal(msg)
@@ -64,127 +176,241 @@ func (t *TelegramNotifier) Send(message string) error {
 	return nil
 }
 
-// NotifyInstanceReclaimed sends a notification when an instance is reclaimed
-func (t *TelegramNotifier) NotifyInstanceReclaimed(instanceID, instanceName, region string) error {
-	message := fmt.Sprintf(`🔴 <b>实例被回收</b>
-━━━━━━━━━━━━━━━
-实例: %s
-ID: <code>%s</code>
-区域: %s
-时间: %s
-━━━━━━━━━━━━━━━
-正在尝试自动启动...`,
-		instanceName, instanceID, region, time.Now().Format("2006-01-02 15:04:05"))
+// accountTitle returns the " [label]" suffix of notification titles, empty for a single account
+func accountTitle(accountLabel string) string {
+	if accountLabel == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", html.EscapeString(accountLabel))
+}
+
+// publicIPInfo returns the public IP shown in notifications, or a placeholder when there is none
+func (t *TelegramNotifier) publicIPInfo(publicIP string) string {
+	if publicIP == "" {
+		return t.tr.T("common.no_public_ip")
+	}
+	return publicIP
+}
 
-	return t.Send(message)
+// NotifyInstanceReclaimed sends a notification when an instance is reclaimed.
+// With batching enabled the notification is queued and sent (or merged) when the window ends.
+func (t *TelegramNotifier) NotifyInstanceReclaimed(accountLabel, instanceID, instanceName, region string) error {
+	message := t.tr.T("notify.reclaimed",
+		accountTitle(accountLabel), instanceName, instanceID, region, formatTime(time.Now(), "2006-01-02 15:04:05"))
+
+	if t.batcher != nil {
+		t.batcher.add(reclaimNotice{
+			accountLabel: accountLabel,
+			instanceID:   instanceID,
+			instanceName: instanceName,
+			region:       region,
+			message:      message,
+		})
+		return nil
+	}
+	return t.sendEvent(message, instanceEvents(EventReclaim, region)...)
+}
+
+// NotifyReclaimWarning sends a notification when EventBridge announced the reclaim of an instance
+func (t *TelegramNotifier) NotifyReclaimWarning(accountLabel, instanceID, instanceName, region string, stopAt time.Time, hookResult string) error {
+	hookInfo := ""
+	if hookResult != "" {
+		hookInfo = "\n" + html.EscapeString(hookResult)
+	}
+
+	message := t.tr.T("notify.reclaim_warning",
+		accountTitle(accountLabel), instanceName, instanceID, region, formatTime(stopAt, "2006-01-02 15:04:05"), hookInfo)
+
+	return t.sendEvent(message, instanceEvents(EventReclaim, region)...)
 }
 
 // NotifyInstanceStarting sends a notification when an instance is starting
 func (t *TelegramNotifier) NotifyInstanceStarting(instanceID, instanceName, region string) error {
-	message := fmt.Sprintf(`🟡 <b>实例启动中</b>
-━━━━━━━━━━━━━━━
-实例: %s
-ID: <code>%s</code>
-区域: %s
-时间: %s
-━━━━━━━━━━━━━━━
-正在等待健康检查...`,
-		instanceName, instanceID, region, time.Now().Format("2006-01-02 15:04:05"))
+	message := t.tr.T("notify.starting",
+		instanceName, instanceID, region, formatTime(time.Now(), "2006-01-02 15:04:05"))
 
-	return t.Send(message)
+	return t.sendEvent(message, instanceEvents(EventStarted, region)...)
 }
 
-// NotifyInstanceStarted sends a notification when an instance is successfully started
-func (t *TelegramNotifier) NotifyInstanceStarted(instanceID, instanceName, region, publicIP string, duration time.Duration) error {
-	ipInfo := "无公网IP"
-	if publicIP != "" {
-		ipInfo = publicIP
+// NotifyInstanceStarted sends a notification when an instance is successfully started.
+// extraInfo, if not empty, is appended as additional lines (e.g. bandwidth package binding).
+func (t *TelegramNotifier) NotifyInstanceStarted(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error {
+	message := t.tr.T("notify.started",
+		accountTitle(accountLabel), instanceName, instanceID, region, t.publicIPInfo(publicIP), duration.Seconds())
+	if extraInfo != "" {
+		message += "\n" + extraInfo
 	}
 
-	message := fmt.Sprintf(`✅ <b>实例已启动</b>
-━━━━━━━━━━━━━━━
-实例: %s
-ID: <code>%s</code>
-区域: %s
-公网IP: <code>%s</code>
-状态: Running ✓
-启动耗时: %.0f 秒
-━━━━━━━━━━━━━━━`,
-		instanceName, instanceID, region, ipInfo, duration.Seconds())
+	return t.sendEvent(message, instanceEvents(EventStarted, region)...)
+}
 
-	return t.Send(message)
+// NotifySSHUnreachable sends the started notification of an instance whose SSH port did not
+// accept connections within the health check timeout
+func (t *TelegramNotifier) NotifySSHUnreachable(accountLabel, instanceID, instanceName, region, publicIP string, port int, duration time.Duration, extraInfo string) error {
+	message := t.tr.T("notify.ssh_unreachable",
+		accountTitle(accountLabel), instanceName, instanceID, region, t.publicIPInfo(publicIP), port, duration.Seconds())
+	if extraInfo != "" {
+		message += "\n" + extraInfo
+	}
+
+	return t.sendEvent(message, instanceEvents(EventFailed, region)...)
+}
+
+// NotifyTrafficResetRestart sends the started notification of an instance that was shut down
+// for exceeding last month's traffic limit and restarted after the monthly reset
+func (t *TelegramNotifier) NotifyTrafficResetRestart(accountLabel, instanceID, instanceName, region, publicIP string, duration time.Duration, extraInfo string) error {
+	message := t.tr.T("notify.traffic_reset_restart",
+		accountTitle(accountLabel), instanceName, instanceID, region, t.publicIPInfo(publicIP), duration.Seconds())
+	if extraInfo != "" {
+		message += "\n" + extraInfo
+	}
+
+	return t.sendEvent(message, instanceEvents(EventStarted, region)...)
+}
+
+// NotifyWebhookFailed sends a notification when an instance's post-start webhook failed
+func (t *TelegramNotifier) NotifyWebhookFailed(accountLabel, instanceID, instanceName, webhookURL string, attempts int, err error) error {
+	message := t.tr.T("notify.webhook_failed",
+		accountTitle(accountLabel), instanceName, instanceID, html.EscapeString(webhookURL), html.EscapeString(err.Error()), attempts)
+
+	return t.sendEvent(message, EventFailed)
 }
 
 // NotifyInstanceStartFailed sends a notification when an instance fails to start
-func (t *TelegramNotifier) NotifyInstanceStartFailed(instanceID, instanceName, region string, retryCount int, err error) error {
-	message := fmt.Sprintf(`❌ <b>启动失败</b>
-━━━━━━━━━━━━━━━
-实例: %s
-ID: <code>%s</code>
-区域: %s
-错误: %s
-重试: %d 次均失败
-━━━━━━━━━━━━━━━
-请手动检查！`,
-		instanceName, instanceID, region, err.Error(), retryCount)
+func (t *TelegramNotifier) NotifyInstanceStartFailed(accountLabel, instanceID, instanceName, region string, retryCount int, err error) error {
+	message := t.tr.T("notify.start_failed",
+		accountTitle(accountLabel), instanceName, instanceID, region, err.Error(), retryCount)
 
-	return t.Send(message)
+	return t.sendEvent(message, instanceEvents(EventFailed, region)...)
 }
 
 // NotifyInstanceNoStock sends a notification when an instance cannot start due to resource sold out
-func (t *TelegramNotifier) NotifyInstanceNoStock(instanceID, instanceName, region string, attempts int) error {
-	message := fmt.Sprintf(`🚫 <b>资源售罄 - 已暂停自动重启</b>
-━━━━━━━━━━━━━━━
-实例: %s
-ID: <code>%s</code>
-区域: %s
-原因: 该可用区资源已售罄 (NoStock)
-尝试: %d 次
-时间: %s
-━━━━━━━━━━━━━━━
-⚠️ <i>自动重启已暂停，直到资源恢复可用</i>
-💡 <i>可尝试更换实例规格或可用区</i>`,
-		instanceName, instanceID, region, attempts, time.Now().Format("2006-01-02 15:04:05"))
+func (t *TelegramNotifier) NotifyInstanceNoStock(accountLabel, instanceID, instanceName, region string, attempts int) error {
+	message := t.tr.T("notify.no_stock",
+		accountTitle(accountLabel), instanceName, instanceID, region, attempts, formatTime(time.Now(), "2006-01-02 15:04:05"))
+
+	return t.sendEvent(message, instanceEvents(EventFailed, region)...)
+}
+
+// NotifyRapidReclaim sends a notification when auto-start is paused after repeated reclaims
+func (t *TelegramNotifier) NotifyRapidReclaim(accountLabel, instanceID, instanceName, region string, count int, window time.Duration, pausedUntil time.Time) error {
+	message := t.tr.T("notify.rapid_reclaim",
+		accountTitle(accountLabel), instanceID, instanceName, region, count, window.Minutes(), formatTime(pausedUntil, "2006-01-02 15:04:05"), instanceID)
+
+	return t.sendEvent(message, instanceEvents(EventReclaim, region)...)
+}
+
+// NotifyMaintenanceStarted sends a notification when a maintenance window opens
+func (t *TelegramNotifier) NotifyMaintenanceStarted(instances, regions string, end time.Time) error {
+	message := t.tr.T("notify.maintenance_started",
+		html.EscapeString(instances), html.EscapeString(regions), formatTime(end, "2006-01-02 15:04:05"))
+
+	return t.Send(message)
+}
+
+// NotifyMaintenanceEnded sends a notification when a maintenance window closes
+func (t *TelegramNotifier) NotifyMaintenanceEnded(instances, regions string) error {
+	message := t.tr.T("notify.maintenance_ended",
+		html.EscapeString(instances), html.EscapeString(regions), formatTime(time.Now(), "2006-01-02 15:04:05"))
 
 	return t.Send(message)
 }
 
 // NotifyHealthCheckTimeout sends a notification when health check times out
 func (t *TelegramNotifier) NotifyHealthCheckTimeout(instanceID, instanceName, region, publicIP string, timeout int) error {
-	ipInfo := "无公网IP"
-	if publicIP != "" {
-		ipInfo = publicIP
-	}
-
-	message := fmt.Sprintf(`⚠️ <b>健康检查超时</b>
-━━━━━━━━━━━━━━━
-实例: %s
-ID: <code>%s</code>
-区域: %s
-公网IP: <code>%s</code>
-检查类型: Ping
-等待时间: %d 秒
-━━━━━━━━━━━━━━━
-实例已启动但可能未就绪，请手动检查！`,
-		instanceName, instanceID, region, ipInfo, timeout)
+	message := t.tr.T("notify.health_check_timeout",
+		instanceName, instanceID, region, t.publicIPInfo(publicIP), timeout)
 
-	return t.Send(message)
+	return t.sendEvent(message, instanceEvents(EventFailed, region)...)
 }
 
+// NotifyDiskIOAnomaly sends a post-start health check notification when no disk I/O is observed
+func (t *TelegramNotifier) NotifyDiskIOAnomaly(accountLabel, instanceID, instanceName, region string, minutes int, readIOPS, writeIOPS float64) error {
+	message := t.tr.T("notify.disk_io_anomaly",
+		accountTitle(accountLabel), instanceName, instanceID, region, minutes, readIOPS, writeIOPS)
+
+	return t.sendEvent(message, instanceEvents(EventFailed, region)...)
+}
+
+// DryRunBanner is shown in the startup notification and /status when DRY_RUN is enabled
+const DryRunBanner = "⚠️ <b>DRY RUN MODE — no actions will be taken.</b>"
+
 // NotifyMonitorStarted sends a notification when the monitor starts
-func (t *TelegramNotifier) NotifyMonitorStarted(instanceCount int, instances []string) error {
+func (t *TelegramNotifier) NotifyMonitorStarted(instanceCount int, instances []string, dryRun bool) error {
 	instanceList := ""
 	for _, inst := range instances {
 		instanceList += fmt.Sprintf("\n• %s", inst)
 	}
 
-	message := fmt.Sprintf(`🚀 <b>监控已启动</b>
-━━━━━━━━━━━━━━━
-监控实例数: %d
-时间: %s
-━━━━━━━━━━━━━━━
-<b>实例列表:</b>%s`,
-		instanceCount, time.Now().Format("2006-01-02 15:04:05"), instanceList)
+	banner := ""
+	if dryRun {
+		banner = DryRunBanner + "\n\n"
+	}
+
+	message := t.tr.T("notify.monitor_started",
+		banner, instanceCount, formatTime(time.Now(), "2006-01-02 15:04:05"), location, instanceList)
+
+	return t.Send(message)
+}
+
+// NotifyMonitorStopping sends a notification when the monitor shuts down. abandonedStarts lists
+// the instances whose start did not finish within the shutdown timeout.
+func (t *TelegramNotifier) NotifyMonitorStopping(abandonedStarts []string) error {
+	message := t.tr.T("notify.monitor_stopping", formatTime(time.Now(), "2006-01-02 15:04:05"))
+
+	if len(abandonedStarts) > 0 {
+		message += "\n━━━━━━━━━━━━━━━\n" + t.tr.T("notify.monitor_stopping.abandoned")
+		for _, inst := range abandonedStarts {
+			message += "\n• " + html.EscapeString(inst)
+		}
+	}
+
+	return t.Send(message)
+}
+
+// NotifyPanic sends a notification when a background component recovers from a panic
+func (t *TelegramNotifier) NotifyPanic(component, summary string) error {
+	message := t.tr.T("notify.panic",
+		component, html.EscapeString(summary), formatTime(time.Now(), "2006-01-02 15:04:05"))
+
+	return t.Send(message)
+}
+
+// NotifySpotTermination sends a notification when the instance running the monitor received a spot reclaim notice
+func (t *TelegramNotifier) NotifySpotTermination(instanceID string, terminationTime time.Time, scriptResult string) error {
+	scriptInfo := ""
+	if scriptResult != "" {
+		scriptInfo = "\n" + html.EscapeString(scriptResult) + "\n"
+	}
+
+	message := t.tr.T("notify.spot_termination",
+		instanceID, formatTime(terminationTime, "2006-01-02 15:04:05"), time.Until(terminationTime).Seconds(), scriptInfo)
+
+	return t.sendEvent(message, EventReclaim)
+}
+
+// NotifyCircuitOpened sends a notification when the ECS API of a region is being skipped after repeated failures
+func (t *TelegramNotifier) NotifyCircuitOpened(accountLabel, region string, failures int, timeout time.Duration) error {
+	accountInfo := ""
+	if accountLabel != "" {
+		accountInfo = t.tr.T("notify.circuit.account", accountLabel)
+	}
+
+	message := t.tr.T("notify.circuit_opened",
+		accountInfo, aliyun.GetRegionDisplayName(region), failures, timeout.Seconds())
+
+	return t.Send(message)
+}
+
+// NotifyCircuitClosed sends a notification when requests to a region succeed again
+func (t *TelegramNotifier) NotifyCircuitClosed(accountLabel, region string, downtime time.Duration) error {
+	accountInfo := ""
+	if accountLabel != "" {
+		accountInfo = t.tr.T("notify.circuit.account", accountLabel)
+	}
+
+	message := t.tr.T("notify.circuit_closed",
+		accountInfo, aliyun.GetRegionDisplayName(region), downtime.Seconds())
 
 	return t.Send(message)
 }
@@ -196,19 +422,16 @@ func (t *TelegramNotifier) NotifyBillingSummary(summary *aliyun.BillingSummary)
 		if summary != nil && summary.AccountLabel != "" {
 			accountTitle = fmt.Sprintf(" [%s]", summary.AccountLabel)
 		}
-		billingCycle := "未知周期"
+		billingCycle := t.tr.T("billing.unknown_cycle")
+		totalLabel := t.tr.T("billing.total_month")
 		if summary != nil {
 			billingCycle = summary.BillingCycle
+			if summary.WindowHours > 0 {
+				totalLabel = t.tr.T("billing.total_window")
+			}
 		}
-		message := fmt.Sprintf(`📊 <b>扣费汇总%s</b> (%s)
-━━━━━━━━━━━━━━━━━━━━━━━━
-
-暂无扣费记录
-
-━━━━━━━━━━━━━━━━━━━━━━━━
-💰 本月累计: ¥0.00
-📈 月度估算: ¥0.00`, accountTitle, billingCycle)
-		return t.Send(message)
+		message := t.tr.T("billing.empty", accountTitle, billingCycle, totalLabel)
+		return t.sendEvent(message, EventBilling)
 	}
 
 	var sb strings.Builder
@@ -216,15 +439,26 @@ func (t *TelegramNotifier) NotifyBillingSummary(summary *aliyun.BillingSummary)
 	if summary.AccountLabel != "" {
 		accountTitle = fmt.Sprintf(" [%s]", summary.AccountLabel)
 	}
-	sb.WriteString(fmt.Sprintf("📊 <b>扣费汇总%s</b> (%s)\n", accountTitle, summary.BillingCycle))
+	sb.WriteString(t.tr.T("billing.header", accountTitle, summary.BillingCycle) + "\n")
 	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
 
 	// Statistics section
-	sb.WriteString(fmt.Sprintf("📅 统计区间: %s 01日 ~ %s\n",
-		summary.BillingCycle,
-		summary.EndTime.Format("02日 15:04")))
-	sb.WriteString(fmt.Sprintf("⏱ 已过天数: %d 天\n", summary.ElapsedDays))
-	sb.WriteString(fmt.Sprintf("🕐 总运行时长: %.1f 小时\n", summary.TotalRunningHours))
+	if summary.WindowHours > 0 {
+		sb.WriteString(t.tr.T("billing.window_range",
+			formatTime(summary.StartTime, "01-02 15:04"),
+			formatTime(summary.EndTime, "01-02 15:04")) + "\n")
+		sb.WriteString(t.tr.T("billing.window_days", summary.ElapsedDays) + "\n")
+	} else {
+		sb.WriteString(t.tr.T("billing.month_range",
+			summary.BillingCycle,
+			formatTime(summary.EndTime, t.tr.T("layout.day_time"))) + "\n")
+		sb.WriteString(t.tr.T("billing.elapsed_days", summary.ElapsedDays) + "\n")
+	}
+	sb.WriteString(t.tr.T("billing.running_hours", summary.TotalRunningHours) + "\n")
+	if len(summary.DailyTotals) > 0 {
+		sb.WriteString(t.tr.T("billing.daily_trend", len(summary.DailyTotals),
+			format.FormatSparkline(summary.DailyTotals, len(summary.DailyTotals)), summary.DailyTotals[len(summary.DailyTotals)-1]) + "\n")
+	}
 	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 
 	for _, inst := range summary.Instances {
@@ -247,140 +481,249 @@ func (t *TelegramNotifier) NotifyBillingSummary(summary *aliyun.BillingSummary)
 
 		// Instance subtotal with hourly cost
 		if inst.RunningHours > 0 && inst.HourlyCost > 0 {
-			sb.WriteString(fmt.Sprintf("   <b>小计: ¥%.4f</b> (%.1fh, ¥%.4f/h)\n\n", inst.TotalAmount, inst.RunningHours, inst.HourlyCost))
+			sb.WriteString(t.tr.T("billing.subtotal_hourly", inst.TotalAmount, inst.RunningHours, inst.HourlyCost) + "\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("   <b>小计: ¥%.4f</b>\n\n", inst.TotalAmount))
+			sb.WriteString(t.tr.T("billing.subtotal", inst.TotalAmount) + "\n")
+		}
+		if inst.OnDemandHourly > 0 {
+			sb.WriteString(t.tr.T("billing.saved", inst.Savings, inst.DiscountPercent()) + "\n")
 		}
+		sb.WriteString("\n")
 	}
 
 	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	sb.WriteString(fmt.Sprintf("💰 <b>本月累计: ¥%.4f</b>\n", summary.TotalAmount))
-	sb.WriteString(fmt.Sprintf("📈 <b>月度估算: ¥%.2f</b>\n", summary.MonthlyEstimate))
+	if summary.WindowHours > 0 {
+		sb.WriteString(t.tr.T("billing.total", t.tr.T("billing.total_window"), summary.TotalAmount) + "\n")
+	} else {
+		sb.WriteString(t.tr.T("billing.total", t.tr.T("billing.total_month"), summary.TotalAmount) + "\n")
+	}
+	sb.WriteString(t.tr.T("billing.estimate", summary.MonthlyEstimate) + "\n")
+	if summary.TotalSavings != 0 {
+		sb.WriteString(t.tr.T("billing.savings", summary.TotalSavings) + "\n")
+	}
+	if summary.RollingDays > 0 {
+		sb.WriteString(t.tr.T("billing.rolling_average", summary.RollingDays, summary.RollingDailyAverage) + "\n")
+		sb.WriteString(t.tr.T("billing.projection", summary.MonthEndProjection, summary.RollingDays) + "\n")
+	}
 
 	// Show calculation method
 	if summary.EstimateMethod != "" {
 		sb.WriteString(fmt.Sprintf("📝 <i>%s</i>", summary.EstimateMethod))
 	}
 
-	return t.Send(sb.String())
+	return t.sendEvent(sb.String(), EventBilling)
 }
 
-// NotifyTrafficSummary sends a traffic summary notification
-func (t *TelegramNotifier) NotifyTrafficSummary(summary *aliyun.TrafficSummary) error {
-	if summary == nil {
-		message := `📶 <b>流量统计</b>
-━━━━━━━━
+// NotifyCostAnomaly sends a notification when an instance's daily cost is far above its baseline
+func (t *TelegramNotifier) NotifyCostAnomaly(accountLabel, instanceID, instanceName, region, day string, cost, baseline float64, baselineDays int) error {
+	accountTitle := ""
+	if accountLabel != "" {
+		accountTitle = fmt.Sprintf(" [%s]", accountLabel)
+	}
 
-暂无流量数据
+	message := t.tr.T("billing.cost_anomaly",
+		accountTitle, instanceID, instanceName, region, day, cost, baselineDays, baseline, cost/baseline)
 
-━━━━━━━━━━━━━━━━`
-		return t.Send(message)
+	return t.sendEvent(message, EventBilling)
+}
+
+// writeProductDetails writes the per-product traffic lines of a traffic scope
+func (t *TelegramNotifier) writeProductDetails(sb *strings.Builder, details map[string]int64) {
+	if len(details) == 0 {
+		return
 	}
+	sb.WriteString(t.tr.T("traffic.products") + "\n")
+	for product, traffic := range details {
+		if traffic > 0 {
+			sb.WriteString(fmt.Sprintf("      • %s: %s\n", product, aliyun.FormatTrafficSize(traffic)))
+		}
+	}
+}
 
-	var sb strings.Builder
+// writeNonChinaRegionDetails writes the traffic of each region outside the Chinese mainland
+func (t *TelegramNotifier) writeNonChinaRegionDetails(sb *strings.Builder, summary *aliyun.TrafficSummary) {
+	if len(summary.RegionDetails) == 0 {
+		return
+	}
+	sb.WriteString(t.tr.T("traffic.region_details") + "\n")
+	for _, detail := range summary.RegionDetails {
+		if !aliyun.IsChinaMainlandRegion(detail.BusinessRegionId) && detail.Traffic > 0 {
+			regionName := aliyun.GetRegionDisplayName(detail.BusinessRegionId)
+			sb.WriteString(fmt.Sprintf("      • %s: %s\n", regionName, aliyun.FormatTrafficSize(detail.Traffic)))
+		}
+	}
+}
+
+// writeTrafficHeader writes the title and statistics period of a traffic summary
+func (t *TelegramNotifier) writeTrafficHeader(sb *strings.Builder, summary *aliyun.TrafficSummary) {
 	accountTitle := ""
 	if summary.AccountLabel != "" {
 		accountTitle = fmt.Sprintf(" [%s]", summary.AccountLabel)
 	}
-	sb.WriteString(fmt.Sprintf("📶 <b>流量统计%s</b> (%s)\n", accountTitle, summary.BillingCycle))
+	sb.WriteString(t.tr.T("traffic.header", accountTitle, summary.BillingCycle) + "\n")
 	sb.WriteString("━━━━━━━━━━━━━━━━\n")
 
 	// Statistics section
-	sb.WriteString(fmt.Sprintf("📅 统计区间: %s 01日 ~ %s\n",
+	sb.WriteString(t.tr.T("traffic.range",
 		summary.BillingCycle,
-		summary.EndTime.Format("02日 15:04")))
+		formatTime(summary.EndTime, t.tr.T("layout.day_time"))) + "\n")
 	sb.WriteString("━━━━━━━━━━━━━━━━\n\n")
+}
+
+// writeTrafficTotal writes the monthly total and the China / non-China share of a traffic summary
+func (t *TelegramNotifier) writeTrafficTotal(sb *strings.Builder, summary *aliyun.TrafficSummary) {
+	sb.WriteString("━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(t.tr.T("traffic.month_total", aliyun.FormatTrafficSize(summary.TotalTraffic)) + "\n")
+
+	// Show percentage breakdown
+	if summary.TotalTraffic > 0 {
+		chinaPercent := float64(summary.ChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
+		nonChinaPercent := float64(summary.NonChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
+		sb.WriteString(t.tr.T("traffic.share", chinaPercent, nonChinaPercent))
+	}
+}
+
+// NotifyTrafficSummary sends a traffic summary notification
+func (t *TelegramNotifier) NotifyTrafficSummary(summary *aliyun.TrafficSummary) error {
+	if summary == nil {
+		return t.sendEvent(t.tr.T("traffic.empty"), EventTraffic)
+	}
+
+	var sb strings.Builder
+	t.writeTrafficHeader(&sb, summary)
 
 	// China Mainland section
-	sb.WriteString("🇨🇳 <b>中国大陆</b>\n")
+	sb.WriteString(t.tr.T("traffic.china") + "\n")
 	if summary.ChinaMainland.Traffic > 0 {
-		sb.WriteString(fmt.Sprintf("   📊 总流量: <b>%s</b>\n", aliyun.FormatTrafficSize(summary.ChinaMainland.Traffic)))
-		sb.WriteString(fmt.Sprintf("   🌐 区域数: %d\n", summary.ChinaMainland.RegionCount))
-		// Product details
-		if len(summary.ChinaMainland.ProductDetails) > 0 {
-			sb.WriteString("   📦 产品明细:\n")
-			for product, traffic := range summary.ChinaMainland.ProductDetails {
-				if traffic > 0 {
-					sb.WriteString(fmt.Sprintf("      • %s: %s\n", product, aliyun.FormatTrafficSize(traffic)))
-				}
-			}
-		}
+		sb.WriteString(t.tr.T("traffic.total", aliyun.FormatTrafficSize(summary.ChinaMainland.Traffic)) + "\n")
+		sb.WriteString(t.tr.T("traffic.region_count", summary.ChinaMainland.RegionCount) + "\n")
+		t.writeProductDetails(&sb, summary.ChinaMainland.ProductDetails)
 		// Region list
 		if len(summary.ChinaMainland.Regions) > 0 {
-			sb.WriteString("   📍 区域列表:\n")
+			sb.WriteString(t.tr.T("traffic.regions") + "\n")
 			for _, region := range summary.ChinaMainland.Regions {
 				regionName := aliyun.GetRegionDisplayName(region)
 				sb.WriteString(fmt.Sprintf("      • %s\n", regionName))
 			}
 		}
 	} else {
-		sb.WriteString("   暂无流量\n")
+		sb.WriteString(t.tr.T("traffic.none") + "\n")
 	}
 	sb.WriteString("\n")
 
 	// Non-China Mainland section
-	sb.WriteString("🌏 <b>非中国大陆</b>\n")
+	sb.WriteString(t.tr.T("traffic.non_china") + "\n")
 	if summary.NonChinaMainland.Traffic > 0 {
-		sb.WriteString(fmt.Sprintf("   📊 总流量: <b>%s</b>\n", aliyun.FormatTrafficSize(summary.NonChinaMainland.Traffic)))
-		sb.WriteString(fmt.Sprintf("   🌐 区域数: %d\n", summary.NonChinaMainland.RegionCount))
-		// Product details
-		if len(summary.NonChinaMainland.ProductDetails) > 0 {
-			sb.WriteString("   📦 产品明细:\n")
-			for product, traffic := range summary.NonChinaMainland.ProductDetails {
-				if traffic > 0 {
-					sb.WriteString(fmt.Sprintf("      • %s: %s\n", product, aliyun.FormatTrafficSize(traffic)))
-				}
-			}
-		}
-		// Region list with traffic details
-		if len(summary.RegionDetails) > 0 {
-			sb.WriteString("   📍 区域明细:\n")
-			for _, detail := range summary.RegionDetails {
-				if !aliyun.IsChinaMainlandRegion(detail.BusinessRegionId) && detail.Traffic > 0 {
-					regionName := aliyun.GetRegionDisplayName(detail.BusinessRegionId)
-					sb.WriteString(fmt.Sprintf("      • %s: %s\n", regionName, aliyun.FormatTrafficSize(detail.Traffic)))
-				}
-			}
-		}
+		sb.WriteString(t.tr.T("traffic.total", aliyun.FormatTrafficSize(summary.NonChinaMainland.Traffic)) + "\n")
+		sb.WriteString(t.tr.T("traffic.region_count", summary.NonChinaMainland.RegionCount) + "\n")
+		t.writeProductDetails(&sb, summary.NonChinaMainland.ProductDetails)
+		t.writeNonChinaRegionDetails(&sb, summary)
 	} else {
-		sb.WriteString("   暂无流量\n")
+		sb.WriteString(t.tr.T("traffic.none") + "\n")
 	}
 	sb.WriteString("\n")
 
+	t.writeTrafficTotal(&sb, summary)
+
+	return t.sendEvent(sb.String(), EventTraffic)
+}
+
+// NotifyNetworkStats sends per-instance network bandwidth statistics
+func (t *TelegramNotifier) NotifyNetworkStats(instanceName string, stats *aliyun.NetworkStats) error {
+	var sb strings.Builder
+	sb.WriteString(t.tr.T("network.header", stats.Hours) + "\n")
 	sb.WriteString("━━━━━━━━━━━━━━━━\n")
-	sb.WriteString(fmt.Sprintf("📈 <b>本月总流量: %s</b>\n", aliyun.FormatTrafficSize(summary.TotalTraffic)))
+	sb.WriteString(t.tr.T("network.instance", instanceName) + "\n")
+	sb.WriteString(fmt.Sprintf("ID: <code>%s</code>\n", stats.InstanceID))
+	sb.WriteString(t.tr.T("network.region", aliyun.GetRegionDisplayName(stats.RegionID)) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━\n\n")
 
-	// Show percentage breakdown
-	if summary.TotalTraffic > 0 {
-		chinaPercent := float64(summary.ChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
-		nonChinaPercent := float64(summary.NonChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
-		sb.WriteString(fmt.Sprintf("📊 中国大陆: %.1f%% | 非中国大陆: %.1f%%", chinaPercent, nonChinaPercent))
-	}
+	sb.WriteString(t.tr.T("network.internet") + "\n")
+	sb.WriteString(t.tr.T("network.in",
+		aliyun.FormatBitRate(stats.InternetIn.AvgBps), aliyun.FormatBitRate(stats.InternetIn.PeakBps)) + "\n")
+	sb.WriteString(t.tr.T("network.out",
+		aliyun.FormatBitRate(stats.InternetOut.AvgBps), aliyun.FormatBitRate(stats.InternetOut.PeakBps)) + "\n\n")
+
+	sb.WriteString(t.tr.T("network.intranet") + "\n")
+	sb.WriteString(t.tr.T("network.in",
+		aliyun.FormatBitRate(stats.IntranetIn.AvgBps), aliyun.FormatBitRate(stats.IntranetIn.PeakBps)) + "\n")
+	sb.WriteString(t.tr.T("network.out",
+		aliyun.FormatBitRate(stats.IntranetOut.AvgBps), aliyun.FormatBitRate(stats.IntranetOut.PeakBps)) + "\n")
+
+	sb.WriteString("━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(t.tr.T("network.source"))
 
 	return t.Send(sb.String())
 }
 
+// trafficScopeLabel returns the display name of a traffic limit scope
+func trafficScopeLabel(scope string) string {
+	switch scope {
+	case aliyun.TrafficScopeChina:
+		return "🇨🇳 中国大陆"
+	case aliyun.TrafficScopeNonChina:
+		return "🌏 非中国大陆"
+	default:
+		return "📍 " + aliyun.GetRegionDisplayName(scope)
+	}
+}
+
+// scopeLabel returns the display name of a traffic limit scope in the notifier's language
+func (t *TelegramNotifier) scopeLabel(scope string) string {
+	switch scope {
+	case aliyun.TrafficScopeChina:
+		return t.tr.T("traffic.scope.china")
+	case aliyun.TrafficScopeNonChina:
+		return t.tr.T("traffic.scope.non_china")
+	default:
+		return trafficScopeLabel(scope)
+	}
+}
+
+// NotifyTrafficWarning sends a notification when traffic crosses a warning threshold.
+// daysLeft < 0 means the remaining days could not be estimated.
+func (t *TelegramNotifier) NotifyTrafficWarning(accountLabel, region string, trafficGB, limitGB, percent, daysLeft float64) error {
+	regionLabel := t.scopeLabel(region)
+
+	var sb strings.Builder
+	accountTitle := ""
+	if accountLabel != "" {
+		accountTitle = fmt.Sprintf(" [%s]", accountLabel)
+	}
+	sb.WriteString(t.tr.T("traffic.warning.header", accountTitle) + "\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	sb.WriteString(t.tr.T("traffic.warning.region", regionLabel) + "\n")
+	sb.WriteString(t.tr.T("traffic.warning.usage", trafficGB, limitGB) + "\n")
+	sb.WriteString(t.tr.T("traffic.warning.percent", percent) + "\n")
+	if daysLeft >= 0 {
+		sb.WriteString(t.tr.T("traffic.warning.days_left", daysLeft) + "\n")
+	}
+	sb.WriteString(t.tr.T("traffic.warning.time", formatTime(time.Now(), "2006-01-02 15:04:05")) + "\n\n")
+
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString(t.tr.T("traffic.warning.note"))
+
+	return t.sendEvent(sb.String(), EventTraffic)
+}
+
 // NotifyTrafficShutdown sends a notification when instances are stopped due to traffic limit
 func (t *TelegramNotifier) NotifyTrafficShutdown(accountLabel, region string, trafficGB, limitGB float64, stoppedInstances []string) error {
-	regionLabel := "🇨🇳 中国大陆"
-	if region == "non-china" {
-		regionLabel = "🌏 非中国大陆"
-	}
+	regionLabel := t.scopeLabel(region)
 
 	var sb strings.Builder
 	accountTitle := ""
 	if accountLabel != "" {
 		accountTitle = fmt.Sprintf(" [%s]", accountLabel)
 	}
-	sb.WriteString(fmt.Sprintf("🚨 <b>流量超额自动关机%s</b>\n", accountTitle))
+	sb.WriteString(t.tr.T("traffic.shutdown.header", accountTitle) + "\n")
 	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
-	sb.WriteString(fmt.Sprintf("📍 区域: %s\n", regionLabel))
-	sb.WriteString(fmt.Sprintf("📊 当前流量: <b>%.2f GB</b>\n", trafficGB))
-	sb.WriteString(fmt.Sprintf("🚫 流量阈值: %.2f GB\n", limitGB))
-	sb.WriteString(fmt.Sprintf("⏰ 时间: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	sb.WriteString(t.tr.T("traffic.warning.region", regionLabel) + "\n")
+	sb.WriteString(t.tr.T("traffic.shutdown.usage", trafficGB) + "\n")
+	sb.WriteString(t.tr.T("traffic.shutdown.limit", limitGB) + "\n")
+	sb.WriteString(t.tr.T("traffic.warning.time", formatTime(time.Now(), "2006-01-02 15:04:05")) + "\n\n")
 
 	if len(stoppedInstances) > 0 {
-		sb.WriteString("🔴 <b>已关闭实例:</b>\n")
+		sb.WriteString(t.tr.T("traffic.shutdown.stopped") + "\n")
 		for _, inst := range stoppedInstances {
 			sb.WriteString(fmt.Sprintf("   • %s\n", inst))
 		}
@@ -388,107 +731,85 @@ func (t *TelegramNotifier) NotifyTrafficShutdown(accountLabel, region string, tr
 	}
 
 	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	sb.WriteString("💡 <i>使用节省停机模式，不再计费 vCPU/内存</i>\n")
-	sb.WriteString("⚠️ <i>自动重启已暂停，新月流量重置后恢复</i>")
+	sb.WriteString(t.tr.T("traffic.shutdown.note"))
 
-	return t.Send(sb.String())
+	return t.sendEvent(sb.String(), EventTraffic)
 }
 
-// NotifyTrafficSummaryWithLimits sends a traffic summary with threshold info
-func (t *TelegramNotifier) NotifyTrafficSummaryWithLimits(summary *aliyun.TrafficSummary, chinaLimitGB, nonChinaLimitGB float64, chinaShutdown, nonChinaShutdown bool) error {
+// NotifyTrafficSummaryWithLimits sends a traffic summary with threshold info.
+// limits and shutdown are keyed by limit scope (see aliyun.TrafficScope).
+func (t *TelegramNotifier) NotifyTrafficSummaryWithLimits(summary *aliyun.TrafficSummary, limits map[string]float64, shutdown map[string]bool) error {
 	if summary == nil {
-		message := `📶 <b>流量统计</b>
-━━━━━━━━
-
-暂无流量数据
-
-━━━━━━━━━━━━━━━━`
-		return t.Send(message)
+		return t.sendEvent(t.tr.T("traffic.empty"), EventTraffic)
 	}
 
-	var sb strings.Builder
-	accountTitle := ""
-	if summary.AccountLabel != "" {
-		accountTitle = fmt.Sprintf(" [%s]", summary.AccountLabel)
-	}
-	sb.WriteString(fmt.Sprintf("📶 <b>流量统计%s</b> (%s)\n", accountTitle, summary.BillingCycle))
-	sb.WriteString("━━━━━━━━━━━━━━━━\n")
+	usage := summary.ScopeTrafficGB(limits)
+	chinaLimitGB, nonChinaLimitGB := limits[aliyun.TrafficScopeChina], limits[aliyun.TrafficScopeNonChina]
+	chinaShutdown, nonChinaShutdown := shutdown[aliyun.TrafficScopeChina], shutdown[aliyun.TrafficScopeNonChina]
 
-	// Statistics section
-	sb.WriteString(fmt.Sprintf("📅 统计区间: %s 01日 ~ %s\n",
-		summary.BillingCycle,
-		summary.EndTime.Format("02日 15:04")))
-	sb.WriteString("━━━━━━━━━━━━━━━━\n\n")
+	var sb strings.Builder
+	t.writeTrafficHeader(&sb, summary)
 
 	// China Mainland section
-	sb.WriteString("🇨🇳 <b>中国大陆</b>\n")
+	sb.WriteString(t.tr.T("traffic.china") + "\n")
 	if summary.ChinaMainland.Traffic > 0 {
-		sb.WriteString(fmt.Sprintf("   📊 总流量: <b>%s</b> / %.0f GB\n", aliyun.FormatTrafficSize(summary.ChinaMainland.Traffic), chinaLimitGB))
-		remainChina := chinaLimitGB - summary.ChinaMainland.TrafficGB
+		sb.WriteString(t.tr.T("traffic.total_limit", aliyun.FormatTrafficSize(summary.ChinaMainland.Traffic), chinaLimitGB) + "\n")
+		remainChina := chinaLimitGB - usage[aliyun.TrafficScopeChina]
 		if remainChina < 0 {
 			remainChina = 0
 		}
-		sb.WriteString(fmt.Sprintf("   📉 剩余额度: %.2f GB\n", remainChina))
+		sb.WriteString(t.tr.T("traffic.remaining", remainChina) + "\n")
 		if chinaShutdown {
-			sb.WriteString("   🔴 <b>已超额关机</b>\n")
-		}
-		sb.WriteString(fmt.Sprintf("   🌐 区域数: %d\n", summary.ChinaMainland.RegionCount))
-		if len(summary.ChinaMainland.ProductDetails) > 0 {
-			sb.WriteString("   📦 产品明细:\n")
-			for product, traffic := range summary.ChinaMainland.ProductDetails {
-				if traffic > 0 {
-					sb.WriteString(fmt.Sprintf("      • %s: %s\n", product, aliyun.FormatTrafficSize(traffic)))
-				}
-			}
+			sb.WriteString(t.tr.T("traffic.over_limit") + "\n")
 		}
+		sb.WriteString(t.tr.T("traffic.region_count", summary.ChinaMainland.RegionCount) + "\n")
+		t.writeProductDetails(&sb, summary.ChinaMainland.ProductDetails)
 	} else {
-		sb.WriteString(fmt.Sprintf("   暂无流量 (阈值: %.0f GB)\n", chinaLimitGB))
+		sb.WriteString(t.tr.T("traffic.none_limit", chinaLimitGB) + "\n")
 	}
 	sb.WriteString("\n")
 
 	// Non-China Mainland section
-	sb.WriteString("🌏 <b>非中国大陆</b>\n")
+	sb.WriteString(t.tr.T("traffic.non_china") + "\n")
 	if summary.NonChinaMainland.Traffic > 0 {
-		sb.WriteString(fmt.Sprintf("   📊 总流量: <b>%s</b> / %.0f GB\n", aliyun.FormatTrafficSize(summary.NonChinaMainland.Traffic), nonChinaLimitGB))
-		remainNonChina := nonChinaLimitGB - summary.NonChinaMainland.TrafficGB
+		sb.WriteString(t.tr.T("traffic.total_limit", aliyun.FormatTrafficSize(summary.NonChinaMainland.Traffic), nonChinaLimitGB) + "\n")
+		remainNonChina := nonChinaLimitGB - usage[aliyun.TrafficScopeNonChina]
 		if remainNonChina < 0 {
 			remainNonChina = 0
 		}
-		sb.WriteString(fmt.Sprintf("   📉 剩余额度: %.2f GB\n", remainNonChina))
+		sb.WriteString(t.tr.T("traffic.remaining", remainNonChina) + "\n")
 		if nonChinaShutdown {
-			sb.WriteString("   🔴 <b>已超额关机</b>\n")
-		}
-		sb.WriteString(fmt.Sprintf("   🌐 区域数: %d\n", summary.NonChinaMainland.RegionCount))
-		if len(summary.NonChinaMainland.ProductDetails) > 0 {
-			sb.WriteString("   📦 产品明细:\n")
-			for product, traffic := range summary.NonChinaMainland.ProductDetails {
-				if traffic > 0 {
-					sb.WriteString(fmt.Sprintf("      • %s: %s\n", product, aliyun.FormatTrafficSize(traffic)))
-				}
-			}
-		}
-		if len(summary.RegionDetails) > 0 {
-			sb.WriteString("   📍 区域明细:\n")
-			for _, detail := range summary.RegionDetails {
-				if !aliyun.IsChinaMainlandRegion(detail.BusinessRegionId) && detail.Traffic > 0 {
-					regionName := aliyun.GetRegionDisplayName(detail.BusinessRegionId)
-					sb.WriteString(fmt.Sprintf("      • %s: %s\n", regionName, aliyun.FormatTrafficSize(detail.Traffic)))
-				}
-			}
+			sb.WriteString(t.tr.T("traffic.over_limit") + "\n")
 		}
+		sb.WriteString(t.tr.T("traffic.region_count", summary.NonChinaMainland.RegionCount) + "\n")
+		t.writeProductDetails(&sb, summary.NonChinaMainland.ProductDetails)
+		t.writeNonChinaRegionDetails(&sb, summary)
 	} else {
-		sb.WriteString(fmt.Sprintf("   暂无流量 (阈值: %.0f GB)\n", nonChinaLimitGB))
+		sb.WriteString(t.tr.T("traffic.none_limit", nonChinaLimitGB) + "\n")
 	}
 	sb.WriteString("\n")
 
-	sb.WriteString("━━━━━━━━━━━━━━━━\n")
-	sb.WriteString(fmt.Sprintf("📈 <b>本月总流量: %s</b>\n", aliyun.FormatTrafficSize(summary.TotalTraffic)))
-
-	if summary.TotalTraffic > 0 {
-		chinaPercent := float64(summary.ChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
-		nonChinaPercent := float64(summary.NonChinaMainland.Traffic) / float64(summary.TotalTraffic) * 100
-		sb.WriteString(fmt.Sprintf("📊 中国大陆: %.1f%% | 非中国大陆: %.1f%%", chinaPercent, nonChinaPercent))
+	// Regions with their own limit, not counted towards the China / non-China limits
+	var regionScopes []string
+	for scope := range limits {
+		if scope != aliyun.TrafficScopeChina && scope != aliyun.TrafficScopeNonChina {
+			regionScopes = append(regionScopes, scope)
+		}
+	}
+	sort.Strings(regionScopes)
+	if len(regionScopes) > 0 {
+		sb.WriteString(t.tr.T("traffic.region_limits") + "\n")
+		for _, scope := range regionScopes {
+			line := fmt.Sprintf("   • %s: %.2f GB / %.0f GB", aliyun.GetRegionDisplayName(scope), usage[scope], limits[scope])
+			if shutdown[scope] {
+				line += t.tr.T("traffic.region_over_limit")
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString(t.tr.T("traffic.region_limits_note") + "\n\n")
 	}
 
-	return t.Send(sb.String())
+	t.writeTrafficTotal(&sb, summary)
+
+	return t.sendEvent(sb.String(), EventTraffic)
 }
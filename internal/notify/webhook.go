@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WebhookPath is the path the webhook server receives Telegram updates on
+const WebhookPath = "/telegram/webhook"
+
+// telegramAPIResponse is the generic envelope of Telegram Bot API responses
+type telegramAPIResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// GenerateWebhookSecret returns a random secret token for webhook validation
+func GenerateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// WebhookURL returns the full webhook URL for a public base URL
+func WebhookURL(baseURL string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if strings.HasSuffix(baseURL, WebhookPath) {
+		return baseURL
+	}
+	return baseURL + WebhookPath
+}
+
+// SetWebhook registers the webhook URL with Telegram.
+// Telegram sends secret in the X-Telegram-Bot-Api-Secret-Token header of every update.
+func (b *BotHandler) SetWebhook(webhookURL, secret string) error {
+	payload := struct {
+		URL            string   `json:"url"`
+		SecretToken    string   `json:"secret_token,omitempty"`
+		AllowedUpdates []string `json:"allowed_updates"`
+	}{
+		URL:            webhookURL,
+		SecretToken:    secret,
+		AllowedUpdates: []string{"message", "callback_query"},
+	}
+
+	if err := b.callAPI("setWebhook", payload); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	log.Infof("Registered Telegram webhook: %s", webhookURL)
+	return nil
+}
+
+// DeleteWebhook removes a previously registered webhook so getUpdates polling works again
+func (b *BotHandler) DeleteWebhook() error {
+	if err := b.callAPI("deleteWebhook", struct{}{}); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// callAPI posts a JSON payload to a Telegram Bot API method and checks the ok flag
+func (b *BotHandler) callAPI(method string, payload interface{}) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/%s", b.botToken, method)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	resp, err := b.client.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var apiResp telegramAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("telegram API error: %s", apiResp.Description)
+	}
+
+	return nil
+}
+
+// ServeWebhook receives updates on POST /telegram/webhook until ctx is cancelled.
+// TLS is served directly when certFile and keyFile are set, otherwise plain HTTP
+// (for use behind a TLS-terminating reverse proxy).
+func (b *BotHandler) ServeWebhook(ctx context.Context, port int, secret, certFile, keyFile string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(WebhookPath, func(w http.ResponseWriter, r *http.Request) {
+		b.handleWebhookRequest(w, r, secret)
+	})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Infof("Telegram webhook server listening on :%d%s", port, WebhookPath)
+		var err error
+		if certFile != "" && keyFile != "" {
+			err = server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("webhook server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down webhook server: %w", err)
+	}
+
+	log.Info("Telegram webhook server stopped")
+	return nil
+}
+
+// handleWebhookRequest validates and processes a single webhook delivery
+func (b *BotHandler) handleWebhookRequest(w http.ResponseWriter, r *http.Request, secret string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		log.Warnf("Rejected webhook request from %s: invalid secret token", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update TelegramUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&update); err != nil {
+		log.Warnf("Failed to decode webhook update: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	b.handleUpdate(update)
+	w.WriteHeader(http.StatusOK)
+}
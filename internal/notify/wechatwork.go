@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// weChatWorkWebhookURL is the group bot endpoint, the bot key is passed as ?key=
+	weChatWorkWebhookURL = "https://qyapi.weixin.qq.com/cgi-bin/webhook/send"
+	// maxWeChatWorkContent is the largest markdown content in bytes a group bot accepts
+	maxWeChatWorkContent = 4096
+	// weChatWorkTimeout is the request timeout of a group bot call
+	weChatWorkTimeout = 10 * time.Second
+	// weChatWorkRetryDelay is the wait before the single retry of a failed message
+	weChatWorkRetryDelay = 2 * time.Second
+)
+
+// weChatWorkColors are the <font> colors of message titles by level
+var weChatWorkColors = map[messageLevel]string{
+	levelInfo:    "comment",
+	levelSuccess: "info",
+	levelWarning: "warning",
+	levelAlert:   "warning",
+}
+
+// WeChatWorkNotifier sends notifications as markdown messages to a WeChat Work (企业微信) group bot
+type WeChatWorkNotifier struct {
+	richNotifier
+	webhookURL string
+	client     *http.Client
+}
+
+// NewWeChatWorkNotifier creates a new WeChat Work notifier for the group bot with the given key
+func NewWeChatWorkNotifier(key string) *WeChatWorkNotifier {
+	client := NewHTTPClient()
+	client.Timeout = weChatWorkTimeout
+
+	w := &WeChatWorkNotifier{
+		webhookURL: weChatWorkWebhookURL + "?key=" + url.QueryEscape(key),
+		client:     client,
+	}
+	w.richNotifier = richNotifier{post: w.sendMarkdown, fromHTML: htmlToMarkdown}
+	return w
+}
+
+// Flush is a no-op, WeChat Work notifications are never held back
+func (w *WeChatWorkNotifier) Flush() {}
+
+// weChatWorkMessage is the body of a group bot call
+type weChatWorkMessage struct {
+	MsgType  string             `json:"msgtype"`
+	Markdown weChatWorkMarkdown `json:"markdown"`
+}
+
+type weChatWorkMarkdown struct {
+	Content string `json:"content"`
+}
+
+// weChatWorkResponse is the reply of a group bot call; errcode 0 means success
+type weChatWorkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// sendMarkdown posts a message as markdown: the title colored by level, the description,
+// one line per field, the footer as a quote and a timestamp. A failed message is retried once.
+func (w *WeChatWorkNotifier) sendMarkdown(msg richMessage) error {
+	var sb strings.Builder
+	if msg.Title != "" {
+		sb.WriteString(fmt.Sprintf("### <font color=\"%s\">%s</font>\n", weChatWorkColors[msg.Level], msg.Title))
+	}
+	if msg.Description != "" {
+		sb.WriteString(msg.Description + "\n")
+	}
+	for _, f := range msg.Fields {
+		if f.Inline {
+			sb.WriteString(fmt.Sprintf("**%s**: %s\n", f.Name, f.Value))
+		} else {
+			sb.WriteString(fmt.Sprintf("**%s**\n%s\n", f.Name, f.Value))
+		}
+	}
+	if msg.Footer != "" {
+		sb.WriteString("> " + msg.Footer + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("<font color=\"comment\">🕐 %s</font>", formatTime(time.Now(), "2006-01-02 15:04:05")))
+
+	body, err := json.Marshal(weChatWorkMessage{
+		MsgType:  "markdown",
+		Markdown: weChatWorkMarkdown{Content: truncateBytes(sb.String(), maxWeChatWorkContent)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if err := w.post(body); err != nil {
+		log.Warnf("WeChat Work message failed, retrying in %s: %v", weChatWorkRetryDelay, err)
+		time.Sleep(weChatWorkRetryDelay)
+		return w.post(body)
+	}
+	return nil
+}
+
+// post sends a message body once
+func (w *WeChatWorkNotifier) post(body []byte) error {
+	resp, err := w.client.Post(w.webhookURL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wechat work webhook returned status %d", resp.StatusCode)
+	}
+	// Errors such as an invalid key or rate limiting come back as HTTP 200 with an errcode
+	var result weChatWorkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode wechat work response: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("wechat work webhook returned error %d: %s", result.ErrCode, result.ErrMsg)
+	}
+
+	return nil
+}
+
+// truncateBytes shortens s to at most max bytes without splitting a character, ending it
+// with "…" when truncated
+func truncateBytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	const ellipsis = "…"
+	cut := max - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
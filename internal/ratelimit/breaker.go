@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Allow while the circuit is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets all requests through
+	StateClosed State = iota
+	// StateOpen rejects all requests until the timeout expires
+	StateOpen
+	// StateHalfOpen lets a single probe request through
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops calls to a failing endpoint. After threshold consecutive failures
+// it opens and rejects calls for timeout; then a single probe is allowed (half-open),
+// whose success closes the circuit again and whose failure reopens it.
+type CircuitBreaker struct {
+	threshold int
+	timeout   time.Duration
+	onChange  func(from, to State)
+
+	state    State
+	failures int       // consecutive failures while closed
+	openedAt time.Time // when the circuit last opened
+	probing  bool      // a half-open probe is in flight
+	mu       sync.Mutex
+}
+
+// NewCircuitBreaker creates a closed circuit breaker. onChange, if not nil, is called
+// (outside the breaker's lock) on every state transition.
+func NewCircuitBreaker(threshold int, timeout time.Duration, onChange func(from, to State)) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		timeout:   timeout,
+		onChange:  onChange,
+	}
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow reports whether a call may proceed, returning ErrCircuitOpen if not.
+// Every allowed call must be followed by Record, or Release if it was abandoned.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.timeout {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+	case StateHalfOpen:
+		if b.probing {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return nil
+}
+
+// Record reports the outcome of an allowed call
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	from := b.state
+	switch {
+	case success:
+		b.state = StateClosed
+		b.failures = 0
+	case b.state == StateHalfOpen:
+		b.state = StateOpen
+		b.openedAt = time.Now()
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state = StateOpen
+			b.openedAt = time.Now()
+			b.failures = 0
+		}
+	}
+	b.probing = false
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// Release ends an allowed call without an outcome, e.g. one cancelled by its caller.
+// A half-open breaker lets the next call probe instead.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *CircuitBreaker) notify(from, to State) {
+	if from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
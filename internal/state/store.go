@@ -0,0 +1,203 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// InstanceState holds per-instance statistics that must survive process restarts
+type InstanceState struct {
+	ReclaimCount        int       `json:"reclaim_count"`
+	LastReclaimAt       time.Time `json:"last_reclaim_at,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	NotifyCooldownUntil time.Time `json:"notify_cooldown_until,omitempty"`
+	LastReclaimNoticeAt time.Time `json:"last_reclaim_notice_at,omitempty"` // last EventBridge reclaim pre-warning
+
+	// Rapid reclaim tracking
+	RecentReclaims []time.Time `json:"recent_reclaims,omitempty"` // reclaim times within the detection window
+	PausedUntil    time.Time   `json:"paused_until,omitempty"`    // auto-start paused until this time
+
+	// Public IP after the last successful start, to detect an IP change across reclaims
+	PublicIP string `json:"public_ip,omitempty"`
+
+	// Traffic limit set via /setlimit, stored under a "traffic-limit:<scope>" key
+	TrafficLimitGB float64 `json:"traffic_limit_gb,omitempty"`
+
+	// Last new-month traffic reset, stored under the "traffic-reset" key
+	TrafficResetAt time.Time `json:"traffic_reset_at,omitempty"`
+
+	// Stopped by an operator via /stop; auto-start skips the instance until cleared
+	ManuallyStopped   bool      `json:"manually_stopped,omitempty"`
+	ManuallyStoppedAt time.Time `json:"manually_stopped_at,omitempty"`
+	ManuallyStoppedBy string    `json:"manually_stopped_by,omitempty"` // Telegram user who sent /stop
+
+	// Cost anomaly detection
+	DailyCosts          map[string]float64 `json:"daily_costs,omitempty"`            // YYYY-MM-DD -> cost in CNY
+	LastCostAnomalyDate string             `json:"last_cost_anomaly_date,omitempty"` // day of the last anomaly alert
+}
+
+// Backend persists JSON-encoded instance states by key, as an alternative to the state file
+type Backend interface {
+	LoadStates() (map[string][]byte, error)
+	SaveState(key string, data []byte) error
+}
+
+// Store is a small key-value store of instance state, backed by a JSON file or a Backend.
+// With an empty path and no backend it keeps state in memory only.
+type Store struct {
+	path      string
+	backend   Backend
+	instances map[string]*InstanceState // instance key -> state
+	mu        sync.Mutex
+}
+
+// fileFormat is the on-disk layout of the state file
+type fileFormat struct {
+	Instances map[string]*InstanceState `json:"instances"`
+}
+
+// Open loads the state file at path, starting empty if it does not exist yet
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		instances: make(map[string]*InstanceState),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	for key, st := range f.Instances {
+		if st != nil {
+			s.instances[key] = st
+		}
+	}
+
+	return s, nil
+}
+
+// OpenBackend loads all state from backend; updates are written to it one instance at a time
+func OpenBackend(backend Backend) (*Store, error) {
+	s := &Store{
+		backend:   backend,
+		instances: make(map[string]*InstanceState),
+	}
+
+	states, err := backend.LoadStates()
+	if err != nil {
+		return nil, err
+	}
+	for key, data := range states {
+		var st InstanceState
+		if err := json.Unmarshal(data, &st); err != nil {
+			return nil, fmt.Errorf("failed to parse state of %s: %w", key, err)
+		}
+		s.instances[key] = &st
+	}
+
+	return s, nil
+}
+
+// Len returns the number of instances with stored state
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.instances)
+}
+
+// Get returns a copy of the state for an instance (zero value if unknown)
+func (s *Store) Get(key string) InstanceState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.instances[key]; ok {
+		cp := *st
+		cp.RecentReclaims = append([]time.Time(nil), st.RecentReclaims...)
+		if st.DailyCosts != nil {
+			cp.DailyCosts = make(map[string]float64, len(st.DailyCosts))
+			for day, cost := range st.DailyCosts {
+				cp.DailyCosts[day] = cost
+			}
+		}
+		return cp
+	}
+	return InstanceState{}
+}
+
+// Update applies fn to the state of an instance and persists it
+func (s *Store) Update(key string, fn func(st *InstanceState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.instances[key]
+	if !ok {
+		st = &InstanceState{}
+		s.instances[key] = st
+	}
+	fn(st)
+
+	if s.backend != nil {
+		data, err := json.Marshal(st)
+		if err != nil {
+			return fmt.Errorf("failed to encode state of %s: %w", key, err)
+		}
+		return s.backend.SaveState(key, data)
+	}
+	return s.flushLocked()
+}
+
+// flushLocked atomically writes the store to disk (write to a temp file, then rename).
+// Caller must hold s.mu.
+func (s *Store) flushLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Instances: s.instances}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write temp state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to sync temp state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close temp state file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, s.path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace state file %s: %w", s.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,435 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go SQLite driver
+)
+
+// Incident event types
+const (
+	EventReclaimDetected = "reclaim_detected"
+	EventReclaimNotice   = "reclaim_notice"
+	EventStartAttempted  = "start_attempted"
+	EventStartSucceeded  = "start_succeeded"
+	EventStartFailed     = "start_failed"
+	EventTrafficLimitHit = "traffic_limit_hit"
+	EventTrafficShutdown = "traffic_shutdown"
+	// EventTrafficLimitChanged records a /setlimit change; InstanceName holds "old → new GB"
+	EventTrafficLimitChanged = "traffic_limit_changed"
+)
+
+// Incident is a single recorded event of an instance
+type Incident struct {
+	ID           int64
+	Time         time.Time
+	EventType    string
+	InstanceID   string
+	InstanceName string
+	Region       string
+	Duration     time.Duration // start events only
+	Error        string
+}
+
+// TrafficSnapshot is the internet traffic of an account on one day
+type TrafficSnapshot struct {
+	Day           string // YYYY-MM-DD in the configured timezone
+	AccountLabel  string
+	ChinaBytes    int64
+	NonChinaBytes int64
+}
+
+// StatusTransition is an observed change of an instance's status
+type StatusTransition struct {
+	InstanceKey string // instance ID (GCP: "gcp:zone/name")
+	Time        time.Time
+	Status      string
+}
+
+// Bot audit outcomes
+const (
+	AuditPending = "pending" // recorded before the command runs, kept if it never finishes
+	AuditSuccess = "success"
+	AuditError   = "error"
+)
+
+// AuditEntry is a bot command or callback query with the Telegram user who sent it
+type AuditEntry struct {
+	ID        int64
+	Time      time.Time
+	UserID    int64
+	Username  string
+	FirstName string
+	Command   string // command with arguments, or "callback:<data>"
+	ChatID    int64
+	Outcome   string
+	Error     string
+}
+
+// DB is the SQLite database holding incident history, instance state, daily traffic, status transitions,
+// the bot audit log and per-chat notification preferences
+type DB struct {
+	db *sql.DB
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS incidents (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts            INTEGER NOT NULL,
+	event_type    TEXT    NOT NULL,
+	instance_id   TEXT    NOT NULL DEFAULT '',
+	instance_name TEXT    NOT NULL DEFAULT '',
+	region        TEXT    NOT NULL DEFAULT '',
+	duration_ms   INTEGER NOT NULL DEFAULT 0,
+	error         TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_incidents_ts ON incidents (ts);
+CREATE INDEX IF NOT EXISTS idx_incidents_instance ON incidents (instance_id, ts);
+
+CREATE TABLE IF NOT EXISTS instance_state (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS traffic_daily (
+	day             TEXT    NOT NULL,
+	account         TEXT    NOT NULL DEFAULT '',
+	china_bytes     INTEGER NOT NULL DEFAULT 0,
+	non_china_bytes INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (day, account)
+);
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	instance_key TEXT    NOT NULL,
+	ts           INTEGER NOT NULL,
+	status       TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_status_transitions_instance ON status_transitions (instance_key, ts);
+
+CREATE TABLE IF NOT EXISTS bot_audit (
+	id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp           INTEGER NOT NULL,
+	telegram_user_id    INTEGER NOT NULL DEFAULT 0,
+	telegram_username   TEXT    NOT NULL DEFAULT '',
+	telegram_first_name TEXT    NOT NULL DEFAULT '',
+	command             TEXT    NOT NULL,
+	chat_id             INTEGER NOT NULL DEFAULT 0,
+	outcome             TEXT    NOT NULL,
+	error_message       TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_timestamp ON bot_audit (timestamp);
+
+CREATE TABLE IF NOT EXISTS notify_prefs (
+	chat_id    TEXT    NOT NULL,
+	event_type TEXT    NOT NULL,
+	enabled    INTEGER NOT NULL,
+	PRIMARY KEY (chat_id, event_type)
+);
+`
+
+// Open opens (creating if needed) the database at path
+func Open(path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY between goroutines
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize database %s: %w", path, err)
+	}
+
+	return &DB{db: db}, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// RecordIncident appends an incident, using the current time when inc.Time is zero
+func (d *DB) RecordIncident(inc Incident) error {
+	if inc.Time.IsZero() {
+		inc.Time = time.Now()
+	}
+
+	_, err := d.db.Exec(`INSERT INTO incidents (ts, event_type, instance_id, instance_name, region, duration_ms, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		inc.Time.Unix(), inc.EventType, inc.InstanceID, inc.InstanceName, inc.Region, inc.Duration.Milliseconds(), inc.Error)
+	if err != nil {
+		return fmt.Errorf("failed to record incident: %w", err)
+	}
+	return nil
+}
+
+// RecentIncidents returns the last limit incidents, newest first
+func (d *DB) RecentIncidents(limit int) ([]Incident, error) {
+	rows, err := d.db.Query(`SELECT id, ts, event_type, instance_id, instance_name, region, duration_ms, error
+		FROM incidents ORDER BY ts DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		var inc Incident
+		var ts, durationMS int64
+		if err := rows.Scan(&inc.ID, &ts, &inc.EventType, &inc.InstanceID, &inc.InstanceName, &inc.Region, &durationMS, &inc.Error); err != nil {
+			return nil, fmt.Errorf("failed to read incident: %w", err)
+		}
+		inc.Time = time.Unix(ts, 0)
+		inc.Duration = time.Duration(durationMS) * time.Millisecond
+		incidents = append(incidents, inc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// CountIncidentsSince returns the number of incidents recorded at or after since
+func (d *DB) CountIncidentsSince(since time.Time) (int, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM incidents WHERE ts >= ?`, since.Unix()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count incidents: %w", err)
+	}
+	return count, nil
+}
+
+// LoadStates returns all encoded instance states keyed by instance key (implements state.Backend)
+func (d *DB) LoadStates() (map[string][]byte, error) {
+	rows, err := d.db.Query(`SELECT key, value FROM instance_state`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query instance state: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string][]byte)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to read instance state: %w", err)
+		}
+		states[key] = []byte(value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read instance state: %w", err)
+	}
+
+	return states, nil
+}
+
+// SaveState stores the encoded state of an instance (implements state.Backend)
+func (d *DB) SaveState(key string, data []byte) error {
+	_, err := d.db.Exec(`INSERT INTO instance_state (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save state of %s: %w", key, err)
+	}
+	return nil
+}
+
+// RecordTrafficSnapshot stores the traffic of an account on a day, replacing an earlier
+// snapshot of the same day
+func (d *DB) RecordTrafficSnapshot(s TrafficSnapshot) error {
+	_, err := d.db.Exec(`INSERT INTO traffic_daily (day, account, china_bytes, non_china_bytes) VALUES (?, ?, ?, ?)
+		ON CONFLICT(day, account) DO UPDATE SET china_bytes = excluded.china_bytes, non_china_bytes = excluded.non_china_bytes`,
+		s.Day, s.AccountLabel, s.ChinaBytes, s.NonChinaBytes)
+	if err != nil {
+		return fmt.Errorf("failed to record traffic snapshot of %s: %w", s.Day, err)
+	}
+	return nil
+}
+
+// DailyTraffic returns the traffic of all accounts summed per day, for days from since
+// (YYYY-MM-DD) on, oldest first. The account label of the results is empty.
+func (d *DB) DailyTraffic(since string) ([]TrafficSnapshot, error) {
+	rows, err := d.db.Query(`SELECT day, SUM(china_bytes), SUM(non_china_bytes)
+		FROM traffic_daily WHERE day >= ? GROUP BY day ORDER BY day`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query traffic snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []TrafficSnapshot
+	for rows.Next() {
+		var s TrafficSnapshot
+		if err := rows.Scan(&s.Day, &s.ChinaBytes, &s.NonChinaBytes); err != nil {
+			return nil, fmt.Errorf("failed to read traffic snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read traffic snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// CountIncidentsByInstance returns the number of incidents of eventType recorded at or after
+// since, keyed by instance ID
+func (d *DB) CountIncidentsByInstance(eventType string, since time.Time) (map[string]int, error) {
+	rows, err := d.db.Query(`SELECT instance_id, COUNT(*) FROM incidents
+		WHERE event_type = ? AND ts >= ? GROUP BY instance_id`, eventType, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count incidents: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var instanceID string
+		var count int
+		if err := rows.Scan(&instanceID, &count); err != nil {
+			return nil, fmt.Errorf("failed to read incident count: %w", err)
+		}
+		counts[instanceID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read incident counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// RecordStatusTransition appends a status change of an instance
+func (d *DB) RecordStatusTransition(t StatusTransition) error {
+	if t.Time.IsZero() {
+		t.Time = time.Now()
+	}
+
+	_, err := d.db.Exec(`INSERT INTO status_transitions (instance_key, ts, status) VALUES (?, ?, ?)`,
+		t.InstanceKey, t.Time.Unix(), t.Status)
+	if err != nil {
+		return fmt.Errorf("failed to record status transition of %s: %w", t.InstanceKey, err)
+	}
+	return nil
+}
+
+// StatusTransitions returns the status transitions recorded at or after since, plus the last
+// transition before since of each instance (its status at the start of the window), ordered by
+// instance and time
+func (d *DB) StatusTransitions(since time.Time) ([]StatusTransition, error) {
+	rows, err := d.db.Query(`SELECT instance_key, ts, status FROM status_transitions t
+		WHERE ts >= ? OR id = (SELECT id FROM status_transitions
+			WHERE instance_key = t.instance_key AND ts < ? ORDER BY ts DESC, id DESC LIMIT 1)
+		ORDER BY instance_key, ts, id`, since.Unix(), since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []StatusTransition
+	for rows.Next() {
+		var t StatusTransition
+		var ts int64
+		if err := rows.Scan(&t.InstanceKey, &ts, &t.Status); err != nil {
+			return nil, fmt.Errorf("failed to read status transition: %w", err)
+		}
+		t.Time = time.Unix(ts, 0)
+		transitions = append(transitions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read status transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// RecordAudit appends a bot audit entry, using the current time when e.Time is zero, and returns its ID
+func (d *DB) RecordAudit(e AuditEntry) (int64, error) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	res, err := d.db.Exec(`INSERT INTO bot_audit (timestamp, telegram_user_id, telegram_username, telegram_first_name,
+		command, chat_id, outcome, error_message) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Time.Unix(), e.UserID, e.Username, e.FirstName, e.Command, e.ChatID, e.Outcome, e.Error)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return id, nil
+}
+
+// FinishAudit sets the outcome of an audit entry recorded by RecordAudit
+func (d *DB) FinishAudit(id int64, outcome, errMsg string) error {
+	_, err := d.db.Exec(`UPDATE bot_audit SET outcome = ?, error_message = ? WHERE id = ?`, outcome, errMsg, id)
+	if err != nil {
+		return fmt.Errorf("failed to update audit entry %d: %w", id, err)
+	}
+	return nil
+}
+
+// RecentAudit returns the last limit audit entries, newest first
+func (d *DB) RecentAudit(limit int) ([]AuditEntry, error) {
+	rows, err := d.db.Query(`SELECT id, timestamp, telegram_user_id, telegram_username, telegram_first_name,
+		command, chat_id, outcome, error_message FROM bot_audit ORDER BY timestamp DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var ts int64
+		if err := rows.Scan(&e.ID, &ts, &e.UserID, &e.Username, &e.FirstName, &e.Command, &e.ChatID, &e.Outcome, &e.Error); err != nil {
+			return nil, fmt.Errorf("failed to read audit entry: %w", err)
+		}
+		e.Time = time.Unix(ts, 0)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SetNotifyPreference turns notifications of an event type on or off for a Telegram chat
+func (d *DB) SetNotifyPreference(chatID, eventType string, enabled bool) error {
+	_, err := d.db.Exec(`INSERT INTO notify_prefs (chat_id, event_type, enabled) VALUES (?, ?, ?)
+		ON CONFLICT (chat_id, event_type) DO UPDATE SET enabled = excluded.enabled`, chatID, eventType, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preference: %w", err)
+	}
+	return nil
+}
+
+// NotifyPreferences returns the stored notification preferences: chat ID -> event type -> enabled
+func (d *DB) NotifyPreferences() (map[string]map[string]bool, error) {
+	rows, err := d.db.Query(`SELECT chat_id, event_type, enabled FROM notify_prefs`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := make(map[string]map[string]bool)
+	for rows.Next() {
+		var chatID, eventType string
+		var enabled bool
+		if err := rows.Scan(&chatID, &eventType, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to read notification preference: %w", err)
+		}
+		if prefs[chatID] == nil {
+			prefs[chatID] = make(map[string]bool)
+		}
+		prefs[chatID][eventType] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notification preferences: %w", err)
+	}
+
+	return prefs, nil
+}
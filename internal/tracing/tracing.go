@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const serviceName = "aliyun-spot-manager"
+
+// Init installs the global tracer provider. With an empty endpoint spans are dropped by
+// a noop provider; otherwise they are batched to the OTLP/HTTP exporter, which reads
+// OTEL_EXPORTER_OTLP_ENDPOINT and the other standard OTEL_EXPORTER_OTLP_* variables.
+// The returned function flushes pending spans and must be called before exit.
+func Init(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if endpoint == "" {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// LogHook annotates log entries written with a span context (log.WithContext) with
+// the trace and span IDs, so log lines can be matched to traces.
+// It implements logrus.Hook so it can be attached with log.AddHook.
+type LogHook struct{}
+
+// Levels implements logrus.Hook: all levels are annotated
+func (LogHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (LogHook) Fire(entry *log.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(entry.Context)
+	if !sc.IsValid() {
+		return nil
+	}
+	entry.Data["trace_id"] = sc.TraceID().String()
+	entry.Data["span_id"] = sc.SpanID().String()
+	return nil
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventPath is the path EventBridge delivers events to
+	EventPath = "/aliyun/event"
+	// SignatureHeader carries the hex HMAC-SHA256 of the request body, optionally prefixed with "sha256="
+	SignatureHeader = "X-Signature"
+)
+
+// maxEventSize caps the accepted request body
+const maxEventSize = 1 << 20
+
+// Event is a spot instance interruption event delivered by EventBridge
+type Event struct {
+	ID         string
+	Type       string
+	InstanceID string
+	RegionID   string
+	Time       time.Time // when the event was emitted, the instance is stopped about 5 minutes later
+}
+
+// cloudEvent is the CloudEvents envelope EventBridge delivers
+type cloudEvent struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+	Subject        string `json:"subject"`
+	Time           string `json:"time"`
+	AliyunRegionID string `json:"aliyunregionid"`
+	Data           struct {
+		InstanceID string `json:"instanceId"`
+		ResourceID string `json:"resourceId"`
+		RegionID   string `json:"regionId"`
+	} `json:"data"`
+}
+
+// Server receives EventBridge events on POST /aliyun/event and answers GET requests on the
+// same path for endpoint validation
+type Server struct {
+	addr     string
+	secret   string
+	certFile string
+	keyFile  string
+	onEvent  func(Event)
+}
+
+// NewServer creates an event server. onEvent is called for every event with a valid
+// signature and must not block for long.
+func NewServer(addr, secret, certFile, keyFile string, onEvent func(Event)) *Server {
+	return &Server{
+		addr:     addr,
+		secret:   secret,
+		certFile: certFile,
+		keyFile:  keyFile,
+		onEvent:  onEvent,
+	}
+}
+
+// Serve listens until ctx is cancelled. TLS is served directly when the certificate and key
+// are set, otherwise plain HTTP (for use behind a TLS-terminating reverse proxy).
+func (s *Server) Serve(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(EventPath, s.handleEvent)
+
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Infof("EventBridge webhook server listening on %s%s", s.addr, EventPath)
+		var err error
+		if s.certFile != "" && s.keyFile != "" {
+			err = server.ListenAndServeTLS(s.certFile, s.keyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("EventBridge webhook server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down EventBridge webhook server: %w", err)
+	}
+
+	log.Info("EventBridge webhook server stopped")
+	return nil
+}
+
+// handleEvent validates and dispatches a single event delivery
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		// Endpoint validation
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEventSize))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if !VerifySignature(body, r.Header.Get(SignatureHeader), s.secret) {
+		log.Warnf("Rejected EventBridge event from %s: invalid signature", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		log.Warnf("Failed to parse EventBridge event: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	log.Infof("EventBridge event %s (%s) for instance %s", event.ID, event.Type, event.InstanceID)
+	s.onEvent(event)
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifySignature reports whether signature is the HMAC-SHA256 of body keyed with secret
+func VerifySignature(body []byte, signature, secret string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// ParseEvent extracts the instance of an EventBridge ECS event. The instance ID is taken from
+// data.instanceId, data.resourceId or the "instance/<id>" suffix of the subject.
+func ParseEvent(body []byte) (Event, error) {
+	var ce cloudEvent
+	if err := json.Unmarshal(body, &ce); err != nil {
+		return Event{}, fmt.Errorf("invalid event JSON: %w", err)
+	}
+
+	event := Event{
+		ID:         ce.ID,
+		Type:       ce.Type,
+		InstanceID: ce.Data.InstanceID,
+		RegionID:   ce.Data.RegionID,
+		Time:       time.Now(),
+	}
+	if event.InstanceID == "" {
+		event.InstanceID = ce.Data.ResourceID
+	}
+	if event.InstanceID == "" {
+		if _, id, ok := strings.Cut(ce.Subject, "instance/"); ok {
+			event.InstanceID = id
+		}
+	}
+	if event.InstanceID == "" {
+		return Event{}, fmt.Errorf("event %s has no instance ID", ce.ID)
+	}
+	if event.RegionID == "" {
+		event.RegionID = ce.AliyunRegionID
+	}
+	if ce.Time != "" {
+		if t, err := time.Parse(time.RFC3339, ce.Time); err == nil {
+			event.Time = t
+		}
+	}
+
+	return event, nil
+}
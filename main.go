@@ -1,21 +1,29 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/iliyian/aliyun-spot-manager/internal/config"
+	"github.com/iliyian/aliyun-spot-manager/internal/logbuf"
 	"github.com/iliyian/aliyun-spot-manager/internal/monitor"
-	"github.com/joho/godotenv"
-	"github.com/robfig/cron/v3"
+	"github.com/iliyian/aliyun-spot-manager/internal/tracing"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	// Load .env file
-	if err := godotenv.Load(); err != nil {
+	if err := config.LoadDotEnv(config.DotEnvFile); err != nil {
 		log.Warn("No .env file found, using environment variables")
 	}
 
@@ -27,18 +35,44 @@ func main() {
 
 	// Setup logging
 	setupLogging(cfg)
+	logBuffer := logbuf.New(cfg.LogBufferSize, cfg.Secrets())
+	log.AddHook(logBuffer)
+	log.AddHook(tracing.LogHook{})
 
-	log.Info("Starting Aliyun Spot Instance Monitor")
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	if cfg.OTLPEndpoint != "" {
+		log.Infof("Exporting traces to %s", cfg.OTLPEndpoint)
+	}
+
+	log.Infof("Starting Aliyun Spot Instance Monitor %s", version)
+	if cfg.ConfigFile != "" {
+		log.Infof("Loaded config file %s", cfg.ConfigFile)
+		if len(cfg.EnvOverrides) > 0 {
+			log.Infof("Config file values overridden by environment: %v", cfg.EnvOverrides)
+		}
+	}
 
 	// Create monitor
 	mon, err := monitor.New(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create monitor: %v", err)
 	}
+	mon.SetLogBuffer(logBuffer)
+	mon.SetVersion(version)
+
+	// Run until interrupt signal
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Start probes before discovery so readiness reports as pending meanwhile
+	healthDone := mon.StartHealthServer(ctx)
 
 	// Run initial check
 	log.Info("Running initial instance discovery...")
-	if err := mon.DiscoverInstances(); err != nil {
+	if err := mon.DiscoverInstances(ctx); err != nil {
 		log.Fatalf("Failed to discover instances: %v", err)
 	}
 
@@ -46,45 +80,23 @@ func main() {
 		log.Info("GCP preemptible instance monitoring enabled")
 	}
 
-	// Start Telegram bot for commands
-	mon.StartBot()
-
-	// Setup cron scheduler
-	c := cron.New()
-	_, err = c.AddFunc(cfg.CronSchedule, func() {
-		if err := mon.Check(); err != nil {
-			log.Errorf("Check failed: %v", err)
-		}
-	})
-	if err != nil {
-		log.Fatalf("Failed to setup cron: %v", err)
+	runErr := mon.Run(ctx)
+	if runErr != nil && !errors.Is(runErr, monitor.ErrShutdownTimeout) {
+		log.Fatalf("Monitor stopped with error: %v", runErr)
 	}
-
-	// Setup traffic check cron if enabled
-	if cfg.TrafficShutdownEnabled {
-		trafficSchedule := fmt.Sprintf("@every %ds", cfg.TrafficCheckInterval)
-		_, err = c.AddFunc(trafficSchedule, func() {
-			if err := mon.CheckTraffic(); err != nil {
-				log.Errorf("Traffic check failed: %v", err)
-			}
-		})
-		if err != nil {
-			log.Fatalf("Failed to setup traffic check cron: %v", err)
-		}
-		log.Infof("Traffic shutdown enabled: China limit=%.0f GB, Non-China limit=%.0f GB, check every %ds",
-			cfg.TrafficLimitChinaGB, cfg.TrafficLimitNonChinaGB, cfg.TrafficCheckInterval)
+	<-healthDone
+	if err := mon.Close(); err != nil {
+		log.Warnf("Failed to close database: %v", err)
+	}
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Warnf("Failed to flush traces: %v", err)
 	}
 
-	c.Start()
-	log.Infof("Scheduler started, checking every %d seconds", cfg.CheckInterval)
-
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info("Shutting down...")
-	c.Stop()
+	if runErr != nil {
+		log.Errorf("Monitor stopped: %v", runErr)
+		os.Exit(1)
+	}
+	log.Info("Monitor stopped")
 }
 
 func setupLogging(cfg *config.Config) {
@@ -96,18 +108,42 @@ func setupLogging(cfg *config.Config) {
 	log.SetLevel(level)
 
 	// Set log format
-	log.SetFormatter(&log.TextFormatter{
-		FullTimestamp:   true,
-		TimestampFormat: "2006-01-02 15:04:05",
-	})
+	if cfg.LogFormat == "json" {
+		log.SetFormatter(&appFormatter{
+			app: filepath.Base(os.Args[0]),
+			Formatter: &log.JSONFormatter{
+				DisableTimestamp: false,
+				TimestampFormat:  time.RFC3339,
+			},
+		})
+	} else {
+		log.SetFormatter(&log.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: "2006-01-02 15:04:05",
+		})
+	}
 
-	// Set log output
+	// Set log output: stdout, plus a rotating log file if configured
 	if cfg.LogFile != "" {
-		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err != nil {
-			log.Warnf("Failed to open log file %s, using stdout: %v", cfg.LogFile, err)
-		} else {
-			log.SetOutput(file)
-		}
+		log.SetOutput(io.MultiWriter(os.Stdout, &lumberjack.Logger{
+			Filename:   cfg.LogFile,
+			MaxSize:    cfg.LogMaxSizeMB,
+			MaxBackups: cfg.LogMaxBackups,
+			MaxAge:     cfg.LogMaxAgeDays,
+		}))
+	} else {
+		log.SetOutput(os.Stdout)
 	}
 }
+
+// appFormatter adds an app field with the binary name to every entry
+type appFormatter struct {
+	log.Formatter
+	app string
+}
+
+// Format implements logrus.Formatter
+func (f *appFormatter) Format(entry *log.Entry) ([]byte, error) {
+	entry.Data["app"] = f.app
+	return f.Formatter.Format(entry)
+}